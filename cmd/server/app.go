@@ -29,12 +29,16 @@ type Application struct {
 	jwtManager *utils.JWTManager
 	server     *http.Server
 	router     *gin.Engine
+	reminders  *services.ReminderDispatcher
+	purger     *services.RetentionPurger
+	startedAt  time.Time
 }
 
 // NewApplication creates and initializes a new application instance
 func NewApplication(cfg *config.Config) (*Application, error) {
 	app := &Application{
-		config: cfg,
+		config:    cfg,
+		startedAt: time.Now(),
 	}
 
 	// Initialize dependencies
@@ -43,7 +47,9 @@ func NewApplication(cfg *config.Config) (*Application, error) {
 	}
 
 	// Setup router and routes
-	app.setupRouter()
+	if err := app.setupRouter(); err != nil {
+		return nil, fmt.Errorf("failed to setup router: %w", err)
+	}
 
 	// Create HTTP server
 	app.server = &http.Server{
@@ -56,9 +62,6 @@ func NewApplication(cfg *config.Config) (*Application, error) {
 
 // initializeDependencies sets up database, JWT, and other dependencies
 func (a *Application) initializeDependencies() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
 	// Connect to database
 	dbCfg := db.DBConfig{
 		Host:     a.config.DBHost,
@@ -66,24 +69,50 @@ func (a *Application) initializeDependencies() error {
 		User:     a.config.DBUser,
 		Password: a.config.DBPassword,
 		DBName:   a.config.DBName,
+
+		MaxOpenConns:    a.config.DBMaxOpenConns,
+		MaxIdleConns:    a.config.DBMaxIdleConns,
+		ConnMaxLifetime: a.config.DBConnMaxLifetime,
+
+		ConnectRetries: a.config.DBConnectRetries,
+		ConnectBackoff: a.config.DBConnectBackoff,
+
+		SlowQueryThreshold: a.config.SlowQueryThreshold,
 	}
-	database, err := db.ConnectDB(ctx, dbCfg)
+	// Sized from the retry settings, so a long backoff is not cut short by a fixed deadline
+	connectCtx, cancelConnect := context.WithTimeout(context.Background(), dbCfg.ConnectTimeout())
+	database, err := db.ConnectDB(connectCtx, dbCfg)
+	cancelConnect()
 	if err != nil {
 		return fmt.Errorf("database connection failed: %w", err)
 	}
 	a.db = database
 	log.Println("Database connection established successfully")
 
-	// Run migrations if configured
-	if a.config.RunMigrations {
+	// Apply or verify the schema as configured, with its own deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	switch a.config.MigrationMode {
+	case config.MigrationModeApply:
 		if err := a.db.Migrate(ctx, "db/schema.sql"); err != nil {
 			return fmt.Errorf("database migration failed: %w", err)
 		}
 		log.Println("Database migrations executed successfully")
+	case config.MigrationModeVerify:
+		if err := a.db.VerifySchema(ctx, "db/schema.sql"); err != nil {
+			return fmt.Errorf("database schema verification failed: %w", err)
+		}
+		log.Println("Database schema verified")
 	}
 
 	// Initialize JWT manager
-	jwtManager, err := utils.NewJWTManager(a.config.JWTSecret)
+	jwtManager, err := utils.NewJWTManager(a.config.JWTSecret,
+		utils.WithIssuer(a.config.JWTIssuer),
+		utils.WithAudience(a.config.JWTAudience),
+		utils.WithSigningMethod(a.config.JWTAlgorithm),
+		utils.WithKeys(a.config.JWTSigningKeyID, a.config.JWTKeys),
+		utils.WithRequireKID(a.config.JWTRequireKID),
+	)
 	if err != nil {
 		return fmt.Errorf("JWT manager initialization failed: %w", err)
 	}
@@ -93,49 +122,115 @@ func (a *Application) initializeDependencies() error {
 }
 
 // setupRouter configures the Gin router with middleware and routes
-func (a *Application) setupRouter() {
+func (a *Application) setupRouter() error {
 	// Initialize repositories (dependency injection)
 	userRepo := repository.NewSQLUserRepository(a.db.Queries)
 	todoRepo := repository.NewSQLTodoRepository(a.db.Queries)
 	categoryRepo := repository.NewSQLCategoryRepository(a.db.Queries)
 	categoryShareRepo := repository.NewSQLCategoryShareRepository(a.db.Queries)
+	todoEventRepo := repository.NewSQLTodoEventRepository(a.db.Queries)
+	apiTokenRepo := repository.NewSQLAPITokenRepository(a.db.Queries)
+	commentRepo := repository.NewSQLCommentRepository(a.db.Queries)
+	txManager := repository.NewSQLTxManager(a.db)
 
 	// Initialize services (dependency injection)
-	authSvc := services.NewAuthService(userRepo, a.jwtManager)
-	todoSvc := services.NewTodoService(todoRepo, categoryRepo, categoryShareRepo, services.PaginationConfig{
+	authSvc := services.NewAuthService(userRepo, a.jwtManager, services.AuthConfig{
+		BcryptCost:                     a.config.BcryptCost,
+		BlockedEmailDomains:            a.config.BlockedEmailDomains,
+		RegisterReturnsLoginOnExisting: a.config.RegisterReturnsLoginOnExisting,
+	})
+	pagination := services.PaginationConfig{
 		DefaultPageSize: a.config.DefaultPageSize,
 		MaxPageSize:     a.config.MaxPageSize,
-	})
-	categorySvc := services.NewCategoryService(categoryRepo, categoryShareRepo, userRepo, todoRepo)
+		DefaultTodoSort: a.config.DefaultTodoSort,
+	}
+	limits := services.LimitsConfig{
+		MaxTodosPerCategory: a.config.MaxTodosPerCategory,
+		MaxRecentTodos:      a.config.MaxRecentTodos,
+		MaxTitleLen:         a.config.MaxTitleLen,
+		MaxDescriptionLen:   a.config.MaxDescriptionLen,
+	}
+	undoTokens, err := utils.NewUndoTokenManager(a.config.JWTSecret)
+	if err != nil {
+		return fmt.Errorf("undo token manager initialization failed: %w", err)
+	}
+	todoSvc := services.NewTodoService(todoRepo, categoryRepo, categoryShareRepo, todoEventRepo, userRepo, txManager, pagination, limits, a.config.AutoCreateCategories, undoTokens)
+	categorySvc := services.NewCategoryService(categoryRepo, categoryShareRepo, userRepo, todoRepo, txManager, pagination, limits)
+	apiTokenSvc := services.NewAPITokenService(apiTokenRepo)
+	commentSvc := services.NewCommentService(commentRepo, todoRepo, categoryRepo, categoryShareRepo, pagination)
+	userSvc := services.NewUserService(userRepo, pagination)
+	a.reminders = services.NewReminderDispatcher(todoRepo, services.LogReminderNotifier{}, a.config.ReminderInterval)
+	a.purger = services.NewRetentionPurger(todoRepo, a.config.SoftDeleteRetention, a.config.PurgeInterval)
 
 	// Initialize handlers (dependency injection)
-	authHandler := handlers.NewAuthHandler(authSvc)
-	todoHandler := handlers.NewTodoHandler(todoSvc)
-	categoryHandler := handlers.NewCategoryHandler(categorySvc)
+	authHandler := handlers.NewAuthHandler(authSvc, a.config.AuthCookieMode)
+	todoHandler := handlers.NewTodoHandler(todoSvc, a.config.MinTodoTitleRunes, pagination)
+	categoryHandler := handlers.NewCategoryHandler(categorySvc, pagination)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenSvc)
+	commentHandler := handlers.NewCommentHandler(commentSvc, pagination)
+	adminHandler := handlers.NewAdminHandler(userSvc, pagination)
+
+	// Lowercase emails stored before registration normalized them, so lookups by the normalized email
+	// use the unique index. After the first run this changes nothing.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	normalized, err := userSvc.NormalizeEmails(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if normalized > 0 {
+		log.Printf("Normalized %d stored emails", normalized)
+	}
+
+	// Seed the configured admin. Registration never grants admin, so an account registered after startup
+	// is only promoted on the next restart
+	if a.config.AdminEmail != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		promoted, err := userSvc.SeedAdmin(ctx, a.config.AdminEmail)
+		cancel()
+		if err != nil {
+			return err
+		}
+		if promoted {
+			log.Printf("Made %s an admin", a.config.AdminEmail)
+		}
+	}
 
 	// Setup Gin router
-	a.router = gin.Default()
+	router, err := newRouter(a.config)
+	if err != nil {
+		return err
+	}
+	a.router = router
 
-	// CORS middleware
-	a.router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Custom-Header")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	// Response time middleware (first, so the measured time includes the other middleware)
+	if a.config.EmitResponseTime {
+		a.router.Use(middleware.ResponseTime())
+	}
 
-		c.Next()
-	})
+	// CORS middleware
+	a.router.Use(middleware.CORS(a.config.CORSMaxAge))
 
 	// Request ID middleware
 	a.router.Use(middleware.RequestIDMiddleware())
 
+	// Response compression middleware
+	a.router.Use(middleware.Gzip())
+
 	// Setup routes
-	routes.SetupRoutes(a.router, authHandler, todoHandler, categoryHandler, a.jwtManager)
+	routes.SetupRoutes(a.router, authHandler, todoHandler, categoryHandler, apiTokenHandler, commentHandler, adminHandler, a.jwtManager, apiTokenSvc, authSvc, a.db, a.purger, a.config.AdminToken, a.config.CORSMaxAge, a.config.MaxConcurrentPerUser)
+	routes.SetupDebugRoutes(a.router, a.config.EnableDebugStats, a.startedAt)
+
+	return nil
+}
+
+// newRouter creates the Gin engine, only honoring X-Forwarded-For from the configured trusted proxies
+func newRouter(cfg *config.Config) (*gin.Engine, error) {
+	router := gin.Default()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	return router, nil
 }
 
 // Start begins listening for HTTP requests in a goroutine
@@ -143,6 +238,12 @@ func (a *Application) setupRouter() {
 func (a *Application) Start() chan error {
 	serverErrors := make(chan error, 1)
 
+	// Start publishing due todo reminders
+	a.reminders.Start()
+
+	// Purge soft-deleted todos past the retention
+	a.purger.Start()
+
 	go func() {
 		log.Printf("Server starting on port %s...", a.config.ServerPort)
 		if err := a.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -181,6 +282,10 @@ func (a *Application) Shutdown() error {
 		return err
 	}
 
+	// Stop the background jobs before their database goes away
+	a.reminders.Stop()
+	a.purger.Stop()
+
 	// Close database connection
 	if a.db != nil {
 		if err := a.db.Close(); err != nil {
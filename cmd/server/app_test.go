@@ -0,0 +1,47 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todo-app/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewRouter_TrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		want           string
+	}{
+		{name: "forwarded header honored from trusted proxy", trustedProxies: []string{"10.0.0.0/8"}, want: "203.0.113.7"},
+		{name: "forwarded header ignored from untrusted peer", trustedProxies: []string{"192.168.0.1"}, want: "10.0.0.5"},
+		{name: "forwarded header ignored when no proxies configured", trustedProxies: nil, want: "10.0.0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, err := newRouter(&config.Config{TrustedProxies: tt.trustedProxies})
+			if err != nil {
+				t.Fatalf("newRouter() error = %v", err)
+			}
+			router.GET("/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = "10.0.0.5:4321"
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
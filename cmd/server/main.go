@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata" // Embed the timezone database so user timezones resolve on images without one
 
 	"todo-app/config"
 
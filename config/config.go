@@ -2,8 +2,24 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"todo-app/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MigrationMode selects what startup does with the database schema
+type MigrationMode string
+
+const (
+	MigrationModeOff    MigrationMode = "off"    // Leave the schema alone
+	MigrationModeApply  MigrationMode = "apply"  // Run db/schema.sql (drops and recreates every table)
+	MigrationModeVerify MigrationMode = "verify" // Fail startup if a table or column from db/schema.sql is missing
 )
 
 // Config holds all configuration for the application
@@ -18,31 +34,154 @@ type Config struct {
 	DBPassword string
 	DBName     string
 
-	// Migration configuration
-	RunMigrations bool
+	// Database connection pool configuration
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+
+	// Database connection retry configuration
+	DBConnectRetries int
+	DBConnectBackoff time.Duration
+
+	// SlowQueryThreshold logs database queries that take at least this long (0 disables)
+	SlowQueryThreshold time.Duration
+
+	// Migration configuration (MIGRATION_MODE; when unset, RUN_MIGRATIONS=true means apply and anything else off)
+	MigrationMode MigrationMode
+
+	// JWT configuration (an empty issuer or audience skips that claim)
+	JWTSecret   string
+	JWTIssuer   string
+	JWTAudience string
+
+	// JWTAlgorithm is the HMAC algorithm new tokens are signed with (HS256, HS384 or HS512)
+	JWTAlgorithm string
+
+	// JWTKeys is the signing key ring by kid (JWT_KEYS="kid:secret,..."). When set, new tokens are signed with
+	// JWTSigningKeyID and any key left in the ring still validates; tokens without a kid fall back to JWTSecret
+	// unless JWTRequireKID is set, which retires JWTSecret for validation.
+	JWTKeys         map[string]string
+	JWTSigningKeyID string
+	JWTRequireKID   bool
+
+	// Password hashing configuration
+	BcryptCost int
 
-	// JWT configuration
-	JWTSecret string
+	// Registration configuration (domains are lowercased, empty allows every domain)
+	BlockedEmailDomains []string
+
+	// Registration replay (when true, registering an existing email with its password logs in instead of 409)
+	RegisterReturnsLoginOnExisting bool
 
 	// Pagination configuration
 	DefaultPageSize int
 	MaxPageSize     int
+	DefaultTodoSort models.TodoSort // "field:direction" ordering for the todo list when none is requested
+
+	// Reminder configuration (how often the dispatcher polls for due reminders)
+	ReminderInterval time.Duration
+
+	// Retention configuration (soft-deleted todos older than SoftDeleteRetention are purged every
+	// PurgeInterval; a zero retention keeps them forever)
+	SoftDeleteRetention time.Duration
+	PurgeInterval       time.Duration
+
+	// Admin configuration (shared secret for the /api/admin operations endpoints, empty disables them)
+	AdminToken string
+
+	// Admin user configuration (the account with this email is made an admin at startup, empty seeds
+	// no admin; registering never grants admin)
+	AdminEmail string
+
+	// Limit configuration (zero disables the limit)
+	MaxTodosPerCategory int
+
+	// Recent todos configuration (most todos GET /api/todos/recent returns, whatever limit is requested)
+	MaxRecentTodos int
+
+	// Category configuration (when false, todos must name an existing category instead of creating one)
+	AutoCreateCategories bool
+
+	// Validation configuration (todo titles must have at least this many Unicode characters after trimming)
+	MinTodoTitleRunes int
+
+	// Length configuration (most Unicode characters a todo title or description may have; the request
+	// binding rules of 255 and 1000 remain the upper bound)
+	MaxTitleLen       int
+	MaxDescriptionLen int
+
+	// Proxy configuration (IPs or CIDRs whose X-Forwarded-For is trusted, empty trusts none)
+	TrustedProxies []string
+
+	// Concurrency configuration (most in-flight requests one authenticated user may have, zero disables the limit)
+	MaxConcurrentPerUser int
+
+	// CORS configuration (how long browsers may cache a preflight response, zero disables caching)
+	CORSMaxAge time.Duration
+
+	// Auth configuration (when true, login and register set the JWT as a Secure HttpOnly cookie
+	// instead of returning it in the response body)
+	AuthCookieMode bool
+
+	// Debug configuration (when true, GET /debug/stats reports goroutines, heap and uptime)
+	EnableDebugStats bool
+
+	// Response time configuration (when true, every response carries X-Response-Time in milliseconds)
+	EmitResponseTime bool
 }
 
 // LoadConfig loads configuration from environment variables
 // Returns an error if any required configuration is missing
 func LoadConfig() (*Config, error) {
+	jwtKeys, err := parseJWTKeys(os.Getenv("JWT_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
-		ServerPort:      getEnvWithDefault("PORT", "8080"),
-		DBHost:          os.Getenv("DB_HOST"),
-		DBPort:          getEnvWithDefault("DB_PORT", "3306"),
-		DBUser:          os.Getenv("DB_USER"),
-		DBPassword:      os.Getenv("DB_PASSWORD"),
-		DBName:          os.Getenv("DB_NAME"),
-		RunMigrations:   parseBool(os.Getenv("RUN_MIGRATIONS")),
-		JWTSecret:       os.Getenv("JWT_SECRET"),
-		DefaultPageSize: getEnvAsIntWithDefault("DEFAULT_PAGE_SIZE", 10),
-		MaxPageSize:     getEnvAsIntWithDefault("MAX_PAGE_SIZE", 100),
+		ServerPort:                     getEnvWithDefault("PORT", "8080"),
+		DBHost:                         os.Getenv("DB_HOST"),
+		DBPort:                         getEnvWithDefault("DB_PORT", "3306"),
+		DBUser:                         os.Getenv("DB_USER"),
+		DBPassword:                     os.Getenv("DB_PASSWORD"),
+		DBName:                         os.Getenv("DB_NAME"),
+		DBMaxOpenConns:                 getEnvAsIntWithDefault("DB_MAX_OPEN_CONNS", 100),
+		DBMaxIdleConns:                 getEnvAsIntWithDefault("DB_MAX_IDLE_CONNS", 10),
+		DBConnMaxLifetime:              getEnvAsDurationWithDefault("DB_CONN_MAX_LIFETIME", time.Hour),
+		DBConnectRetries:               getEnvAsIntWithDefault("DB_CONNECT_RETRIES", 5),
+		DBConnectBackoff:               getEnvAsDurationWithDefault("DB_CONNECT_BACKOFF", time.Second),
+		SlowQueryThreshold:             getEnvAsDurationWithDefault("SLOW_QUERY_THRESHOLD", 500*time.Millisecond),
+		MigrationMode:                  migrationModeFromEnv(),
+		JWTSecret:                      os.Getenv("JWT_SECRET"),
+		JWTIssuer:                      os.Getenv("JWT_ISSUER"),
+		JWTAudience:                    os.Getenv("JWT_AUDIENCE"),
+		JWTAlgorithm:                   getEnvWithDefault("JWT_ALGORITHM", "HS256"),
+		JWTKeys:                        jwtKeys,
+		JWTSigningKeyID:                os.Getenv("JWT_SIGNING_KEY_ID"),
+		JWTRequireKID:                  getEnvAsBoolWithDefault("JWT_REQUIRE_KID", false),
+		BcryptCost:                     getEnvAsIntWithDefault("BCRYPT_COST", bcrypt.DefaultCost),
+		BlockedEmailDomains:            getEnvAsList("BLOCKED_EMAIL_DOMAINS"),
+		RegisterReturnsLoginOnExisting: getEnvAsBoolWithDefault("REGISTER_RETURNS_LOGIN_ON_EXISTING", false),
+		DefaultPageSize:                getEnvAsIntWithDefault("DEFAULT_PAGE_SIZE", 10),
+		MaxPageSize:                    getEnvAsIntWithDefault("MAX_PAGE_SIZE", 100),
+		DefaultTodoSort:                models.TodoSort(getEnvWithDefault("DEFAULT_TODO_SORT", string(models.TodoSortCreatedAtDesc))),
+		ReminderInterval:               getEnvAsDurationWithDefault("REMINDER_INTERVAL", time.Minute),
+		SoftDeleteRetention:            getEnvAsDurationWithDefault("SOFT_DELETE_RETENTION", 0),
+		PurgeInterval:                  getEnvAsDurationWithDefault("PURGE_INTERVAL", time.Hour),
+		AdminToken:                     os.Getenv("ADMIN_TOKEN"),
+		AdminEmail:                     strings.TrimSpace(os.Getenv("ADMIN_EMAIL")),
+		MaxTodosPerCategory:            getEnvAsIntWithDefault("MAX_TODOS_PER_CATEGORY", 1000),
+		MaxRecentTodos:                 getEnvAsIntWithDefault("MAX_RECENT_TODOS", 100),
+		AutoCreateCategories:           getEnvAsBoolWithDefault("AUTO_CREATE_CATEGORIES", true),
+		MinTodoTitleRunes:              getEnvAsIntWithDefault("MIN_TODO_TITLE_RUNES", 1),
+		MaxTitleLen:                    getEnvAsIntWithDefault("MAX_TITLE_LEN", 255),
+		MaxDescriptionLen:              getEnvAsIntWithDefault("MAX_DESCRIPTION_LEN", 1000),
+		TrustedProxies:                 getEnvAsList("TRUSTED_PROXIES"),
+		MaxConcurrentPerUser:           getEnvAsIntWithDefault("MAX_CONCURRENT_PER_USER", 20),
+		CORSMaxAge:                     getEnvAsDurationWithDefault("CORS_MAX_AGE", 10*time.Minute),
+		AuthCookieMode:                 getEnvAsBoolWithDefault("AUTH_COOKIE_MODE", false),
+		EnableDebugStats:               getEnvAsBoolWithDefault("ENABLE_DEBUG_STATS", false),
+		EmitResponseTime:               getEnvAsBoolWithDefault("EMIT_RESPONSE_TIME", false),
 	}
 
 	// Validate required fields
@@ -67,12 +206,109 @@ func (c *Config) validate() error {
 	if c.DBName == "" {
 		return fmt.Errorf("DB_NAME is required")
 	}
+	if c.MigrationMode != MigrationModeOff && c.MigrationMode != MigrationModeApply && c.MigrationMode != MigrationModeVerify {
+		return fmt.Errorf("MIGRATION_MODE %q must be off, apply or verify", c.MigrationMode)
+	}
 	if c.JWTSecret == "" {
 		return fmt.Errorf("JWT_SECRET is required")
 	}
+	switch c.JWTAlgorithm {
+	case "HS256", "HS384", "HS512":
+	default:
+		return fmt.Errorf("JWT_ALGORITHM %q must be HS256, HS384 or HS512", c.JWTAlgorithm)
+	}
+	if len(c.JWTKeys) > 0 {
+		if _, ok := c.JWTKeys[c.JWTSigningKeyID]; !ok {
+			return fmt.Errorf("JWT_SIGNING_KEY_ID %q must name a key in JWT_KEYS", c.JWTSigningKeyID)
+		}
+	} else if c.JWTSigningKeyID != "" {
+		return fmt.Errorf("JWT_SIGNING_KEY_ID requires JWT_KEYS")
+	} else if c.JWTRequireKID {
+		return fmt.Errorf("JWT_REQUIRE_KID requires JWT_KEYS")
+	}
+	if c.BcryptCost < bcrypt.MinCost || c.BcryptCost > bcrypt.MaxCost {
+		return fmt.Errorf("BCRYPT_COST must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	if c.DefaultPageSize < 1 {
+		return fmt.Errorf("DEFAULT_PAGE_SIZE must be at least 1")
+	}
+	if c.MaxPageSize < 1 {
+		return fmt.Errorf("MAX_PAGE_SIZE must be at least 1")
+	}
+	if c.DefaultPageSize > c.MaxPageSize {
+		return fmt.Errorf("DEFAULT_PAGE_SIZE (%d) must not exceed MAX_PAGE_SIZE (%d)", c.DefaultPageSize, c.MaxPageSize)
+	}
+	if !c.DefaultTodoSort.IsValid() {
+		return fmt.Errorf("DEFAULT_TODO_SORT %q must be created_at, updated_at or title followed by :asc or :desc", c.DefaultTodoSort)
+	}
+	if c.MinTodoTitleRunes < 1 || c.MinTodoTitleRunes > 255 {
+		return fmt.Errorf("MIN_TODO_TITLE_RUNES must be between 1 and 255")
+	}
+	if c.MaxTitleLen < c.MinTodoTitleRunes || c.MaxTitleLen > 255 {
+		return fmt.Errorf("MAX_TITLE_LEN must be between MIN_TODO_TITLE_RUNES and 255")
+	}
+	if c.MaxDescriptionLen < 1 || c.MaxDescriptionLen > 1000 {
+		return fmt.Errorf("MAX_DESCRIPTION_LEN must be between 1 and 1000")
+	}
+	if c.DBMaxOpenConns < 1 {
+		return fmt.Errorf("DB_MAX_OPEN_CONNS must be at least 1")
+	}
+	if c.DBMaxIdleConns < 0 || c.DBMaxIdleConns > c.DBMaxOpenConns {
+		return fmt.Errorf("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS")
+	}
+	if c.DBConnMaxLifetime < time.Second {
+		return fmt.Errorf("DB_CONN_MAX_LIFETIME must be at least 1s")
+	}
+	if c.DBConnectRetries < 0 {
+		return fmt.Errorf("DB_CONNECT_RETRIES must not be negative")
+	}
+	if c.DBConnectBackoff <= 0 {
+		return fmt.Errorf("DB_CONNECT_BACKOFF must be positive")
+	}
+	if c.SlowQueryThreshold < 0 {
+		return fmt.Errorf("SLOW_QUERY_THRESHOLD must not be negative")
+	}
+	if c.ReminderInterval < time.Second {
+		return fmt.Errorf("REMINDER_INTERVAL must be at least 1s")
+	}
+	if c.SoftDeleteRetention < 0 {
+		return fmt.Errorf("SOFT_DELETE_RETENTION must not be negative")
+	}
+	if c.AdminEmail != "" && !strings.Contains(c.AdminEmail, "@") {
+		return fmt.Errorf("ADMIN_EMAIL must be an email address")
+	}
+	if c.MaxConcurrentPerUser < 0 {
+		return fmt.Errorf("MAX_CONCURRENT_PER_USER must not be negative")
+	}
+	if c.CORSMaxAge < 0 {
+		return fmt.Errorf("CORS_MAX_AGE must not be negative")
+	}
+	if c.PurgeInterval < time.Second {
+		return fmt.Errorf("PURGE_INTERVAL must be at least 1s")
+	}
+	if c.MaxTodosPerCategory < 0 {
+		return fmt.Errorf("MAX_TODOS_PER_CATEGORY must not be negative")
+	}
+	if c.MaxRecentTodos < 1 {
+		return fmt.Errorf("MAX_RECENT_TODOS must be at least 1")
+	}
+	for _, proxy := range c.TrustedProxies {
+		if !isIPOrCIDR(proxy) {
+			return fmt.Errorf("TRUSTED_PROXIES entry %q is not a valid IP or CIDR", proxy)
+		}
+	}
 	return nil
 }
 
+// isIPOrCIDR reports whether value is a single IP address or a CIDR range
+func isIPOrCIDR(value string) bool {
+	if net.ParseIP(value) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(value)
+	return err == nil
+}
+
 // getEnvWithDefault returns the environment variable value or a default if not set
 func getEnvWithDefault(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -82,6 +318,17 @@ func getEnvWithDefault(key, defaultValue string) string {
 	return value
 }
 
+// migrationModeFromEnv reads MIGRATION_MODE, falling back to the older RUN_MIGRATIONS flag when it is unset
+func migrationModeFromEnv() MigrationMode {
+	if mode := os.Getenv("MIGRATION_MODE"); mode != "" {
+		return MigrationMode(strings.ToLower(mode))
+	}
+	if parseBool(os.Getenv("RUN_MIGRATIONS")) {
+		return MigrationModeApply
+	}
+	return MigrationModeOff
+}
+
 // parseBool converts string to bool, treating "true" as true and everything else as false
 func parseBool(value string) bool {
 	b, err := strconv.ParseBool(value)
@@ -91,6 +338,32 @@ func parseBool(value string) bool {
 	return b
 }
 
+// getEnvAsBoolWithDefault returns the environment variable as bool or a default if not set or invalid
+func getEnvAsBoolWithDefault(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue
+	}
+	return b
+}
+
+// getEnvAsDurationWithDefault returns the environment variable as a duration (e.g. "30m") or a default if not set or invalid
+func getEnvAsDurationWithDefault(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultValue
+	}
+	return duration
+}
+
 // getEnvAsIntWithDefault returns the environment variable as int or a default if not set or invalid
 func getEnvAsIntWithDefault(key string, defaultValue int) int {
 	value := os.Getenv(key)
@@ -103,3 +376,38 @@ func getEnvAsIntWithDefault(key string, defaultValue int) int {
 	}
 	return intValue
 }
+
+// parseJWTKeys parses a comma-separated list of kid:secret pairs into a key ring
+// An empty value yields no keys; a malformed or repeated entry is an error rather than being skipped
+func parseJWTKeys(value string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kid, secret, ok := strings.Cut(entry, ":")
+		kid = strings.TrimSpace(kid)
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("JWT_KEYS entries must be kid:secret pairs")
+		}
+		if _, exists := keys[kid]; exists {
+			return nil, fmt.Errorf("JWT_KEYS lists key %q more than once", kid)
+		}
+		keys[kid] = secret
+	}
+	return keys, nil
+}
+
+// getEnvAsList returns the comma-separated environment variable as a list of trimmed, lowercased values
+// Empty entries are skipped, so an unset variable yields an empty list
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
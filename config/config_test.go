@@ -0,0 +1,711 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"todo-app/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// setRequiredEnv sets the minimum environment needed for LoadConfig to succeed
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_USER", "root")
+	t.Setenv("DB_PASSWORD", "secret")
+	t.Setenv("DB_NAME", "todo")
+	t.Setenv("JWT_SECRET", "test-secret-key")
+}
+
+func TestLoadConfig_BcryptCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		wantCost int
+		wantErr  bool
+	}{
+		{
+			name:     "default cost",
+			envValue: "",
+			wantCost: bcrypt.DefaultCost,
+			wantErr:  false,
+		},
+		{
+			name:     "custom cost",
+			envValue: "12",
+			wantCost: 12,
+			wantErr:  false,
+		},
+		{
+			name:     "minimum cost",
+			envValue: "4",
+			wantCost: bcrypt.MinCost,
+			wantErr:  false,
+		},
+		{
+			name:     "cost below minimum",
+			envValue: "3",
+			wantErr:  true,
+		},
+		{
+			name:     "cost above maximum",
+			envValue: "32",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("BCRYPT_COST", tt.envValue)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && cfg.BcryptCost != tt.wantCost {
+				t.Errorf("LoadConfig() BcryptCost = %v, want %v", cfg.BcryptCost, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_DBPool(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantOpen     int
+		wantIdle     int
+		wantLifetime time.Duration
+		wantErr      bool
+	}{
+		{
+			name:         "defaults",
+			env:          map[string]string{},
+			wantOpen:     100,
+			wantIdle:     10,
+			wantLifetime: time.Hour,
+		},
+		{
+			name: "custom values",
+			env: map[string]string{
+				"DB_MAX_OPEN_CONNS":    "25",
+				"DB_MAX_IDLE_CONNS":    "5",
+				"DB_CONN_MAX_LIFETIME": "30m",
+			},
+			wantOpen:     25,
+			wantIdle:     5,
+			wantLifetime: 30 * time.Minute,
+		},
+		{
+			name:    "zero open connections",
+			env:     map[string]string{"DB_MAX_OPEN_CONNS": "0"},
+			wantErr: true,
+		},
+		{
+			name: "idle exceeds open",
+			env: map[string]string{
+				"DB_MAX_OPEN_CONNS": "5",
+				"DB_MAX_IDLE_CONNS": "10",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "lifetime too short",
+			env:     map[string]string{"DB_CONN_MAX_LIFETIME": "10ms"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if cfg.DBMaxOpenConns != tt.wantOpen {
+				t.Errorf("LoadConfig() DBMaxOpenConns = %v, want %v", cfg.DBMaxOpenConns, tt.wantOpen)
+			}
+			if cfg.DBMaxIdleConns != tt.wantIdle {
+				t.Errorf("LoadConfig() DBMaxIdleConns = %v, want %v", cfg.DBMaxIdleConns, tt.wantIdle)
+			}
+			if cfg.DBConnMaxLifetime != tt.wantLifetime {
+				t.Errorf("LoadConfig() DBConnMaxLifetime = %v, want %v", cfg.DBConnMaxLifetime, tt.wantLifetime)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Pagination(t *testing.T) {
+	tests := []struct {
+		name        string
+		defaultSize string
+		maxSize     string
+		wantDefault int
+		wantMax     int
+		wantErr     bool
+	}{
+		{
+			name:        "defaults",
+			wantDefault: 10,
+			wantMax:     100,
+		},
+		{
+			name:        "custom values",
+			defaultSize: "20",
+			maxSize:     "50",
+			wantDefault: 20,
+			wantMax:     50,
+		},
+		{
+			name:        "default equals max",
+			defaultSize: "50",
+			maxSize:     "50",
+			wantDefault: 50,
+			wantMax:     50,
+		},
+		{
+			name:        "zero default page size",
+			defaultSize: "0",
+			wantErr:     true,
+		},
+		{
+			name:    "negative max page size",
+			maxSize: "-5",
+			wantErr: true,
+		},
+		{
+			name:        "default exceeds max",
+			defaultSize: "200",
+			maxSize:     "100",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("DEFAULT_PAGE_SIZE", tt.defaultSize)
+			t.Setenv("MAX_PAGE_SIZE", tt.maxSize)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if cfg.DefaultPageSize != tt.wantDefault {
+				t.Errorf("LoadConfig() DefaultPageSize = %v, want %v", cfg.DefaultPageSize, tt.wantDefault)
+			}
+			if cfg.MaxPageSize != tt.wantMax {
+				t.Errorf("LoadConfig() MaxPageSize = %v, want %v", cfg.MaxPageSize, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_BlockedEmailDomains(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []string
+	}{
+		{name: "unset", value: "", want: nil},
+		{name: "single domain", value: "mailinator.com", want: []string{"mailinator.com"}},
+		{name: "trimmed, lowercased, empty entries skipped", value: " Mailinator.com, ,TempMail.dev ", want: []string{"mailinator.com", "tempmail.dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("BLOCKED_EMAIL_DOMAINS", tt.value)
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.BlockedEmailDomains, tt.want) {
+				t.Errorf("LoadConfig() BlockedEmailDomains = %v, want %v", cfg.BlockedEmailDomains, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_TrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "unset trusts none", value: "", want: nil},
+		{name: "IPs and CIDRs", value: "10.0.0.1, 192.168.0.0/16,::1", want: []string{"10.0.0.1", "192.168.0.0/16", "::1"}},
+		{name: "invalid CIDR", value: "10.0.0.0/33", wantErr: true},
+		{name: "hostname rejected", value: "proxy.internal", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("TRUSTED_PROXIES", tt.value)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(cfg.TrustedProxies, tt.want) {
+				t.Errorf("LoadConfig() TrustedProxies = %v, want %v", cfg.TrustedProxies, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_SlowQueryThreshold(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", value: "", want: 500 * time.Millisecond},
+		{name: "custom", value: "2s", want: 2 * time.Second},
+		{name: "zero disables", value: "0s", want: 0},
+		{name: "negative", value: "-1s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("SLOW_QUERY_THRESHOLD", tt.value)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.SlowQueryThreshold != tt.want {
+				t.Errorf("LoadConfig() SlowQueryThreshold = %v, want %v", cfg.SlowQueryThreshold, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Retention(t *testing.T) {
+	tests := []struct {
+		name          string
+		retention     string
+		interval      string
+		wantRetention time.Duration
+		wantInterval  time.Duration
+		wantErr       bool
+	}{
+		{name: "defaults keep deleted todos", wantRetention: 0, wantInterval: time.Hour},
+		{name: "custom", retention: "720h", interval: "10m", wantRetention: 720 * time.Hour, wantInterval: 10 * time.Minute},
+		{name: "negative retention", retention: "-1h", wantErr: true},
+		{name: "interval too short", interval: "500ms", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("SOFT_DELETE_RETENTION", tt.retention)
+			t.Setenv("PURGE_INTERVAL", tt.interval)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cfg.SoftDeleteRetention != tt.wantRetention || cfg.PurgeInterval != tt.wantInterval {
+				t.Errorf("LoadConfig() retention = %v, interval = %v, want %v and %v", cfg.SoftDeleteRetention, cfg.PurgeInterval, tt.wantRetention, tt.wantInterval)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_CORSMaxAge(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", value: "", want: 10 * time.Minute},
+		{name: "custom", value: "2h", want: 2 * time.Hour},
+		{name: "disabled", value: "0s", want: 0},
+		{name: "negative", value: "-1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("CORS_MAX_AGE", tt.value)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.CORSMaxAge != tt.want {
+				t.Errorf("LoadConfig() CORSMaxAge = %v, want %v", cfg.CORSMaxAge, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_MaxRecentTodos(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "default", value: "", want: 100},
+		{name: "custom", value: "25", want: 25},
+		{name: "zero", value: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("MAX_RECENT_TODOS", tt.value)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.MaxRecentTodos != tt.want {
+				t.Errorf("LoadConfig() MaxRecentTodos = %d, want %d", cfg.MaxRecentTodos, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_MaxConcurrentPerUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "default", value: "", want: 20},
+		{name: "custom", value: "5", want: 5},
+		{name: "zero disables", value: "0", want: 0},
+		{name: "negative", value: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("MAX_CONCURRENT_PER_USER", tt.value)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.MaxConcurrentPerUser != tt.want {
+				t.Errorf("LoadConfig() MaxConcurrentPerUser = %d, want %d", cfg.MaxConcurrentPerUser, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_AdminEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "default none", value: "", want: ""},
+		{name: "custom", value: "ops@example.com", want: "ops@example.com"},
+		{name: "trimmed", value: "  ops@example.com ", want: "ops@example.com"},
+		{name: "not an email", value: "ops", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("ADMIN_EMAIL", tt.value)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.AdminEmail != tt.want {
+				t.Errorf("LoadConfig() AdminEmail = %q, want %q", cfg.AdminEmail, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_AuthCookieMode(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{name: "default off", value: "", want: false},
+		{name: "enabled", value: "true", want: true},
+		{name: "disabled", value: "false", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("AUTH_COOKIE_MODE", tt.value)
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.AuthCookieMode != tt.want {
+				t.Errorf("LoadConfig() AuthCookieMode = %v, want %v", cfg.AuthCookieMode, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_EmitResponseTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{name: "default off", value: "", want: false},
+		{name: "enabled", value: "true", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("EMIT_RESPONSE_TIME", tt.value)
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.EmitResponseTime != tt.want {
+				t.Errorf("LoadConfig() EmitResponseTime = %v, want %v", cfg.EmitResponseTime, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_MaxLengths(t *testing.T) {
+	tests := []struct {
+		name            string
+		title           string
+		description     string
+		wantTitle       int
+		wantDescription int
+		wantErr         bool
+	}{
+		{name: "defaults match the binding bounds", wantTitle: 255, wantDescription: 1000},
+		{name: "custom", title: "80", description: "500", wantTitle: 80, wantDescription: 500},
+		{name: "title above binding bound", title: "256", wantErr: true},
+		{name: "title below minimum runes", title: "0", wantErr: true},
+		{name: "description above binding bound", description: "1001", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("MAX_TITLE_LEN", tt.title)
+			t.Setenv("MAX_DESCRIPTION_LEN", tt.description)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cfg.MaxTitleLen != tt.wantTitle || cfg.MaxDescriptionLen != tt.wantDescription {
+				t.Errorf("LoadConfig() MaxTitleLen = %d, MaxDescriptionLen = %d, want %d and %d", cfg.MaxTitleLen, cfg.MaxDescriptionLen, tt.wantTitle, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_MinTodoTitleRunes(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{name: "default", value: "", want: 1},
+		{name: "custom", value: "3", want: 3},
+		{name: "zero", value: "0", wantErr: true},
+		{name: "above title limit", value: "256", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("MIN_TODO_TITLE_RUNES", tt.value)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.MinTodoTitleRunes != tt.want {
+				t.Errorf("LoadConfig() MinTodoTitleRunes = %d, want %d", cfg.MinTodoTitleRunes, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_DefaultTodoSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    models.TodoSort
+		wantErr bool
+	}{
+		{name: "default", value: "", want: models.TodoSortCreatedAtDesc},
+		{name: "custom", value: "title:asc", want: models.TodoSortTitleAsc},
+		{name: "unknown field", value: "priority:desc", wantErr: true},
+		{name: "missing direction", value: "title", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("DEFAULT_TODO_SORT", tt.value)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.DefaultTodoSort != tt.want {
+				t.Errorf("LoadConfig() DefaultTodoSort = %q, want %q", cfg.DefaultTodoSort, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_MigrationMode(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		runMigrations string
+		want          MigrationMode
+		wantErr       bool
+	}{
+		{name: "default off", want: MigrationModeOff},
+		{name: "legacy flag", runMigrations: "true", want: MigrationModeApply},
+		{name: "verify", mode: "verify", want: MigrationModeVerify},
+		{name: "case insensitive", mode: "APPLY", want: MigrationModeApply},
+		{name: "mode wins over legacy flag", mode: "off", runMigrations: "true", want: MigrationModeOff},
+		{name: "unknown", mode: "sometimes", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("MIGRATION_MODE", tt.mode)
+			t.Setenv("RUN_MIGRATIONS", tt.runMigrations)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && cfg.MigrationMode != tt.want {
+				t.Errorf("LoadConfig() MigrationMode = %v, want %v", cfg.MigrationMode, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_JWTKeys(t *testing.T) {
+	tests := []struct {
+		name         string
+		keys         string
+		signingKeyID string
+		requireKID   string
+		algorithm    string
+		want         map[string]string
+		wantErr      bool
+	}{
+		{name: "no key ring", want: map[string]string{}},
+		{name: "two keys", keys: "2026-10:new-secret, 2026-04:old:secret", signingKeyID: "2026-10", want: map[string]string{"2026-10": "new-secret", "2026-04": "old:secret"}},
+		{name: "signing key not in ring", keys: "a:secret", signingKeyID: "b", wantErr: true},
+		{name: "signing key without ring", signingKeyID: "a", wantErr: true},
+		{name: "require kid with ring", keys: "a:secret", signingKeyID: "a", requireKID: "true", want: map[string]string{"a": "secret"}},
+		{name: "require kid without ring", requireKID: "true", wantErr: true},
+		{name: "missing secret", keys: "a:", signingKeyID: "a", wantErr: true},
+		{name: "missing separator", keys: "a", signingKeyID: "a", wantErr: true},
+		{name: "repeated kid", keys: "a:one,a:two", signingKeyID: "a", wantErr: true},
+		{name: "unsupported algorithm", algorithm: "RS256", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("JWT_KEYS", tt.keys)
+			t.Setenv("JWT_SIGNING_KEY_ID", tt.signingKeyID)
+			t.Setenv("JWT_REQUIRE_KID", tt.requireKID)
+			t.Setenv("JWT_ALGORITHM", tt.algorithm)
+
+			cfg, err := LoadConfig()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(cfg.JWTKeys, tt.want) {
+				t.Errorf("LoadConfig() JWTKeys = %v, want %v", cfg.JWTKeys, tt.want)
+			}
+			if cfg.JWTAlgorithm != "HS256" {
+				t.Errorf("LoadConfig() JWTAlgorithm = %q, want HS256", cfg.JWTAlgorithm)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_RegisterReturnsLoginOnExisting(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{name: "default off", value: "", want: false},
+		{name: "enabled", value: "true", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("REGISTER_RETURNS_LOGIN_ON_EXISTING", tt.value)
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+			if cfg.RegisterReturnsLoginOnExisting != tt.want {
+				t.Errorf("LoadConfig() RegisterReturnsLoginOnExisting = %v, want %v", cfg.RegisterReturnsLoginOnExisting, tt.want)
+			}
+		})
+	}
+}
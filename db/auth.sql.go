@@ -7,20 +7,38 @@ package db
 
 import (
 	"context"
+	"strings"
 )
 
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*) as count FROM users
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :execlastid
-INSERT INTO users (name, email, password) VALUES (?, ?, ?)
+INSERT INTO users (name, email, password, is_admin) VALUES (?, ?, ?, ?)
 `
 
 type CreateUserParams struct {
 	Name     string `db:"name" json:"name"`
 	Email    string `db:"email" json:"email"`
 	Password string `db:"password" json:"password"`
+	IsAdmin  bool   `db:"is_admin" json:"is_admin"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, createUser, arg.Name, arg.Email, arg.Password)
+	result, err := q.db.ExecContext(ctx, createUser,
+		arg.Name,
+		arg.Email,
+		arg.Password,
+		arg.IsAdmin,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -28,7 +46,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (int64,
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, name, email, password, created_at, updated_at FROM users WHERE email = ?
+SELECT id, name, email, password, timezone, is_admin, created_at, updated_at FROM users WHERE email = ?
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -39,6 +57,8 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Name,
 		&i.Email,
 		&i.Password,
+		&i.Timezone,
+		&i.IsAdmin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -46,7 +66,7 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, email, password, created_at, updated_at FROM users WHERE id = ?
+SELECT id, name, email, password, timezone, is_admin, created_at, updated_at FROM users WHERE id = ?
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id uint64) (User, error) {
@@ -57,8 +77,142 @@ func (q *Queries) GetUserByID(ctx context.Context, id uint64) (User, error) {
 		&i.Name,
 		&i.Email,
 		&i.Password,
+		&i.Timezone,
+		&i.IsAdmin,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
+
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT id, name, email, password, timezone, is_admin, created_at, updated_at FROM users WHERE id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) GetUsersByIDs(ctx context.Context, ids []uint64) ([]User, error) {
+	query := getUsersByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Password,
+			&i.Timezone,
+			&i.IsAdmin,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, name, email, password, timezone, is_admin, created_at, updated_at
+FROM users
+ORDER BY created_at ASC, id ASC
+LIMIT ? OFFSET ?
+`
+
+type ListUsersParams struct {
+	Limit  int32 `db:"limit" json:"limit"`
+	Offset int32 `db:"offset" json:"offset"`
+}
+
+// Oldest account first, so pages stay stable as new users register
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Password,
+			&i.Timezone,
+			&i.IsAdmin,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const normalizeUserEmails = `-- name: NormalizeUserEmails :execrows
+UPDATE users SET email = LOWER(TRIM(email)) WHERE BINARY email <> LOWER(TRIM(email))
+`
+
+// Lowercases and trims emails stored before registration normalized them, so lookups by the normalized
+// email can use the unique index. BINARY keeps the comparison case-sensitive under a case-insensitive collation.
+func (q *Queries) NormalizeUserEmails(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, normalizeUserEmails)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setUserAdminByEmail = `-- name: SetUserAdminByEmail :execrows
+UPDATE users SET is_admin = TRUE WHERE email = ? AND is_admin = FALSE
+`
+
+// Only a user who is not an admin yet is updated, so the affected count tells whether anything changed
+func (q *Queries) SetUserAdminByEmail(ctx context.Context, email string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserAdminByEmail, email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserTimezone = `-- name: UpdateUserTimezone :exec
+UPDATE users SET timezone = ? WHERE id = ?
+`
+
+type UpdateUserTimezoneParams struct {
+	Timezone string `db:"timezone" json:"timezone"`
+	ID       uint64 `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateUserTimezone(ctx context.Context, arg UpdateUserTimezoneParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserTimezone, arg.Timezone, arg.ID)
+	return err
+}
@@ -8,11 +8,12 @@ package db
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 )
 
 const countCategoriesByOwnerID = `-- name: CountCategoriesByOwnerID :one
-SELECT COUNT(*) as count FROM categories WHERE owner_id = ?
+SELECT COUNT(*) as count FROM categories WHERE owner_id = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) CountCategoriesByOwnerID(ctx context.Context, ownerID uint64) (int64, error) {
@@ -22,17 +23,49 @@ func (q *Queries) CountCategoriesByOwnerID(ctx context.Context, ownerID uint64)
 	return count, err
 }
 
+const countSharedCategoriesForUser = `-- name: CountSharedCategoriesForUser :one
+SELECT COUNT(*) as count FROM category_shares cs
+JOIN categories c ON cs.category_id = c.id
+WHERE cs.shared_with_user_id = ? AND c.deleted_at IS NULL
+AND (CAST(? AS CHAR) = '' OR cs.permission = CAST(? AS CHAR))
+`
+
+type CountSharedCategoriesForUserParams struct {
+	SharedWithUserID uint64 `db:"shared_with_user_id" json:"shared_with_user_id"`
+	Permission       string `db:"permission" json:"permission"`
+}
+
+// permission is an optional filter, an empty value counts every share
+func (q *Queries) CountSharedCategoriesForUser(ctx context.Context, arg CountSharedCategoriesForUserParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSharedCategoriesForUser, arg.SharedWithUserID, arg.Permission, arg.Permission)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSharesForCategory = `-- name: CountSharesForCategory :one
+SELECT COUNT(*) as count FROM category_shares WHERE category_id = ?
+`
+
+func (q *Queries) CountSharesForCategory(ctx context.Context, categoryID uint64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSharesForCategory, categoryID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createCategory = `-- name: CreateCategory :execlastid
-INSERT INTO categories (name, owner_id) VALUES (?, ?)
+INSERT INTO categories (name, owner_id, default_share_permission) VALUES (?, ?, ?)
 `
 
 type CreateCategoryParams struct {
-	Name    string `db:"name" json:"name"`
-	OwnerID uint64 `db:"owner_id" json:"owner_id"`
+	Name                   string                           `db:"name" json:"name"`
+	OwnerID                uint64                           `db:"owner_id" json:"owner_id"`
+	DefaultSharePermission CategoriesDefaultSharePermission `db:"default_share_permission" json:"default_share_permission"`
 }
 
 func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, createCategory, arg.Name, arg.OwnerID)
+	result, err := q.db.ExecContext(ctx, createCategory, arg.Name, arg.OwnerID, arg.DefaultSharePermission)
 	if err != nil {
 		return 0, err
 	}
@@ -60,13 +93,16 @@ func (q *Queries) CreateCategoryShare(ctx context.Context, arg CreateCategorySha
 	return result.LastInsertId()
 }
 
-const deleteCategory = `-- name: DeleteCategory :exec
-DELETE FROM categories WHERE id = ?
+const deleteAllSharesForCategory = `-- name: DeleteAllSharesForCategory :execrows
+DELETE FROM category_shares WHERE category_id = ?
 `
 
-func (q *Queries) DeleteCategory(ctx context.Context, id uint64) error {
-	_, err := q.db.ExecContext(ctx, deleteCategory, id)
-	return err
+func (q *Queries) DeleteAllSharesForCategory(ctx context.Context, categoryID uint64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAllSharesForCategory, categoryID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const deleteCategoryShare = `-- name: DeleteCategoryShare :exec
@@ -92,10 +128,57 @@ func (q *Queries) DeleteCategoryShareByUserAndCategory(ctx context.Context, arg
 	return err
 }
 
+const getCategoriesByIDs = `-- name: GetCategoriesByIDs :many
+SELECT id, name, owner_id, allow_duplicate_titles, default_share_permission, created_at, updated_at
+FROM categories
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetCategoriesByIDs(ctx context.Context, ids []uint64) ([]Category, error) {
+	query := getCategoriesByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OwnerID,
+			&i.AllowDuplicateTitles,
+			&i.DefaultSharePermission,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getCategoriesByOwnerID = `-- name: GetCategoriesByOwnerID :many
-SELECT id, name, owner_id, created_at, updated_at
+SELECT id, name, owner_id, allow_duplicate_titles, default_share_permission, created_at, updated_at
 FROM categories
-WHERE owner_id = ?
+WHERE owner_id = ? AND deleted_at IS NULL
 ORDER BY name ASC
 `
 
@@ -112,6 +195,8 @@ func (q *Queries) GetCategoriesByOwnerID(ctx context.Context, ownerID uint64) ([
 			&i.ID,
 			&i.Name,
 			&i.OwnerID,
+			&i.AllowDuplicateTitles,
+			&i.DefaultSharePermission,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -129,9 +214,9 @@ func (q *Queries) GetCategoriesByOwnerID(ctx context.Context, ownerID uint64) ([
 }
 
 const getCategoryByID = `-- name: GetCategoryByID :one
-SELECT id, name, owner_id, created_at, updated_at
+SELECT id, name, owner_id, allow_duplicate_titles, default_share_permission, created_at, updated_at
 FROM categories
-WHERE id = ?
+WHERE id = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) GetCategoryByID(ctx context.Context, id uint64) (Category, error) {
@@ -141,6 +226,8 @@ func (q *Queries) GetCategoryByID(ctx context.Context, id uint64) (Category, err
 		&i.ID,
 		&i.Name,
 		&i.OwnerID,
+		&i.AllowDuplicateTitles,
+		&i.DefaultSharePermission,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -148,9 +235,9 @@ func (q *Queries) GetCategoryByID(ctx context.Context, id uint64) (Category, err
 }
 
 const getCategoryByNameAndOwner = `-- name: GetCategoryByNameAndOwner :one
-SELECT id, name, owner_id, created_at, updated_at
+SELECT id, name, owner_id, allow_duplicate_titles, default_share_permission, created_at, updated_at
 FROM categories
-WHERE owner_id = ? AND name = ?
+WHERE owner_id = ? AND name = ? AND deleted_at IS NULL
 `
 
 type GetCategoryByNameAndOwnerParams struct {
@@ -165,6 +252,8 @@ func (q *Queries) GetCategoryByNameAndOwner(ctx context.Context, arg GetCategory
 		&i.ID,
 		&i.Name,
 		&i.OwnerID,
+		&i.AllowDuplicateTitles,
+		&i.DefaultSharePermission,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -214,6 +303,97 @@ func (q *Queries) GetCategoryShareByID(ctx context.Context, id uint64) (Category
 	return i, err
 }
 
+const getEmptyCategoriesByOwnerID = `-- name: GetEmptyCategoriesByOwnerID :many
+SELECT c.id, c.name, c.owner_id, c.allow_duplicate_titles, c.default_share_permission, c.created_at, c.updated_at
+FROM categories c
+WHERE c.owner_id = ? AND c.deleted_at IS NULL
+  AND (SELECT COUNT(*) FROM todos t WHERE t.category_id = c.id AND t.deleted_at IS NULL) = 0
+  AND (SELECT COUNT(*) FROM category_shares cs WHERE cs.category_id = c.id) = 0
+ORDER BY c.name ASC
+`
+
+// Owned categories with no live todos and no shares, which are safe to remove
+func (q *Queries) GetEmptyCategoriesByOwnerID(ctx context.Context, ownerID uint64) ([]Category, error) {
+	rows, err := q.db.QueryContext(ctx, getEmptyCategoriesByOwnerID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Category
+	for rows.Next() {
+		var i Category
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OwnerID,
+			&i.AllowDuplicateTitles,
+			&i.DefaultSharePermission,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGrantedMembersByOwner = `-- name: GetGrantedMembersByOwner :many
+SELECT u.id as user_id, u.name as user_name, u.email as user_email,
+       c.id as category_id, c.name as category_name, cs.permission
+FROM category_shares cs
+JOIN categories c ON cs.category_id = c.id
+JOIN users u ON cs.shared_with_user_id = u.id
+WHERE c.owner_id = ? AND c.deleted_at IS NULL
+ORDER BY u.email ASC, u.id ASC, c.name ASC, c.id ASC
+`
+
+type GetGrantedMembersByOwnerRow struct {
+	UserID       uint64                   `db:"user_id" json:"user_id"`
+	UserName     string                   `db:"user_name" json:"user_name"`
+	UserEmail    string                   `db:"user_email" json:"user_email"`
+	CategoryID   uint64                   `db:"category_id" json:"category_id"`
+	CategoryName string                   `db:"category_name" json:"category_name"`
+	Permission   CategorySharesPermission `db:"permission" json:"permission"`
+}
+
+// Every user with a share on a category owned by owner_id, one row per share, grouped by user
+func (q *Queries) GetGrantedMembersByOwner(ctx context.Context, ownerID uint64) ([]GetGrantedMembersByOwnerRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGrantedMembersByOwner, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGrantedMembersByOwnerRow
+	for rows.Next() {
+		var i GetGrantedMembersByOwnerRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.UserName,
+			&i.UserEmail,
+			&i.CategoryID,
+			&i.CategoryName,
+			&i.Permission,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSharedCategoriesForUser = `-- name: GetSharedCategoriesForUser :many
 SELECT c.id, c.name, c.owner_id, c.created_at, c.updated_at,
        cs.permission,
@@ -221,10 +401,19 @@ SELECT c.id, c.name, c.owner_id, c.created_at, c.updated_at,
 FROM category_shares cs
 JOIN categories c ON cs.category_id = c.id
 JOIN users u ON c.owner_id = u.id
-WHERE cs.shared_with_user_id = ?
-ORDER BY c.name ASC
+WHERE cs.shared_with_user_id = ? AND c.deleted_at IS NULL
+AND (CAST(? AS CHAR) = '' OR cs.permission = CAST(? AS CHAR))
+ORDER BY c.name ASC, c.id ASC
+LIMIT ? OFFSET ?
 `
 
+type GetSharedCategoriesForUserParams struct {
+	SharedWithUserID uint64 `db:"shared_with_user_id" json:"shared_with_user_id"`
+	Permission       string `db:"permission" json:"permission"`
+	Limit            int32  `db:"limit" json:"limit"`
+	Offset           int32  `db:"offset" json:"offset"`
+}
+
 type GetSharedCategoriesForUserRow struct {
 	ID         uint64                   `db:"id" json:"id"`
 	Name       string                   `db:"name" json:"name"`
@@ -236,8 +425,15 @@ type GetSharedCategoriesForUserRow struct {
 	OwnerEmail string                   `db:"owner_email" json:"owner_email"`
 }
 
-func (q *Queries) GetSharedCategoriesForUser(ctx context.Context, sharedWithUserID uint64) ([]GetSharedCategoriesForUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getSharedCategoriesForUser, sharedWithUserID)
+// permission is an optional filter, an empty value returns every share
+func (q *Queries) GetSharedCategoriesForUser(ctx context.Context, arg GetSharedCategoriesForUserParams) ([]GetSharedCategoriesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSharedCategoriesForUser,
+		arg.SharedWithUserID,
+		arg.Permission,
+		arg.Permission,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -268,15 +464,77 @@ func (q *Queries) GetSharedCategoriesForUser(ctx context.Context, sharedWithUser
 	return items, nil
 }
 
+const getSharesByOwner = `-- name: GetSharesByOwner :many
+SELECT cs.id, cs.category_id, cs.shared_with_user_id, cs.permission, cs.created_at,
+       u.name as shared_with_user_name, u.email as shared_with_user_email, c.name as category_name
+FROM category_shares cs
+JOIN categories c ON cs.category_id = c.id
+JOIN users u ON cs.shared_with_user_id = u.id
+WHERE c.owner_id = ? AND c.deleted_at IS NULL
+ORDER BY c.name ASC, c.id ASC, u.email ASC
+`
+
+type GetSharesByOwnerRow struct {
+	ID                  uint64                   `db:"id" json:"id"`
+	CategoryID          uint64                   `db:"category_id" json:"category_id"`
+	SharedWithUserID    uint64                   `db:"shared_with_user_id" json:"shared_with_user_id"`
+	Permission          CategorySharesPermission `db:"permission" json:"permission"`
+	CreatedAt           time.Time                `db:"created_at" json:"created_at"`
+	SharedWithUserName  string                   `db:"shared_with_user_name" json:"shared_with_user_name"`
+	SharedWithUserEmail string                   `db:"shared_with_user_email" json:"shared_with_user_email"`
+	CategoryName        string                   `db:"category_name" json:"category_name"`
+}
+
+// Every share of every category owned by owner_id; categories without shares produce no rows
+func (q *Queries) GetSharesByOwner(ctx context.Context, ownerID uint64) ([]GetSharesByOwnerRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSharesByOwner, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSharesByOwnerRow
+	for rows.Next() {
+		var i GetSharesByOwnerRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CategoryID,
+			&i.SharedWithUserID,
+			&i.Permission,
+			&i.CreatedAt,
+			&i.SharedWithUserName,
+			&i.SharedWithUserEmail,
+			&i.CategoryName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSharesForCategory = `-- name: GetSharesForCategory :many
 SELECT cs.id, cs.category_id, cs.shared_with_user_id, cs.permission, cs.created_at,
        u.name as shared_with_user_name, u.email as shared_with_user_email
 FROM category_shares cs
 JOIN users u ON cs.shared_with_user_id = u.id
 WHERE cs.category_id = ?
-ORDER BY cs.created_at DESC
+ORDER BY CASE WHEN CAST(? AS CHAR) = 'email' THEN u.email END ASC, cs.created_at DESC, cs.id DESC
+LIMIT ? OFFSET ?
 `
 
+type GetSharesForCategoryParams struct {
+	CategoryID uint64 `db:"category_id" json:"category_id"`
+	SortBy     string `db:"sort_by" json:"sort_by"`
+	Limit      int32  `db:"limit" json:"limit"`
+	Offset     int32  `db:"offset" json:"offset"`
+}
+
 type GetSharesForCategoryRow struct {
 	ID                  uint64                   `db:"id" json:"id"`
 	CategoryID          uint64                   `db:"category_id" json:"category_id"`
@@ -287,8 +545,14 @@ type GetSharesForCategoryRow struct {
 	SharedWithUserEmail string                   `db:"shared_with_user_email" json:"shared_with_user_email"`
 }
 
-func (q *Queries) GetSharesForCategory(ctx context.Context, categoryID uint64) ([]GetSharesForCategoryRow, error) {
-	rows, err := q.db.QueryContext(ctx, getSharesForCategory, categoryID)
+// sort_by 'email' orders by recipient email, any other value orders newest first
+func (q *Queries) GetSharesForCategory(ctx context.Context, arg GetSharesForCategoryParams) ([]GetSharesForCategoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSharesForCategory,
+		arg.CategoryID,
+		arg.SortBy,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -342,17 +606,16 @@ LEFT JOIN category_shares cs ON c.id = cs.category_id AND cs.shared_with_user_id
 LEFT JOIN todos t ON c.id = t.category_id AND t.deleted_at IS NULL
 LEFT JOIN users owner ON c.owner_id = owner.id
 LEFT JOIN users creator ON t.created_by = creator.id
-WHERE
-    c.owner_id = ?
-    OR cs.shared_with_user_id = ?
+WHERE c.deleted_at IS NULL AND (
+    (c.owner_id = ? AND CAST(? AS CHAR) <> 'shared')
+    OR (cs.shared_with_user_id = ? AND CAST(? AS CHAR) <> 'owned')
+)
 ORDER BY c.name ASC, t.created_at DESC
 `
 
 type GetTodosGroupedByCategoryParams struct {
-	OwnerID            uint64 `db:"owner_id" json:"owner_id"`
-	SharedWithUserID   uint64 `db:"shared_with_user_id" json:"shared_with_user_id"`
-	OwnerID_2          uint64 `db:"owner_id_2" json:"owner_id_2"`
-	SharedWithUserID_2 uint64 `db:"shared_with_user_id_2" json:"shared_with_user_id_2"`
+	UserID uint64 `db:"user_id" json:"user_id"`
+	Scope  string `db:"scope" json:"scope"`
 }
 
 type GetTodosGroupedByCategoryRow struct {
@@ -374,12 +637,15 @@ type GetTodosGroupedByCategoryRow struct {
 
 // Returns all accessible categories with their todos for a user
 // Categories are accessible if user owns them OR they are shared with user
+// scope 'owned' or 'shared' limits the result to one of the two, any other value returns both
 func (q *Queries) GetTodosGroupedByCategory(ctx context.Context, arg GetTodosGroupedByCategoryParams) ([]GetTodosGroupedByCategoryRow, error) {
 	rows, err := q.db.QueryContext(ctx, getTodosGroupedByCategory,
-		arg.OwnerID,
-		arg.SharedWithUserID,
-		arg.OwnerID_2,
-		arg.SharedWithUserID_2,
+		arg.UserID,
+		arg.UserID,
+		arg.UserID,
+		arg.Scope,
+		arg.UserID,
+		arg.Scope,
 	)
 	if err != nil {
 		return nil, err
@@ -425,7 +691,7 @@ SELECT
     END as permission
 FROM categories c
 LEFT JOIN category_shares cs ON c.id = cs.category_id AND cs.shared_with_user_id = ?
-WHERE c.id = ?
+WHERE c.id = ? AND c.deleted_at IS NULL
 `
 
 type GetUserPermissionForCategoryParams struct {
@@ -441,17 +707,111 @@ func (q *Queries) GetUserPermissionForCategory(ctx context.Context, arg GetUserP
 	return permission, err
 }
 
+const getWritableCategoriesForUser = `-- name: GetWritableCategoriesForUser :many
+SELECT c.id, c.name, 'owner' as permission
+FROM categories c
+WHERE c.owner_id = ? AND c.deleted_at IS NULL
+UNION ALL
+SELECT c.id, c.name, 'write' as permission
+FROM category_shares cs
+JOIN categories c ON cs.category_id = c.id
+WHERE cs.shared_with_user_id = ? AND cs.permission = 'write' AND c.deleted_at IS NULL
+ORDER BY name ASC, id ASC
+`
+
+type GetWritableCategoriesForUserRow struct {
+	ID         uint64 `db:"id" json:"id"`
+	Name       string `db:"name" json:"name"`
+	Permission string `db:"permission" json:"permission"`
+}
+
+// Returns the categories a user can add todos to: owned ones plus those shared with write permission
+func (q *Queries) GetWritableCategoriesForUser(ctx context.Context, userID uint64) ([]GetWritableCategoriesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWritableCategoriesForUser, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWritableCategoriesForUserRow
+	for rows.Next() {
+		var i GetWritableCategoriesForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Permission,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockCategory = `-- name: LockCategory :one
+SELECT id FROM categories WHERE id = ? FOR UPDATE
+`
+
+// Locks the category row until the transaction ends, so writers checking its todo limit take turns
+func (q *Queries) LockCategory(ctx context.Context, id uint64) (uint64, error) {
+	row := q.db.QueryRowContext(ctx, lockCategory, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const softDeleteCategory = `-- name: SoftDeleteCategory :exec
+UPDATE categories c
+LEFT JOIN todos t ON t.category_id = c.id AND t.deleted_at IS NULL
+SET c.deleted_at = CURRENT_TIMESTAMP, t.deleted_at = CURRENT_TIMESTAMP
+WHERE c.id = ? AND c.deleted_at IS NULL
+`
+
+// Soft deletes the category and its todos in one statement so neither is left half deleted
+func (q *Queries) SoftDeleteCategory(ctx context.Context, id uint64) error {
+	_, err := q.db.ExecContext(ctx, softDeleteCategory, id)
+	return err
+}
+
+const softDeleteEmptyCategory = `-- name: SoftDeleteEmptyCategory :execrows
+UPDATE categories c
+SET c.deleted_at = CURRENT_TIMESTAMP
+WHERE c.id = ? AND c.deleted_at IS NULL
+  AND (SELECT COUNT(*) FROM todos t WHERE t.category_id = c.id AND t.deleted_at IS NULL) = 0
+  AND (SELECT COUNT(*) FROM category_shares cs WHERE cs.category_id = c.id) = 0
+`
+
+// Rechecks emptiness in the same statement, so a todo or share added since the category was listed keeps it
+func (q *Queries) SoftDeleteEmptyCategory(ctx context.Context, id uint64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, softDeleteEmptyCategory, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateCategory = `-- name: UpdateCategory :exec
-UPDATE categories SET name = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+UPDATE categories SET name = ?, allow_duplicate_titles = ?, default_share_permission = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL
 `
 
 type UpdateCategoryParams struct {
-	Name string `db:"name" json:"name"`
-	ID   uint64 `db:"id" json:"id"`
+	Name                   string                           `db:"name" json:"name"`
+	AllowDuplicateTitles   bool                             `db:"allow_duplicate_titles" json:"allow_duplicate_titles"`
+	DefaultSharePermission CategoriesDefaultSharePermission `db:"default_share_permission" json:"default_share_permission"`
+	ID                     uint64                           `db:"id" json:"id"`
 }
 
 func (q *Queries) UpdateCategory(ctx context.Context, arg UpdateCategoryParams) error {
-	_, err := q.db.ExecContext(ctx, updateCategory, arg.Name, arg.ID)
+	_, err := q.db.ExecContext(ctx, updateCategory,
+		arg.Name,
+		arg.AllowDuplicateTitles,
+		arg.DefaultSharePermission,
+		arg.ID,
+	)
 	return err
 }
 
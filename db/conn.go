@@ -3,18 +3,22 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"strings"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 // DB holds the database connection and SQLC queries instance
 type DB struct {
 	SQL     *sql.DB
 	Queries *Queries
+
+	slowQueryThreshold time.Duration
 }
 
 // DBConfig holds database connection parameters
@@ -24,37 +28,121 @@ type DBConfig struct {
 	User     string
 	Password string
 	DBName   string
+
+	// Connection pool settings
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// ConnectRetries is how many times to retry a failed connection attempt
+	// ConnectBackoff is the delay before the first retry; it doubles on each subsequent retry
+	ConnectRetries int
+	ConnectBackoff time.Duration
+
+	// SlowQueryThreshold logs queries that take at least this long; 0 disables the log
+	SlowQueryThreshold time.Duration
+}
+
+// connectAttemptAllowance is how long ConnectTimeout budgets for each connection attempt itself
+const connectAttemptAllowance = 10 * time.Second
+
+// ConnectTimeout returns a deadline long enough for every attempt ConnectDB may make: each attempt's
+// allowance plus the backoff slept before every retry
+func (cfg DBConfig) ConnectTimeout() time.Duration {
+	timeout := connectAttemptAllowance
+	delay := cfg.ConnectBackoff
+	for i := 0; i < cfg.ConnectRetries; i++ {
+		timeout += delay + connectAttemptAllowance
+		delay *= 2
+	}
+	return timeout
 }
 
 // ConnectDB opens a database connection and prepares sqlc queries
+// Transient connection failures are retried with exponential backoff until
+// cfg.ConnectRetries is exhausted or ctx is done
 func ConnectDB(ctx context.Context, cfg DBConfig) (*DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
 
-	// open DB
-	sqlDB, err := sql.Open("mysql", dsn)
-	if err != nil {
-		return nil, err
-	}
+	dial := func(ctx context.Context) (*sql.DB, error) {
+		sqlDB, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return nil, err
+		}
+
+		configurePool(sqlDB, cfg)
 
-	// Set reasonable connection pool defaults
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+		// Ping database to verify connection
+		if err := sqlDB.PingContext(ctx); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+		return sqlDB, nil
+	}
 
-	// Ping database to verify connection
-	if err := sqlDB.PingContext(ctx); err != nil {
+	sqlDB, err := connectWithRetry(ctx, cfg.ConnectRetries, cfg.ConnectBackoff, dial)
+	if err != nil {
 		return nil, err
 	}
 
 	// Create DB instance with connection and queries
 	database := &DB{
-		SQL:     sqlDB,
-		Queries: New(sqlDB),
+		SQL:                sqlDB,
+		Queries:            New(withSlowQueryLog(sqlDB, cfg.SlowQueryThreshold)),
+		slowQueryThreshold: cfg.SlowQueryThreshold,
 	}
 
 	return database, nil
 }
 
+// QueriesForTx returns queries bound to tx, logging slow statements like Queries does
+func (d *DB) QueriesForTx(tx *sql.Tx) *Queries {
+	return New(withSlowQueryLog(tx, d.slowQueryThreshold))
+}
+
+// connectWithRetry calls dial until it succeeds, a non-transient error occurs,
+// retries are exhausted, or ctx is done
+func connectWithRetry(ctx context.Context, retries int, backoff time.Duration, dial func(ctx context.Context) (*sql.DB, error)) (*sql.DB, error) {
+	delay := backoff
+	for attempt := 1; ; attempt++ {
+		sqlDB, err := dial(ctx)
+		if err == nil {
+			return sqlDB, nil
+		}
+
+		if attempt > retries || !isTransientConnError(err) {
+			return nil, err
+		}
+
+		log.Printf("Database connection attempt %d/%d failed: %v; retrying in %v", attempt, retries+1, err, delay)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("giving up connecting to database: %w (last error: %v)", ctx.Err(), err)
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// isTransientConnError reports whether a connection error is worth retrying.
+// Errors returned by the MySQL server itself (bad credentials, unknown database)
+// will not resolve on their own, so they are not retried.
+func isTransientConnError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var mysqlErr *mysql.MySQLError
+	return !errors.As(err, &mysqlErr)
+}
+
+// configurePool applies the connection pool settings from cfg to sqlDB
+func configurePool(sqlDB *sql.DB, cfg DBConfig) {
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+}
+
 // Close closes the underlying SQL connection
 func (d *DB) Close() error {
 	if d.SQL != nil {
@@ -63,6 +151,145 @@ func (d *DB) Close() error {
 	return nil
 }
 
+// schemaTables are the tables SchemaReady requires before the application can serve requests
+var schemaTables = []string{"users", "categories", "todos"}
+
+// SchemaReady checks that the database is reachable and that migrations have created the core tables
+func (d *DB) SchemaReady(ctx context.Context) error {
+	if d.SQL == nil {
+		return fmt.Errorf("database not connected")
+	}
+
+	rows, err := d.SQL.QueryContext(ctx,
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name IN (?, ?, ?)",
+		schemaTables[0], schemaTables[1], schemaTables[2])
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool, len(schemaTables))
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		found[strings.ToLower(name)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, table := range schemaTables {
+		if !found[table] {
+			missing = append(missing, table)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("schema not migrated: missing tables %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// VerifySchema checks, without changing anything, that every table and column the schema file creates
+// exists in the database. The returned error lists everything that is missing.
+func (d *DB) VerifySchema(ctx context.Context, schemaPath string) error {
+	if d.SQL == nil {
+		return fmt.Errorf("database not connected")
+	}
+
+	content, err := os.ReadFile(schemaPath)
+	if err != nil {
+		return err
+	}
+	expected := schemaColumns(string(content))
+
+	rows, err := d.SQL.QueryContext(ctx,
+		"SELECT table_name, column_name FROM information_schema.columns WHERE table_schema = DATABASE()")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	actual := make(map[string]map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return err
+		}
+		table, column = strings.ToLower(table), strings.ToLower(column)
+		if actual[table] == nil {
+			actual[table] = make(map[string]bool)
+		}
+		actual[table][column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if missing := schemaDrift(expected, actual); len(missing) > 0 {
+		return fmt.Errorf("schema drift: missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// schemaTable is a table and its columns, in the order the schema file creates them
+type schemaTable struct {
+	name    string
+	columns []string
+}
+
+// schemaColumns extracts the tables and column names from the CREATE TABLE statements in a schema file.
+// Index, key and constraint lines are skipped; only presence is compared, not types or defaults.
+func schemaColumns(schema string) []schemaTable {
+	var tables []schemaTable
+	for _, stmt := range strings.Split(schema, ";") {
+		stmt = strings.TrimSpace(stmt)
+		if !strings.HasPrefix(strings.ToUpper(stmt), "CREATE TABLE") {
+			continue
+		}
+		open := strings.Index(stmt, "(")
+		if open < 0 {
+			continue
+		}
+		header := strings.Fields(stmt[:open])
+		table := schemaTable{name: strings.ToLower(strings.Trim(header[len(header)-1], "`"))}
+
+		for _, line := range strings.Split(stmt[open+1:], "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 || strings.HasPrefix(fields[0], "--") || strings.HasPrefix(fields[0], ")") {
+				continue
+			}
+			switch strings.ToUpper(fields[0]) {
+			case "PRIMARY", "FOREIGN", "UNIQUE", "INDEX", "KEY", "CONSTRAINT", "CHECK":
+				continue
+			}
+			table.columns = append(table.columns, strings.ToLower(strings.Trim(fields[0], "`")))
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// schemaDrift lists the expected tables and table.column pairs that are absent from actual
+func schemaDrift(expected []schemaTable, actual map[string]map[string]bool) []string {
+	var missing []string
+	for _, table := range expected {
+		columns, ok := actual[table.name]
+		if !ok {
+			missing = append(missing, "table "+table.name)
+			continue
+		}
+		for _, column := range table.columns {
+			if !columns[column] {
+				missing = append(missing, "column "+table.name+"."+column)
+			}
+		}
+	}
+	return missing
+}
+
 // Migrate executes SQL statements from the schema file sequentially (non-destructive/migrations are simple)
 func (d *DB) Migrate(ctx context.Context, schemaPath string) error {
 	if d.SQL == nil {
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestConfigurePool(t *testing.T) {
+	// sql.Open does not dial, so no database is needed here
+	sqlDB, err := sql.Open("mysql", "user:pass@tcp(127.0.0.1:3306)/todo")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	defer sqlDB.Close()
+
+	configurePool(sqlDB, DBConfig{
+		MaxOpenConns:    7,
+		MaxIdleConns:    3,
+		ConnMaxLifetime: time.Minute,
+	})
+
+	if got := sqlDB.Stats().MaxOpenConnections; got != 7 {
+		t.Errorf("MaxOpenConnections = %v, want 7", got)
+	}
+}
+
+func TestConnectWithRetry_SucceedsOnThirdAttempt(t *testing.T) {
+	attempts := 0
+	dial := func(ctx context.Context) (*sql.DB, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("dial tcp 127.0.0.1:3306: connect: connection refused")
+		}
+		return sql.Open("mysql", "user:pass@tcp(127.0.0.1:3306)/todo")
+	}
+
+	sqlDB, err := connectWithRetry(context.Background(), 5, time.Millisecond, dial)
+	if err != nil {
+		t.Fatalf("connectWithRetry() error = %v", err)
+	}
+	defer sqlDB.Close()
+
+	if attempts != 3 {
+		t.Errorf("connectWithRetry() attempts = %d, want 3", attempts)
+	}
+}
+
+func TestConnectWithRetry_ExhaustsRetries(t *testing.T) {
+	attempts := 0
+	dial := func(ctx context.Context) (*sql.DB, error) {
+		attempts++
+		return nil, errors.New("connection refused")
+	}
+
+	if _, err := connectWithRetry(context.Background(), 2, time.Millisecond, dial); err == nil {
+		t.Fatal("connectWithRetry() expected error after exhausting retries")
+	}
+	if attempts != 3 {
+		t.Errorf("connectWithRetry() attempts = %d, want 3", attempts)
+	}
+}
+
+func TestConnectWithRetry_DoesNotRetryServerErrors(t *testing.T) {
+	attempts := 0
+	dial := func(ctx context.Context) (*sql.DB, error) {
+		attempts++
+		return nil, &mysql.MySQLError{Number: 1045, Message: "Access denied"}
+	}
+
+	if _, err := connectWithRetry(context.Background(), 5, time.Millisecond, dial); err == nil {
+		t.Fatal("connectWithRetry() expected error")
+	}
+	if attempts != 1 {
+		t.Errorf("connectWithRetry() attempts = %d, want 1", attempts)
+	}
+}
+
+func TestConnectWithRetry_RespectsContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	dial := func(ctx context.Context) (*sql.DB, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	start := time.Now()
+	_, err := connectWithRetry(ctx, 100, 10*time.Millisecond, dial)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("connectWithRetry() error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("connectWithRetry() took %v, expected to stop at the context deadline", elapsed)
+	}
+}
+
+func TestDBConfig_ConnectTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+		backoff time.Duration
+		want    time.Duration
+	}{
+		{name: "no retries", retries: 0, backoff: time.Second, want: connectAttemptAllowance},
+		// The defaults sleep 1+2+4+8+16 = 31s between six attempts
+		{name: "defaults", retries: 5, backoff: time.Second, want: 31*time.Second + 6*connectAttemptAllowance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DBConfig{ConnectRetries: tt.retries, ConnectBackoff: tt.backoff}
+			if got := cfg.ConnectTimeout(); got != tt.want {
+				t.Errorf("ConnectTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaColumns_ParsesSchemaFile(t *testing.T) {
+	content, err := os.ReadFile("schema.sql")
+	if err != nil {
+		t.Fatalf("read schema: %v", err)
+	}
+	tables := schemaColumns(string(content))
+
+	byName := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		byName[table.name] = table.columns
+	}
+	for _, name := range []string{"users", "categories", "category_shares", "todos", "todo_events", "todo_comments", "api_tokens"} {
+		if _, ok := byName[name]; !ok {
+			t.Errorf("schemaColumns() is missing table %s", name)
+		}
+	}
+
+	// Generated columns count, while keys, indexes and comments do not
+	want := []string{"id", "name", "owner_id", "allow_duplicate_titles", "default_share_permission", "deleted_at", "live", "created_at", "updated_at"}
+	if !slices.Equal(byName["categories"], want) {
+		t.Errorf("categories columns = %v, want %v", byName["categories"], want)
+	}
+}
+
+func TestSchemaDrift(t *testing.T) {
+	expected := []schemaTable{
+		{name: "users", columns: []string{"id", "timezone"}},
+		{name: "todos", columns: []string{"id", "remind_at"}},
+	}
+
+	t.Run("empty database reports every table", func(t *testing.T) {
+		got := schemaDrift(expected, map[string]map[string]bool{})
+		want := []string{"table users", "table todos"}
+		if !slices.Equal(got, want) {
+			t.Errorf("schemaDrift() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		got := schemaDrift(expected, map[string]map[string]bool{
+			"users": {"id": true, "timezone": true},
+			"todos": {"id": true},
+		})
+		want := []string{"column todos.remind_at"}
+		if !slices.Equal(got, want) {
+			t.Errorf("schemaDrift() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("matching schema", func(t *testing.T) {
+		got := schemaDrift(expected, map[string]map[string]bool{
+			"users": {"id": true, "timezone": true, "extra": true},
+			"todos": {"id": true, "remind_at": true},
+		})
+		if len(got) != 0 {
+			t.Errorf("schemaDrift() = %v, want no drift", got)
+		}
+	})
+}
@@ -11,6 +11,48 @@ import (
 	"time"
 )
 
+type CategoriesDefaultSharePermission string
+
+const (
+	CategoriesDefaultSharePermissionRead  CategoriesDefaultSharePermission = "read"
+	CategoriesDefaultSharePermissionWrite CategoriesDefaultSharePermission = "write"
+)
+
+func (e *CategoriesDefaultSharePermission) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CategoriesDefaultSharePermission(s)
+	case string:
+		*e = CategoriesDefaultSharePermission(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CategoriesDefaultSharePermission: %T", src)
+	}
+	return nil
+}
+
+type NullCategoriesDefaultSharePermission struct {
+	CategoriesDefaultSharePermission CategoriesDefaultSharePermission `json:"categories_default_share_permission"`
+	Valid                            bool                             `json:"valid"` // Valid is true if CategoriesDefaultSharePermission is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCategoriesDefaultSharePermission) Scan(value interface{}) error {
+	if value == nil {
+		ns.CategoriesDefaultSharePermission, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CategoriesDefaultSharePermission.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCategoriesDefaultSharePermission) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CategoriesDefaultSharePermission), nil
+}
+
 type CategorySharesPermission string
 
 const (
@@ -53,12 +95,70 @@ func (ns NullCategorySharesPermission) Value() (driver.Value, error) {
 	return string(ns.CategorySharesPermission), nil
 }
 
+type TodoEventsAction string
+
+const (
+	TodoEventsActionCreate  TodoEventsAction = "create"
+	TodoEventsActionUpdate  TodoEventsAction = "update"
+	TodoEventsActionDelete  TodoEventsAction = "delete"
+	TodoEventsActionRestore TodoEventsAction = "restore"
+)
+
+func (e *TodoEventsAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TodoEventsAction(s)
+	case string:
+		*e = TodoEventsAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TodoEventsAction: %T", src)
+	}
+	return nil
+}
+
+type NullTodoEventsAction struct {
+	TodoEventsAction TodoEventsAction `json:"todo_events_action"`
+	Valid            bool             `json:"valid"` // Valid is true if TodoEventsAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTodoEventsAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.TodoEventsAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TodoEventsAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTodoEventsAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TodoEventsAction), nil
+}
+
+type ApiToken struct {
+	ID        uint64       `db:"id" json:"id"`
+	UserID    uint64       `db:"user_id" json:"user_id"`
+	Name      string       `db:"name" json:"name"`
+	TokenHash string       `db:"token_hash" json:"token_hash"`
+	Scopes    string       `db:"scopes" json:"scopes"`
+	RevokedAt sql.NullTime `db:"revoked_at" json:"revoked_at"`
+	CreatedAt time.Time    `db:"created_at" json:"created_at"`
+}
+
 type Category struct {
-	ID        uint64    `db:"id" json:"id"`
-	Name      string    `db:"name" json:"name"`
-	OwnerID   uint64    `db:"owner_id" json:"owner_id"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID                     uint64                           `db:"id" json:"id"`
+	Name                   string                           `db:"name" json:"name"`
+	OwnerID                uint64                           `db:"owner_id" json:"owner_id"`
+	AllowDuplicateTitles   bool                             `db:"allow_duplicate_titles" json:"allow_duplicate_titles"`
+	DefaultSharePermission CategoriesDefaultSharePermission `db:"default_share_permission" json:"default_share_permission"`
+	DeletedAt              sql.NullTime                     `db:"deleted_at" json:"deleted_at"`
+	Live                   sql.NullInt16                    `db:"live" json:"live"`
+	CreatedAt              time.Time                        `db:"created_at" json:"created_at"`
+	UpdatedAt              time.Time                        `db:"updated_at" json:"updated_at"`
 }
 
 type CategoryShare struct {
@@ -70,16 +170,36 @@ type CategoryShare struct {
 }
 
 type Todo struct {
-	ID          uint64         `db:"id" json:"id"`
-	Title       string         `db:"title" json:"title"`
-	Description sql.NullString `db:"description" json:"description"`
-	CategoryID  uint64         `db:"category_id" json:"category_id"`
-	Completed   bool           `db:"completed" json:"completed"`
-	UserID      uint64         `db:"user_id" json:"user_id"`
-	CreatedBy   uint64         `db:"created_by" json:"created_by"`
-	DeletedAt   sql.NullTime   `db:"deleted_at" json:"deleted_at"`
-	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+	ID           uint64         `db:"id" json:"id"`
+	Title        string         `db:"title" json:"title"`
+	Description  sql.NullString `db:"description" json:"description"`
+	CategoryID   uint64         `db:"category_id" json:"category_id"`
+	Completed    bool           `db:"completed" json:"completed"`
+	CompletedAt  sql.NullTime   `db:"completed_at" json:"completed_at"`
+	RemindAt     sql.NullTime   `db:"remind_at" json:"remind_at"`
+	ReminderSent bool           `db:"reminder_sent" json:"reminder_sent"`
+	UserID       uint64         `db:"user_id" json:"user_id"`
+	CreatedBy    uint64         `db:"created_by" json:"created_by"`
+	DeletedAt    sql.NullTime   `db:"deleted_at" json:"deleted_at"`
+	CreatedAt    time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type TodoComment struct {
+	ID        uint64    `db:"id" json:"id"`
+	TodoID    uint64    `db:"todo_id" json:"todo_id"`
+	AuthorID  uint64    `db:"author_id" json:"author_id"`
+	Body      string    `db:"body" json:"body"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type TodoEvent struct {
+	ID            uint64           `db:"id" json:"id"`
+	TodoID        uint64           `db:"todo_id" json:"todo_id"`
+	ActorID       uint64           `db:"actor_id" json:"actor_id"`
+	Action        TodoEventsAction `db:"action" json:"action"`
+	ChangedFields string           `db:"changed_fields" json:"changed_fields"`
+	CreatedAt     time.Time        `db:"created_at" json:"created_at"`
 }
 
 type User struct {
@@ -87,6 +207,8 @@ type User struct {
 	Name      string    `db:"name" json:"name"`
 	Email     string    `db:"email" json:"email"`
 	Password  string    `db:"password" json:"password"`
+	Timezone  string    `db:"timezone" json:"timezone"`
+	IsAdmin   bool      `db:"is_admin" json:"is_admin"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
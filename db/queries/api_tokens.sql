@@ -0,0 +1,23 @@
+-- name: CreateAPIToken :execlastid
+INSERT INTO api_tokens (user_id, name, token_hash, scopes)
+VALUES (?, ?, ?, ?);
+
+-- name: GetAPITokenByID :one
+SELECT id, user_id, name, token_hash, scopes, revoked_at, created_at
+FROM api_tokens
+WHERE id = ?;
+
+-- name: GetActiveAPITokenByHash :one
+SELECT id, user_id, name, token_hash, scopes, revoked_at, created_at
+FROM api_tokens
+WHERE token_hash = ? AND revoked_at IS NULL;
+
+-- name: ListAPITokensByUser :many
+SELECT id, user_id, name, token_hash, scopes, revoked_at, created_at
+FROM api_tokens
+WHERE user_id = ?
+ORDER BY created_at DESC, id DESC;
+
+-- name: RevokeAPIToken :execrows
+UPDATE api_tokens SET revoked_at = NOW()
+WHERE id = ? AND user_id = ? AND revoked_at IS NULL;
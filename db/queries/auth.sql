@@ -1,8 +1,33 @@
 -- name: CreateUser :execlastid
-INSERT INTO users (name, email, password) VALUES (?, ?, ?);
+INSERT INTO users (name, email, password, is_admin) VALUES (?, ?, ?, ?);
 
 -- name: GetUserByEmail :one
-SELECT id, name, email, password, created_at, updated_at FROM users WHERE email = ?;
+SELECT id, name, email, password, timezone, is_admin, created_at, updated_at FROM users WHERE email = ?;
 
 -- name: GetUserByID :one
-SELECT id, name, email, password, created_at, updated_at FROM users WHERE id = ?;
+SELECT id, name, email, password, timezone, is_admin, created_at, updated_at FROM users WHERE id = ?;
+
+-- name: UpdateUserTimezone :exec
+UPDATE users SET timezone = ? WHERE id = ?;
+
+-- name: GetUsersByIDs :many
+SELECT id, name, email, password, timezone, is_admin, created_at, updated_at FROM users WHERE id IN (sqlc.slice(ids));
+
+-- name: ListUsers :many
+-- Oldest account first, so pages stay stable as new users register
+SELECT id, name, email, password, timezone, is_admin, created_at, updated_at
+FROM users
+ORDER BY created_at ASC, id ASC
+LIMIT ? OFFSET ?;
+
+-- name: CountUsers :one
+SELECT COUNT(*) as count FROM users;
+
+-- name: SetUserAdminByEmail :execrows
+-- Only a user who is not an admin yet is updated, so the affected count tells whether anything changed
+UPDATE users SET is_admin = TRUE WHERE email = ? AND is_admin = FALSE;
+
+-- name: NormalizeUserEmails :execrows
+-- Lowercases and trims emails stored before registration normalized them, so lookups by the normalized
+-- email can use the unique index. BINARY keeps the comparison case-sensitive under a case-insensitive collation.
+UPDATE users SET email = LOWER(TRIM(email)) WHERE BINARY email <> LOWER(TRIM(email));
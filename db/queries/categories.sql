@@ -1,30 +1,60 @@
 -- name: CreateCategory :execlastid
-INSERT INTO categories (name, owner_id) VALUES (?, ?);
+INSERT INTO categories (name, owner_id, default_share_permission) VALUES (?, ?, ?);
 
 -- name: GetCategoryByID :one
-SELECT id, name, owner_id, created_at, updated_at
+SELECT id, name, owner_id, allow_duplicate_titles, default_share_permission, created_at, updated_at
 FROM categories
-WHERE id = ?;
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: GetCategoriesByIDs :many
+SELECT id, name, owner_id, allow_duplicate_titles, default_share_permission, created_at, updated_at
+FROM categories
+WHERE id IN (sqlc.slice(ids)) AND deleted_at IS NULL;
 
 -- name: GetCategoriesByOwnerID :many
-SELECT id, name, owner_id, created_at, updated_at
+SELECT id, name, owner_id, allow_duplicate_titles, default_share_permission, created_at, updated_at
 FROM categories
-WHERE owner_id = ?
+WHERE owner_id = ? AND deleted_at IS NULL
 ORDER BY name ASC;
 
 -- name: GetCategoryByNameAndOwner :one
-SELECT id, name, owner_id, created_at, updated_at
+SELECT id, name, owner_id, allow_duplicate_titles, default_share_permission, created_at, updated_at
 FROM categories
-WHERE owner_id = ? AND name = ?;
+WHERE owner_id = ? AND name = ? AND deleted_at IS NULL;
 
 -- name: UpdateCategory :exec
-UPDATE categories SET name = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?;
+UPDATE categories SET name = ?, allow_duplicate_titles = ?, default_share_permission = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL;
+
+-- name: SoftDeleteCategory :exec
+-- Soft deletes the category and its todos in one statement so neither is left half deleted
+UPDATE categories c
+LEFT JOIN todos t ON t.category_id = c.id AND t.deleted_at IS NULL
+SET c.deleted_at = CURRENT_TIMESTAMP, t.deleted_at = CURRENT_TIMESTAMP
+WHERE c.id = ? AND c.deleted_at IS NULL;
+
+-- name: GetEmptyCategoriesByOwnerID :many
+-- Owned categories with no live todos and no shares, which are safe to remove
+SELECT c.id, c.name, c.owner_id, c.allow_duplicate_titles, c.default_share_permission, c.created_at, c.updated_at
+FROM categories c
+WHERE c.owner_id = ? AND c.deleted_at IS NULL
+  AND (SELECT COUNT(*) FROM todos t WHERE t.category_id = c.id AND t.deleted_at IS NULL) = 0
+  AND (SELECT COUNT(*) FROM category_shares cs WHERE cs.category_id = c.id) = 0
+ORDER BY c.name ASC;
+
+-- name: SoftDeleteEmptyCategory :execrows
+-- Rechecks emptiness in the same statement, so a todo or share added since the category was listed keeps it
+UPDATE categories c
+SET c.deleted_at = CURRENT_TIMESTAMP
+WHERE c.id = ? AND c.deleted_at IS NULL
+  AND (SELECT COUNT(*) FROM todos t WHERE t.category_id = c.id AND t.deleted_at IS NULL) = 0
+  AND (SELECT COUNT(*) FROM category_shares cs WHERE cs.category_id = c.id) = 0;
 
--- name: DeleteCategory :exec
-DELETE FROM categories WHERE id = ?;
+-- name: LockCategory :one
+-- Locks the category row until the transaction ends, so writers checking its todo limit take turns
+SELECT id FROM categories WHERE id = ? FOR UPDATE;
 
 -- name: CountCategoriesByOwnerID :one
-SELECT COUNT(*) as count FROM categories WHERE owner_id = ?;
+SELECT COUNT(*) as count FROM categories WHERE owner_id = ? AND deleted_at IS NULL;
 
 -- Category Shares queries
 
@@ -43,22 +73,57 @@ FROM category_shares
 WHERE category_id = ? AND shared_with_user_id = ?;
 
 -- name: GetSharesForCategory :many
+-- sort_by 'email' orders by recipient email, any other value orders newest first
 SELECT cs.id, cs.category_id, cs.shared_with_user_id, cs.permission, cs.created_at,
        u.name as shared_with_user_name, u.email as shared_with_user_email
 FROM category_shares cs
 JOIN users u ON cs.shared_with_user_id = u.id
-WHERE cs.category_id = ?
-ORDER BY cs.created_at DESC;
+WHERE cs.category_id = sqlc.arg(category_id)
+ORDER BY CASE WHEN CAST(sqlc.arg(sort_by) AS CHAR) = 'email' THEN u.email END ASC, cs.created_at DESC, cs.id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: GetSharesByOwner :many
+-- Every share of every category owned by owner_id; categories without shares produce no rows
+SELECT cs.id, cs.category_id, cs.shared_with_user_id, cs.permission, cs.created_at,
+       u.name as shared_with_user_name, u.email as shared_with_user_email, c.name as category_name
+FROM category_shares cs
+JOIN categories c ON cs.category_id = c.id
+JOIN users u ON cs.shared_with_user_id = u.id
+WHERE c.owner_id = ? AND c.deleted_at IS NULL
+ORDER BY c.name ASC, c.id ASC, u.email ASC;
+
+-- name: GetGrantedMembersByOwner :many
+-- Every user with a share on a category owned by owner_id, one row per share, grouped by user
+SELECT u.id as user_id, u.name as user_name, u.email as user_email,
+       c.id as category_id, c.name as category_name, cs.permission
+FROM category_shares cs
+JOIN categories c ON cs.category_id = c.id
+JOIN users u ON cs.shared_with_user_id = u.id
+WHERE c.owner_id = ? AND c.deleted_at IS NULL
+ORDER BY u.email ASC, u.id ASC, c.name ASC, c.id ASC;
+
+-- name: CountSharesForCategory :one
+SELECT COUNT(*) as count FROM category_shares WHERE category_id = ?;
+
+-- name: CountSharedCategoriesForUser :one
+-- permission is an optional filter, an empty value counts every share
+SELECT COUNT(*) as count FROM category_shares cs
+JOIN categories c ON cs.category_id = c.id
+WHERE cs.shared_with_user_id = sqlc.arg(shared_with_user_id) AND c.deleted_at IS NULL
+AND (CAST(sqlc.arg(permission) AS CHAR) = '' OR cs.permission = CAST(sqlc.arg(permission) AS CHAR));
 
 -- name: GetSharedCategoriesForUser :many
+-- permission is an optional filter, an empty value returns every share
 SELECT c.id, c.name, c.owner_id, c.created_at, c.updated_at,
        cs.permission,
        u.name as owner_name, u.email as owner_email
 FROM category_shares cs
 JOIN categories c ON cs.category_id = c.id
 JOIN users u ON c.owner_id = u.id
-WHERE cs.shared_with_user_id = ?
-ORDER BY c.name ASC;
+WHERE cs.shared_with_user_id = sqlc.arg(shared_with_user_id) AND c.deleted_at IS NULL
+AND (CAST(sqlc.arg(permission) AS CHAR) = '' OR cs.permission = CAST(sqlc.arg(permission) AS CHAR))
+ORDER BY c.name ASC, c.id ASC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: UpdateCategorySharePermission :exec
 UPDATE category_shares SET permission = ? WHERE id = ?;
@@ -69,6 +134,9 @@ DELETE FROM category_shares WHERE id = ?;
 -- name: DeleteCategoryShareByUserAndCategory :exec
 DELETE FROM category_shares WHERE category_id = ? AND shared_with_user_id = ?;
 
+-- name: DeleteAllSharesForCategory :execrows
+DELETE FROM category_shares WHERE category_id = ?;
+
 -- name: GetUserPermissionForCategory :one
 SELECT
     CASE
@@ -77,11 +145,12 @@ SELECT
     END as permission
 FROM categories c
 LEFT JOIN category_shares cs ON c.id = cs.category_id AND cs.shared_with_user_id = ?
-WHERE c.id = ?;
+WHERE c.id = ? AND c.deleted_at IS NULL;
 
 -- name: GetTodosGroupedByCategory :many
 -- Returns all accessible categories with their todos for a user
 -- Categories are accessible if user owns them OR they are shared with user
+-- scope 'owned' or 'shared' limits the result to one of the two, any other value returns both
 SELECT
     c.id as category_id,
     c.name as category_name,
@@ -89,7 +158,7 @@ SELECT
     owner.name as category_owner_name,
     COALESCE(cs.permission, '') as share_permission,
     CASE
-        WHEN c.owner_id = ? THEN 'owner'
+        WHEN c.owner_id = sqlc.arg(user_id) THEN 'owner'
         ELSE cs.permission
     END as user_permission,
     COALESCE(t.id, 0) as todo_id,
@@ -101,11 +170,24 @@ SELECT
     t.created_at as todo_created_at,
     t.updated_at as todo_updated_at
 FROM categories c
-LEFT JOIN category_shares cs ON c.id = cs.category_id AND cs.shared_with_user_id = ?
+LEFT JOIN category_shares cs ON c.id = cs.category_id AND cs.shared_with_user_id = sqlc.arg(user_id)
 LEFT JOIN todos t ON c.id = t.category_id AND t.deleted_at IS NULL
 LEFT JOIN users owner ON c.owner_id = owner.id
 LEFT JOIN users creator ON t.created_by = creator.id
-WHERE
-    c.owner_id = ?
-    OR cs.shared_with_user_id = ?
+WHERE c.deleted_at IS NULL AND (
+    (c.owner_id = sqlc.arg(user_id) AND CAST(sqlc.arg(scope) AS CHAR) <> 'shared')
+    OR (cs.shared_with_user_id = sqlc.arg(user_id) AND CAST(sqlc.arg(scope) AS CHAR) <> 'owned')
+)
 ORDER BY c.name ASC, t.created_at DESC;
+
+-- name: GetWritableCategoriesForUser :many
+-- Returns the categories a user can add todos to: owned ones plus those shared with write permission
+SELECT c.id, c.name, 'owner' as permission
+FROM categories c
+WHERE c.owner_id = sqlc.arg(user_id) AND c.deleted_at IS NULL
+UNION ALL
+SELECT c.id, c.name, 'write' as permission
+FROM category_shares cs
+JOIN categories c ON cs.category_id = c.id
+WHERE cs.shared_with_user_id = sqlc.arg(user_id) AND cs.permission = 'write' AND c.deleted_at IS NULL
+ORDER BY name ASC, id ASC;
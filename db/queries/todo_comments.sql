@@ -0,0 +1,22 @@
+-- name: CreateTodoComment :execlastid
+INSERT INTO todo_comments (todo_id, author_id, body)
+VALUES (?, ?, ?);
+
+-- name: GetTodoCommentByID :one
+SELECT id, todo_id, author_id, body, created_at
+FROM todo_comments
+WHERE id = ?;
+
+-- name: GetTodoCommentsByTodoID :many
+-- Oldest first, so a discussion reads top to bottom
+SELECT id, todo_id, author_id, body, created_at
+FROM todo_comments
+WHERE todo_id = ?
+ORDER BY created_at ASC, id ASC
+LIMIT ? OFFSET ?;
+
+-- name: CountTodoCommentsByTodoID :one
+SELECT COUNT(*) as count FROM todo_comments WHERE todo_id = ?;
+
+-- name: DeleteTodoComment :execrows
+DELETE FROM todo_comments WHERE id = ?;
@@ -0,0 +1,13 @@
+-- name: CreateTodoEvent :exec
+INSERT INTO todo_events (todo_id, actor_id, action, changed_fields)
+VALUES (?, ?, ?, ?);
+
+-- name: GetTodoEventsByTodoID :many
+SELECT id, todo_id, actor_id, action, changed_fields, created_at
+FROM todo_events
+WHERE todo_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?;
+
+-- name: CountTodoEventsByTodoID :one
+SELECT COUNT(*) as count FROM todo_events WHERE todo_id = ?;
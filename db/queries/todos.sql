@@ -1,44 +1,160 @@
 -- name: CreateTodo :execlastid
-INSERT INTO todos (title, description, category_id, completed, user_id, created_by)
-VALUES (?, ?, ?, ?, ?, ?);
+INSERT INTO todos (title, description, category_id, completed, remind_at, user_id, created_by)
+VALUES (?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetTodoByID :one
-SELECT id, title, description, category_id, completed, user_id, created_by, deleted_at, created_at, updated_at
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
 FROM todos
 WHERE id = ? AND deleted_at IS NULL;
 
+-- name: GetTodoByIDIncludingDeleted :one
+-- Like GetTodoByID, but also finds a soft-deleted todo
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE id = ?;
+
+-- name: GetTodosByIDs :many
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE id IN (sqlc.slice(ids)) AND deleted_at IS NULL;
+
 -- name: CountTodosByUserID :one
 SELECT COUNT(*) as count FROM todos WHERE user_id = ? AND deleted_at IS NULL;
 
+-- name: CountTodosByUserIDAndStatus :one
+-- completed is an optional filter, a NULL value counts every todo
+SELECT COUNT(*) as count FROM todos
+WHERE user_id = sqlc.arg(user_id) AND deleted_at IS NULL
+AND (sqlc.narg(completed) IS NULL OR completed = sqlc.narg(completed));
+
 -- name: GetTodosByUserIDWithPagination :many
-SELECT id, title, description, category_id, completed, user_id, created_by, deleted_at, created_at, updated_at
+-- sort_by is a "field:direction" ordering; any unknown value orders newest first
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
 FROM todos
-WHERE user_id = ? AND deleted_at IS NULL
-ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+WHERE user_id = sqlc.arg(user_id) AND deleted_at IS NULL
+ORDER BY
+    CASE WHEN CAST(sqlc.arg(sort_by) AS CHAR) = 'title:asc' THEN title END ASC,
+    CASE WHEN CAST(sqlc.arg(sort_by) AS CHAR) = 'title:desc' THEN title END DESC,
+    CASE WHEN CAST(sqlc.arg(sort_by) AS CHAR) = 'updated_at:asc' THEN updated_at END ASC,
+    CASE WHEN CAST(sqlc.arg(sort_by) AS CHAR) = 'updated_at:desc' THEN updated_at END DESC,
+    CASE WHEN CAST(sqlc.arg(sort_by) AS CHAR) = 'created_at:asc' THEN created_at END ASC,
+    created_at DESC, id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: UpdateTodo :exec
+-- completed_at is stamped the first time a todo is completed and cleared when it is reopened
+-- (MySQL evaluates SET assignments left to right, so completed already holds the new value)
 UPDATE todos
-SET title = ?, description = ?, category_id = ?, completed = ?, updated_at = CURRENT_TIMESTAMP
+SET title = ?, description = ?, category_id = ?, completed = ?, remind_at = ?, reminder_sent = ?,
+    completed_at = CASE WHEN completed THEN COALESCE(completed_at, CURRENT_TIMESTAMP) ELSE NULL END,
+    updated_at = CURRENT_TIMESTAMP
 WHERE id = ? AND deleted_at IS NULL;
 
+-- name: TouchTodo :exec
+-- Bumps updated_at without changing anything else, e.g. to move a todo up a recently-updated list
+UPDATE todos SET updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL;
+
 -- name: SoftDeleteTodo :exec
 UPDATE todos SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?;
 
+-- name: RestoreTodo :execrows
+-- Clears a todo's soft delete unless its category has been deleted since
+UPDATE todos t
+JOIN categories c ON c.id = t.category_id
+SET t.deleted_at = NULL
+WHERE t.id = ? AND t.deleted_at IS NOT NULL AND c.deleted_at IS NULL;
+
+-- name: GetTodosInAccessibleCategories :many
+-- Loads the todos of several categories at once; categories the user neither owns nor is shared on contribute nothing
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+LEFT JOIN category_shares cs ON cs.category_id = c.id AND cs.shared_with_user_id = sqlc.arg(user_id)
+WHERE t.category_id IN (sqlc.slice(category_ids)) AND (c.owner_id = sqlc.arg(user_id) OR cs.id IS NOT NULL)
+AND t.deleted_at IS NULL AND c.deleted_at IS NULL
+ORDER BY t.category_id ASC, t.created_at DESC, t.id DESC;
+
+-- name: GetTodosInCategoriesWithPagination :many
+-- Lists the todos of a set of categories newest first; categories the user neither owns nor is shared on contribute nothing
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+LEFT JOIN category_shares cs ON cs.category_id = c.id AND cs.shared_with_user_id = sqlc.arg(user_id)
+WHERE t.category_id IN (sqlc.slice(category_ids)) AND (c.owner_id = sqlc.arg(user_id) OR cs.id IS NOT NULL)
+AND t.deleted_at IS NULL AND c.deleted_at IS NULL
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountTodosInCategories :one
+-- Counts what GetTodosInCategoriesWithPagination pages through
+SELECT COUNT(*) as count
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+LEFT JOIN category_shares cs ON cs.category_id = c.id AND cs.shared_with_user_id = sqlc.arg(user_id)
+WHERE t.category_id IN (sqlc.slice(category_ids)) AND (c.owner_id = sqlc.arg(user_id) OR cs.id IS NOT NULL)
+AND t.deleted_at IS NULL AND c.deleted_at IS NULL;
+
+-- name: GetDeletedTodos :many
+-- Trashed todos whose category is still live, locked until the transaction ends
+-- A category_id of 0 selects the user's own todos in every category, otherwise every todo in that category
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+WHERE t.deleted_at IS NOT NULL AND c.deleted_at IS NULL
+AND (t.category_id = sqlc.arg(category_id) OR (sqlc.arg(category_id) = 0 AND t.user_id = sqlc.arg(user_id)))
+ORDER BY t.id ASC
+FOR UPDATE;
+
+-- name: RestoreTodosByIDs :execrows
+-- Batch form of RestoreTodo
+UPDATE todos t
+JOIN categories c ON c.id = t.category_id
+SET t.deleted_at = NULL
+WHERE t.id IN (sqlc.slice(ids)) AND t.deleted_at IS NOT NULL AND c.deleted_at IS NULL;
+
 -- name: GetTodosByCategoryID :many
-SELECT id, title, description, category_id, completed, user_id, created_by, deleted_at, created_at, updated_at
+-- created_by and not_created_by are optional creator filters, a NULL value disables the filter
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
 FROM todos
-WHERE category_id = ? AND deleted_at IS NULL
+WHERE category_id = sqlc.arg(category_id) AND deleted_at IS NULL
+AND (sqlc.narg(created_by) IS NULL OR created_by = sqlc.narg(created_by))
+AND (sqlc.narg(not_created_by) IS NULL OR created_by <> sqlc.narg(not_created_by))
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: GetTodosByCategoryIDAfter :many
+-- Keyset page of a category's todos, newest first. after_created_at and after_id identify the last todo of
+-- the previous page; a NULL after_created_at starts from the newest todo
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE category_id = sqlc.arg(category_id) AND deleted_at IS NULL
+AND (sqlc.narg(created_by) IS NULL OR created_by = sqlc.narg(created_by))
+AND (sqlc.narg(not_created_by) IS NULL OR created_by <> sqlc.narg(not_created_by))
+AND (sqlc.narg(after_created_at) IS NULL OR created_at < sqlc.narg(after_created_at)
+    OR (created_at = sqlc.narg(after_created_at) AND id < sqlc.arg(after_id)))
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
 
 -- name: CountTodosByCategoryID :one
-SELECT COUNT(*) as count FROM todos WHERE category_id = ? AND deleted_at IS NULL;
+SELECT COUNT(*) as count FROM todos
+WHERE category_id = sqlc.arg(category_id) AND deleted_at IS NULL
+AND (sqlc.narg(created_by) IS NULL OR created_by = sqlc.narg(created_by))
+AND (sqlc.narg(not_created_by) IS NULL OR created_by <> sqlc.narg(not_created_by));
+
+-- name: CountTodosByStatusInCategory :one
+-- Totals and completed count in one pass, for category headers that only show counts
+SELECT COUNT(*) as total, COUNT(CASE WHEN completed THEN 1 END) as completed
+FROM todos
+WHERE category_id = ? AND deleted_at IS NULL;
+
+-- name: CountTodosByCategoryAndTitle :one
+-- Title comparison follows the column collation, which is case-insensitive
+SELECT COUNT(*) as count FROM todos WHERE category_id = ? AND title = ? AND deleted_at IS NULL;
 
 -- name: GetAccessibleTodosWithPagination :many
 -- Gets todos from categories owned by user OR shared with user
 -- Parameters: user_id, user_id, user_id, limit, offset
-SELECT DISTINCT t.id, t.title, t.description, t.category_id, t.completed, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+SELECT DISTINCT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
 FROM todos t
 INNER JOIN categories c ON t.category_id = c.id
 LEFT JOIN category_shares cs ON c.id = cs.category_id AND cs.shared_with_user_id = ?
@@ -56,3 +172,120 @@ INNER JOIN categories c ON t.category_id = c.id
 LEFT JOIN category_shares cs ON c.id = cs.category_id AND cs.shared_with_user_id = ?
 WHERE t.deleted_at IS NULL
 AND (c.owner_id = ? OR cs.shared_with_user_id = ?);
+
+-- name: GetTodoIDsInCategory :many
+-- Live todos in a category, locked until the transaction ends
+-- completed is an optional filter, a NULL value selects every todo
+SELECT id FROM todos
+WHERE category_id = sqlc.arg(category_id) AND deleted_at IS NULL
+AND (sqlc.narg(completed) IS NULL OR completed = sqlc.narg(completed))
+ORDER BY id ASC
+FOR UPDATE;
+
+-- name: MoveTodosByIDs :execrows
+-- Moves a chosen set of live todos to another category and owner
+UPDATE todos
+SET category_id = sqlc.arg(target_category_id), user_id = sqlc.arg(target_owner_id), updated_at = CURRENT_TIMESTAMP
+WHERE id IN (sqlc.slice(ids)) AND deleted_at IS NULL;
+
+-- name: SetTodosCompletedByIDs :execrows
+-- Only todos whose state changes are touched, so the affected count is the number of todos changed
+UPDATE todos
+SET completed = sqlc.arg(completed),
+    completed_at = CASE WHEN completed THEN COALESCE(completed_at, CURRENT_TIMESTAMP) ELSE NULL END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (sqlc.slice(ids)) AND completed <> sqlc.arg(completed) AND deleted_at IS NULL;
+
+-- name: CountCompletedTodosBefore :one
+SELECT COUNT(*) as count FROM todos
+WHERE user_id = ? AND completed = TRUE AND completed_at < ? AND deleted_at IS NULL;
+
+-- name: ListCompletionTimes :many
+-- Days are bucketed by the caller in the user's timezone, so only the timestamps are returned
+SELECT completed_at FROM todos
+WHERE user_id = sqlc.arg(user_id) AND completed = TRUE AND deleted_at IS NULL
+AND completed_at >= sqlc.arg(completed_from) AND completed_at < sqlc.arg(completed_to)
+ORDER BY completed_at ASC;
+
+-- name: GetCompletedTodoIDsBefore :many
+-- A user's live todos completed before the cutoff, locked until the transaction ends
+SELECT id FROM todos
+WHERE user_id = ? AND completed = TRUE AND completed_at < ? AND deleted_at IS NULL
+ORDER BY id ASC
+FOR UPDATE;
+
+-- name: SoftDeleteTodosByIDs :execrows
+-- Batch form of SoftDeleteTodo that skips todos already deleted
+UPDATE todos SET deleted_at = CURRENT_TIMESTAMP
+WHERE id IN (sqlc.slice(ids)) AND deleted_at IS NULL;
+
+-- name: GetDueReminders :many
+-- Completed todos are skipped, since there is nothing left to be reminded of
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE remind_at <= ? AND reminder_sent = FALSE AND completed = FALSE AND deleted_at IS NULL
+ORDER BY remind_at ASC
+LIMIT ?;
+
+-- name: GetRecentTodos :many
+-- The user's most recently updated todos across owned and shared categories (an owner cannot share with
+-- themselves, so the two halves never overlap)
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+WHERE c.owner_id = sqlc.arg(user_id) AND t.deleted_at IS NULL AND c.deleted_at IS NULL
+UNION ALL
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN category_shares cs ON cs.category_id = t.category_id
+JOIN categories c ON c.id = t.category_id
+WHERE cs.shared_with_user_id = sqlc.arg(user_id) AND t.deleted_at IS NULL AND c.deleted_at IS NULL
+ORDER BY updated_at DESC, id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: GetTodoChangesSince :many
+-- IDs of the user's todos across owned and shared categories changed at or after since, soft-deleted ones
+-- included; updated_at follows every write, deletes too. Todos of the user's own deleted categories are kept
+-- as tombstones, while a deleted category's shares are removed with it.
+SELECT t.id, t.updated_at, t.deleted_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+WHERE c.owner_id = sqlc.arg(user_id) AND t.updated_at >= sqlc.arg(since)
+UNION ALL
+SELECT t.id, t.updated_at, t.deleted_at
+FROM todos t
+JOIN category_shares cs ON cs.category_id = t.category_id
+WHERE cs.shared_with_user_id = sqlc.arg(user_id) AND t.updated_at >= sqlc.arg(since)
+ORDER BY updated_at ASC, id ASC;
+
+-- name: GetTodosChangedSince :many
+-- The user's todos across owned and shared categories changed after since, soft-deleted ones included, oldest
+-- change first. Changes made in the current second are left for the next call, so a cursor taken from the last
+-- updated_at never splits a second and a later change in it is never skipped.
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+WHERE c.owner_id = sqlc.arg(user_id) AND t.updated_at > sqlc.arg(since) AND t.updated_at < CURRENT_TIMESTAMP
+UNION ALL
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN category_shares cs ON cs.category_id = t.category_id
+WHERE cs.shared_with_user_id = sqlc.arg(user_id) AND t.updated_at > sqlc.arg(since) AND t.updated_at < CURRENT_TIMESTAMP
+ORDER BY updated_at ASC, id ASC;
+
+-- name: MarkReminderSent :execrows
+-- Only an unsent reminder that is still due is updated, so exactly one caller sees an affected row for each reminder
+-- and a reminder moved later since it was fetched is left for its new time. Dispatching is not an edit, so updated_at
+-- is pinned to keep the todo out of the recent and sync feeds
+UPDATE todos SET reminder_sent = TRUE, updated_at = updated_at WHERE id = ? AND reminder_sent = FALSE AND remind_at <= ?;
+
+-- name: ReleaseReminder :exec
+-- Undoes MarkReminderSent after a failed delivery so the next dispatch retries it, leaving updated_at alone like it
+UPDATE todos SET reminder_sent = FALSE, updated_at = updated_at WHERE id = ? AND reminder_sent = TRUE;
+
+-- name: PurgeDeletedTodosBefore :execrows
+-- Hard deletes up to limit todos soft-deleted before the cutoff; their history goes with them via ON DELETE CASCADE
+DELETE FROM todos
+WHERE deleted_at IS NOT NULL AND deleted_at < ?
+ORDER BY deleted_at ASC
+LIMIT ?;
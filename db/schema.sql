@@ -1,3 +1,6 @@
+DROP TABLE IF EXISTS api_tokens;
+DROP TABLE IF EXISTS todo_comments;
+DROP TABLE IF EXISTS todo_events;
 DROP TABLE IF EXISTS todos;
 DROP TABLE IF EXISTS category_shares;
 DROP TABLE IF EXISTS categories;
@@ -8,6 +11,8 @@ CREATE TABLE users (
   name VARCHAR(255) NOT NULL,
   email VARCHAR(255) NOT NULL UNIQUE,
   password VARCHAR(255) NOT NULL,
+  timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+  is_admin BOOLEAN NOT NULL DEFAULT FALSE,
   created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
   updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 );
@@ -16,11 +21,16 @@ CREATE TABLE categories (
   id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
   name VARCHAR(255) NOT NULL,
   owner_id BIGINT UNSIGNED NOT NULL,
+  allow_duplicate_titles BOOLEAN NOT NULL DEFAULT TRUE,
+  default_share_permission ENUM('read', 'write') NOT NULL DEFAULT 'read',
+  deleted_at DATETIME NULL DEFAULT NULL,
+  -- 1 while the category is live and NULL once deleted, so the unique key only covers live names
+  live TINYINT AS (IF(deleted_at IS NULL, 1, NULL)) STORED,
   created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
   updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
   FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE,
   INDEX idx_categories_owner_id (owner_id),
-  UNIQUE KEY unique_user_category (owner_id, name)
+  UNIQUE KEY unique_user_category (owner_id, name, live)
 );
 
 CREATE TABLE category_shares (
@@ -41,6 +51,9 @@ CREATE TABLE todos (
   description TEXT,
   category_id BIGINT UNSIGNED NOT NULL,
   completed BOOLEAN NOT NULL DEFAULT FALSE,
+  completed_at DATETIME NULL DEFAULT NULL,
+  remind_at DATETIME NULL DEFAULT NULL,
+  reminder_sent BOOLEAN NOT NULL DEFAULT FALSE,
   user_id BIGINT UNSIGNED NOT NULL,
   created_by BIGINT UNSIGNED NOT NULL,
   deleted_at DATETIME NULL DEFAULT NULL,
@@ -51,5 +64,42 @@ CREATE TABLE todos (
   FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE,
   INDEX idx_todos_user_id (user_id),
   INDEX idx_todos_category_id (category_id),
-  INDEX idx_todos_deleted_at (deleted_at)
+  INDEX idx_todos_deleted_at (deleted_at),
+  INDEX idx_todos_reminders (reminder_sent, remind_at)
+);
+
+CREATE TABLE todo_events (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  todo_id BIGINT UNSIGNED NOT NULL,
+  actor_id BIGINT UNSIGNED NOT NULL,
+  action ENUM('create', 'update', 'delete', 'restore') NOT NULL,
+  changed_fields VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE,
+  INDEX idx_todo_events_todo_id (todo_id, created_at)
+);
+
+CREATE TABLE todo_comments (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  todo_id BIGINT UNSIGNED NOT NULL,
+  author_id BIGINT UNSIGNED NOT NULL,
+  body TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (todo_id) REFERENCES todos(id) ON DELETE CASCADE,
+  FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+  INDEX idx_todo_comments_todo_id (todo_id, created_at)
+);
+
+CREATE TABLE api_tokens (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  user_id BIGINT UNSIGNED NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  scopes VARCHAR(255) NOT NULL DEFAULT '',
+  revoked_at DATETIME NULL DEFAULT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  UNIQUE KEY unique_api_token_hash (token_hash),
+  INDEX idx_api_tokens_user_id (user_id)
 );
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"time"
+
+	"todo-app/pkg/utils"
+)
+
+// slowQueryDB wraps a DBTX and logs any statement that takes longer than threshold
+type slowQueryDB struct {
+	inner     DBTX
+	threshold time.Duration
+}
+
+// withSlowQueryLog wraps inner so slow statements are logged; a threshold of 0 returns inner unchanged
+func withSlowQueryLog(inner DBTX, threshold time.Duration) DBTX {
+	if threshold <= 0 {
+		return inner
+	}
+	return &slowQueryDB{inner: inner, threshold: threshold}
+}
+
+func (d *slowQueryDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer d.observe(ctx, query, time.Now())
+	return d.inner.ExecContext(ctx, query, args...)
+}
+
+func (d *slowQueryDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	defer d.observe(ctx, query, time.Now())
+	return d.inner.PrepareContext(ctx, query)
+}
+
+// QueryContext times the statement until its first rows are available, not the iteration over them
+func (d *slowQueryDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer d.observe(ctx, query, time.Now())
+	return d.inner.QueryContext(ctx, query, args...)
+}
+
+func (d *slowQueryDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer d.observe(ctx, query, time.Now())
+	return d.inner.QueryRowContext(ctx, query, args...)
+}
+
+// observe logs a warning when the statement started at start ran past the threshold
+func (d *slowQueryDB) observe(ctx context.Context, query string, start time.Time) {
+	elapsed := time.Since(start)
+	if elapsed < d.threshold {
+		return
+	}
+	log.Printf("[WARN] slow query name=%s duration=%v request=%s", queryName(query), elapsed, utils.GetRequestID(ctx))
+}
+
+// queryName extracts the name from the "-- name: GetTodo :one" header sqlc puts on each query
+func queryName(query string) string {
+	const prefix = "-- name: "
+	if !strings.HasPrefix(query, prefix) {
+		return "unnamed"
+	}
+	header := strings.TrimPrefix(query, prefix)
+	if end := strings.IndexAny(header, " \n"); end >= 0 {
+		header = header[:end]
+	}
+	return header
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-app/pkg/utils"
+)
+
+// sleepingDB is a DBTX whose statements take delay to run
+type sleepingDB struct {
+	delay time.Duration
+}
+
+func (d sleepingDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	time.Sleep(d.delay)
+	return nil, nil
+}
+
+func (d sleepingDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	time.Sleep(d.delay)
+	return nil, nil
+}
+
+func (d sleepingDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	time.Sleep(d.delay)
+	return nil, nil
+}
+
+func (d sleepingDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	time.Sleep(d.delay)
+	return nil
+}
+
+// captureLog redirects the standard logger into a buffer for the rest of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	writer, flags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(writer)
+		log.SetFlags(flags)
+	})
+	return &buf
+}
+
+func TestSlowQueryLog_WarnsOnSlowQuery(t *testing.T) {
+	buf := captureLog(t)
+	dbtx := withSlowQueryLog(sleepingDB{delay: 20 * time.Millisecond}, 10*time.Millisecond)
+	ctx := context.WithValue(context.Background(), utils.RequestIDKey, "req-123")
+
+	dbtx.ExecContext(ctx, "-- name: DeleteTodo :exec\nDELETE FROM todos WHERE id = ?", 1)
+
+	out := buf.String()
+	for _, want := range []string{"[WARN] slow query", "name=DeleteTodo", "request=req-123"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log = %q, want it to contain %q", out, want)
+		}
+	}
+}
+
+func TestSlowQueryLog_IgnoresFastQuery(t *testing.T) {
+	buf := captureLog(t)
+	dbtx := withSlowQueryLog(sleepingDB{}, time.Second)
+
+	dbtx.QueryRowContext(context.Background(), "-- name: GetTodo :one\nSELECT 1")
+
+	if buf.Len() != 0 {
+		t.Errorf("log = %q, want nothing logged", buf.String())
+	}
+}
+
+func TestSlowQueryLog_DisabledReturnsInner(t *testing.T) {
+	inner := sleepingDB{}
+	if got := withSlowQueryLog(inner, 0); got != DBTX(inner) {
+		t.Errorf("withSlowQueryLog() with 0 threshold = %T, want the inner DBTX", got)
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"-- name: GetTodo :one\nSELECT 1", "GetTodo"},
+		{"-- name: ListTodos :many\nSELECT 1", "ListTodos"},
+		{"SELECT 1", "unnamed"},
+	}
+
+	for _, tt := range tests {
+		if got := queryName(tt.query); got != tt.want {
+			t.Errorf("queryName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
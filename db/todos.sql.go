@@ -8,6 +8,8 @@ package db
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"time"
 )
 
 const countAccessibleTodos = `-- name: CountAccessibleTodos :one
@@ -34,17 +36,85 @@ func (q *Queries) CountAccessibleTodos(ctx context.Context, arg CountAccessibleT
 	return count, err
 }
 
+const countCompletedTodosBefore = `-- name: CountCompletedTodosBefore :one
+SELECT COUNT(*) as count FROM todos
+WHERE user_id = ? AND completed = TRUE AND completed_at < ? AND deleted_at IS NULL
+`
+
+type CountCompletedTodosBeforeParams struct {
+	UserID      uint64       `db:"user_id" json:"user_id"`
+	CompletedAt sql.NullTime `db:"completed_at" json:"completed_at"`
+}
+
+func (q *Queries) CountCompletedTodosBefore(ctx context.Context, arg CountCompletedTodosBeforeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countCompletedTodosBefore, arg.UserID, arg.CompletedAt)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTodosByCategoryAndTitle = `-- name: CountTodosByCategoryAndTitle :one
+SELECT COUNT(*) as count FROM todos WHERE category_id = ? AND title = ? AND deleted_at IS NULL
+`
+
+type CountTodosByCategoryAndTitleParams struct {
+	CategoryID uint64 `db:"category_id" json:"category_id"`
+	Title      string `db:"title" json:"title"`
+}
+
+// Title comparison follows the column collation, which is case-insensitive
+func (q *Queries) CountTodosByCategoryAndTitle(ctx context.Context, arg CountTodosByCategoryAndTitleParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTodosByCategoryAndTitle, arg.CategoryID, arg.Title)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countTodosByCategoryID = `-- name: CountTodosByCategoryID :one
-SELECT COUNT(*) as count FROM todos WHERE category_id = ? AND deleted_at IS NULL
+SELECT COUNT(*) as count FROM todos
+WHERE category_id = ? AND deleted_at IS NULL
+AND (? IS NULL OR created_by = ?)
+AND (? IS NULL OR created_by <> ?)
 `
 
-func (q *Queries) CountTodosByCategoryID(ctx context.Context, categoryID uint64) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countTodosByCategoryID, categoryID)
+type CountTodosByCategoryIDParams struct {
+	CategoryID   uint64        `db:"category_id" json:"category_id"`
+	CreatedBy    sql.NullInt64 `db:"created_by" json:"created_by"`
+	NotCreatedBy sql.NullInt64 `db:"not_created_by" json:"not_created_by"`
+}
+
+func (q *Queries) CountTodosByCategoryID(ctx context.Context, arg CountTodosByCategoryIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTodosByCategoryID,
+		arg.CategoryID,
+		arg.CreatedBy,
+		arg.CreatedBy,
+		arg.NotCreatedBy,
+		arg.NotCreatedBy,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const countTodosByStatusInCategory = `-- name: CountTodosByStatusInCategory :one
+SELECT COUNT(*) as total, COUNT(CASE WHEN completed THEN 1 END) as completed
+FROM todos
+WHERE category_id = ? AND deleted_at IS NULL
+`
+
+type CountTodosByStatusInCategoryRow struct {
+	Total     int64 `db:"total" json:"total"`
+	Completed int64 `db:"completed" json:"completed"`
+}
+
+// Totals and completed count in one pass, for category headers that only show counts
+func (q *Queries) CountTodosByStatusInCategory(ctx context.Context, categoryID uint64) (CountTodosByStatusInCategoryRow, error) {
+	row := q.db.QueryRowContext(ctx, countTodosByStatusInCategory, categoryID)
+	var i CountTodosByStatusInCategoryRow
+	err := row.Scan(&i.Total, &i.Completed)
+	return i, err
+}
+
 const countTodosByUserID = `-- name: CountTodosByUserID :one
 SELECT COUNT(*) as count FROM todos WHERE user_id = ? AND deleted_at IS NULL
 `
@@ -56,9 +126,62 @@ func (q *Queries) CountTodosByUserID(ctx context.Context, userID uint64) (int64,
 	return count, err
 }
 
+const countTodosByUserIDAndStatus = `-- name: CountTodosByUserIDAndStatus :one
+SELECT COUNT(*) as count FROM todos
+WHERE user_id = ? AND deleted_at IS NULL
+AND (? IS NULL OR completed = ?)
+`
+
+type CountTodosByUserIDAndStatusParams struct {
+	UserID    uint64       `db:"user_id" json:"user_id"`
+	Completed sql.NullBool `db:"completed" json:"completed"`
+}
+
+// completed is an optional filter, a NULL value counts every todo
+func (q *Queries) CountTodosByUserIDAndStatus(ctx context.Context, arg CountTodosByUserIDAndStatusParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTodosByUserIDAndStatus, arg.UserID, arg.Completed, arg.Completed)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTodosInCategories = `-- name: CountTodosInCategories :one
+SELECT COUNT(*) as count
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+LEFT JOIN category_shares cs ON cs.category_id = c.id AND cs.shared_with_user_id = ?
+WHERE t.category_id IN (/*SLICE:category_ids*/?) AND (c.owner_id = ? OR cs.id IS NOT NULL)
+AND t.deleted_at IS NULL AND c.deleted_at IS NULL
+`
+
+type CountTodosInCategoriesParams struct {
+	UserID      uint64   `db:"user_id" json:"user_id"`
+	CategoryIds []uint64 `db:"category_ids" json:"category_ids"`
+}
+
+// Counts what GetTodosInCategoriesWithPagination pages through
+func (q *Queries) CountTodosInCategories(ctx context.Context, arg CountTodosInCategoriesParams) (int64, error) {
+	query := countTodosInCategories
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.UserID)
+	if len(arg.CategoryIds) > 0 {
+		for _, v := range arg.CategoryIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:category_ids*/?", strings.Repeat(",?", len(arg.CategoryIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:category_ids*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.UserID)
+	row := q.db.QueryRowContext(ctx, query, queryParams...)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createTodo = `-- name: CreateTodo :execlastid
-INSERT INTO todos (title, description, category_id, completed, user_id, created_by)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO todos (title, description, category_id, completed, remind_at, user_id, created_by)
+VALUES (?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateTodoParams struct {
@@ -66,6 +189,7 @@ type CreateTodoParams struct {
 	Description sql.NullString `db:"description" json:"description"`
 	CategoryID  uint64         `db:"category_id" json:"category_id"`
 	Completed   bool           `db:"completed" json:"completed"`
+	RemindAt    sql.NullTime   `db:"remind_at" json:"remind_at"`
 	UserID      uint64         `db:"user_id" json:"user_id"`
 	CreatedBy   uint64         `db:"created_by" json:"created_by"`
 }
@@ -76,6 +200,7 @@ func (q *Queries) CreateTodo(ctx context.Context, arg CreateTodoParams) (int64,
 		arg.Description,
 		arg.CategoryID,
 		arg.Completed,
+		arg.RemindAt,
 		arg.UserID,
 		arg.CreatedBy,
 	)
@@ -86,7 +211,7 @@ func (q *Queries) CreateTodo(ctx context.Context, arg CreateTodoParams) (int64,
 }
 
 const getAccessibleTodosWithPagination = `-- name: GetAccessibleTodosWithPagination :many
-SELECT DISTINCT t.id, t.title, t.description, t.category_id, t.completed, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+SELECT DISTINCT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
 FROM todos t
 INNER JOIN categories c ON t.category_id = c.id
 LEFT JOIN category_shares cs ON c.id = cs.category_id AND cs.shared_with_user_id = ?
@@ -127,6 +252,9 @@ func (q *Queries) GetAccessibleTodosWithPagination(ctx context.Context, arg GetA
 			&i.Description,
 			&i.CategoryID,
 			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
 			&i.UserID,
 			&i.CreatedBy,
 			&i.DeletedAt,
@@ -146,46 +274,65 @@ func (q *Queries) GetAccessibleTodosWithPagination(ctx context.Context, arg GetA
 	return items, nil
 }
 
-const getTodoByID = `-- name: GetTodoByID :one
-SELECT id, title, description, category_id, completed, user_id, created_by, deleted_at, created_at, updated_at
-FROM todos
-WHERE id = ? AND deleted_at IS NULL
+const getCompletedTodoIDsBefore = `-- name: GetCompletedTodoIDsBefore :many
+SELECT id FROM todos
+WHERE user_id = ? AND completed = TRUE AND completed_at < ? AND deleted_at IS NULL
+ORDER BY id ASC
+FOR UPDATE
 `
 
-func (q *Queries) GetTodoByID(ctx context.Context, id uint64) (Todo, error) {
-	row := q.db.QueryRowContext(ctx, getTodoByID, id)
-	var i Todo
-	err := row.Scan(
-		&i.ID,
-		&i.Title,
-		&i.Description,
-		&i.CategoryID,
-		&i.Completed,
-		&i.UserID,
-		&i.CreatedBy,
-		&i.DeletedAt,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+type GetCompletedTodoIDsBeforeParams struct {
+	UserID      uint64       `db:"user_id" json:"user_id"`
+	CompletedAt sql.NullTime `db:"completed_at" json:"completed_at"`
 }
 
-const getTodosByCategoryID = `-- name: GetTodosByCategoryID :many
-SELECT id, title, description, category_id, completed, user_id, created_by, deleted_at, created_at, updated_at
-FROM todos
-WHERE category_id = ? AND deleted_at IS NULL
-ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+// A user's live todos completed before the cutoff, locked until the transaction ends
+func (q *Queries) GetCompletedTodoIDsBefore(ctx context.Context, arg GetCompletedTodoIDsBeforeParams) ([]uint64, error) {
+	rows, err := q.db.QueryContext(ctx, getCompletedTodoIDsBefore, arg.UserID, arg.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletedTodos = `-- name: GetDeletedTodos :many
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+WHERE t.deleted_at IS NOT NULL AND c.deleted_at IS NULL
+AND (t.category_id = ? OR (? = 0 AND t.user_id = ?))
+ORDER BY t.id ASC
+FOR UPDATE
 `
 
-type GetTodosByCategoryIDParams struct {
+type GetDeletedTodosParams struct {
 	CategoryID uint64 `db:"category_id" json:"category_id"`
-	Limit      int32  `db:"limit" json:"limit"`
-	Offset     int32  `db:"offset" json:"offset"`
+	UserID     uint64 `db:"user_id" json:"user_id"`
 }
 
-func (q *Queries) GetTodosByCategoryID(ctx context.Context, arg GetTodosByCategoryIDParams) ([]Todo, error) {
-	rows, err := q.db.QueryContext(ctx, getTodosByCategoryID, arg.CategoryID, arg.Limit, arg.Offset)
+// Trashed todos whose category is still live, locked until the transaction ends
+// A category_id of 0 selects the user's own todos in every category, otherwise every todo in that category
+func (q *Queries) GetDeletedTodos(ctx context.Context, arg GetDeletedTodosParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, getDeletedTodos,
+		arg.CategoryID,
+		arg.CategoryID,
+		arg.UserID,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -199,6 +346,9 @@ func (q *Queries) GetTodosByCategoryID(ctx context.Context, arg GetTodosByCatego
 			&i.Description,
 			&i.CategoryID,
 			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
 			&i.UserID,
 			&i.CreatedBy,
 			&i.DeletedAt,
@@ -218,22 +368,81 @@ func (q *Queries) GetTodosByCategoryID(ctx context.Context, arg GetTodosByCatego
 	return items, nil
 }
 
-const getTodosByUserIDWithPagination = `-- name: GetTodosByUserIDWithPagination :many
-SELECT id, title, description, category_id, completed, user_id, created_by, deleted_at, created_at, updated_at
+const getDueReminders = `-- name: GetDueReminders :many
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
 FROM todos
-WHERE user_id = ? AND deleted_at IS NULL
-ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+WHERE remind_at <= ? AND reminder_sent = FALSE AND completed = FALSE AND deleted_at IS NULL
+ORDER BY remind_at ASC
+LIMIT ?
 `
 
-type GetTodosByUserIDWithPaginationParams struct {
+type GetDueRemindersParams struct {
+	RemindAt sql.NullTime `db:"remind_at" json:"remind_at"`
+	Limit    int32        `db:"limit" json:"limit"`
+}
+
+// Completed todos are skipped, since there is nothing left to be reminded of
+func (q *Queries) GetDueReminders(ctx context.Context, arg GetDueRemindersParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, getDueReminders, arg.RemindAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CategoryID,
+			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
+			&i.UserID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentTodos = `-- name: GetRecentTodos :many
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+WHERE c.owner_id = ? AND t.deleted_at IS NULL AND c.deleted_at IS NULL
+UNION ALL
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN category_shares cs ON cs.category_id = t.category_id
+JOIN categories c ON c.id = t.category_id
+WHERE cs.shared_with_user_id = ? AND t.deleted_at IS NULL AND c.deleted_at IS NULL
+ORDER BY updated_at DESC, id DESC
+LIMIT ?
+`
+
+type GetRecentTodosParams struct {
 	UserID uint64 `db:"user_id" json:"user_id"`
 	Limit  int32  `db:"limit" json:"limit"`
-	Offset int32  `db:"offset" json:"offset"`
 }
 
-func (q *Queries) GetTodosByUserIDWithPagination(ctx context.Context, arg GetTodosByUserIDWithPaginationParams) ([]Todo, error) {
-	rows, err := q.db.QueryContext(ctx, getTodosByUserIDWithPagination, arg.UserID, arg.Limit, arg.Offset)
+// The user's most recently updated todos across owned and shared categories (an owner cannot share with
+// themselves, so the two halves never overlap)
+func (q *Queries) GetRecentTodos(ctx context.Context, arg GetRecentTodosParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentTodos, arg.UserID, arg.UserID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -247,6 +456,9 @@ func (q *Queries) GetTodosByUserIDWithPagination(ctx context.Context, arg GetTod
 			&i.Description,
 			&i.CategoryID,
 			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
 			&i.UserID,
 			&i.CreatedBy,
 			&i.DeletedAt,
@@ -266,35 +478,876 @@ func (q *Queries) GetTodosByUserIDWithPagination(ctx context.Context, arg GetTod
 	return items, nil
 }
 
-const softDeleteTodo = `-- name: SoftDeleteTodo :exec
-UPDATE todos SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?
+const getTodoByID = `-- name: GetTodoByID :one
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE id = ? AND deleted_at IS NULL
 `
 
-func (q *Queries) SoftDeleteTodo(ctx context.Context, id uint64) error {
-	_, err := q.db.ExecContext(ctx, softDeleteTodo, id)
-	return err
+func (q *Queries) GetTodoByID(ctx context.Context, id uint64) (Todo, error) {
+	row := q.db.QueryRowContext(ctx, getTodoByID, id)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CategoryID,
+		&i.Completed,
+		&i.CompletedAt,
+		&i.RemindAt,
+		&i.ReminderSent,
+		&i.UserID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const updateTodo = `-- name: UpdateTodo :exec
-UPDATE todos
-SET title = ?, description = ?, category_id = ?, completed = ?, updated_at = CURRENT_TIMESTAMP
-WHERE id = ? AND deleted_at IS NULL
+const getTodoByIDIncludingDeleted = `-- name: GetTodoByIDIncludingDeleted :one
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE id = ?
 `
 
-type UpdateTodoParams struct {
-	Title       string         `db:"title" json:"title"`
-	Description sql.NullString `db:"description" json:"description"`
-	CategoryID  uint64         `db:"category_id" json:"category_id"`
-	Completed   bool           `db:"completed" json:"completed"`
-	ID          uint64         `db:"id" json:"id"`
+// Like GetTodoByID, but also finds a soft-deleted todo
+func (q *Queries) GetTodoByIDIncludingDeleted(ctx context.Context, id uint64) (Todo, error) {
+	row := q.db.QueryRowContext(ctx, getTodoByIDIncludingDeleted, id)
+	var i Todo
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.CategoryID,
+		&i.Completed,
+		&i.CompletedAt,
+		&i.RemindAt,
+		&i.ReminderSent,
+		&i.UserID,
+		&i.CreatedBy,
+		&i.DeletedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTodoChangesSince = `-- name: GetTodoChangesSince :many
+SELECT t.id, t.updated_at, t.deleted_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+WHERE c.owner_id = ? AND t.updated_at >= ?
+UNION ALL
+SELECT t.id, t.updated_at, t.deleted_at
+FROM todos t
+JOIN category_shares cs ON cs.category_id = t.category_id
+WHERE cs.shared_with_user_id = ? AND t.updated_at >= ?
+ORDER BY updated_at ASC, id ASC
+`
+
+type GetTodoChangesSinceParams struct {
+	UserID uint64    `db:"user_id" json:"user_id"`
+	Since  time.Time `db:"since" json:"since"`
+}
+
+type GetTodoChangesSinceRow struct {
+	ID        uint64       `db:"id" json:"id"`
+	UpdatedAt time.Time    `db:"updated_at" json:"updated_at"`
+	DeletedAt sql.NullTime `db:"deleted_at" json:"deleted_at"`
+}
+
+// IDs of the user's todos across owned and shared categories changed at or after since, soft-deleted ones
+// included; updated_at follows every write, deletes too. Todos of the user's own deleted categories are kept
+// as tombstones, while a deleted category's shares are removed with it.
+func (q *Queries) GetTodoChangesSince(ctx context.Context, arg GetTodoChangesSinceParams) ([]GetTodoChangesSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTodoChangesSince,
+		arg.UserID,
+		arg.Since,
+		arg.UserID,
+		arg.Since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTodoChangesSinceRow
+	for rows.Next() {
+		var i GetTodoChangesSinceRow
+		if err := rows.Scan(&i.ID, &i.UpdatedAt, &i.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodoIDsInCategory = `-- name: GetTodoIDsInCategory :many
+SELECT id FROM todos
+WHERE category_id = ? AND deleted_at IS NULL
+AND (? IS NULL OR completed = ?)
+ORDER BY id ASC
+FOR UPDATE
+`
+
+type GetTodoIDsInCategoryParams struct {
+	CategoryID uint64       `db:"category_id" json:"category_id"`
+	Completed  sql.NullBool `db:"completed" json:"completed"`
+}
+
+// Live todos in a category, locked until the transaction ends
+// completed is an optional filter, a NULL value selects every todo
+func (q *Queries) GetTodoIDsInCategory(ctx context.Context, arg GetTodoIDsInCategoryParams) ([]uint64, error) {
+	rows, err := q.db.QueryContext(ctx, getTodoIDsInCategory, arg.CategoryID, arg.Completed, arg.Completed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodosByCategoryID = `-- name: GetTodosByCategoryID :many
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE category_id = ? AND deleted_at IS NULL
+AND (? IS NULL OR created_by = ?)
+AND (? IS NULL OR created_by <> ?)
+ORDER BY created_at DESC
+LIMIT ? OFFSET ?
+`
+
+type GetTodosByCategoryIDParams struct {
+	CategoryID   uint64        `db:"category_id" json:"category_id"`
+	CreatedBy    sql.NullInt64 `db:"created_by" json:"created_by"`
+	NotCreatedBy sql.NullInt64 `db:"not_created_by" json:"not_created_by"`
+	Limit        int32         `db:"limit" json:"limit"`
+	Offset       int32         `db:"offset" json:"offset"`
+}
+
+// created_by and not_created_by are optional creator filters, a NULL value disables the filter
+func (q *Queries) GetTodosByCategoryID(ctx context.Context, arg GetTodosByCategoryIDParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, getTodosByCategoryID,
+		arg.CategoryID,
+		arg.CreatedBy,
+		arg.CreatedBy,
+		arg.NotCreatedBy,
+		arg.NotCreatedBy,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CategoryID,
+			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
+			&i.UserID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodosByCategoryIDAfter = `-- name: GetTodosByCategoryIDAfter :many
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE category_id = ? AND deleted_at IS NULL
+AND (? IS NULL OR created_by = ?)
+AND (? IS NULL OR created_by <> ?)
+AND (? IS NULL OR created_at < ?
+    OR (created_at = ? AND id < ?))
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+type GetTodosByCategoryIDAfterParams struct {
+	CategoryID     uint64        `db:"category_id" json:"category_id"`
+	CreatedBy      sql.NullInt64 `db:"created_by" json:"created_by"`
+	NotCreatedBy   sql.NullInt64 `db:"not_created_by" json:"not_created_by"`
+	AfterCreatedAt sql.NullTime  `db:"after_created_at" json:"after_created_at"`
+	AfterID        uint64        `db:"after_id" json:"after_id"`
+	Limit          int32         `db:"limit" json:"limit"`
+}
+
+// Keyset page of a category's todos, newest first. after_created_at and after_id identify the last todo of
+// the previous page; a NULL after_created_at starts from the newest todo
+func (q *Queries) GetTodosByCategoryIDAfter(ctx context.Context, arg GetTodosByCategoryIDAfterParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, getTodosByCategoryIDAfter,
+		arg.CategoryID,
+		arg.CreatedBy,
+		arg.CreatedBy,
+		arg.NotCreatedBy,
+		arg.NotCreatedBy,
+		arg.AfterCreatedAt,
+		arg.AfterCreatedAt,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CategoryID,
+			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
+			&i.UserID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodosByIDs = `-- name: GetTodosByIDs :many
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+func (q *Queries) GetTodosByIDs(ctx context.Context, ids []uint64) ([]Todo, error) {
+	query := getTodosByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CategoryID,
+			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
+			&i.UserID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodosByUserIDWithPagination = `-- name: GetTodosByUserIDWithPagination :many
+SELECT id, title, description, category_id, completed, completed_at, remind_at, reminder_sent, user_id, created_by, deleted_at, created_at, updated_at
+FROM todos
+WHERE user_id = ? AND deleted_at IS NULL
+ORDER BY
+    CASE WHEN CAST(? AS CHAR) = 'title:asc' THEN title END ASC,
+    CASE WHEN CAST(? AS CHAR) = 'title:desc' THEN title END DESC,
+    CASE WHEN CAST(? AS CHAR) = 'updated_at:asc' THEN updated_at END ASC,
+    CASE WHEN CAST(? AS CHAR) = 'updated_at:desc' THEN updated_at END DESC,
+    CASE WHEN CAST(? AS CHAR) = 'created_at:asc' THEN created_at END ASC,
+    created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+type GetTodosByUserIDWithPaginationParams struct {
+	UserID uint64 `db:"user_id" json:"user_id"`
+	SortBy string `db:"sort_by" json:"sort_by"`
+	Limit  int32  `db:"limit" json:"limit"`
+	Offset int32  `db:"offset" json:"offset"`
+}
+
+// sort_by is a "field:direction" ordering; any unknown value orders newest first
+func (q *Queries) GetTodosByUserIDWithPagination(ctx context.Context, arg GetTodosByUserIDWithPaginationParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, getTodosByUserIDWithPagination,
+		arg.UserID,
+		arg.SortBy,
+		arg.SortBy,
+		arg.SortBy,
+		arg.SortBy,
+		arg.SortBy,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CategoryID,
+			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
+			&i.UserID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodosChangedSince = `-- name: GetTodosChangedSince :many
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+WHERE c.owner_id = ? AND t.updated_at > ? AND t.updated_at < CURRENT_TIMESTAMP
+UNION ALL
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN category_shares cs ON cs.category_id = t.category_id
+WHERE cs.shared_with_user_id = ? AND t.updated_at > ? AND t.updated_at < CURRENT_TIMESTAMP
+ORDER BY updated_at ASC, id ASC
+`
+
+type GetTodosChangedSinceParams struct {
+	UserID uint64    `db:"user_id" json:"user_id"`
+	Since  time.Time `db:"since" json:"since"`
+}
+
+// The user's todos across owned and shared categories changed after since, soft-deleted ones included, oldest
+// change first. Changes made in the current second are left for the next call, so a cursor taken from the last
+// updated_at never splits a second and a later change in it is never skipped.
+func (q *Queries) GetTodosChangedSince(ctx context.Context, arg GetTodosChangedSinceParams) ([]Todo, error) {
+	rows, err := q.db.QueryContext(ctx, getTodosChangedSince,
+		arg.UserID,
+		arg.Since,
+		arg.UserID,
+		arg.Since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CategoryID,
+			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
+			&i.UserID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodosInAccessibleCategories = `-- name: GetTodosInAccessibleCategories :many
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+LEFT JOIN category_shares cs ON cs.category_id = c.id AND cs.shared_with_user_id = ?
+WHERE t.category_id IN (/*SLICE:category_ids*/?) AND (c.owner_id = ? OR cs.id IS NOT NULL)
+AND t.deleted_at IS NULL AND c.deleted_at IS NULL
+ORDER BY t.category_id ASC, t.created_at DESC, t.id DESC
+`
+
+type GetTodosInAccessibleCategoriesParams struct {
+	UserID      uint64   `db:"user_id" json:"user_id"`
+	CategoryIds []uint64 `db:"category_ids" json:"category_ids"`
+}
+
+// Loads the todos of several categories at once; categories the user neither owns nor is shared on contribute nothing
+func (q *Queries) GetTodosInAccessibleCategories(ctx context.Context, arg GetTodosInAccessibleCategoriesParams) ([]Todo, error) {
+	query := getTodosInAccessibleCategories
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.UserID)
+	if len(arg.CategoryIds) > 0 {
+		for _, v := range arg.CategoryIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:category_ids*/?", strings.Repeat(",?", len(arg.CategoryIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:category_ids*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.UserID)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CategoryID,
+			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
+			&i.UserID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTodosInCategoriesWithPagination = `-- name: GetTodosInCategoriesWithPagination :many
+SELECT t.id, t.title, t.description, t.category_id, t.completed, t.completed_at, t.remind_at, t.reminder_sent, t.user_id, t.created_by, t.deleted_at, t.created_at, t.updated_at
+FROM todos t
+JOIN categories c ON c.id = t.category_id
+LEFT JOIN category_shares cs ON cs.category_id = c.id AND cs.shared_with_user_id = ?
+WHERE t.category_id IN (/*SLICE:category_ids*/?) AND (c.owner_id = ? OR cs.id IS NOT NULL)
+AND t.deleted_at IS NULL AND c.deleted_at IS NULL
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT ? OFFSET ?
+`
+
+type GetTodosInCategoriesWithPaginationParams struct {
+	UserID      uint64   `db:"user_id" json:"user_id"`
+	CategoryIds []uint64 `db:"category_ids" json:"category_ids"`
+	Limit       int32    `db:"limit" json:"limit"`
+	Offset      int32    `db:"offset" json:"offset"`
+}
+
+// Lists the todos of a set of categories newest first; categories the user neither owns nor is shared on contribute nothing
+func (q *Queries) GetTodosInCategoriesWithPagination(ctx context.Context, arg GetTodosInCategoriesWithPaginationParams) ([]Todo, error) {
+	query := getTodosInCategoriesWithPagination
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.UserID)
+	if len(arg.CategoryIds) > 0 {
+		for _, v := range arg.CategoryIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:category_ids*/?", strings.Repeat(",?", len(arg.CategoryIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:category_ids*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.UserID)
+	queryParams = append(queryParams, arg.Limit)
+	queryParams = append(queryParams, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Todo
+	for rows.Next() {
+		var i Todo
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CategoryID,
+			&i.Completed,
+			&i.CompletedAt,
+			&i.RemindAt,
+			&i.ReminderSent,
+			&i.UserID,
+			&i.CreatedBy,
+			&i.DeletedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCompletionTimes = `-- name: ListCompletionTimes :many
+SELECT completed_at FROM todos
+WHERE user_id = ? AND completed = TRUE AND deleted_at IS NULL
+AND completed_at >= ? AND completed_at < ?
+ORDER BY completed_at ASC
+`
+
+type ListCompletionTimesParams struct {
+	UserID        uint64       `db:"user_id" json:"user_id"`
+	CompletedFrom sql.NullTime `db:"completed_from" json:"completed_from"`
+	CompletedTo   sql.NullTime `db:"completed_to" json:"completed_to"`
+}
+
+// Days are bucketed by the caller in the user's timezone, so only the timestamps are returned
+func (q *Queries) ListCompletionTimes(ctx context.Context, arg ListCompletionTimesParams) ([]sql.NullTime, error) {
+	rows, err := q.db.QueryContext(ctx, listCompletionTimes, arg.UserID, arg.CompletedFrom, arg.CompletedTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullTime
+	for rows.Next() {
+		var completed_at sql.NullTime
+		if err := rows.Scan(&completed_at); err != nil {
+			return nil, err
+		}
+		items = append(items, completed_at)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markReminderSent = `-- name: MarkReminderSent :execrows
+UPDATE todos SET reminder_sent = TRUE, updated_at = updated_at WHERE id = ? AND reminder_sent = FALSE AND remind_at <= ?
+`
+
+type MarkReminderSentParams struct {
+	ID       uint64       `db:"id" json:"id"`
+	RemindAt sql.NullTime `db:"remind_at" json:"remind_at"`
+}
+
+// Only an unsent reminder that is still due is updated, so exactly one caller sees an affected row for each reminder
+// and a reminder moved later since it was fetched is left for its new time. Dispatching is not an edit, so updated_at
+// is pinned to keep the todo out of the recent and sync feeds
+func (q *Queries) MarkReminderSent(ctx context.Context, arg MarkReminderSentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markReminderSent, arg.ID, arg.RemindAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const moveTodosByIDs = `-- name: MoveTodosByIDs :execrows
+UPDATE todos
+SET category_id = ?, user_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+type MoveTodosByIDsParams struct {
+	TargetCategoryID uint64   `db:"target_category_id" json:"target_category_id"`
+	TargetOwnerID    uint64   `db:"target_owner_id" json:"target_owner_id"`
+	Ids              []uint64 `db:"ids" json:"ids"`
+}
+
+// Moves a chosen set of live todos to another category and owner
+func (q *Queries) MoveTodosByIDs(ctx context.Context, arg MoveTodosByIDsParams) (int64, error) {
+	query := moveTodosByIDs
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.TargetCategoryID)
+	queryParams = append(queryParams, arg.TargetOwnerID)
+	if len(arg.Ids) > 0 {
+		for _, v := range arg.Ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(arg.Ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeDeletedTodosBefore = `-- name: PurgeDeletedTodosBefore :execrows
+DELETE FROM todos
+WHERE deleted_at IS NOT NULL AND deleted_at < ?
+ORDER BY deleted_at ASC
+LIMIT ?
+`
+
+type PurgeDeletedTodosBeforeParams struct {
+	DeletedAt sql.NullTime `db:"deleted_at" json:"deleted_at"`
+	Limit     int32        `db:"limit" json:"limit"`
+}
+
+// Hard deletes up to limit todos soft-deleted before the cutoff; their history goes with them via ON DELETE CASCADE
+func (q *Queries) PurgeDeletedTodosBefore(ctx context.Context, arg PurgeDeletedTodosBeforeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeDeletedTodosBefore, arg.DeletedAt, arg.Limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const releaseReminder = `-- name: ReleaseReminder :exec
+UPDATE todos SET reminder_sent = FALSE, updated_at = updated_at WHERE id = ? AND reminder_sent = TRUE
+`
+
+// Undoes MarkReminderSent after a failed delivery so the next dispatch retries it, leaving updated_at alone like it
+func (q *Queries) ReleaseReminder(ctx context.Context, id uint64) error {
+	_, err := q.db.ExecContext(ctx, releaseReminder, id)
+	return err
+}
+
+const restoreTodo = `-- name: RestoreTodo :execrows
+UPDATE todos t
+JOIN categories c ON c.id = t.category_id
+SET t.deleted_at = NULL
+WHERE t.id = ? AND t.deleted_at IS NOT NULL AND c.deleted_at IS NULL
+`
+
+// Clears a todo's soft delete unless its category has been deleted since
+func (q *Queries) RestoreTodo(ctx context.Context, id uint64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreTodo, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreTodosByIDs = `-- name: RestoreTodosByIDs :execrows
+UPDATE todos t
+JOIN categories c ON c.id = t.category_id
+SET t.deleted_at = NULL
+WHERE t.id IN (/*SLICE:ids*/?) AND t.deleted_at IS NOT NULL AND c.deleted_at IS NULL
+`
+
+// Batch form of RestoreTodo
+func (q *Queries) RestoreTodosByIDs(ctx context.Context, ids []uint64) (int64, error) {
+	query := restoreTodosByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setTodosCompletedByIDs = `-- name: SetTodosCompletedByIDs :execrows
+UPDATE todos
+SET completed = ?,
+    completed_at = CASE WHEN completed THEN COALESCE(completed_at, CURRENT_TIMESTAMP) ELSE NULL END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (/*SLICE:ids*/?) AND completed <> ? AND deleted_at IS NULL
+`
+
+type SetTodosCompletedByIDsParams struct {
+	Completed bool     `db:"completed" json:"completed"`
+	Ids       []uint64 `db:"ids" json:"ids"`
+}
+
+// Only todos whose state changes are touched, so the affected count is the number of todos changed
+func (q *Queries) SetTodosCompletedByIDs(ctx context.Context, arg SetTodosCompletedByIDsParams) (int64, error) {
+	query := setTodosCompletedByIDs
+	var queryParams []interface{}
+	queryParams = append(queryParams, arg.Completed)
+	if len(arg.Ids) > 0 {
+		for _, v := range arg.Ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(arg.Ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	queryParams = append(queryParams, arg.Completed)
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const softDeleteTodo = `-- name: SoftDeleteTodo :exec
+UPDATE todos SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?
+`
+
+func (q *Queries) SoftDeleteTodo(ctx context.Context, id uint64) error {
+	_, err := q.db.ExecContext(ctx, softDeleteTodo, id)
+	return err
+}
+
+const softDeleteTodosByIDs = `-- name: SoftDeleteTodosByIDs :execrows
+UPDATE todos SET deleted_at = CURRENT_TIMESTAMP
+WHERE id IN (/*SLICE:ids*/?) AND deleted_at IS NULL
+`
+
+// Batch form of SoftDeleteTodo that skips todos already deleted
+func (q *Queries) SoftDeleteTodosByIDs(ctx context.Context, ids []uint64) (int64, error) {
+	query := softDeleteTodosByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	result, err := q.db.ExecContext(ctx, query, queryParams...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const touchTodo = `-- name: TouchTodo :exec
+UPDATE todos SET updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL
+`
+
+// Bumps updated_at without changing anything else, e.g. to move a todo up a recently-updated list
+func (q *Queries) TouchTodo(ctx context.Context, id uint64) error {
+	_, err := q.db.ExecContext(ctx, touchTodo, id)
+	return err
+}
+
+const updateTodo = `-- name: UpdateTodo :exec
+UPDATE todos
+SET title = ?, description = ?, category_id = ?, completed = ?, remind_at = ?, reminder_sent = ?,
+    completed_at = CASE WHEN completed THEN COALESCE(completed_at, CURRENT_TIMESTAMP) ELSE NULL END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type UpdateTodoParams struct {
+	Title        string         `db:"title" json:"title"`
+	Description  sql.NullString `db:"description" json:"description"`
+	CategoryID   uint64         `db:"category_id" json:"category_id"`
+	Completed    bool           `db:"completed" json:"completed"`
+	RemindAt     sql.NullTime   `db:"remind_at" json:"remind_at"`
+	ReminderSent bool           `db:"reminder_sent" json:"reminder_sent"`
+	ID           uint64         `db:"id" json:"id"`
 }
 
+// completed_at is stamped the first time a todo is completed and cleared when it is reopened
+// (MySQL evaluates SET assignments left to right, so completed already holds the new value)
 func (q *Queries) UpdateTodo(ctx context.Context, arg UpdateTodoParams) error {
 	_, err := q.db.ExecContext(ctx, updateTodo,
 		arg.Title,
 		arg.Description,
 		arg.CategoryID,
 		arg.Completed,
+		arg.RemindAt,
+		arg.ReminderSent,
 		arg.ID,
 	)
 	return err
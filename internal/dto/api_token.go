@@ -0,0 +1,16 @@
+package dto
+
+import "todo-app/internal/models"
+
+// CreateAPITokenRequest represents the data needed to create a personal access token
+type CreateAPITokenRequest struct {
+	UserID uint
+	Name   string
+	Scopes []string // Optional: limit the token to these scopes
+}
+
+// CreateAPITokenResponse carries the new token's metadata and its secret value, which is never shown again
+type CreateAPITokenResponse struct {
+	Token  *models.APIToken
+	Secret string
+}
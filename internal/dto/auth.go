@@ -19,4 +19,12 @@ type LoginRequest struct {
 type AuthResponse struct {
 	User  *models.User
 	Token string
+	// Existing is set when a registration matched an existing account and logged in to it instead
+	Existing bool
+}
+
+// UpdateProfileRequest represents a partial profile update; nil fields are left unchanged
+type UpdateProfileRequest struct {
+	UserID   uint
+	Timezone *string
 }
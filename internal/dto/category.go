@@ -4,23 +4,53 @@ import "todo-app/internal/models"
 
 // CreateCategoryRequest represents the data needed to create a category
 type CreateCategoryRequest struct {
-	Name    string
-	OwnerID uint
+	Name                   string
+	OwnerID                uint
+	DefaultSharePermission models.Permission // Optional; empty means read
 }
 
 // UpdateCategoryRequest represents the data needed to update a category
 type UpdateCategoryRequest struct {
-	ID      uint
-	UserID  uint // For ownership verification
-	Name    string
+	ID                     uint
+	UserID                 uint // For ownership verification
+	Name                   string
+	AllowDuplicateTitles   *bool              // Optional; nil leaves the setting unchanged
+	DefaultSharePermission *models.Permission // Optional; nil leaves the setting unchanged
 }
 
 // ShareCategoryRequest represents the data needed to share a category
 type ShareCategoryRequest struct {
-	CategoryID      uint
-	OwnerID         uint   // User sharing the category (must be owner)
-	ShareWithEmail  string // Email of user to share with
-	Permission      models.Permission
+	CategoryID     uint
+	OwnerID        uint   // User sharing the category (must be owner)
+	ShareWithEmail string // Email of user to share with
+	Permission     models.Permission
+}
+
+// SharePreviewRequest represents the data needed to preview sharing a category
+type SharePreviewRequest struct {
+	CategoryID     uint
+	OwnerID        uint   // User sharing the category (must be owner)
+	ShareWithEmail string // Email of user to share with
+}
+
+// SharePreviewUser identifies the user a share preview resolved to
+type SharePreviewUser struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// ShareCategoryResponse holds a new share and whether the recipient already owns a category with the same name
+type ShareCategoryResponse struct {
+	Share         *models.CategoryShare
+	NameCollision bool
+}
+
+// SharePreviewResponse reports what sharing with an email would do
+type SharePreviewResponse struct {
+	Found         bool              `json:"found"`
+	User          *SharePreviewUser `json:"user,omitempty"`
+	AlreadyShared bool              `json:"already_shared"`
+	Permission    models.Permission `json:"permission,omitempty"` // Existing share's permission when already shared
 }
 
 // UnshareCategoryRequest represents the data needed to unshare a category
@@ -38,8 +68,45 @@ type UpdateSharePermissionRequest struct {
 	Permission       models.Permission
 }
 
+// SharePermissionUpdate is one entry of a bulk share permission update
+type SharePermissionUpdate struct {
+	UserID     uint
+	Permission models.Permission
+}
+
+// BulkUpdateSharePermissionsRequest represents the data needed to update several shares of a category at once
+type BulkUpdateSharePermissionsRequest struct {
+	CategoryID uint
+	OwnerID    uint // User updating (must be owner)
+	Updates    []SharePermissionUpdate
+}
+
+// BulkUpdateSharePermissionsResponse lists, per user, which share permissions were changed
+type BulkUpdateSharePermissionsResponse struct {
+	Updated  []uint // Users whose share now has the requested permission
+	NotFound []uint // Users the category is not shared with
+}
+
 // CategoryListResponse represents a list of categories
 type CategoryListResponse struct {
-	OwnedCategories  []models.Category             `json:"owned_categories"`
+	OwnedCategories  []models.Category                `json:"owned_categories"`
 	SharedCategories []models.SharedCategoryWithOwner `json:"shared_categories"`
 }
+
+// SharedCategoryListResponse represents a paginated page of the categories shared with a user
+type SharedCategoryListResponse struct {
+	Categories []models.SharedCategoryWithOwner
+	Total      int64
+	Page       int
+	PageSize   int
+	TotalPages int64
+}
+
+// ShareListResponse represents a paginated page of a category's shares
+type ShareListResponse struct {
+	Shares     []models.CategoryShareWithUser
+	Total      int64
+	Page       int
+	PageSize   int
+	TotalPages int64
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "todo-app/internal/models"
+
+// CreateCommentRequest represents the data needed to comment on a todo
+type CreateCommentRequest struct {
+	TodoID uint
+	UserID uint // Author; must be able to read the todo
+	Body   string
+}
+
+// DeleteCommentRequest represents the data needed to delete a comment on a todo
+type DeleteCommentRequest struct {
+	TodoID    uint
+	CommentID uint
+	UserID    uint // For permission verification
+}
+
+// CommentListResponse represents a paginated page of a todo's comments
+type CommentListResponse struct {
+	Comments   []models.Comment
+	Total      int64
+	Page       int
+	PageSize   int
+	TotalPages int64
+}
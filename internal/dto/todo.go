@@ -1,14 +1,19 @@
 package dto
 
-import "todo-app/internal/models"
+import (
+	"time"
+
+	"todo-app/internal/models"
+)
 
 // CreateTodoRequest represents the data needed to create a todo
 type CreateTodoRequest struct {
 	Title       string
 	Description string
-	Category    string // Category name (used only when CategoryID is not set; will be created if doesn't exist)
-	CategoryID  *uint  // Optional: use this category when set (user must have write access)
-	UserID      uint   // User creating the todo
+	Category    string     // Category name (used only when CategoryID is not set; will be created if doesn't exist)
+	CategoryID  *uint      // Optional: use this category when set (user must have write access)
+	RemindAt    *time.Time // Optional: when to send a reminder for the todo
+	UserID      uint       // User creating the todo
 }
 
 // UpdateTodoRequest represents the data needed to update a todo
@@ -19,12 +24,22 @@ type UpdateTodoRequest struct {
 	Description *string
 	CategoryID  *uint
 	Completed   *bool
+	RemindAt    *time.Time // Setting a new time re-arms the reminder
+	Replace     bool       // PUT semantics: a nil Description, Completed or RemindAt resets the field to its default
+}
+
+// ListCategoryTodosRequest represents the data needed to list the todos of one category
+type ListCategoryTodosRequest struct {
+	CategoryID uint
+	UserID     uint   // For permission verification
+	CreatedBy  string // Optional creator filter: "me", "others" or a user ID
 }
 
 // GetTodoRequest represents the data needed to get a single todo
 type GetTodoRequest struct {
-	ID     uint
-	UserID uint // For permission verification
+	ID             uint
+	UserID         uint // For permission verification
+	IncludeDeleted bool // Also find a soft-deleted todo, for users with write access to its category
 }
 
 // DeleteTodoRequest represents the data needed to delete a todo
@@ -33,6 +48,70 @@ type DeleteTodoRequest struct {
 	UserID uint // For permission verification
 }
 
+// DeleteTodoResponse carries the token that undoes a deletion until UndoExpiresAt
+type DeleteTodoResponse struct {
+	UndoToken     string    `json:"undo_token"`
+	UndoExpiresAt time.Time `json:"undo_expires_at"`
+}
+
+// UndoDeleteRequest represents the data needed to restore a just-deleted todo
+type UndoDeleteRequest struct {
+	UserID    uint
+	UndoToken string
+}
+
+// TodoHistoryResponse represents a paginated page of a todo's history
+type TodoHistoryResponse struct {
+	Events     []models.TodoEvent
+	Total      int64
+	Page       int
+	PageSize   int
+	TotalPages int64
+}
+
+// TodoPermissions lists the actions a user may take on a todo
+type TodoPermissions struct {
+	CanRead   bool `json:"can_read"`
+	CanWrite  bool `json:"can_write"`
+	CanDelete bool `json:"can_delete"`
+}
+
+// BulkMoveTodosRequest represents the data needed to move a filtered set of todos between categories
+type BulkMoveTodosRequest struct {
+	UserID         uint
+	FromCategoryID uint
+	ToCategoryID   uint
+	Completed      *bool // Only todos in this completed state move; nil moves every todo
+}
+
+// CleanupTodosRequest represents the data needed to clean up old completed todos
+type CleanupTodosRequest struct {
+	UserID    uint
+	OlderThan time.Duration // Todos completed longer ago than this are removed
+	DryRun    bool          // When true, only count matching todos
+}
+
+// CompletionReportRequest represents the data needed to build a completion report
+type CompletionReportRequest struct {
+	UserID uint
+	From   time.Time // First day of the report; only the date is used, in the user's timezone
+	To     time.Time // Last day of the report, inclusive
+}
+
+// CompletionReport holds the number of todos completed on each day of a window, zero-filled
+type CompletionReport struct {
+	From  time.Time
+	To    time.Time
+	Total int64
+	Days  []DayCompletionCount
+}
+
+// DayCompletionCount is one day of a completion report
+type DayCompletionCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int64  `json:"count"`
+}
+
 // TodoListResponse represents paginated todo list response
 type TodoListResponse struct {
 	Todos      []models.Todo
@@ -42,16 +121,68 @@ type TodoListResponse struct {
 	TotalPages int64
 }
 
+// TodoCursorPage is one keyset page of todos, newest first; NextCursor is empty on the last page
+type TodoCursorPage struct {
+	Todos      []models.Todo
+	PageSize   int
+	NextCursor string
+}
+
+// TodoSyncResponse holds the todos changed since a sync cursor, grouped by kind of change
+// NextCursor is passed to the next sync and stays the same when nothing changed
+type TodoSyncResponse struct {
+	Created    []models.Todo
+	Updated    []models.Todo
+	Deleted    []uint
+	NextCursor string
+}
+
+// BatchGetTodosResponse holds the todos found for a batch of IDs, in the requested order,
+// along with the IDs that were skipped
+type BatchGetTodosResponse struct {
+	Todos     []models.Todo
+	NotFound  []uint // IDs with no non-deleted todo
+	Forbidden []uint // IDs of todos in categories the user cannot read
+}
+
+// TodoExpand selects the related objects inlined into todos (?expand=category,creator)
+type TodoExpand struct {
+	Category bool
+	Creator  bool
+}
+
+// CategoryBrief is the inlined summary of a todo's category
+type CategoryBrief struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserBrief is the inlined summary of the user who created a todo
+type UserBrief struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// ExpandedTodo is a todo with the related objects requested through TodoExpand
+// Fields that were not requested are omitted from the JSON
+type ExpandedTodo struct {
+	models.Todo
+	Category *CategoryBrief `json:"category,omitempty"`
+	Creator  *UserBrief     `json:"creator,omitempty"`
+}
+
 // TodoInCategory represents a todo item within a category
+// Timestamps serialize like models.Todo's (RFC 3339 with sub-second precision), and as null when missing
 type TodoInCategory struct {
-	ID          uint   `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Completed   bool   `json:"completed"`
-	CreatedBy   uint   `json:"created_by"`
-	CreatorName string `json:"creator_name"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Completed   bool       `json:"completed"`
+	CreatedBy   uint       `json:"created_by"`
+	CreatorName string     `json:"creator_name"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
 }
 
 // CategoryWithTodos represents a category and all its todos
@@ -62,6 +193,7 @@ type CategoryWithTodos struct {
 	OwnerName      string           `json:"owner_name"`
 	UserPermission string           `json:"user_permission"` // "owner", "read", or "write"
 	Todos          []TodoInCategory `json:"todos"`
+	NextCursor     string           `json:"next_cursor,omitempty"` // Set when todos were cut to a per-category limit
 }
 
 // TodosGroupedByCategoryResponse represents the full grouped response
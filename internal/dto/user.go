@@ -0,0 +1,12 @@
+package dto
+
+import "todo-app/internal/models"
+
+// UserListResponse represents a paginated page of all users, for admins
+type UserListResponse struct {
+	Users      []models.User
+	Total      int64
+	Page       int
+	PageSize   int
+	TotalPages int64
+}
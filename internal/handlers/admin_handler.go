@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"todo-app/internal/services"
+	"todo-app/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TodoPurger hard-deletes todos that were soft-deleted longer ago than the retention
+type TodoPurger interface {
+	PurgeExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// Purge returns a handler that runs a retention purge on demand and reports how many todos were removed
+func Purge(purger TodoPurger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		purged, err := purger.PurgeExpired(ctx, time.Now())
+		if err != nil {
+			if ctx.Err() != nil {
+				respondTimeout(c)
+				return
+			}
+			log.Printf("[purge] error=%v", err)
+			respondInternalError(c, "Failed to purge deleted todos", err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "Deleted todos purged successfully",
+			"data": gin.H{
+				"purged": purged,
+			},
+		})
+	}
+}
+
+// AdminHandler handles HTTP requests for the admin user tools
+type AdminHandler struct {
+	userService services.UserService
+	pagination  services.PaginationConfig
+}
+
+// NewAdminHandler creates a new AdminHandler with the provided service and pagination defaults
+func NewAdminHandler(svc services.UserService, pagination services.PaginationConfig) *AdminHandler {
+	return &AdminHandler{userService: svc, pagination: pagination}
+}
+
+// ListUsers handles listing all users, oldest account first, for an admin
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	// Parse pagination params (the service caps the page size)
+	page, pageSize, err := parsePagination(c, h.pagination)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.userService.ListUsers(ctx, page, pageSize)
+	if err != nil {
+		if ctx.Err() != nil {
+			respondTimeout(c)
+			return
+		}
+		rid := utils.GetRequestID(c.Request.Context())
+		log.Printf("[list users] request=%s error=%v", rid, err)
+		respondInternalError(c, "Failed to fetch users", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"message":           "Users retrieved successfully",
+		"data":              response.Users,
+		"count":             len(response.Users),
+		"total":             response.Total,
+		"page":              response.Page,
+		"page_size":         response.PageSize,
+		"page_size_clamped": pageSize > response.PageSize,
+		"total_pages":       response.TotalPages,
+	})
+}
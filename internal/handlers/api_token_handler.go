@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/services"
+	"todo-app/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APITokenHandler handles HTTP requests for personal access tokens
+type APITokenHandler struct {
+	tokenService services.APITokenService
+}
+
+// NewAPITokenHandler creates a new APITokenHandler with the provided service
+func NewAPITokenHandler(svc services.APITokenService) *APITokenHandler {
+	return &APITokenHandler{tokenService: svc}
+}
+
+// CreateAPITokenInput represents the create token request body
+type CreateAPITokenInput struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes"` // Optional: e.g. ["todos:read"]; omit for unrestricted access
+}
+
+// handleAPITokenError maps service errors to HTTP responses
+func (h *APITokenHandler) handleAPITokenError(c *gin.Context, ctx context.Context, err error, operation string, userID uint) bool {
+	if err == nil {
+		return false
+	}
+
+	// Check for timeout
+	if ctx.Err() != nil {
+		respondTimeout(c)
+		return true
+	}
+
+	// Handle specific business errors
+	if errors.Is(err, services.ErrAPITokenNameRequired) {
+		respondBadRequest(c, CodeTokenNameRequired, err.Error(), nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrInvalidAPITokenScope) {
+		respondBadRequest(c, CodeInvalidTokenScope, err.Error(), nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrAPITokenNotFound) {
+		respondNotFound(c, CodeTokenNotFound, "Token")
+		return true
+	}
+
+	// Log and return generic error
+	rid := utils.GetRequestID(c.Request.Context())
+	log.Printf("[%s] request=%s user=%v error=%v", operation, rid, userID, err)
+
+	respondInternalError(c, "Failed to "+operation, err)
+	return true
+}
+
+// CreateToken handles creating a personal access token HTTP request
+// The token value is only returned in this response
+func (h *APITokenHandler) CreateToken(c *gin.Context) {
+	var input CreateAPITokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.tokenService.CreateToken(ctx, dto.CreateAPITokenRequest{
+		UserID: userID,
+		Name:   input.Name,
+		Scopes: input.Scopes,
+	})
+
+	if h.handleAPITokenError(c, ctx, err, "create token", userID) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Token created successfully. Store it now, it will not be shown again",
+		"data": gin.H{
+			"token":     response.Secret,
+			"api_token": response.Token,
+		},
+	})
+}
+
+// ListTokens handles listing the user's personal access tokens HTTP request
+func (h *APITokenHandler) ListTokens(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	tokens, err := h.tokenService.ListTokens(ctx, userID)
+	if h.handleAPITokenError(c, ctx, err, "fetch tokens", userID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tokens retrieved successfully",
+		"data":    tokens,
+		"count":   len(tokens),
+	})
+}
+
+// RevokeToken handles revoking a personal access token HTTP request
+func (h *APITokenHandler) RevokeToken(c *gin.Context) {
+	tokenID, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid token ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	err = h.tokenService.RevokeToken(ctx, userID, tokenID)
+	if h.handleAPITokenError(c, ctx, err, "revoke token", userID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Token revoked successfully",
+	})
+}
@@ -17,11 +17,13 @@ import (
 // AuthHandler handles HTTP requests for authentication
 type AuthHandler struct {
 	authService services.AuthService
+	cookieMode  bool
 }
 
-// NewAuthHandler creates a new AuthHandler with the provided service
-func NewAuthHandler(svc services.AuthService) *AuthHandler {
-	return &AuthHandler{authService: svc}
+// NewAuthHandler creates a new AuthHandler with the provided service.
+// In cookie mode, register and login set the JWT as an HttpOnly cookie instead of returning it in the body.
+func NewAuthHandler(svc services.AuthService, cookieMode bool) *AuthHandler {
+	return &AuthHandler{authService: svc, cookieMode: cookieMode}
 }
 
 // RegisterInput represents the registration request body
@@ -37,6 +39,11 @@ type LoginInput struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// UpdateProfileInput represents the profile update request body; omitted fields are left unchanged
+type UpdateProfileInput struct {
+	Timezone *string `json:"timezone"`
+}
+
 // handleAuthError maps service errors to HTTP responses
 func (h *AuthHandler) handleAuthError(c *gin.Context, ctx context.Context, err error, operation string, email string) bool {
 	if err == nil {
@@ -51,12 +58,27 @@ func (h *AuthHandler) handleAuthError(c *gin.Context, ctx context.Context, err e
 
 	// Handle specific business errors
 	if errors.Is(err, services.ErrEmailAlreadyRegistered) {
-		respondConflict(c, err.Error())
+		respondConflict(c, CodeEmailAlreadyRegistered, err.Error())
+		return true
+	}
+
+	if errors.Is(err, services.ErrEmailDomainBlocked) {
+		respondBadRequest(c, CodeEmailDomainBlocked, err.Error(), nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrInvalidTimezone) {
+		respondBadRequest(c, CodeInvalidTimezone, err.Error(), nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrUserNotFound) {
+		respondNotFound(c, CodeUserNotFound, "User")
 		return true
 	}
 
 	if errors.Is(err, services.ErrInvalidCredentials) {
-		respondUnauthorizedWithMessage(c, err.Error())
+		respondUnauthorizedWithMessage(c, CodeInvalidCredentials, err.Error())
 		return true
 	}
 
@@ -72,7 +94,7 @@ func (h *AuthHandler) handleAuthError(c *gin.Context, ctx context.Context, err e
 func (h *AuthHandler) Register(c *gin.Context) {
 	var input RegisterInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		respondBadRequest(c, "Validation failed", err)
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
 		return
 	}
 
@@ -89,13 +111,20 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	// A replayed registration logged in to the account it created earlier, so nothing new was created
+	if response.Existing {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "User already registered, logged in",
+			"data":    h.authData(c, response),
+		})
+		return
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"success": true,
 		"message": "User registered successfully",
-		"data": gin.H{
-			"user":  response.User,
-			"token": response.Token,
-		},
+		"data":    h.authData(c, response),
 	})
 }
 
@@ -103,7 +132,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var input LoginInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		respondBadRequest(c, "Validation failed", err)
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
 		return
 	}
 
@@ -122,9 +151,78 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Login successful",
-		"data": gin.H{
+		"data":    h.authData(c, response),
+	})
+}
+
+// authData builds the register/login response data, moving the token into a cookie in cookie mode
+func (h *AuthHandler) authData(c *gin.Context, response *dto.AuthResponse) gin.H {
+	if !h.cookieMode {
+		return gin.H{
 			"user":  response.User,
 			"token": response.Token,
-		},
+		}
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     utils.AuthCookieName,
+		Value:    response.Token,
+		Path:     "/",
+		MaxAge:   int(utils.TokenTTL.Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return gin.H{"user": response.User}
+}
+
+// GetProfile returns the authenticated user, as loaded by middleware.LoadUser
+func (h *AuthHandler) GetProfile(c *gin.Context) {
+	user, ok := getUser(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Profile retrieved successfully",
+		"data":    user,
+	})
+}
+
+// UpdateProfile handles the profile update HTTP request for the authenticated user
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var input UpdateProfileInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+	if input.Timezone == nil {
+		respondBadRequest(c, CodeValidationFailed, "At least one field must be provided", nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	user, err := h.authService.UpdateProfile(ctx, dto.UpdateProfileRequest{
+		UserID:   userID,
+		Timezone: input.Timezone,
+	})
+	if h.handleAuthError(c, ctx, err, "update profile", "") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Profile updated successfully",
+		"data":    user,
 	})
 }
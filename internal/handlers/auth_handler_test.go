@@ -13,6 +13,7 @@ import (
 	"todo-app/internal/models"
 	"todo-app/internal/services"
 	"todo-app/internal/services/mocks"
+	"todo-app/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -114,7 +115,7 @@ func TestAuthHandler_Register(t *testing.T) {
 			mockService := &mocks.MockAuthService{
 				RegisterUserFunc: tt.mockFunc,
 			}
-			handler := NewAuthHandler(mockService)
+			handler := NewAuthHandler(mockService, false)
 
 			router := gin.New()
 			router.POST("/register", handler.Register)
@@ -219,7 +220,7 @@ func TestAuthHandler_Login(t *testing.T) {
 			mockService := &mocks.MockAuthService{
 				LoginUserFunc: tt.mockFunc,
 			}
-			handler := NewAuthHandler(mockService)
+			handler := NewAuthHandler(mockService, false)
 
 			router := gin.New()
 			router.POST("/login", handler.Login)
@@ -246,3 +247,52 @@ func TestAuthHandler_Login(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_Login_CookieMode(t *testing.T) {
+	mockService := &mocks.MockAuthService{
+		LoginUserFunc: func(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error) {
+			return &dto.AuthResponse{
+				User:  &models.User{ID: 1, Name: "John Doe", Email: req.Email},
+				Token: "test-token-123",
+			}, nil
+		},
+	}
+	handler := NewAuthHandler(mockService, true)
+
+	router := gin.New()
+	router.POST("/login", handler.Login)
+
+	body, _ := json.Marshal(map[string]any{"email": "john@example.com", "password": "password123"})
+	req, _ := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Login() status = %v, want %v", w.Code, http.StatusOK)
+	}
+
+	var response struct {
+		Data map[string]any `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if _, ok := response.Data["token"]; ok {
+		t.Error("Login() body contains token in cookie mode")
+	}
+	if _, ok := response.Data["user"]; !ok {
+		t.Error("Login() body is missing user")
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Login() set %d cookies, want 1", len(cookies))
+	}
+	cookie := cookies[0]
+	if cookie.Name != utils.AuthCookieName || cookie.Value != "test-token-123" {
+		t.Errorf("Login() cookie = %s=%s, want %s=test-token-123", cookie.Name, cookie.Value, utils.AuthCookieName)
+	}
+	if !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteStrictMode {
+		t.Errorf("Login() cookie Secure=%v HttpOnly=%v SameSite=%v, want Secure HttpOnly Strict", cookie.Secure, cookie.HttpOnly, cookie.SameSite)
+	}
+}
@@ -3,8 +3,10 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,16 +21,19 @@ import (
 // CategoryHandler handles HTTP requests for categories
 type CategoryHandler struct {
 	categoryService services.CategoryService
+	pagination      services.PaginationConfig
 }
 
 // NewCategoryHandler creates a new CategoryHandler with the provided service
-func NewCategoryHandler(svc services.CategoryService) *CategoryHandler {
-	return &CategoryHandler{categoryService: svc}
+// pagination supplies the default page size for list endpoints
+func NewCategoryHandler(svc services.CategoryService, pagination services.PaginationConfig) *CategoryHandler {
+	return &CategoryHandler{categoryService: svc, pagination: pagination}
 }
 
 // CreateCategoryInput represents the create category request body
 type CreateCategoryInput struct {
-	Name string `json:"name" binding:"required,min=1,max=255"`
+	Name                   string `json:"name" binding:"required,min=1,max=255"`
+	DefaultSharePermission string `json:"default_share_permission" binding:"omitempty,oneof=read write"`
 }
 
 // Validate performs custom validation on CreateCategoryInput
@@ -42,7 +47,9 @@ func (c *CreateCategoryInput) Validate() error {
 
 // UpdateCategoryInput represents the update category request body
 type UpdateCategoryInput struct {
-	Name string `json:"name" binding:"required,min=1,max=255"`
+	Name                   string  `json:"name" binding:"required,min=1,max=255"`
+	AllowDuplicateTitles   *bool   `json:"allow_duplicate_titles"`
+	DefaultSharePermission *string `json:"default_share_permission" binding:"omitempty,oneof=read write"`
 }
 
 // Validate performs custom validation on UpdateCategoryInput
@@ -55,9 +62,15 @@ func (u *UpdateCategoryInput) Validate() error {
 }
 
 // ShareCategoryInput represents the share category request body
+// POST may omit permission to use the category's default; PUT requires it
 type ShareCategoryInput struct {
 	Email      string `json:"email" binding:"required,email"`
-	Permission string `json:"permission" binding:"required,oneof=read write"`
+	Permission string `json:"permission" binding:"omitempty,oneof=read write"`
+}
+
+// SharePreviewInput represents the share preview request body
+type SharePreviewInput struct {
+	Email string `json:"email" binding:"required,email"`
 }
 
 // UpdateSharePermissionInput represents the update share permission request body
@@ -65,6 +78,22 @@ type UpdateSharePermissionInput struct {
 	Permission string `json:"permission" binding:"required,oneof=read write"`
 }
 
+// SharePermissionUpdateInput is one entry of the bulk share permission request body
+type SharePermissionUpdateInput struct {
+	UserID     uint   `json:"user_id" binding:"required,min=1"`
+	Permission string `json:"permission" binding:"required,oneof=read write"`
+}
+
+// BulkUpdateSharePermissionsInput represents the bulk share permission request body
+type BulkUpdateSharePermissionsInput struct {
+	Updates []SharePermissionUpdateInput `json:"updates" binding:"required,min=1,max=100,dive"`
+}
+
+// MoveTodosInput represents the move todos request body
+type MoveTodosInput struct {
+	TargetCategoryID uint `json:"target_category_id" binding:"required"`
+}
+
 // handleCategoryError maps service errors to HTTP responses
 func (h *CategoryHandler) handleCategoryError(c *gin.Context, ctx context.Context, err error, operation string, userID uint, categoryID uint) bool {
 	if err == nil {
@@ -79,37 +108,52 @@ func (h *CategoryHandler) handleCategoryError(c *gin.Context, ctx context.Contex
 
 	// Handle specific business errors
 	if errors.Is(err, services.ErrCategoryNotFound) {
-		respondNotFound(c, "Category")
+		respondNotFound(c, CodeCategoryNotFound, "Category")
 		return true
 	}
 
 	if errors.Is(err, services.ErrCategoryForbidden) {
-		respondForbidden(c, "You don't have permission to access this category")
+		respondForbidden(c, CodeCategoryForbidden, "You don't have permission to access this category")
 		return true
 	}
 
 	if errors.Is(err, services.ErrCategoryNameExists) {
-		respondConflict(c, "Category with this name already exists")
+		respondConflict(c, CodeCategoryNameExists, "Category with this name already exists")
 		return true
 	}
 
 	if errors.Is(err, services.ErrUserNotFound) {
-		respondNotFound(c, "User")
+		respondNotFound(c, CodeUserNotFound, "User")
 		return true
 	}
 
 	if errors.Is(err, services.ErrCannotShareWithSelf) {
-		respondBadRequest(c, "Cannot share category with yourself", nil)
+		respondBadRequest(c, CodeCannotShareWithSelf, "Cannot share category with yourself", nil)
 		return true
 	}
 
 	if errors.Is(err, services.ErrShareAlreadyExists) {
-		respondConflict(c, "Category is already shared with this user")
+		respondConflict(c, CodeShareAlreadyExists, "Category is already shared with this user")
 		return true
 	}
 
 	if errors.Is(err, services.ErrShareNotFound) {
-		respondNotFound(c, "Share")
+		respondNotFound(c, CodeShareNotFound, "Share")
+		return true
+	}
+
+	if errors.Is(err, services.ErrInvalidPermission) {
+		respondBadRequest(c, CodeInvalidPermission, "Permission must be 'read' or 'write'", nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrSameCategory) {
+		respondBadRequest(c, CodeSameCategory, "Source and target category must be different", nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrTodoLimitReached) {
+		respondConflict(c, CodeTodoLimitReached, "Target category has reached the maximum number of todos")
 		return true
 	}
 
@@ -125,18 +169,18 @@ func (h *CategoryHandler) handleCategoryError(c *gin.Context, ctx context.Contex
 func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	var input CreateCategoryInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		respondBadRequest(c, "Validation failed", err)
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
 		return
 	}
 
 	if err := input.Validate(); err != nil {
-		respondBadRequest(c, err.Error(), nil)
+		respondBadRequest(c, CodeValidationFailed, err.Error(), nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
@@ -144,15 +188,16 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	defer cancel()
 
 	category, err := h.categoryService.CreateCategory(ctx, dto.CreateCategoryRequest{
-		Name:    input.Name,
-		OwnerID: userID,
+		Name:                   input.Name,
+		OwnerID:                userID,
+		DefaultSharePermission: models.Permission(input.DefaultSharePermission),
 	})
 
 	if h.handleCategoryError(c, ctx, err, "create category", userID, 0) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	respondCreated(c, fmt.Sprintf("/api/categories/%d", category.ID), gin.H{
 		"success": true,
 		"message": "Category created successfully",
 		"data":    category,
@@ -160,10 +205,31 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 }
 
 // GetCategories retrieves all categories for the authenticated user
+// An optional ?permission=read|write narrows the shared categories
 func (h *CategoryHandler) GetCategories(c *gin.Context) {
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
+		return
+	}
+
+	permission := models.Permission(c.Query("permission"))
+	if permission != "" && !permission.IsValid() {
+		respondBadRequest(c, CodeInvalidQueryParameter, "Invalid permission filter", nil)
+		return
+	}
+
+	// Pagination applies to the shared categories (the service caps the page size)
+	page, pageSize, err := parsePagination(c, h.pagination)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+
+	// Todos are loaded unless the client only needs the category list
+	includeTodos, err := strconv.ParseBool(c.DefaultQuery("include_todos", "true"))
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, "include_todos must be true or false", nil)
 		return
 	}
 
@@ -171,13 +237,13 @@ func (h *CategoryHandler) GetCategories(c *gin.Context) {
 	defer cancel()
 
 	// Get owned categories
-	ownedCategories, err := h.categoryService.GetCategories(ctx, userID)
+	ownedCategories, err := h.categoryService.GetCategories(ctx, userID, includeTodos)
 	if h.handleCategoryError(c, ctx, err, "fetch categories", userID, 0) {
 		return
 	}
 
-	// Get shared categories
-	sharedCategories, err := h.categoryService.GetSharedCategories(ctx, userID)
+	// Get a page of shared categories
+	shared, err := h.categoryService.GetSharedCategories(ctx, userID, permission, page, pageSize, includeTodos)
 	if h.handleCategoryError(c, ctx, err, "fetch shared categories", userID, 0) {
 		return
 	}
@@ -187,22 +253,138 @@ func (h *CategoryHandler) GetCategories(c *gin.Context) {
 		"message": "Categories retrieved successfully",
 		"data": dto.CategoryListResponse{
 			OwnedCategories:  ownedCategories,
-			SharedCategories: sharedCategories,
+			SharedCategories: shared.Categories,
+		},
+		"shared_pagination": gin.H{
+			"total":             shared.Total,
+			"page":              shared.Page,
+			"page_size":         shared.PageSize,
+			"page_size_clamped": pageSize > shared.PageSize,
+			"total_pages":       shared.TotalPages,
 		},
 	})
 }
 
+// GetWritableCategories lists the categories the user can add todos to, without their todos
+func (h *CategoryHandler) GetWritableCategories(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	categories, err := h.categoryService.GetWritableCategories(ctx, userID)
+	if h.handleCategoryError(c, ctx, err, "fetch writable categories", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Writable categories retrieved successfully",
+		"data":    categories,
+		"count":   len(categories),
+	})
+}
+
+// GetCategoriesSharedByMe lists the user's own categories that are shared, with their recipients
+func (h *CategoryHandler) GetCategoriesSharedByMe(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	categories, err := h.categoryService.GetCategoriesSharedByMe(ctx, userID)
+	if h.handleCategoryError(c, ctx, err, "fetch shared-out categories", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Shared categories retrieved successfully",
+		"data":    categories,
+		"count":   len(categories),
+	})
+}
+
+// GetGrantedMembers lists everyone the caller has shared a category with, once per user, with their grants
+func (h *CategoryHandler) GetGrantedMembers(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	members, err := h.categoryService.GetGrantedMembers(ctx, userID)
+	if h.handleCategoryError(c, ctx, err, "fetch granted members", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Granted members retrieved successfully",
+		"data":    members,
+		"count":   len(members),
+	})
+}
+
+// CleanupEmptyCategories removes the caller's categories that have no todos and no shares
+// Runs as a dry run (list only) unless ?dry_run=false is passed
+func (h *CategoryHandler) CleanupEmptyCategories(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, "dry_run must be true or false", nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	categories, err := h.categoryService.CleanupEmptyCategories(ctx, userID, dryRun)
+	if h.handleCategoryError(c, ctx, err, "clean up empty categories", userID, 0) {
+		return
+	}
+
+	message := "Empty categories removed successfully"
+	if dryRun {
+		message = "Dry run: no categories were removed"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    categories,
+		"count":   len(categories),
+		"dry_run": dryRun,
+	})
+}
+
 // GetCategory retrieves a single category by ID
 func (h *CategoryHandler) GetCategory(c *gin.Context) {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid category ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
@@ -225,34 +407,42 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid category ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
 	var input UpdateCategoryInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		respondBadRequest(c, "Validation failed", err)
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
 		return
 	}
 
 	if err := input.Validate(); err != nil {
-		respondBadRequest(c, err.Error(), nil)
+		respondBadRequest(c, CodeValidationFailed, err.Error(), nil)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
+	var defaultSharePermission *models.Permission
+	if input.DefaultSharePermission != nil {
+		permission := models.Permission(*input.DefaultSharePermission)
+		defaultSharePermission = &permission
+	}
+
 	category, err := h.categoryService.UpdateCategory(ctx, dto.UpdateCategoryRequest{
-		ID:     id,
-		UserID: userID,
-		Name:   input.Name,
+		ID:                     id,
+		UserID:                 userID,
+		Name:                   input.Name,
+		AllowDuplicateTitles:   input.AllowDuplicateTitles,
+		DefaultSharePermission: defaultSharePermission,
 	})
 
 	if h.handleCategoryError(c, ctx, err, "update category", userID, id) {
@@ -270,13 +460,13 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid category ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
@@ -298,26 +488,26 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 func (h *CategoryHandler) ShareCategory(c *gin.Context) {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid category ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
 	var input ShareCategoryInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		respondBadRequest(c, "Validation failed", err)
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	share, err := h.categoryService.ShareCategory(ctx, dto.ShareCategoryRequest{
+	response, err := h.categoryService.ShareCategory(ctx, dto.ShareCategoryRequest{
 		CategoryID:     id,
 		OwnerID:        userID,
 		ShareWithEmail: input.Email,
@@ -328,30 +518,125 @@ func (h *CategoryHandler) ShareCategory(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	body := gin.H{
 		"success": true,
 		"message": "Category shared successfully",
+		"data":    response.Share,
+	}
+	// Warn (without blocking) that the recipient already owns a category with this name
+	if response.NameCollision {
+		body["name_collision"] = true
+	}
+	c.JSON(http.StatusCreated, body)
+}
+
+// UpsertShare handles sharing a category or updating an existing share's permission HTTP request
+// Responds 201 when the share is created and 200 when it already existed
+func (h *CategoryHandler) UpsertShare(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var input ShareCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+	// Unlike POST, an upsert may change an existing share, so it never falls back to the default
+	if input.Permission == "" {
+		respondBadRequest(c, CodeValidationFailed, "permission is required", nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	share, created, err := h.categoryService.UpsertShare(ctx, dto.ShareCategoryRequest{
+		CategoryID:     id,
+		OwnerID:        userID,
+		ShareWithEmail: input.Email,
+		Permission:     models.Permission(input.Permission),
+	})
+	if h.handleCategoryError(c, ctx, err, "upsert share", userID, id) {
+		return
+	}
+
+	status, message := http.StatusOK, "Share updated successfully"
+	if created {
+		status, message = http.StatusCreated, "Category shared successfully"
+	}
+	c.JSON(status, gin.H{
+		"success": true,
+		"message": message,
 		"data":    share,
 	})
 }
 
+// PreviewShare handles resolving who a share would go to, without creating it
+func (h *CategoryHandler) PreviewShare(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var input SharePreviewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	preview, err := h.categoryService.PreviewShare(ctx, dto.SharePreviewRequest{
+		CategoryID:     id,
+		OwnerID:        userID,
+		ShareWithEmail: input.Email,
+	})
+
+	if h.handleCategoryError(c, ctx, err, "preview share", userID, id) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Share preview generated successfully",
+		"data":    preview,
+	})
+}
+
 // UnshareCategory handles removing sharing of a category
 func (h *CategoryHandler) UnshareCategory(c *gin.Context) {
 	categoryID, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid category ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
 		return
 	}
 
 	shareUserID, err := parseIDParam(c, "user_id")
 	if err != nil {
-		respondBadRequest(c, "Invalid user ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid user ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
@@ -374,29 +659,60 @@ func (h *CategoryHandler) UnshareCategory(c *gin.Context) {
 	})
 }
 
+// UnshareAll handles removing every share of a category
+func (h *CategoryHandler) UnshareAll(c *gin.Context) {
+	categoryID, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	removed, err := h.categoryService.UnshareAll(ctx, categoryID, userID)
+	if h.handleCategoryError(c, ctx, err, "unshare category from all users", userID, categoryID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Category shares removed successfully",
+		"data": gin.H{
+			"removed": removed,
+		},
+	})
+}
+
 // UpdateSharePermission handles updating the permission of a share
 func (h *CategoryHandler) UpdateSharePermission(c *gin.Context) {
 	categoryID, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid category ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
 		return
 	}
 
 	shareUserID, err := parseIDParam(c, "user_id")
 	if err != nil {
-		respondBadRequest(c, "Invalid user ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid user ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
 	var input UpdateSharePermissionInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		respondBadRequest(c, "Validation failed", err)
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
 		return
 	}
 
@@ -420,32 +736,170 @@ func (h *CategoryHandler) UpdateSharePermission(c *gin.Context) {
 	})
 }
 
+// BulkUpdateSharePermissions handles updating the permission of several shares of a category at once
+// Users the category is not shared with are listed under not_found
+func (h *CategoryHandler) BulkUpdateSharePermissions(c *gin.Context) {
+	categoryID, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var input BulkUpdateSharePermissionsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+
+	updates := make([]dto.SharePermissionUpdate, len(input.Updates))
+	for i, update := range input.Updates {
+		updates[i] = dto.SharePermissionUpdate{
+			UserID:     update.UserID,
+			Permission: models.Permission(update.Permission),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.categoryService.BulkUpdateSharePermissions(ctx, dto.BulkUpdateSharePermissionsRequest{
+		CategoryID: categoryID,
+		OwnerID:    userID,
+		Updates:    updates,
+	})
+
+	if h.handleCategoryError(c, ctx, err, "update share permissions", userID, categoryID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Share permissions updated successfully",
+		"data": gin.H{
+			"updated":   response.Updated,
+			"not_found": response.NotFound,
+		},
+	})
+}
+
 // GetShares retrieves all shares for a category
 func (h *CategoryHandler) GetShares(c *gin.Context) {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid category ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
+		return
+	}
+
+	sortBy := c.DefaultQuery("sort", services.ShareSortCreatedAt)
+	if sortBy != services.ShareSortCreatedAt && sortBy != services.ShareSortEmail {
+		respondBadRequest(c, CodeInvalidQueryParameter, "sort must be 'email' or 'created_at'", nil)
+		return
+	}
+
+	// Parse pagination params (the service caps the page size)
+	page, pageSize, err := parsePagination(c, h.pagination)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	shares, err := h.categoryService.GetSharesForCategory(ctx, id, userID)
+	response, err := h.categoryService.GetSharesForCategory(ctx, id, userID, sortBy, page, pageSize)
 	if h.handleCategoryError(c, ctx, err, "fetch shares", userID, id) {
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"message":           "Shares retrieved successfully",
+		"data":              response.Shares,
+		"count":             len(response.Shares),
+		"total":             response.Total,
+		"page":              response.Page,
+		"page_size":         response.PageSize,
+		"page_size_clamped": pageSize > response.PageSize,
+		"total_pages":       response.TotalPages,
+	})
+}
+
+// GetPermission returns the authenticated user's effective permission on a category
+func (h *CategoryHandler) GetPermission(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	permission, err := h.categoryService.GetUserPermissionForCategory(ctx, userID, id)
+	if h.handleCategoryError(c, ctx, err, "fetch category permission", userID, id) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Permission retrieved successfully",
+		"data": gin.H{
+			"permission": permission,
+		},
+	})
+}
+
+// MoveTodos moves every todo in a category into another category
+func (h *CategoryHandler) MoveTodos(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var input MoveTodosInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	moved, err := h.categoryService.MoveTodos(ctx, userID, id, input.TargetCategoryID)
+	if h.handleCategoryError(c, ctx, err, "move todos", userID, id) {
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Shares retrieved successfully",
-		"data":    shares,
-		"count":   len(shares),
+		"message": "Todos moved successfully",
+		"data": gin.H{
+			"moved": moved,
+		},
 	})
 }
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/models"
+	"todo-app/internal/services"
+	"todo-app/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCategoryHandler_GetPermission(t *testing.T) {
+	tests := []struct {
+		name               string
+		categoryID         string
+		mockPermission     string
+		mockErr            error
+		expectedStatus     int
+		expectedPermission string
+	}{
+		{
+			name:               "owner",
+			categoryID:         "1",
+			mockPermission:     "owner",
+			expectedStatus:     http.StatusOK,
+			expectedPermission: "owner",
+		},
+		{
+			name:               "shared with write",
+			categoryID:         "1",
+			mockPermission:     "write",
+			expectedStatus:     http.StatusOK,
+			expectedPermission: "write",
+		},
+		{
+			name:               "no access",
+			categoryID:         "1",
+			mockPermission:     "none",
+			expectedStatus:     http.StatusOK,
+			expectedPermission: "none",
+		},
+		{
+			name:           "category not found",
+			categoryID:     "999",
+			mockErr:        services.ErrCategoryNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid category ID",
+			categoryID:     "abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockCategoryService{
+				GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+					return tt.mockPermission, tt.mockErr
+				},
+			}
+			handler := NewCategoryHandler(mockService, testPagination)
+
+			router := gin.New()
+			router.GET("/categories/:id/permission", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.GetPermission(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/categories/"+tt.categoryID+"/permission", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("GetPermission() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data struct {
+					Permission string `json:"permission"`
+				} `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+
+			if response.Data.Permission != tt.expectedPermission {
+				t.Errorf("GetPermission() permission = %v, want %v", response.Data.Permission, tt.expectedPermission)
+			}
+		})
+	}
+}
+
+func TestCategoryHandler_GetCategories_PermissionFilter(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		expectedStatus     int
+		expectedPermission models.Permission
+	}{
+		{
+			name:           "no filter",
+			query:          "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:               "write filter",
+			query:              "?permission=write",
+			expectedStatus:     http.StatusOK,
+			expectedPermission: models.PermissionWrite,
+		},
+		{
+			name:           "invalid filter",
+			query:          "?permission=admin",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPermission models.Permission
+			mockService := &mocks.MockCategoryService{
+				GetSharedCategoriesFunc: func(ctx context.Context, userID uint, permission models.Permission, page, pageSize int, includeTodos bool) (*dto.SharedCategoryListResponse, error) {
+					gotPermission = permission
+					return &dto.SharedCategoryListResponse{Categories: []models.SharedCategoryWithOwner{}, Page: page, PageSize: pageSize}, nil
+				},
+			}
+			handler := NewCategoryHandler(mockService, testPagination)
+
+			router := gin.New()
+			router.GET("/categories", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.GetCategories(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/categories"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("GetCategories() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if gotPermission != tt.expectedPermission {
+				t.Errorf("GetCategories() permission filter = %q, want %q", gotPermission, tt.expectedPermission)
+			}
+		})
+	}
+}
+
+func TestCategoryHandler_CreateCategory_Location(t *testing.T) {
+	mockService := &mocks.MockCategoryService{
+		CreateCategoryFunc: func(ctx context.Context, req dto.CreateCategoryRequest) (*models.Category, error) {
+			return &models.Category{ID: 42, Name: req.Name, OwnerID: req.OwnerID}, nil
+		},
+	}
+	handler := NewCategoryHandler(mockService, testPagination)
+
+	router := gin.New()
+	router.POST("/categories", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		handler.CreateCategory(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/categories", bytes.NewBufferString(`{"name":"Work"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CreateCategory() status = %v, want %v", w.Code, http.StatusCreated)
+	}
+	if got := w.Header().Get("Location"); got != "/api/categories/42" {
+		t.Errorf("CreateCategory() Location = %q, want %q", got, "/api/categories/42")
+	}
+}
+
+func TestCategoryHandler_UpsertShare(t *testing.T) {
+	shared := false
+	mockService := &mocks.MockCategoryService{
+		UpsertShareFunc: func(ctx context.Context, req dto.ShareCategoryRequest) (*models.CategoryShare, bool, error) {
+			created := !shared
+			shared = true
+			return &models.CategoryShare{ID: 10, CategoryID: req.CategoryID, SharedWithUserID: 2, Permission: req.Permission}, created, nil
+		},
+	}
+	handler := NewCategoryHandler(mockService, testPagination)
+
+	router := gin.New()
+	router.PUT("/categories/:id/share", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		handler.UpsertShare(c)
+	})
+
+	for _, step := range []struct {
+		body           string
+		expectedStatus int
+	}{
+		{body: `{"email":"user2@test.com","permission":"read"}`, expectedStatus: http.StatusCreated},
+		{body: `{"email":"user2@test.com","permission":"write"}`, expectedStatus: http.StatusOK},
+	} {
+		req, _ := http.NewRequest(http.MethodPut, "/categories/1/share", bytes.NewBufferString(step.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != step.expectedStatus {
+			t.Errorf("UpsertShare(%s) status = %v, want %v", step.body, w.Code, step.expectedStatus)
+		}
+	}
+}
+
+func TestCategoryHandler_PreviewShare(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		preview        *dto.SharePreviewResponse
+		serviceErr     error
+		expectedStatus int
+		expectedFound  bool
+		expectedShared bool
+	}{
+		{
+			name:           "found",
+			body:           `{"email":"user2@test.com"}`,
+			preview:        &dto.SharePreviewResponse{Found: true, User: &dto.SharePreviewUser{ID: 2, Name: "User Two"}},
+			expectedStatus: http.StatusOK,
+			expectedFound:  true,
+		},
+		{
+			name:           "not found",
+			body:           `{"email":"typo@test.com"}`,
+			preview:        &dto.SharePreviewResponse{Found: false},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "already shared",
+			body:           `{"email":"user2@test.com"}`,
+			preview:        &dto.SharePreviewResponse{Found: true, User: &dto.SharePreviewUser{ID: 2, Name: "User Two"}, AlreadyShared: true, Permission: models.PermissionRead},
+			expectedStatus: http.StatusOK,
+			expectedFound:  true,
+			expectedShared: true,
+		},
+		{
+			name:           "not the owner",
+			body:           `{"email":"user2@test.com"}`,
+			serviceErr:     services.ErrCategoryForbidden,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "invalid email",
+			body:           `{"email":"not-an-email"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockCategoryService{
+				PreviewShareFunc: func(ctx context.Context, req dto.SharePreviewRequest) (*dto.SharePreviewResponse, error) {
+					return tt.preview, tt.serviceErr
+				},
+			}
+			handler := NewCategoryHandler(mockService, testPagination)
+
+			router := gin.New()
+			router.POST("/categories/:id/share/preview", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.PreviewShare(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/categories/1/share/preview", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("PreviewShare() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data struct {
+					Found         bool `json:"found"`
+					AlreadyShared bool `json:"already_shared"`
+					User          *struct {
+						ID   uint   `json:"id"`
+						Name string `json:"name"`
+					} `json:"user"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if response.Data.Found != tt.expectedFound || response.Data.AlreadyShared != tt.expectedShared {
+				t.Errorf("PreviewShare() found = %v, already_shared = %v, want %v and %v", response.Data.Found, response.Data.AlreadyShared, tt.expectedFound, tt.expectedShared)
+			}
+			if tt.expectedFound && (response.Data.User == nil || response.Data.User.ID != 2) {
+				t.Errorf("PreviewShare() user = %+v, want user 2", response.Data.User)
+			}
+		})
+	}
+}
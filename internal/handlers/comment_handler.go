@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/services"
+	"todo-app/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CommentHandler handles HTTP requests for comments on todos
+type CommentHandler struct {
+	commentService services.CommentService
+	pagination     services.PaginationConfig
+}
+
+// NewCommentHandler creates a new CommentHandler with the provided service and pagination defaults
+func NewCommentHandler(svc services.CommentService, pagination services.PaginationConfig) *CommentHandler {
+	return &CommentHandler{commentService: svc, pagination: pagination}
+}
+
+// CreateCommentInput represents the create comment request body
+type CreateCommentInput struct {
+	Body string `json:"body" binding:"required,max=1000"`
+}
+
+// handleCommentError maps service errors to HTTP responses
+func (h *CommentHandler) handleCommentError(c *gin.Context, ctx context.Context, err error, operation string, userID, todoID uint) bool {
+	if err == nil {
+		return false
+	}
+
+	// Check for timeout
+	if ctx.Err() != nil {
+		respondTimeout(c)
+		return true
+	}
+
+	// Handle specific business errors
+	if errors.Is(err, services.ErrTodoNotFound) {
+		respondNotFound(c, CodeTodoNotFound, "Todo")
+		return true
+	}
+
+	if errors.Is(err, services.ErrForbidden) {
+		respondForbidden(c, CodeTodoForbidden, "You don't have permission to access this todo")
+		return true
+	}
+
+	if errors.Is(err, services.ErrCategoryNotFound) {
+		respondNotFound(c, CodeCategoryNotFound, "Category")
+		return true
+	}
+
+	if errors.Is(err, services.ErrCommentNotFound) {
+		respondNotFound(c, CodeCommentNotFound, "Comment")
+		return true
+	}
+
+	if errors.Is(err, services.ErrCommentBodyRequired) {
+		respondBadRequest(c, CodeValidationFailed, err.Error(), nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrCommentDeleteDenied) {
+		respondForbidden(c, CodeCommentDeleteForbidden, "Only the comment's author or the category owner can delete it")
+		return true
+	}
+
+	// Log and return generic error
+	rid := utils.GetRequestID(c.Request.Context())
+	log.Printf("[%s] request=%s user=%v todo=%v error=%v", operation, rid, userID, todoID, err)
+
+	respondInternalError(c, "Failed to "+operation, err)
+	return true
+}
+
+// CreateComment handles commenting on a todo HTTP request
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	todoID, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
+		return
+	}
+
+	var input CreateCommentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	comment, err := h.commentService.CreateComment(ctx, dto.CreateCommentRequest{
+		TodoID: todoID,
+		UserID: userID,
+		Body:   input.Body,
+	})
+	if h.handleCommentError(c, ctx, err, "create comment", userID, todoID) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Comment created successfully",
+		"data":    comment,
+	})
+}
+
+// GetComments handles listing a todo's comments HTTP request
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	todoID, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	// Parse pagination params (the service caps the page size)
+	page, pageSize, err := parsePagination(c, h.pagination)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.commentService.GetComments(ctx, todoID, userID, page, pageSize)
+	if h.handleCommentError(c, ctx, err, "fetch comments", userID, todoID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"message":           "Comments retrieved successfully",
+		"data":              response.Comments,
+		"count":             len(response.Comments),
+		"total":             response.Total,
+		"page":              response.Page,
+		"page_size":         response.PageSize,
+		"page_size_clamped": pageSize > response.PageSize,
+		"total_pages":       response.TotalPages,
+	})
+}
+
+// DeleteComment handles deleting a comment on a todo HTTP request
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	todoID, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
+		return
+	}
+
+	commentID, err := parseIDParam(c, "commentID")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid comment ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	err = h.commentService.DeleteComment(ctx, dto.DeleteCommentRequest{
+		TodoID:    todoID,
+		CommentID: commentID,
+		UserID:    userID,
+	})
+	if h.handleCommentError(c, ctx, err, "delete comment", userID, todoID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Comment deleted successfully",
+	})
+}
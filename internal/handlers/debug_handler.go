@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DebugStats returns a handler reporting goroutine count, heap usage and uptime since startedAt
+func DebugStats(startedAt time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var mem runtime.MemStats
+		runtime.ReadMemStats(&mem)
+
+		c.JSON(http.StatusOK, gin.H{
+			"goroutines":       runtime.NumGoroutine(),
+			"heap_alloc_bytes": mem.HeapAlloc,
+			"uptime_seconds":   int64(time.Since(startedAt).Seconds()),
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+// Stable machine-readable error codes returned in the "code" field of error responses
+// Messages may be reworded; these values must not change once released
+const (
+	// Generic request errors
+	CodeValidationFailed      = "validation_failed"
+	CodeInvalidID             = "invalid_id"
+	CodeInvalidQueryParameter = "invalid_query_parameter"
+	CodeRequestTimeout        = "request_timeout"
+	CodeInternalError         = "internal_error"
+	CodeRouteNotFound         = "route_not_found"
+	CodeMethodNotAllowed      = "method_not_allowed"
+
+	// Auth errors
+	CodeEmailAlreadyRegistered = "email_already_registered"
+	CodeEmailDomainBlocked     = "email_domain_blocked"
+	CodeInvalidCredentials     = "invalid_credentials"
+	CodeInvalidTimezone        = "invalid_timezone"
+
+	// Todo errors
+	CodeTodoNotFound      = "todo_not_found"
+	CodeTodoForbidden     = "todo_forbidden"
+	CodeCategoryRequired  = "category_required"
+	CodeNoWritePermission = "no_write_permission"
+	CodeInvalidCreatedBy  = "invalid_created_by"
+	CodeInvalidScope      = "invalid_scope"
+	CodeInvalidSort       = "invalid_sort"
+	CodeDuplicateTodo     = "duplicate_todo"
+	CodeTodoLimitReached  = "todo_limit_reached"
+	CodeInvalidDateRange  = "invalid_date_range"
+	CodeInvalidUndoToken  = "invalid_undo_token"
+	CodeInvalidCursor     = "invalid_cursor"
+
+	// Comment errors
+	CodeCommentNotFound        = "comment_not_found"
+	CodeCommentDeleteForbidden = "comment_delete_forbidden"
+
+	// Category errors
+	CodeCategoryNotFound    = "category_not_found"
+	CodeCategoryForbidden   = "category_forbidden"
+	CodeCategoryNameExists  = "category_name_exists"
+	CodeUserNotFound        = "user_not_found"
+	CodeCannotShareWithSelf = "cannot_share_with_self"
+	CodeShareAlreadyExists  = "share_already_exists"
+	CodeShareNotFound       = "share_not_found"
+	CodeInvalidPermission   = "invalid_permission"
+	CodeSameCategory        = "same_category"
+
+	// Personal access token errors
+	CodeTokenNameRequired = "token_name_required"
+	CodeInvalidTokenScope = "invalid_token_scope"
+	CodeTokenNotFound     = "token_not_found"
+)
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RouteNotFound answers requests that match no route with the standard JSON error envelope
+func RouteNotFound(c *gin.Context) {
+	respondNotFound(c, CodeRouteNotFound, "Route "+c.Request.Method+" "+c.Request.URL.Path)
+}
+
+// MethodNotAllowed answers requests whose path exists but not for the request method
+// It only runs when the engine has HandleMethodNotAllowed set
+func MethodNotAllowed(c *gin.Context) {
+	c.JSON(http.StatusMethodNotAllowed, gin.H{
+		"success": false,
+		"code":    CodeMethodNotAllowed,
+		"message": "Method " + c.Request.Method + " is not allowed for " + c.Request.URL.Path,
+	})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFallbackHandlers(t *testing.T) {
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(RouteNotFound)
+	router.NoMethod(MethodNotAllowed)
+	router.GET("/api/version", Version)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "unknown path", method: http.MethodGet, path: "/api/nope", wantCode: http.StatusNotFound, wantErr: CodeRouteNotFound},
+		{name: "wrong method", method: http.MethodDelete, path: "/api/version", wantCode: http.StatusMethodNotAllowed, wantErr: CodeMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("%s %s: expected %d, got %d", tt.method, tt.path, tt.wantCode, w.Code)
+			}
+
+			var response map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode response %q: %v", w.Body.String(), err)
+			}
+			if response["success"] != false || response["code"] != tt.wantErr || response["message"] == "" {
+				t.Errorf("response = %v, want success false and code %q", response, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessChecker reports whether the database is reachable and migrated
+type ReadinessChecker interface {
+	SchemaReady(ctx context.Context) error
+}
+
+// Ready returns a handler that responds 200 once checker passes and 503 until then
+func Ready(checker ReadinessChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+		defer cancel()
+
+		if err := checker.SchemaReady(ctx); err != nil {
+			log.Printf("Readiness check failed: %v", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":  "unavailable",
+				"message": "Database schema is not ready",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "ok",
+			"message": "Todo API is ready",
+		})
+	}
+}
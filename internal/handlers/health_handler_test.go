@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubReadiness fails until migrated is set
+type stubReadiness struct {
+	migrated bool
+}
+
+func (s *stubReadiness) SchemaReady(ctx context.Context) error {
+	if !s.migrated {
+		return errors.New("schema not migrated: missing tables users, categories, todos")
+	}
+	return nil
+}
+
+func TestReady(t *testing.T) {
+	checker := &stubReadiness{}
+	router := gin.New()
+	router.GET("/api/ready", Ready(checker))
+
+	get := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ready", nil))
+		return w.Code
+	}
+
+	if code := get(); code != http.StatusServiceUnavailable {
+		t.Errorf("GET /api/ready before migration: expected 503, got %d", code)
+	}
+
+	checker.migrated = true
+	if code := get(); code != http.StatusOK {
+		t.Errorf("GET /api/ready after migration: expected 200, got %d", code)
+	}
+}
@@ -1,8 +1,16 @@
 package handlers
 
 import (
+	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+
+	"todo-app/internal/models"
+	"todo-app/internal/services"
+	"todo-app/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,6 +26,15 @@ func getUserID(c *gin.Context) (uint, bool) {
 	return userID.(uint), true
 }
 
+// getUser returns the user set in context by middleware.LoadUser, or nil and false on routes without it
+func getUser(c *gin.Context) (*models.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		return nil, false
+	}
+	return user.(*models.User), true
+}
+
 // parseIDParam parses ID from URL parameter
 func parseIDParam(c *gin.Context, paramName string) (uint, error) {
 	idParam := c.Param(paramName)
@@ -28,21 +45,100 @@ func parseIDParam(c *gin.Context, paramName string) (uint, error) {
 	return uint(id), nil
 }
 
-// respondUnauthorized sends unauthorized response
-func respondUnauthorized(c *gin.Context) {
-	c.JSON(http.StatusUnauthorized, gin.H{
-		"success": false,
-		"message": "User not authenticated",
-	})
+// maxPageOffset is the furthest row a page may start at, since LIMIT and OFFSET are 32-bit query arguments
+const maxPageOffset = math.MaxInt32
+
+// parsePagination parses the optional page and page_size query params.
+// An absent page means the first page and an absent or zero page_size means cfg.DefaultPageSize.
+// Non-numeric values, a page below 1, a negative page_size and a page starting past maxPageOffset are
+// rejected instead of being coerced. A page_size above cfg.MaxPageSize is returned as requested:
+// the service caps it, and handlers report that as page_size_clamped.
+func parsePagination(c *gin.Context, cfg services.PaginationConfig) (page, pageSize int, err error) {
+	page, err = parseQueryInt(c, "page", 1, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	pageSize, err = parseQueryInt(c, "page_size", 0, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	if pageSize == 0 {
+		pageSize = cfg.DefaultPageSize
+	}
+
+	// Pages are at most MaxPageSize long once capped, which bounds how far in a page can start
+	if maxPage := maxPageOffset/max(min(pageSize, cfg.MaxPageSize), 1) + 1; page > maxPage {
+		return 0, 0, fmt.Errorf("page must be at most %d", maxPage)
+	}
+	return page, pageSize, nil
+}
+
+// parseQueryInt parses an optional integer query param of at least minValue, returning defaultValue when it is absent
+func parseQueryInt(c *gin.Context, key string, defaultValue, minValue int) (int, error) {
+	raw, ok := c.GetQuery(key)
+	if !ok {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", key)
+	}
+	if value < minValue {
+		return 0, fmt.Errorf("%s must be at least %d", key, minValue)
+	}
+	return value, nil
+}
+
+// parseQueryIDs parses an optional comma-separated list of positive IDs, returning nil when it is absent.
+// Repeated IDs are kept once, and a list longer than maxIDs is rejected.
+func parseQueryIDs(c *gin.Context, key string, maxIDs int) ([]uint, error) {
+	raw, ok := c.GetQuery(key)
+	if !ok {
+		return nil, nil
+	}
+
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, field := range strings.Split(raw, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("%s must be a comma-separated list of positive integer IDs", key)
+		}
+		if !seen[uint(id)] {
+			seen[uint(id)] = true
+			ids = append(ids, uint(id))
+		}
+	}
+	if len(ids) > maxIDs {
+		return nil, fmt.Errorf("%s must list at most %d IDs", key, maxIDs)
+	}
+	return ids, nil
+}
+
+// respondCreated sends a created response with a Location header pointing at the new resource
+func respondCreated(c *gin.Context, location string, payload gin.H) {
+	c.Header("Location", location)
+	c.JSON(http.StatusCreated, payload)
+}
+
+// respondMissingUser sends an internal error when a handler runs without the userID that AuthMiddleware sets.
+// Only the middleware answers 401, so reaching a protected handler without it is a routing bug, not a client error.
+func respondMissingUser(c *gin.Context) {
+	log.Printf("[auth] request=%s path=%s reached a protected handler without a userID in context", utils.GetRequestID(c.Request.Context()), c.Request.URL.Path)
+	respondInternalError(c, "User not available in request context", nil)
 }
 
 // respondBadRequest sends bad request response
-func respondBadRequest(c *gin.Context, message string, err error) {
+// Binding validation failures are reported per field in "errors"; any other error is passed through in "error"
+func respondBadRequest(c *gin.Context, code, message string, err error) {
 	response := gin.H{
 		"success": false,
+		"code":    code,
 		"message": message,
 	}
-	if err != nil {
+	if details, ok := fieldErrors(err); ok {
+		response["errors"] = details
+	} else if err != nil {
 		response["error"] = err.Error()
 	}
 	c.JSON(http.StatusBadRequest, response)
@@ -52,22 +148,25 @@ func respondBadRequest(c *gin.Context, message string, err error) {
 func respondTimeout(c *gin.Context) {
 	c.JSON(http.StatusRequestTimeout, gin.H{
 		"success": false,
+		"code":    CodeRequestTimeout,
 		"message": "Request timeout",
 	})
 }
 
 // respondNotFound sends not found response
-func respondNotFound(c *gin.Context, resource string) {
+func respondNotFound(c *gin.Context, code, resource string) {
 	c.JSON(http.StatusNotFound, gin.H{
 		"success": false,
+		"code":    code,
 		"message": resource + " not found",
 	})
 }
 
 // respondForbidden sends forbidden response
-func respondForbidden(c *gin.Context, message string) {
+func respondForbidden(c *gin.Context, code, message string) {
 	c.JSON(http.StatusForbidden, gin.H{
 		"success": false,
+		"code":    code,
 		"message": message,
 	})
 }
@@ -76,6 +175,7 @@ func respondForbidden(c *gin.Context, message string) {
 func respondInternalError(c *gin.Context, message string, err error) {
 	response := gin.H{
 		"success": false,
+		"code":    CodeInternalError,
 		"message": message,
 	}
 	if err != nil {
@@ -85,17 +185,19 @@ func respondInternalError(c *gin.Context, message string, err error) {
 }
 
 // respondConflict sends conflict response (e.g., duplicate resource)
-func respondConflict(c *gin.Context, message string) {
+func respondConflict(c *gin.Context, code, message string) {
 	c.JSON(http.StatusConflict, gin.H{
 		"success": false,
+		"code":    code,
 		"message": message,
 	})
 }
 
 // respondUnauthorizedWithMessage sends unauthorized response with custom message
-func respondUnauthorizedWithMessage(c *gin.Context, message string) {
+func respondUnauthorizedWithMessage(c *gin.Context, code, message string) {
 	c.JSON(http.StatusUnauthorized, gin.H{
 		"success": false,
+		"code":    code,
 		"message": message,
 	})
 }
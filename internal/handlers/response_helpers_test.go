@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todo-app/internal/services"
+	"todo-app/internal/services/mocks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testPagination is the pagination config handlers are built with in tests
+var testPagination = services.PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100}
+
+func TestProtectedHandlers_MissingUserIDIsInternalError(t *testing.T) {
+	todoHandler := NewTodoHandler(&mocks.MockTodoService{}, 1, testPagination)
+	categoryHandler := NewCategoryHandler(&mocks.MockCategoryService{}, testPagination)
+	authHandler := NewAuthHandler(&mocks.MockAuthService{}, false)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		route   string
+		handler gin.HandlerFunc
+	}{
+		{name: "list todos", method: http.MethodGet, path: "/todos", route: "/todos", handler: todoHandler.GetTodos},
+		{name: "list categories", method: http.MethodGet, path: "/categories", route: "/categories", handler: categoryHandler.GetCategories},
+		{name: "delete category", method: http.MethodDelete, path: "/categories/1", route: "/categories/:id", handler: categoryHandler.DeleteCategory},
+		{name: "update profile", method: http.MethodPatch, path: "/auth/profile", route: "/auth/profile", handler: authHandler.UpdateProfile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Registered without AuthMiddleware, so userID is never set
+			router := gin.New()
+			router.Handle(tt.method, tt.route, tt.handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != http.StatusInternalServerError {
+				t.Fatalf("%s %s status = %d, want %d", tt.method, tt.path, w.Code, http.StatusInternalServerError)
+			}
+
+			var response struct {
+				Code string `json:"code"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if response.Code != CodeInternalError {
+				t.Errorf("%s %s code = %q, want %q", tt.method, tt.path, response.Code, CodeInternalError)
+			}
+		})
+	}
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		wantPage     int
+		wantPageSize int
+		wantErr      string
+	}{
+		{name: "defaults", query: "", wantPage: 1, wantPageSize: 10},
+		{name: "explicit values", query: "page=3&page_size=25", wantPage: 3, wantPageSize: 25},
+		{name: "zero page_size means the default", query: "page_size=0", wantPage: 1, wantPageSize: 10},
+		// The service caps it and the response reports page_size_clamped
+		{name: "oversized page_size is passed through", query: "page_size=500", wantPage: 1, wantPageSize: 500},
+		{name: "zero page", query: "page=0", wantErr: "page must be at least 1"},
+		{name: "negative page", query: "page=-2", wantErr: "page must be at least 1"},
+		{name: "negative page_size", query: "page_size=-5", wantErr: "page_size must be at least 0"},
+		{name: "non-numeric page", query: "page=two", wantErr: "page must be an integer"},
+		{name: "non-numeric page_size", query: "page_size=10abc", wantErr: "page_size must be an integer"},
+		{name: "page past the last addressable row", query: "page=21474838&page_size=100", wantErr: "page must be at most 21474837"},
+		{name: "page past the last row at the capped size", query: "page=21474838&page_size=500", wantErr: "page must be at most 21474837"},
+		{name: "last addressable page", query: "page=21474837&page_size=100", wantPage: 21474837, wantPageSize: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/todos?"+tt.query, nil)
+
+			page, pageSize, err := parsePagination(c, testPagination)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parsePagination() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePagination() error = %v", err)
+			}
+			if page != tt.wantPage || pageSize != tt.wantPageSize {
+				t.Errorf("parsePagination() = (%d, %d), want (%d, %d)", page, pageSize, tt.wantPage, tt.wantPageSize)
+			}
+		})
+	}
+}
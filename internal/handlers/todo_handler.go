@@ -3,13 +3,16 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"todo-app/internal/dto"
+	"todo-app/internal/models"
 	"todo-app/internal/services"
 	"todo-app/pkg/utils"
 
@@ -18,60 +21,101 @@ import (
 
 // TodoHandler handles HTTP requests for todos
 type TodoHandler struct {
-	todoService services.TodoService
+	todoService       services.TodoService
+	minTodoTitleRunes int
+	pagination        services.PaginationConfig
 }
 
+// maxCategoryFilterIDs caps how many categories ?category_ids may list
+const maxCategoryFilterIDs = 50
+
 // NewTodoHandler creates a new TodoHandler with the provided service
-func NewTodoHandler(svc services.TodoService) *TodoHandler {
-	return &TodoHandler{todoService: svc}
+// minTodoTitleRunes is the fewest Unicode characters a trimmed title may have, and pagination supplies
+// the default page size for list endpoints
+func NewTodoHandler(svc services.TodoService, minTodoTitleRunes int, pagination services.PaginationConfig) *TodoHandler {
+	return &TodoHandler{todoService: svc, minTodoTitleRunes: minTodoTitleRunes, pagination: pagination}
 }
 
 // CreateTodoInput represents the create todo request body
 type CreateTodoInput struct {
-	Title       string `json:"title" binding:"required,min=1,max=255"`
-	Description string `json:"description" binding:"max=1000"`
-	Category    string `json:"category" binding:"-"`            // Validated in Validate(); optional when category_id is set
-	CategoryID  *uint  `json:"category_id" binding:"omitempty"` // ID: use this category (must have write access)
+	Title       string     `json:"title" binding:"required,min=1,max=255"`
+	Description string     `json:"description" binding:"max=1000"`
+	Category    string     `json:"category" binding:"-"`            // Validated in Validate(); exactly one of category and category_id is required
+	CategoryID  *uint      `json:"category_id" binding:"omitempty"` // ID: use this category (must have write access)
+	RemindAt    *time.Time `json:"remind_at"`                       // Optional RFC 3339 reminder time
 }
 
 // Validate performs custom validation on CreateTodoInput
-func (c *CreateTodoInput) Validate() error {
+func (c *CreateTodoInput) Validate(minTitleRunes int) error {
 	c.Title = strings.TrimSpace(c.Title)
-	if c.Title == "" {
-		return errors.New("title cannot be empty or whitespace only")
+	if err := validateTitleLength(c.Title, minTitleRunes); err != nil {
+		return err
 	}
 	c.Description = strings.TrimSpace(c.Description)
 	c.Category = strings.TrimSpace(c.Category)
-	// Require either category_id or category name
+	// Require either category_id or category name, but not both: the name would otherwise be silently ignored
 	hasID := c.CategoryID != nil && *c.CategoryID > 0
 	if !hasID && c.Category == "" {
 		return errors.New("either category or category_id is required")
 	}
+	if hasID && c.Category != "" {
+		return errors.New("category and category_id cannot both be set")
+	}
 	return nil
 }
 
 // UpdateTodoInput represents the update todo request body
 type UpdateTodoInput struct {
-	Title       *string `json:"title" binding:"omitempty,min=1,max=255"`
-	Description *string `json:"description" binding:"omitempty,max=1000"`
-	CategoryID  *uint   `json:"category_id"`
-	Completed   *bool   `json:"completed"`
+	Title       *string    `json:"title" binding:"omitempty,min=1,max=255"`
+	Description *string    `json:"description" binding:"omitempty,max=1000"`
+	CategoryID  *uint      `json:"category_id"`
+	Completed   *bool      `json:"completed"`
+	RemindAt    *time.Time `json:"remind_at"`
+}
+
+// CompleteAllInput represents the complete-all request body
+// Completed defaults to true; pass false to reopen every todo in the category
+type CompleteAllInput struct {
+	Completed *bool `json:"completed"`
+}
+
+// UndoDeleteInput represents the undo-delete request body
+type UndoDeleteInput struct {
+	UndoToken string `json:"undo_token" binding:"required"`
+}
+
+// BatchGetTodosInput represents the batch-get request body
+type BatchGetTodosInput struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=100,dive,min=1"`
+}
+
+// BulkMoveTodosInput represents the bulk-move request body
+// An empty filter moves every todo in the source category
+type BulkMoveTodosInput struct {
+	FromCategoryID uint                `json:"from_category_id" binding:"required"`
+	ToCategoryID   uint                `json:"to_category_id" binding:"required"`
+	Filter         BulkMoveTodosFilter `json:"filter"`
+}
+
+// BulkMoveTodosFilter narrows which todos a bulk move applies to
+type BulkMoveTodosFilter struct {
+	Completed *bool `json:"completed"`
 }
 
 // IsEmpty returns true if no fields are provided for update
 func (u *UpdateTodoInput) IsEmpty() bool {
-	return u.Title == nil && u.Description == nil && u.CategoryID == nil && u.Completed == nil
+	return u.Title == nil && u.Description == nil && u.CategoryID == nil && u.Completed == nil && u.RemindAt == nil
 }
 
 // Validate performs custom validation on UpdateTodoInput
-func (u *UpdateTodoInput) Validate() error {
+func (u *UpdateTodoInput) Validate(minTitleRunes int) error {
 	if u.IsEmpty() {
-		return errors.New("at least one field must be provided for update")
+		return errors.New("at least one field must be provided for PATCH, which only changes the fields provided")
 	}
 	if u.Title != nil {
 		trimmed := strings.TrimSpace(*u.Title)
-		if trimmed == "" {
-			return errors.New("title cannot be empty or whitespace only")
+		if err := validateTitleLength(trimmed, minTitleRunes); err != nil {
+			return err
 		}
 		u.Title = &trimmed
 	}
@@ -82,6 +126,48 @@ func (u *UpdateTodoInput) Validate() error {
 	return nil
 }
 
+// ReplaceTodoInput represents the full-replace (PUT) todo request body
+// Title and category_id are required; an omitted description or completed resets to its default
+type ReplaceTodoInput struct {
+	Title       *string    `json:"title" binding:"omitempty,max=255"`
+	Description *string    `json:"description" binding:"omitempty,max=1000"`
+	CategoryID  *uint      `json:"category_id"`
+	Completed   *bool      `json:"completed"`
+	RemindAt    *time.Time `json:"remind_at"`
+}
+
+// Validate performs custom validation on ReplaceTodoInput
+func (r *ReplaceTodoInput) Validate(minTitleRunes int) error {
+	if r.Title == nil || strings.TrimSpace(*r.Title) == "" {
+		return errors.New("title is required for PUT, which replaces the whole todo (use PATCH for a partial update)")
+	}
+	if r.CategoryID == nil || *r.CategoryID == 0 {
+		return errors.New("category_id is required for PUT, which replaces the whole todo (use PATCH for a partial update)")
+	}
+	title := strings.TrimSpace(*r.Title)
+	if err := validateTitleLength(title, minTitleRunes); err != nil {
+		return err
+	}
+	r.Title = &title
+	if r.Description != nil {
+		trimmed := strings.TrimSpace(*r.Description)
+		r.Description = &trimmed
+	}
+	return nil
+}
+
+// validateTitleLength rejects a trimmed title with fewer than minRunes Unicode characters, so a
+// multibyte character such as an emoji counts once rather than once per byte
+func validateTitleLength(title string, minRunes int) error {
+	if title == "" {
+		return errors.New("title cannot be empty or whitespace only")
+	}
+	if utf8.RuneCountInString(title) < minRunes {
+		return fmt.Errorf("title must be at least %d characters after trimming whitespace", minRunes)
+	}
+	return nil
+}
+
 // handleTodoError maps service errors to HTTP responses
 func (h *TodoHandler) handleTodoError(c *gin.Context, ctx context.Context, err error, operation string, userID uint, todoID uint) bool {
 	if err == nil {
@@ -96,27 +182,77 @@ func (h *TodoHandler) handleTodoError(c *gin.Context, ctx context.Context, err e
 
 	// Handle specific business errors
 	if errors.Is(err, services.ErrTodoNotFound) {
-		respondNotFound(c, "Todo")
+		respondNotFound(c, CodeTodoNotFound, "Todo")
 		return true
 	}
 
 	if errors.Is(err, services.ErrForbidden) {
-		respondForbidden(c, "You don't have permission to access this todo")
+		respondForbidden(c, CodeTodoForbidden, "You don't have permission to access this todo")
 		return true
 	}
 
 	if errors.Is(err, services.ErrCategoryNotFound) {
-		respondNotFound(c, "Category")
+		respondNotFound(c, CodeCategoryNotFound, "Category")
 		return true
 	}
 
 	if errors.Is(err, services.ErrCategoryRequired) {
-		respondBadRequest(c, "Category is required", nil)
+		respondBadRequest(c, CodeCategoryRequired, "Category is required", nil)
 		return true
 	}
 
 	if errors.Is(err, services.ErrNoWritePermission) {
-		respondForbidden(c, "You don't have write permission for this category")
+		respondForbidden(c, CodeNoWritePermission, "You don't have write permission for this category")
+		return true
+	}
+
+	if errors.Is(err, services.ErrInvalidCreator) {
+		respondBadRequest(c, CodeInvalidCreatedBy, "Invalid created_by filter", nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrInvalidSort) {
+		respondBadRequest(c, CodeInvalidSort, err.Error(), nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrInvalidScope) {
+		respondBadRequest(c, CodeInvalidScope, err.Error(), nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrDuplicateTodo) {
+		respondConflict(c, CodeDuplicateTodo, "A todo with this title already exists in this category")
+		return true
+	}
+
+	if errors.Is(err, services.ErrTodoLimitReached) {
+		respondConflict(c, CodeTodoLimitReached, "Category has reached the maximum number of todos")
+		return true
+	}
+	if errors.Is(err, services.ErrSameCategory) {
+		respondBadRequest(c, CodeSameCategory, "Source and target category must be different", nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrInvalidDateRange) || errors.Is(err, services.ErrDateRangeTooLarge) {
+		respondBadRequest(c, CodeInvalidDateRange, err.Error(), nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrInvalidCursor) {
+		respondBadRequest(c, CodeInvalidCursor, err.Error(), nil)
+		return true
+	}
+
+	if errors.Is(err, services.ErrInvalidUndoToken) {
+		respondBadRequest(c, CodeInvalidUndoToken, err.Error(), nil)
+		return true
+	}
+
+	var tooLong *services.FieldTooLongError
+	if errors.As(err, &tooLong) {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
 		return true
 	}
 
@@ -132,19 +268,19 @@ func (h *TodoHandler) handleTodoError(c *gin.Context, ctx context.Context, err e
 func (h *TodoHandler) CreateTodo(c *gin.Context) {
 	var input CreateTodoInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		respondBadRequest(c, "Validation failed", err)
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
 		return
 	}
 
 	// Custom validation for whitespace trimming
-	if err := input.Validate(); err != nil {
-		respondBadRequest(c, err.Error(), nil)
+	if err := input.Validate(h.minTodoTitleRunes); err != nil {
+		respondBadRequest(c, CodeValidationFailed, err.Error(), nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
@@ -156,6 +292,7 @@ func (h *TodoHandler) CreateTodo(c *gin.Context) {
 		Description: input.Description,
 		Category:    input.Category,
 		CategoryID:  input.CategoryID,
+		RemindAt:    input.RemindAt,
 		UserID:      userID,
 	})
 
@@ -163,7 +300,7 @@ func (h *TodoHandler) CreateTodo(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
+	respondCreated(c, fmt.Sprintf("/api/todos/%d", todo.ID), gin.H{
 		"success": true,
 		"message": "Todo created successfully",
 		"data":    todo,
@@ -174,31 +311,243 @@ func (h *TodoHandler) CreateTodo(c *gin.Context) {
 func (h *TodoHandler) GetTodos(c *gin.Context) {
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
+		return
+	}
+
+	// Parse pagination params (the service caps the page size)
+	page, pageSize, err := parsePagination(c, h.pagination)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+
+	expand, ok := parseTodoExpand(c.Query("expand"))
+	if !ok {
+		respondBadRequest(c, CodeInvalidQueryParameter, "expand must be a comma-separated list of 'category' and 'creator'", nil)
+		return
+	}
+
+	// An absent category_id lists every accessible todo
+	categoryID, err := parseQueryInt(c, "category_id", 0, 1)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
 		return
 	}
 
-	// Parse pagination params (service handles validation)
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	categoryIDs, err := parseQueryIDs(c, "category_ids", maxCategoryFilterIDs)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+	if categoryID > 0 && categoryIDs != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, "use either category_id or category_ids, not both", nil)
+		return
+	}
+	// Category listings have a fixed order, so a sort_by would be silently ignored
+	if c.Query("sort_by") != "" && (categoryID > 0 || categoryIDs != nil) {
+		respondBadRequest(c, CodeInvalidQueryParameter, "sort_by cannot be combined with category_id or category_ids", nil)
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	response, err := h.todoService.GetTodos(ctx, userID, page, pageSize)
+	var response *dto.TodoListResponse
+	switch {
+	case categoryIDs != nil:
+		// Several categories at once; the ones the user cannot read are left out
+		response, err = h.todoService.GetTodosInCategories(ctx, userID, categoryIDs, page, pageSize)
+	case categoryID > 0:
+		// Scoped to one category, which checks read access and lists it in the category endpoint's order
+		response, err = h.todoService.GetTodosByCategoryID(ctx, dto.ListCategoryTodosRequest{
+			CategoryID: uint(categoryID),
+			UserID:     userID,
+		}, page, pageSize)
+	default:
+		// An absent sort_by uses the configured default ordering
+		response, err = h.todoService.GetTodos(ctx, userID, models.TodoSort(c.Query("sort_by")), page, pageSize)
+	}
 	if h.handleTodoError(c, ctx, err, "fetch todos", userID, 0) {
 		return
 	}
 
+	var data interface{} = response.Todos
+	if expand != (dto.TodoExpand{}) {
+		expanded, err := h.todoService.ExpandTodos(ctx, response.Todos, expand)
+		if h.handleTodoError(c, ctx, err, "expand todos", userID, 0) {
+			return
+		}
+		data = expanded
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"message":           "Todos retrieved successfully",
+		"data":              data,
+		"count":             len(response.Todos),
+		"total":             response.Total,
+		"page":              response.Page,
+		"page_size":         response.PageSize,
+		"page_size_clamped": pageSize > response.PageSize,
+		"total_pages":       response.TotalPages,
+	})
+}
+
+// CountTodos returns the number of the user's todos for a badge, without any todo bodies
+// Optional ?completed=true|false counts only completed or only open todos
+func (h *TodoHandler) CountTodos(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var completed *bool
+	if raw := c.Query("completed"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			respondBadRequest(c, CodeInvalidQueryParameter, "completed must be true or false", nil)
+			return
+		}
+		completed = &value
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	count, err := h.todoService.CountTodos(ctx, userID, completed)
+	if h.handleTodoError(c, ctx, err, "count todos", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Todos counted successfully",
+		"data": gin.H{
+			"count": count,
+		},
+	})
+}
+
+// GetCategoryTodos retrieves the todos of a single category HTTP request
+func (h *TodoHandler) GetCategoryTodos(c *gin.Context) {
+	categoryID, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	// A cursor (even an empty one, for the first page) switches to keyset pagination
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		h.getCategoryTodosAfter(c, userID, categoryID, cursor)
+		return
+	}
+
+	// Parse pagination params (the service caps the page size)
+	page, pageSize, err := parsePagination(c, h.pagination)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.todoService.GetTodosByCategoryID(ctx, dto.ListCategoryTodosRequest{
+		CategoryID: categoryID,
+		UserID:     userID,
+		CreatedBy:  c.Query("created_by"),
+	}, page, pageSize)
+	if h.handleTodoError(c, ctx, err, "fetch category todos", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"message":           "Todos retrieved successfully",
+		"data":              response.Todos,
+		"count":             len(response.Todos),
+		"total":             response.Total,
+		"page":              response.Page,
+		"page_size":         response.PageSize,
+		"page_size_clamped": pageSize > response.PageSize,
+		"total_pages":       response.TotalPages,
+	})
+}
+
+// getCategoryTodosAfter serves GetCategoryTodos in cursor mode: a page of todos newest first and the cursor
+// of the page that follows, which is null on the last page
+func (h *TodoHandler) getCategoryTodosAfter(c *gin.Context, userID, categoryID uint, cursor string) {
+	if _, ok := c.GetQuery("page"); ok {
+		respondBadRequest(c, CodeInvalidQueryParameter, "cursor cannot be combined with page", nil)
+		return
+	}
+	pageSize, err := parseQueryInt(c, "page_size", 0, 0)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.todoService.GetCategoryTodosAfter(ctx, dto.ListCategoryTodosRequest{
+		CategoryID: categoryID,
+		UserID:     userID,
+		CreatedBy:  c.Query("created_by"),
+	}, cursor, pageSize)
+	if h.handleTodoError(c, ctx, err, "fetch category todos", userID, 0) {
+		return
+	}
+
+	var nextCursor *string
+	if response.NextCursor != "" {
+		nextCursor = &response.NextCursor
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success":     true,
 		"message":     "Todos retrieved successfully",
 		"data":        response.Todos,
 		"count":       len(response.Todos),
-		"total":       response.Total,
-		"page":        response.Page,
 		"page_size":   response.PageSize,
-		"total_pages": response.TotalPages,
+		"next_cursor": nextCursor,
+	})
+}
+
+// CountCategoryTodos returns how many todos a category has, completed and open, for a category header
+func (h *TodoHandler) CountCategoryTodos(c *gin.Context) {
+	categoryID, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	counts, err := h.todoService.CountCategoryTodos(ctx, userID, categoryID)
+	if h.handleTodoError(c, ctx, err, "count category todos", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Todos counted successfully",
+		"data":    counts,
 	})
 }
 
@@ -206,13 +555,26 @@ func (h *TodoHandler) GetTodos(c *gin.Context) {
 func (h *TodoHandler) GetTodo(c *gin.Context) {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid todo ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
+		return
+	}
+
+	expand, ok := parseTodoExpand(c.Query("expand"))
+	if !ok {
+		respondBadRequest(c, CodeInvalidQueryParameter, "expand must be a comma-separated list of 'category' and 'creator'", nil)
+		return
+	}
+
+	// Writers may look up a soft-deleted todo; everyone else still gets 404 for it
+	includeDeleted, err := strconv.ParseBool(c.DefaultQuery("include_deleted", "false"))
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, "include_deleted must be true or false", nil)
 		return
 	}
 
@@ -220,57 +582,118 @@ func (h *TodoHandler) GetTodo(c *gin.Context) {
 	defer cancel()
 
 	todo, err := h.todoService.GetTodoByID(ctx, dto.GetTodoRequest{
-		ID:     id,
-		UserID: userID,
+		ID:             id,
+		UserID:         userID,
+		IncludeDeleted: includeDeleted,
 	})
 
 	if h.handleTodoError(c, ctx, err, "fetch todo", userID, id) {
 		return
 	}
 
+	var data interface{} = todo
+	if expand != (dto.TodoExpand{}) {
+		expanded, err := h.todoService.ExpandTodos(ctx, []models.Todo{*todo}, expand)
+		if h.handleTodoError(c, ctx, err, "expand todo", userID, id) {
+			return
+		}
+		data = expanded[0]
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Todo retrieved successfully",
-		"data":    todo,
+		"data":    data,
 	})
 }
 
-// UpdateTodo handles updating an existing todo HTTP request
+// BatchGetTodos retrieves several todos by ID in one HTTP request
+// Todos are returned in the requested order; missing and unreadable IDs are listed separately
+func (h *TodoHandler) BatchGetTodos(c *gin.Context) {
+	var input BatchGetTodosInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.todoService.GetTodosByIDs(ctx, userID, input.IDs)
+	if h.handleTodoError(c, ctx, err, "fetch todos", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   "Todos retrieved successfully",
+		"data":      response.Todos,
+		"not_found": response.NotFound,
+		"forbidden": response.Forbidden,
+	})
+}
+
+// parseTodoExpand parses the comma-separated ?expand values, reporting false for an unknown value
+func parseTodoExpand(value string) (dto.TodoExpand, bool) {
+	var expand dto.TodoExpand
+	for _, field := range strings.Split(value, ",") {
+		switch strings.TrimSpace(field) {
+		case "":
+		case "category":
+			expand.Category = true
+		case "creator":
+			expand.Creator = true
+		default:
+			return dto.TodoExpand{}, false
+		}
+	}
+	return expand, true
+}
+
+// UpdateTodo handles partially updating an existing todo (PATCH) HTTP request
+// Only the fields provided in the body are changed
 func (h *TodoHandler) UpdateTodo(c *gin.Context) {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid todo ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
 	var input UpdateTodoInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		respondBadRequest(c, "Validation failed", err)
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
 		return
 	}
 
 	// Custom validation for update-specific rules
-	if err := input.Validate(); err != nil {
-		respondBadRequest(c, err.Error(), nil)
+	if err := input.Validate(h.minTodoTitleRunes); err != nil {
+		respondBadRequest(c, CodeValidationFailed, err.Error(), nil)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	todo, err := h.todoService.UpdateTodo(ctx, dto.UpdateTodoRequest{
+	todo, changed, err := h.todoService.UpdateTodo(ctx, dto.UpdateTodoRequest{
 		ID:          id,
 		UserID:      userID,
 		Title:       input.Title,
 		Description: input.Description,
 		CategoryID:  input.CategoryID,
 		Completed:   input.Completed,
+		RemindAt:    input.RemindAt,
 	})
 
 	if h.handleTodoError(c, ctx, err, "update todo", userID, id) {
@@ -278,9 +701,62 @@ func (h *TodoHandler) UpdateTodo(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success": true,
-		"message": "Todo updated successfully",
-		"data":    todo,
+		"success":      true,
+		"message":      "Todo updated successfully",
+		"data":         todo,
+		"not_modified": !changed,
+	})
+}
+
+// ReplaceTodo handles replacing an existing todo (PUT) HTTP request
+// Every mutable field is set; omitted optional fields are reset to their defaults
+func (h *TodoHandler) ReplaceTodo(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var input ReplaceTodoInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+
+	if err := input.Validate(h.minTodoTitleRunes); err != nil {
+		respondBadRequest(c, CodeValidationFailed, err.Error(), nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	todo, changed, err := h.todoService.UpdateTodo(ctx, dto.UpdateTodoRequest{
+		ID:          id,
+		UserID:      userID,
+		Title:       input.Title,
+		Description: input.Description,
+		CategoryID:  input.CategoryID,
+		Completed:   input.Completed,
+		RemindAt:    input.RemindAt,
+		Replace:     true,
+	})
+
+	if h.handleTodoError(c, ctx, err, "replace todo", userID, id) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"message":      "Todo replaced successfully",
+		"data":         todo,
+		"not_modified": !changed,
 	})
 }
 
@@ -288,20 +764,20 @@ func (h *TodoHandler) UpdateTodo(c *gin.Context) {
 func (h *TodoHandler) DeleteTodo(c *gin.Context) {
 	id, err := parseIDParam(c, "id")
 	if err != nil {
-		respondBadRequest(c, "Invalid todo ID", nil)
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
 		return
 	}
 
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	err = h.todoService.DeleteTodo(ctx, dto.DeleteTodoRequest{
+	response, err := h.todoService.DeleteTodo(ctx, dto.DeleteTodoRequest{
 		ID:     id,
 		UserID: userID,
 	})
@@ -313,21 +789,465 @@ func (h *TodoHandler) DeleteTodo(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Todo deleted successfully",
+		"data":    response,
+	})
+}
+
+// UndoDelete handles restoring a just-deleted todo from its undo token HTTP request
+func (h *TodoHandler) UndoDelete(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var input UndoDeleteInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	todo, err := h.todoService.UndoDelete(ctx, dto.UndoDeleteRequest{
+		UserID:    userID,
+		UndoToken: input.UndoToken,
+	})
+	if h.handleTodoError(c, ctx, err, "undo delete", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Todo restored successfully",
+		"data":    todo,
+	})
+}
+
+// RestoreAllTodos handles restoring every trashed todo HTTP request, optionally limited with ?category_id=
+func (h *TodoHandler) RestoreAllTodos(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	categoryID, err := parseQueryInt(c, "category_id", 0, 1)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	restored, err := h.todoService.RestoreAllTodos(ctx, userID, uint(categoryID))
+	if h.handleTodoError(c, ctx, err, "restore trashed todos", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Trashed todos restored successfully",
+		"data": gin.H{
+			"restored": restored,
+		},
+	})
+}
+
+// BulkMoveTodos handles moving the todos of one category that match a filter into another HTTP request
+func (h *TodoHandler) BulkMoveTodos(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var input BulkMoveTodosInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	moved, err := h.todoService.BulkMoveTodos(ctx, dto.BulkMoveTodosRequest{
+		UserID:         userID,
+		FromCategoryID: input.FromCategoryID,
+		ToCategoryID:   input.ToCategoryID,
+		Completed:      input.Filter.Completed,
+	})
+	if h.handleTodoError(c, ctx, err, "bulk move todos", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Todos moved successfully",
+		"data": gin.H{
+			"moved": moved,
+		},
+	})
+}
+
+// TouchTodo bumps a todo's updated_at without changing it HTTP request
+func (h *TodoHandler) TouchTodo(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	todo, err := h.todoService.TouchTodo(ctx, dto.GetTodoRequest{
+		ID:     id,
+		UserID: userID,
+	})
+	if h.handleTodoError(c, ctx, err, "touch todo", userID, id) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Todo touched successfully",
+		"data":    todo,
+	})
+}
+
+// GetTodoPermissions returns the caller's effective actions on a todo HTTP request
+func (h *TodoHandler) GetTodoPermissions(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	permissions, err := h.todoService.GetTodoPermissions(ctx, dto.GetTodoRequest{
+		ID:     id,
+		UserID: userID,
+	})
+	if h.handleTodoError(c, ctx, err, "fetch todo permissions", userID, id) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Todo permissions retrieved successfully",
+		"data":    permissions,
+	})
+}
+
+// GetTodoHistory retrieves the change history of a todo HTTP request
+func (h *TodoHandler) GetTodoHistory(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid todo ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	// Parse pagination params (the service caps the page size)
+	page, pageSize, err := parsePagination(c, h.pagination)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	response, err := h.todoService.GetTodoHistory(ctx, dto.GetTodoRequest{
+		ID:     id,
+		UserID: userID,
+	}, page, pageSize)
+	if h.handleTodoError(c, ctx, err, "fetch todo history", userID, id) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":           true,
+		"message":           "Todo history retrieved successfully",
+		"data":              response.Events,
+		"count":             len(response.Events),
+		"total":             response.Total,
+		"page":              response.Page,
+		"page_size":         response.PageSize,
+		"page_size_clamped": pageSize > response.PageSize,
+		"total_pages":       response.TotalPages,
 	})
 }
 
-// GetTodosGroupedByCategory retrieves all accessible todos grouped by category
+// CleanupTodos soft deletes the caller's todos completed longer ago than ?older_than
+// Runs as a dry run (count only) unless ?dry_run=false is passed
+func (h *TodoHandler) CleanupTodos(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	olderThan, err := utils.ParseDuration(c.Query("older_than"))
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, "older_than must be a positive duration such as 30d or 24h", nil)
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, "dry_run must be true or false", nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	count, err := h.todoService.CleanupCompletedTodos(ctx, dto.CleanupTodosRequest{
+		UserID:    userID,
+		OlderThan: olderThan,
+		DryRun:    dryRun,
+	})
+	if h.handleTodoError(c, ctx, err, "clean up todos", userID, 0) {
+		return
+	}
+
+	message := "Completed todos cleaned up successfully"
+	if dryRun {
+		message = "Dry run: no todos were deleted"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data": gin.H{
+			"count":   count,
+			"dry_run": dryRun,
+		},
+	})
+}
+
+// GetCompletionReport handles the per-day completion report HTTP request (?from=YYYY-MM-DD&to=YYYY-MM-DD)
+func (h *TodoHandler) GetCompletionReport(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	from, err := time.Parse(time.DateOnly, c.Query("from"))
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, "from must be a date in YYYY-MM-DD format", nil)
+		return
+	}
+	to, err := time.Parse(time.DateOnly, c.Query("to"))
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, "to must be a date in YYYY-MM-DD format", nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	report, err := h.todoService.GetCompletionReport(ctx, dto.CompletionReportRequest{
+		UserID: userID,
+		From:   from,
+		To:     to,
+	})
+	if h.handleTodoError(c, ctx, err, "build completion report", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Completion report retrieved successfully",
+		"data": gin.H{
+			"from":  report.From.Format(time.DateOnly),
+			"to":    report.To.Format(time.DateOnly),
+			"total": report.Total,
+			"days":  report.Days,
+		},
+	})
+}
+
+// GetRecentTodos lists the user's most recently updated todos across categories HTTP request
+// ?limit=N sets how many are returned (default 20, capped by the service)
+func (h *TodoHandler) GetRecentTodos(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	limit, err := parseQueryInt(c, "limit", services.DefaultRecentTodos, 1)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	todos, err := h.todoService.GetRecentTodos(ctx, userID, limit)
+	if h.handleTodoError(c, ctx, err, "fetch recent todos", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Recent todos retrieved successfully",
+		"data":    todos,
+		"count":   len(todos),
+	})
+}
+
+// GetTodoIDs handles listing the IDs of todos changed since ?since= (RFC 3339, omitted for every todo) HTTP request
+// Deleted todos are reported as tombstones so sync clients can drop their local copies
+func (h *TodoHandler) GetTodoIDs(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	var since time.Time
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondBadRequest(c, CodeInvalidQueryParameter, "since must be an RFC 3339 timestamp", nil)
+			return
+		}
+		since = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	changes, err := h.todoService.GetTodoChanges(ctx, userID, since)
+	if h.handleTodoError(c, ctx, err, "fetch todo ids", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Todo IDs retrieved successfully",
+		"data":    changes,
+		"count":   len(changes),
+	})
+}
+
+// SyncTodos handles the delta-sync HTTP request: the todos changed since ?cursor=, omitted for a full sync
+func (h *TodoHandler) SyncTodos(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	changes, err := h.todoService.SyncTodos(ctx, userID, c.Query("cursor"))
+	if h.handleTodoError(c, ctx, err, "sync todos", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Todo changes retrieved successfully",
+		"data": gin.H{
+			"created":     changes.Created,
+			"updated":     changes.Updated,
+			"deleted":     changes.Deleted,
+			"next_cursor": changes.NextCursor,
+		},
+	})
+}
+
+// CompleteAllInCategory marks every todo in a category as completed HTTP request
+func (h *TodoHandler) CompleteAllInCategory(c *gin.Context) {
+	categoryID, err := parseIDParam(c, "id")
+	if err != nil {
+		respondBadRequest(c, CodeInvalidID, "Invalid category ID", nil)
+		return
+	}
+
+	userID, ok := getUserID(c)
+	if !ok {
+		respondMissingUser(c)
+		return
+	}
+
+	// The body is optional; an empty body completes every todo
+	var input CompleteAllInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			respondBadRequest(c, CodeValidationFailed, "Validation failed", err)
+			return
+		}
+	}
+	completed := input.Completed == nil || *input.Completed
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	count, err := h.todoService.CompleteAllInCategory(ctx, userID, categoryID, completed)
+	if h.handleTodoError(c, ctx, err, "update category todos", userID, 0) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Todos updated successfully",
+		"data": gin.H{
+			"updated":   count,
+			"completed": completed,
+		},
+	})
+}
+
+// GetTodosGroupedByCategory retrieves accessible todos grouped by category
+// Optional ?scope=owned|shared|all (default all) limits which categories are included, and
+// ?per_category=N returns only the newest N todos of each category with a next_cursor for the rest
 func (h *TodoHandler) GetTodosGroupedByCategory(c *gin.Context) {
 	userID, ok := getUserID(c)
 	if !ok {
-		respondUnauthorized(c)
+		respondMissingUser(c)
+		return
+	}
+
+	perCategory, err := parseQueryInt(c, "per_category", 0, 1)
+	if err != nil {
+		respondBadRequest(c, CodeInvalidQueryParameter, err.Error(), nil)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	response, err := h.todoService.GetTodosGroupedByCategory(ctx, userID)
+	response, err := h.todoService.GetTodosGroupedByCategory(ctx, userID, c.Query("scope"), perCategory)
 	if h.handleTodoError(c, ctx, err, "fetch todos by category", userID, 0) {
 		return
 	}
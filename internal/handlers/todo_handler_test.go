@@ -7,7 +7,11 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"todo-app/internal/dto"
 	"todo-app/internal/models"
@@ -72,6 +76,32 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedMsg:    "either category or category_id is required",
 		},
+		{
+			name: "validation error - both category and category_id",
+			requestBody: map[string]interface{}{
+				"title":       "Test Todo",
+				"category":    "Work",
+				"category_id": 1,
+			},
+			userID:         1,
+			mockFunc:       nil,
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "category and category_id cannot both be set",
+		},
+		{
+			name: "category_id of zero with a category name",
+			requestBody: map[string]interface{}{
+				"title":       "Test Todo",
+				"category":    "Work",
+				"category_id": 0,
+			},
+			userID: 1,
+			mockFunc: func(ctx context.Context, req dto.CreateTodoRequest) (*models.Todo, error) {
+				return &models.Todo{ID: 1, Title: req.Title, CategoryID: 1, UserID: req.UserID}, nil
+			},
+			expectedStatus: http.StatusCreated,
+			expectedMsg:    "Todo created successfully",
+		},
 		{
 			name: "service error",
 			requestBody: map[string]interface{}{
@@ -85,6 +115,19 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			expectedMsg:    "Failed to create todo",
 		},
+		{
+			name: "duplicate title",
+			requestBody: map[string]interface{}{
+				"title":       "Test Todo",
+				"category_id": 1,
+			},
+			userID: 1,
+			mockFunc: func(ctx context.Context, req dto.CreateTodoRequest) (*models.Todo, error) {
+				return nil, services.ErrDuplicateTodo
+			},
+			expectedStatus: http.StatusConflict,
+			expectedMsg:    "A todo with this title already exists in this category",
+		},
 		{
 			name: "validation error - whitespace only title",
 			requestBody: map[string]interface{}{
@@ -111,129 +154,893 @@ func TestTodoHandler_CreateTodo(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockService := &mocks.MockTodoService{
-				CreateTodoFunc: tt.mockFunc,
-			}
-			handler := NewTodoHandler(mockService)
-
+			mockService := &mocks.MockTodoService{
+				CreateTodoFunc: tt.mockFunc,
+			}
+			handler := NewTodoHandler(mockService, 1, testPagination)
+
+			router := gin.New()
+			router.POST("/todos", func(c *gin.Context) {
+				c.Set("userID", tt.userID)
+				handler.CreateTodo(c)
+			})
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("CreateTodo() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+
+			var response map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &response)
+
+			if msg, ok := response["message"].(string); ok {
+				if msg != tt.expectedMsg {
+					t.Errorf("CreateTodo() message = %v, want %v", msg, tt.expectedMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoHandler_CreateTodo_Location(t *testing.T) {
+	mockService := &mocks.MockTodoService{
+		CreateTodoFunc: func(ctx context.Context, req dto.CreateTodoRequest) (*models.Todo, error) {
+			return &models.Todo{ID: 7, Title: req.Title, CategoryID: 1, UserID: req.UserID}, nil
+		},
+	}
+	handler := NewTodoHandler(mockService, 1, testPagination)
+
+	router := gin.New()
+	router.POST("/todos", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		handler.CreateTodo(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/todos", bytes.NewBufferString(`{"title":"Test Todo","category_id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CreateTodo() status = %v, want %v", w.Code, http.StatusCreated)
+	}
+	if got := w.Header().Get("Location"); got != "/api/todos/7" {
+		t.Errorf("CreateTodo() Location = %q, want %q", got, "/api/todos/7")
+	}
+}
+
+func TestTodoHandler_BatchGetTodos(t *testing.T) {
+	tooMany := make([]uint, 101)
+	for i := range tooMany {
+		tooMany[i] = uint(i + 1)
+	}
+
+	tests := []struct {
+		name           string
+		ids            []uint
+		expectedStatus int
+	}{
+		{name: "readable and forbidden", ids: []uint{1, 2}, expectedStatus: http.StatusOK},
+		{name: "no ids", ids: []uint{}, expectedStatus: http.StatusBadRequest},
+		{name: "more than 100 ids", ids: tooMany, expectedStatus: http.StatusBadRequest},
+		{name: "zero id", ids: []uint{0}, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockTodoService{
+				GetTodosByIDsFunc: func(ctx context.Context, userID uint, ids []uint) (*dto.BatchGetTodosResponse, error) {
+					return &dto.BatchGetTodosResponse{
+						Todos:     []models.Todo{{ID: 1}},
+						NotFound:  []uint{},
+						Forbidden: []uint{2},
+					}, nil
+				},
+			}
+			handler := NewTodoHandler(mockService, 1, testPagination)
+
+			router := gin.New()
+			router.POST("/todos/batch-get", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.BatchGetTodos(c)
+			})
+
+			body, _ := json.Marshal(map[string]interface{}{"ids": tt.ids})
+			req, _ := http.NewRequest(http.MethodPost, "/todos/batch-get", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("BatchGetTodos() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data      []models.Todo `json:"data"`
+				NotFound  []uint        `json:"not_found"`
+				Forbidden []uint        `json:"forbidden"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if len(response.Data) != 1 || response.Data[0].ID != 1 {
+				t.Errorf("BatchGetTodos() data = %+v, want todo 1", response.Data)
+			}
+			if len(response.Forbidden) != 1 || response.Forbidden[0] != 2 || response.NotFound == nil {
+				t.Errorf("BatchGetTodos() forbidden = %v, not_found = %v, want [2] and []", response.Forbidden, response.NotFound)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_CreateTodo_ValidationDetails(t *testing.T) {
+	handler := NewTodoHandler(&mocks.MockTodoService{}, 1, testPagination)
+
+	router := gin.New()
+	router.POST("/todos", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		handler.CreateTodo(c)
+	})
+
+	body, _ := json.Marshal(map[string]any{
+		"title":    strings.Repeat("a", 256),
+		"category": "Work",
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("CreateTodo() status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	var response struct {
+		Code   string       `json:"code"`
+		Errors []FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Code != CodeValidationFailed {
+		t.Errorf("CreateTodo() code = %q, want %q", response.Code, CodeValidationFailed)
+	}
+	want := FieldError{Field: "title", Rule: "max", Message: "title must be at most 255 characters"}
+	if len(response.Errors) != 1 || response.Errors[0] != want {
+		t.Errorf("CreateTodo() errors = %+v, want [%+v]", response.Errors, want)
+	}
+}
+
+func TestTodoHandler_CreateTodo_ConfiguredLengthLimit(t *testing.T) {
+	mockService := &mocks.MockTodoService{
+		CreateTodoFunc: func(ctx context.Context, req dto.CreateTodoRequest) (*models.Todo, error) {
+			return nil, &services.FieldTooLongError{Field: "title", Max: 50}
+		},
+	}
+	handler := NewTodoHandler(mockService, 1, testPagination)
+
+	router := gin.New()
+	router.POST("/todos", func(c *gin.Context) {
+		c.Set("userID", uint(1))
+		handler.CreateTodo(c)
+	})
+
+	body, _ := json.Marshal(map[string]any{
+		"title":    strings.Repeat("a", 51),
+		"category": "Work",
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("CreateTodo() status = %v, want %v", w.Code, http.StatusBadRequest)
+	}
+
+	var response struct {
+		Code   string       `json:"code"`
+		Errors []FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Code != CodeValidationFailed {
+		t.Errorf("CreateTodo() code = %q, want %q", response.Code, CodeValidationFailed)
+	}
+	want := FieldError{Field: "title", Rule: "max", Message: "title must be at most 50 characters"}
+	if len(response.Errors) != 1 || response.Errors[0] != want {
+		t.Errorf("CreateTodo() errors = %+v, want [%+v]", response.Errors, want)
+	}
+}
+
+func TestTodoInput_Validate_MinTitleRunes(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		minRunes int
+		wantErr  bool
+	}{
+		{name: "single emoji at default minimum", title: "🎉", minRunes: 1},
+		{name: "multibyte title at minimum", title: "日本語", minRunes: 3},
+		{name: "multibyte title below minimum", title: "日本", minRunes: 3, wantErr: true},
+		{name: "emoji below minimum despite byte length", title: "🎉🎉", minRunes: 3, wantErr: true},
+		{name: "surrounding whitespace not counted", title: "  🎉🎉🎉  ", minRunes: 3},
+		{name: "padded title below minimum", title: "  ab  ", minRunes: 3, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryID := uint(1)
+			title := tt.title
+
+			create := CreateTodoInput{Title: title, CategoryID: &categoryID}
+			if err := create.Validate(tt.minRunes); (err != nil) != tt.wantErr {
+				t.Errorf("CreateTodoInput.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			update := UpdateTodoInput{Title: &title}
+			if err := update.Validate(tt.minRunes); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateTodoInput.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			replace := ReplaceTodoInput{Title: &title, CategoryID: &categoryID}
+			if err := replace.Validate(tt.minRunes); (err != nil) != tt.wantErr {
+				t.Errorf("ReplaceTodoInput.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_GetTodos(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         uint
+		queryParams    string
+		mockFunc       func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error)
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name:        "successful retrieval",
+			userID:      1,
+			queryParams: "",
+			mockFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error) {
+				// Absent params mean the first page at the configured default size
+				if page != 1 || pageSize != 10 {
+					t.Errorf("Expected page=1, pageSize=10, got page=%d, pageSize=%d", page, pageSize)
+				}
+				return &dto.TodoListResponse{
+					Todos: []models.Todo{
+						{ID: 1, Title: "Todo 1", CategoryID: 1, UserID: userID},
+						{ID: 2, Title: "Todo 2", CategoryID: 2, UserID: userID},
+					},
+					Total:      2,
+					Page:       page,
+					PageSize:   pageSize,
+					TotalPages: 1,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:        "with pagination",
+			userID:      1,
+			queryParams: "?page=1&page_size=5",
+			mockFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error) {
+				if page != 1 || pageSize != 5 {
+					t.Errorf("Expected page=1, pageSize=5, got page=%d, pageSize=%d", page, pageSize)
+				}
+				return &dto.TodoListResponse{
+					Todos: []models.Todo{
+						{ID: 1, Title: "Todo 1", CategoryID: 1, UserID: userID},
+					},
+					Total:      10,
+					Page:       page,
+					PageSize:   pageSize,
+					TotalPages: 2,
+				}, nil
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "non-numeric page",
+			userID:         1,
+			queryParams:    "?page=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "zero page",
+			userID:         1,
+			queryParams:    "?page=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "negative page size",
+			userID:         1,
+			queryParams:    "?page_size=-5",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "service error",
+			userID:      1,
+			queryParams: "",
+			mockFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error) {
+				return nil, errors.New("database error")
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFunc := tt.mockFunc
+			if mockFunc == nil {
+				mockFunc = func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error) {
+					t.Error("GetTodos service should not be called for invalid pagination")
+					return nil, errors.New("unexpected call")
+				}
+			}
+			mockService := &mocks.MockTodoService{
+				GetTodosFunc: mockFunc,
+			}
+			handler := NewTodoHandler(mockService, 1, testPagination)
+
+			router := gin.New()
+			router.GET("/todos", func(c *gin.Context) {
+				c.Set("userID", tt.userID)
+				handler.GetTodos(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/todos"+tt.queryParams, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("GetTodos() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var response map[string]interface{}
+				json.Unmarshal(w.Body.Bytes(), &response)
+
+				count := int(response["count"].(float64))
+				if count != tt.expectedCount {
+					t.Errorf("GetTodos() count = %v, want %v", count, tt.expectedCount)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoHandler_GetTodos_PageSizeClamped(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		wantSize    int
+		wantClamped bool
+	}{
+		{name: "within the maximum", query: "?page_size=50", wantSize: 50, wantClamped: false},
+		{name: "default page size", query: "", wantSize: 10, wantClamped: false},
+		{name: "oversized", query: "?page_size=1000", wantSize: 100, wantClamped: true},
+	}
+
+	// The service applies the default and caps page_size at a maximum of 100
+	mockService := &mocks.MockTodoService{
+		GetTodosFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error) {
+			if pageSize < 1 {
+				pageSize = 10
+			}
+			return &dto.TodoListResponse{Todos: []models.Todo{}, Page: page, PageSize: min(pageSize, 100)}, nil
+		},
+	}
+	handler := NewTodoHandler(mockService, 1, testPagination)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/todos", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.GetTodos(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/todos"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("GetTodos() status = %v, want %v", w.Code, http.StatusOK)
+			}
+
+			var response struct {
+				PageSize        int  `json:"page_size"`
+				PageSizeClamped bool `json:"page_size_clamped"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if response.PageSize != tt.wantSize || response.PageSizeClamped != tt.wantClamped {
+				t.Errorf("GetTodos() page_size = %d, page_size_clamped = %v, want %d and %v",
+					response.PageSize, response.PageSizeClamped, tt.wantSize, tt.wantClamped)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_GetTodos_CategoryFilter(t *testing.T) {
+	// User 1 can read categories 1 and 2; category 3 belongs to someone else and 4 does not exist
+	todos := []models.Todo{
+		{ID: 1, Title: "Work task", CategoryID: 1},
+		{ID: 2, Title: "Home task", CategoryID: 2},
+		{ID: 3, Title: "Other work task", CategoryID: 1},
+	}
+	mockService := &mocks.MockTodoService{
+		GetTodosFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error) {
+			return &dto.TodoListResponse{Todos: todos, Total: int64(len(todos)), Page: page, PageSize: 10}, nil
+		},
+		GetTodosByCategoryIDFunc: func(ctx context.Context, req dto.ListCategoryTodosRequest, page, pageSize int) (*dto.TodoListResponse, error) {
+			switch req.CategoryID {
+			case 3:
+				return nil, services.ErrForbidden
+			case 4:
+				return nil, services.ErrCategoryNotFound
+			}
+			var matched []models.Todo
+			for _, todo := range todos {
+				if todo.CategoryID == req.CategoryID {
+					matched = append(matched, todo)
+				}
+			}
+			return &dto.TodoListResponse{Todos: matched, Total: int64(len(matched)), Page: page, PageSize: 10}, nil
+		},
+	}
+	handler := NewTodoHandler(mockService, 1, testPagination)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		wantIDs        []uint
+	}{
+		{name: "no filter", query: "", expectedStatus: http.StatusOK, wantIDs: []uint{1, 2, 3}},
+		{name: "one category", query: "?category_id=1", expectedStatus: http.StatusOK, wantIDs: []uint{1, 3}},
+		{name: "inaccessible category", query: "?category_id=3", expectedStatus: http.StatusForbidden},
+		{name: "unknown category", query: "?category_id=4", expectedStatus: http.StatusNotFound},
+		{name: "invalid category id", query: "?category_id=abc", expectedStatus: http.StatusBadRequest},
+		{name: "zero category id", query: "?category_id=0", expectedStatus: http.StatusBadRequest},
+		{name: "with sort_by", query: "?category_id=1&sort_by=title:asc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/todos", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.GetTodos(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/todos"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("GetTodos() status = %v, want %v body=%s", w.Code, tt.expectedStatus, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data []models.Todo `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			var gotIDs []uint
+			for _, todo := range response.Data {
+				gotIDs = append(gotIDs, todo.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("GetTodos() ids = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_GetTodos_MultipleCategories(t *testing.T) {
+	// User 1 can read categories 1 and 2; category 3 belongs to someone else
+	todos := []models.Todo{
+		{ID: 1, Title: "Work task", CategoryID: 1},
+		{ID: 2, Title: "Home task", CategoryID: 2},
+		{ID: 3, Title: "Private task", CategoryID: 3},
+	}
+	var gotCategoryIDs []uint
+	mockService := &mocks.MockTodoService{
+		GetTodosInCategoriesFunc: func(ctx context.Context, userID uint, categoryIDs []uint, page, pageSize int) (*dto.TodoListResponse, error) {
+			gotCategoryIDs = categoryIDs
+			var matched []models.Todo
+			for _, todo := range todos {
+				for _, id := range categoryIDs {
+					if todo.CategoryID == id && id != 3 {
+						matched = append(matched, todo)
+					}
+				}
+			}
+			return &dto.TodoListResponse{Todos: matched, Total: int64(len(matched)), Page: page, PageSize: 10}, nil
+		},
+	}
+	handler := NewTodoHandler(mockService, 1, testPagination)
+
+	tooMany := make([]string, maxCategoryFilterIDs+1)
+	for i := range tooMany {
+		tooMany[i] = strconv.Itoa(i + 1)
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		expectedStatus  int
+		wantCategoryIDs []uint
+		wantIDs         []uint
+	}{
+		{name: "accessible and inaccessible", query: "?category_ids=1,2,3", expectedStatus: http.StatusOK, wantCategoryIDs: []uint{1, 2, 3}, wantIDs: []uint{1, 2}},
+		{name: "repeated ids are kept once", query: "?category_ids=2,%202,1", expectedStatus: http.StatusOK, wantCategoryIDs: []uint{2, 1}, wantIDs: []uint{1, 2}},
+		{name: "unparsable id", query: "?category_ids=1,abc", expectedStatus: http.StatusBadRequest},
+		{name: "zero id", query: "?category_ids=0", expectedStatus: http.StatusBadRequest},
+		{name: "empty list", query: "?category_ids=", expectedStatus: http.StatusBadRequest},
+		{name: "too many ids", query: "?category_ids=" + strings.Join(tooMany, ","), expectedStatus: http.StatusBadRequest},
+		{name: "with category_id", query: "?category_ids=1,2&category_id=1", expectedStatus: http.StatusBadRequest},
+		{name: "with sort_by", query: "?category_ids=1,2&sort_by=title:asc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCategoryIDs = nil
+			router := gin.New()
+			router.GET("/todos", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.GetTodos(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/todos"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("GetTodos() status = %v, want %v body=%s", w.Code, tt.expectedStatus, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if !reflect.DeepEqual(gotCategoryIDs, tt.wantCategoryIDs) {
+				t.Errorf("GetTodosInCategories() category ids = %v, want %v", gotCategoryIDs, tt.wantCategoryIDs)
+			}
+
+			var response struct {
+				Data []models.Todo `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			var gotIDs []uint
+			for _, todo := range response.Data {
+				gotIDs = append(gotIDs, todo.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("GetTodos() ids = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_CountTodos(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		wantCompleted  *bool
+	}{
+		{name: "all todos", query: "", expectedStatus: http.StatusOK},
+		{name: "open todos", query: "?completed=false", expectedStatus: http.StatusOK, wantCompleted: new(bool)},
+		{name: "invalid completed", query: "?completed=maybe", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCompleted *bool
+			mockService := &mocks.MockTodoService{
+				CountTodosFunc: func(ctx context.Context, userID uint, completed *bool) (int64, error) {
+					gotCompleted = completed
+					return 3, nil
+				},
+			}
+			handler := NewTodoHandler(mockService, 1, testPagination)
+
+			router := gin.New()
+			router.GET("/todos/count", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.CountTodos(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/todos/count"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("CountTodos() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if (gotCompleted == nil) != (tt.wantCompleted == nil) || (gotCompleted != nil && *gotCompleted != *tt.wantCompleted) {
+				t.Errorf("CountTodos() completed filter = %v, want %v", gotCompleted, tt.wantCompleted)
+			}
+
+			// The badge response carries only the number, never todo bodies
+			var response struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if len(response.Data) != 1 || response.Data["count"] != float64(3) {
+				t.Errorf("CountTodos() data = %v, want only {count: 3}", response.Data)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_GetTodoIDs(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		wantSince      time.Time
+	}{
+		{name: "every todo", query: "", expectedStatus: http.StatusOK},
+		{name: "since timestamp", query: "?since=2024-03-01T10:00:00Z", expectedStatus: http.StatusOK, wantSince: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "date only", query: "?since=2024-03-01", expectedStatus: http.StatusBadRequest},
+		{name: "not a time", query: "?since=yesterday", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSince time.Time
+			mockService := &mocks.MockTodoService{
+				GetTodoChangesFunc: func(ctx context.Context, userID uint, since time.Time) ([]models.TodoChange, error) {
+					gotSince = since
+					return []models.TodoChange{{ID: 4, UpdatedAt: since}, {ID: 7, UpdatedAt: since, Deleted: true}}, nil
+				},
+			}
+			handler := NewTodoHandler(mockService, 1, testPagination)
+
+			router := gin.New()
+			router.GET("/todos/ids", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.GetTodoIDs(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/todos/ids"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("GetTodoIDs() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			if !gotSince.Equal(tt.wantSince) {
+				t.Errorf("GetTodoIDs() since = %v, want %v", gotSince, tt.wantSince)
+			}
+
+			var response struct {
+				Data  []map[string]interface{} `json:"data"`
+				Count int                      `json:"count"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if response.Count != 2 || len(response.Data) != 2 || response.Data[1]["deleted"] != true {
+				t.Errorf("GetTodoIDs() response = %+v, want 2 entries with the second a tombstone", response)
+			}
+			// Only the id, change time and tombstone flag are sent
+			if len(response.Data[0]) != 3 {
+				t.Errorf("GetTodoIDs() entry = %v, want only id, updated_at and deleted", response.Data[0])
+			}
+		})
+	}
+}
+
+func TestTodoHandler_CountCategoryTodos(t *testing.T) {
+	// Category 1 holds two completed and three open todos; 2 is someone else's and 3 does not exist
+	todos := []models.Todo{
+		{ID: 1, CategoryID: 1, Completed: true},
+		{ID: 2, CategoryID: 1},
+		{ID: 3, CategoryID: 1, Completed: true},
+		{ID: 4, CategoryID: 1},
+		{ID: 5, CategoryID: 1},
+	}
+	mockService := &mocks.MockTodoService{
+		CountCategoryTodosFunc: func(ctx context.Context, userID, categoryID uint) (*models.TodoStatusCounts, error) {
+			switch categoryID {
+			case 2:
+				return nil, services.ErrForbidden
+			case 3:
+				return nil, services.ErrCategoryNotFound
+			}
+			counts := &models.TodoStatusCounts{}
+			for _, todo := range todos {
+				counts.Total++
+				if todo.Completed {
+					counts.Completed++
+				} else {
+					counts.Open++
+				}
+			}
+			return counts, nil
+		},
+	}
+	handler := NewTodoHandler(mockService, 1, testPagination)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{name: "readable category", path: "/categories/1/count", expectedStatus: http.StatusOK},
+		{name: "inaccessible category", path: "/categories/2/count", expectedStatus: http.StatusForbidden},
+		{name: "unknown category", path: "/categories/3/count", expectedStatus: http.StatusNotFound},
+		{name: "invalid category id", path: "/categories/abc/count", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/categories/:id/count", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.CountCategoryTodos(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("CountCategoryTodos() status = %v, want %v body=%s", w.Code, tt.expectedStatus, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var response struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			want := map[string]interface{}{"total": float64(5), "completed": float64(2), "open": float64(3)}
+			if !reflect.DeepEqual(response.Data, want) {
+				t.Errorf("CountCategoryTodos() data = %v, want %v", response.Data, want)
+			}
+		})
+	}
+}
+
+func TestTodoHandler_GetCategoryTodos_Cursor(t *testing.T) {
+	mockService := &mocks.MockTodoService{
+		GetCategoryTodosAfterFunc: func(ctx context.Context, req dto.ListCategoryTodosRequest, cursor string, pageSize int) (*dto.TodoCursorPage, error) {
+			switch cursor {
+			case "":
+				return &dto.TodoCursorPage{Todos: []models.Todo{{ID: 3}, {ID: 2}}, PageSize: 2, NextCursor: "next"}, nil
+			case "next":
+				return &dto.TodoCursorPage{Todos: []models.Todo{{ID: 1}}, PageSize: 2}, nil
+			}
+			return nil, services.ErrInvalidCursor
+		},
+	}
+	handler := NewTodoHandler(mockService, 1, testPagination)
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedCode   string
+		wantNext       any
+	}{
+		{name: "first page", query: "?cursor=&page_size=2", expectedStatus: http.StatusOK, wantNext: "next"},
+		{name: "last page", query: "?cursor=next&page_size=2", expectedStatus: http.StatusOK, wantNext: nil},
+		{name: "invalid cursor", query: "?cursor=bogus", expectedStatus: http.StatusBadRequest, expectedCode: CodeInvalidCursor},
+		{name: "cursor with page", query: "?cursor=&page=2", expectedStatus: http.StatusBadRequest, expectedCode: CodeInvalidQueryParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
-			router.POST("/todos", func(c *gin.Context) {
-				c.Set("userID", tt.userID)
-				handler.CreateTodo(c)
+			router.GET("/categories/:id/todos", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.GetCategoryTodos(c)
 			})
 
-			body, _ := json.Marshal(tt.requestBody)
-			req, _ := http.NewRequest(http.MethodPost, "/todos", bytes.NewBuffer(body))
-			req.Header.Set("Content-Type", "application/json")
-
+			req, _ := http.NewRequest(http.MethodGet, "/categories/1/todos"+tt.query, nil)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
 			if w.Code != tt.expectedStatus {
-				t.Errorf("CreateTodo() status = %v, want %v", w.Code, tt.expectedStatus)
+				t.Fatalf("GetCategoryTodos() status = %v, want %v body=%s", w.Code, tt.expectedStatus, w.Body.String())
 			}
 
-			var response map[string]interface{}
+			var response map[string]any
 			json.Unmarshal(w.Body.Bytes(), &response)
-
-			if msg, ok := response["message"].(string); ok {
-				if msg != tt.expectedMsg {
-					t.Errorf("CreateTodo() message = %v, want %v", msg, tt.expectedMsg)
+			if tt.expectedCode != "" {
+				if code, _ := response["code"].(string); code != tt.expectedCode {
+					t.Errorf("GetCategoryTodos() code = %q, want %q", code, tt.expectedCode)
 				}
+				return
+			}
+			if next, ok := response["next_cursor"]; !ok || next != tt.wantNext {
+				t.Errorf("GetCategoryTodos() next_cursor = %v, want %v", next, tt.wantNext)
 			}
 		})
 	}
 }
 
-func TestTodoHandler_GetTodos(t *testing.T) {
+func TestTodoHandler_GetTodo_ExpandCategory(t *testing.T) {
 	tests := []struct {
 		name           string
-		userID         uint
-		queryParams    string
-		mockFunc       func(ctx context.Context, userID uint, page, pageSize int) (*dto.TodoListResponse, error)
+		query          string
 		expectedStatus int
-		expectedCount  int
+		wantCategory   bool
 	}{
-		{
-			name:        "successful retrieval",
-			userID:      1,
-			queryParams: "",
-			mockFunc: func(ctx context.Context, userID uint, page, pageSize int) (*dto.TodoListResponse, error) {
-				return &dto.TodoListResponse{
-					Todos: []models.Todo{
-						{ID: 1, Title: "Todo 1", CategoryID: 1, UserID: userID},
-						{ID: 2, Title: "Todo 2", CategoryID: 2, UserID: userID},
-					},
-					Total:      2,
-					Page:       page,
-					PageSize:   pageSize,
-					TotalPages: 1,
-				}, nil
-			},
-			expectedStatus: http.StatusOK,
-			expectedCount:  2,
-		},
-		{
-			name:        "with pagination",
-			userID:      1,
-			queryParams: "?page=1&page_size=5",
-			mockFunc: func(ctx context.Context, userID uint, page, pageSize int) (*dto.TodoListResponse, error) {
-				if page != 1 || pageSize != 5 {
-					t.Errorf("Expected page=1, pageSize=5, got page=%d, pageSize=%d", page, pageSize)
-				}
-				return &dto.TodoListResponse{
-					Todos: []models.Todo{
-						{ID: 1, Title: "Todo 1", CategoryID: 1, UserID: userID},
-					},
-					Total:      10,
-					Page:       page,
-					PageSize:   pageSize,
-					TotalPages: 2,
-				}, nil
-			},
-			expectedStatus: http.StatusOK,
-			expectedCount:  1,
-		},
-		{
-			name:        "service error",
-			userID:      1,
-			queryParams: "",
-			mockFunc: func(ctx context.Context, userID uint, page, pageSize int) (*dto.TodoListResponse, error) {
-				return nil, errors.New("database error")
-			},
-			expectedStatus: http.StatusInternalServerError,
-		},
+		{name: "category omitted by default", query: "", expectedStatus: http.StatusOK},
+		{name: "category expanded", query: "?expand=category", expectedStatus: http.StatusOK, wantCategory: true},
+		{name: "unknown expand", query: "?expand=owner", expectedStatus: http.StatusBadRequest},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := &mocks.MockTodoService{
-				GetTodosFunc: tt.mockFunc,
+				GetTodoByIDFunc: func(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error) {
+					return &models.Todo{ID: req.ID, Title: "Test Todo", CategoryID: 4, UserID: 1}, nil
+				},
+				ExpandTodosFunc: func(ctx context.Context, todos []models.Todo, expand dto.TodoExpand) ([]dto.ExpandedTodo, error) {
+					if !expand.Category {
+						t.Errorf("ExpandTodos() expand = %+v, want category", expand)
+					}
+					return []dto.ExpandedTodo{{Todo: todos[0], Category: &dto.CategoryBrief{ID: 4, Name: "Work"}}}, nil
+				},
 			}
-			handler := NewTodoHandler(mockService)
+			handler := NewTodoHandler(mockService, 1, testPagination)
 
 			router := gin.New()
-			router.GET("/todos", func(c *gin.Context) {
-				c.Set("userID", tt.userID)
-				handler.GetTodos(c)
+			router.GET("/todos/:id", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.GetTodo(c)
 			})
 
-			req, _ := http.NewRequest(http.MethodGet, "/todos"+tt.queryParams, nil)
+			req, _ := http.NewRequest(http.MethodGet, "/todos/1"+tt.query, nil)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
 			if w.Code != tt.expectedStatus {
-				t.Errorf("GetTodos() status = %v, want %v", w.Code, tt.expectedStatus)
+				t.Fatalf("GetTodo() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
 			}
 
-			if tt.expectedStatus == http.StatusOK {
-				var response map[string]interface{}
-				json.Unmarshal(w.Body.Bytes(), &response)
-
-				count := int(response["count"].(float64))
-				if count != tt.expectedCount {
-					t.Errorf("GetTodos() count = %v, want %v", count, tt.expectedCount)
-				}
+			var response struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			category, present := response.Data["category"].(map[string]interface{})
+			if present != tt.wantCategory {
+				t.Fatalf("GetTodo() category present = %v, want %v (data = %v)", present, tt.wantCategory, response.Data)
+			}
+			if tt.wantCategory && category["name"] != "Work" {
+				t.Errorf("GetTodo() category name = %v, want Work", category["name"])
+			}
+			if response.Data["title"] != "Test Todo" {
+				t.Errorf("GetTodo() title = %v, want the todo fields alongside the category", response.Data["title"])
 			}
 		})
 	}
@@ -246,6 +1053,7 @@ func TestTodoHandler_GetTodo(t *testing.T) {
 		userID         uint
 		mockFunc       func(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error)
 		expectedStatus int
+		expectedCode   string
 	}{
 		{
 			name:   "successful retrieval",
@@ -269,6 +1077,7 @@ func TestTodoHandler_GetTodo(t *testing.T) {
 				return nil, nil
 			},
 			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeInvalidID,
 		},
 		{
 			name:   "not found",
@@ -278,6 +1087,7 @@ func TestTodoHandler_GetTodo(t *testing.T) {
 				return nil, services.ErrTodoNotFound
 			},
 			expectedStatus: http.StatusNotFound,
+			expectedCode:   CodeTodoNotFound,
 		},
 		{
 			name:   "forbidden - different user",
@@ -287,6 +1097,30 @@ func TestTodoHandler_GetTodo(t *testing.T) {
 				return nil, services.ErrForbidden
 			},
 			expectedStatus: http.StatusForbidden,
+			expectedCode:   CodeTodoForbidden,
+		},
+		{
+			name:   "include deleted is passed to the service",
+			todoID: "1?include_deleted=true",
+			userID: 1,
+			mockFunc: func(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error) {
+				if !req.IncludeDeleted {
+					return nil, services.ErrTodoNotFound
+				}
+				deletedAt := time.Now()
+				return &models.Todo{ID: req.ID, CategoryID: 1, UserID: 1, DeletedAt: &deletedAt}, nil
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "invalid include deleted",
+			todoID: "1?include_deleted=maybe",
+			userID: 1,
+			mockFunc: func(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error) {
+				return nil, nil
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   CodeInvalidQueryParameter,
 		},
 	}
 
@@ -295,7 +1129,7 @@ func TestTodoHandler_GetTodo(t *testing.T) {
 			mockService := &mocks.MockTodoService{
 				GetTodoByIDFunc: tt.mockFunc,
 			}
-			handler := NewTodoHandler(mockService)
+			handler := NewTodoHandler(mockService, 1, testPagination)
 
 			router := gin.New()
 			router.GET("/todos/:id", func(c *gin.Context) {
@@ -310,6 +1144,91 @@ func TestTodoHandler_GetTodo(t *testing.T) {
 			if w.Code != tt.expectedStatus {
 				t.Errorf("GetTodo() status = %v, want %v", w.Code, tt.expectedStatus)
 			}
+
+			if tt.expectedCode != "" {
+				var response map[string]any
+				json.Unmarshal(w.Body.Bytes(), &response)
+				if code, _ := response["code"].(string); code != tt.expectedCode {
+					t.Errorf("GetTodo() code = %q, want %q", code, tt.expectedCode)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoHandler_GetTodoPermissions(t *testing.T) {
+	const ownerID, readerID = 1, 2
+
+	tests := []struct {
+		name           string
+		todoID         string
+		userID         uint
+		expectedStatus int
+		want           map[string]bool
+	}{
+		{
+			name:           "owner can do everything",
+			todoID:         "1",
+			userID:         ownerID,
+			expectedStatus: http.StatusOK,
+			want:           map[string]bool{"can_read": true, "can_write": true, "can_delete": true},
+		},
+		{
+			name:           "read-only sharer can only read",
+			todoID:         "1",
+			userID:         readerID,
+			expectedStatus: http.StatusOK,
+			want:           map[string]bool{"can_read": true, "can_write": false, "can_delete": false},
+		},
+		{
+			name:           "not found",
+			todoID:         "999",
+			userID:         ownerID,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	mockService := &mocks.MockTodoService{
+		GetTodoPermissionsFunc: func(ctx context.Context, req dto.GetTodoRequest) (*dto.TodoPermissions, error) {
+			if req.ID != 1 {
+				return nil, services.ErrTodoNotFound
+			}
+			if req.UserID == ownerID {
+				return &dto.TodoPermissions{CanRead: true, CanWrite: true, CanDelete: true}, nil
+			}
+			return &dto.TodoPermissions{CanRead: true}, nil
+		},
+	}
+	handler := NewTodoHandler(mockService, 1, testPagination)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/todos/:id/permissions", func(c *gin.Context) {
+				c.Set("userID", tt.userID)
+				handler.GetTodoPermissions(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/todos/"+tt.todoID+"/permissions", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("GetTodoPermissions() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if tt.want == nil {
+				return
+			}
+
+			var response struct {
+				Data map[string]bool `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			for key, want := range tt.want {
+				if got, ok := response.Data[key]; !ok || got != want {
+					t.Errorf("GetTodoPermissions() %s = %v, want %v", key, got, want)
+				}
+			}
 		})
 	}
 }
@@ -320,7 +1239,7 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 		todoID         string
 		userID         uint
 		requestBody    map[string]interface{}
-		updateFunc     func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error)
+		updateFunc     func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error)
 		expectedStatus int
 	}{
 		{
@@ -331,14 +1250,14 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 				"title":     "Updated Title",
 				"completed": true,
 			},
-			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error) {
+			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error) {
 				return &models.Todo{
 					ID:         req.ID,
 					Title:      *req.Title,
 					CategoryID: 1,
 					Completed:  *req.Completed,
 					UserID:     req.UserID,
-				}, nil
+				}, true, nil
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -349,7 +1268,7 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 			requestBody: map[string]interface{}{
 				"category_id": 2,
 			},
-			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error) {
+			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error) {
 				if req.CategoryID == nil || *req.CategoryID != 2 {
 					t.Errorf("Expected category_id to be 2, got %v", req.CategoryID)
 				}
@@ -358,7 +1277,7 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 					Title:      "Original Title",
 					CategoryID: *req.CategoryID,
 					UserID:     req.UserID,
-				}, nil
+				}, true, nil
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -372,7 +1291,7 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 				"category_id": 3,
 				"completed":   true,
 			},
-			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error) {
+			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error) {
 				if req.CategoryID == nil || *req.CategoryID != 3 {
 					t.Errorf("Expected category_id to be 3, got %v", req.CategoryID)
 				}
@@ -383,7 +1302,7 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 					CategoryID:  *req.CategoryID,
 					Completed:   *req.Completed,
 					UserID:      req.UserID,
-				}, nil
+				}, true, nil
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -394,8 +1313,8 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 			requestBody: map[string]interface{}{
 				"title": "Updated Title",
 			},
-			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error) {
-				return nil, services.ErrTodoNotFound
+			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error) {
+				return nil, false, services.ErrTodoNotFound
 			},
 			expectedStatus: http.StatusNotFound,
 		},
@@ -406,8 +1325,8 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 			requestBody: map[string]interface{}{
 				"title": "Updated Title",
 			},
-			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error) {
-				return nil, services.ErrForbidden
+			updateFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error) {
+				return nil, false, services.ErrForbidden
 			},
 			expectedStatus: http.StatusForbidden,
 		},
@@ -446,16 +1365,16 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 			mockService := &mocks.MockTodoService{
 				UpdateTodoFunc: tt.updateFunc,
 			}
-			handler := NewTodoHandler(mockService)
+			handler := NewTodoHandler(mockService, 1, testPagination)
 
 			router := gin.New()
-			router.PUT("/todos/:id", func(c *gin.Context) {
+			router.PATCH("/todos/:id", func(c *gin.Context) {
 				c.Set("userID", tt.userID)
 				handler.UpdateTodo(c)
 			})
 
 			body, _ := json.Marshal(tt.requestBody)
-			req, _ := http.NewRequest(http.MethodPut, "/todos/"+tt.todoID, bytes.NewBuffer(body))
+			req, _ := http.NewRequest(http.MethodPatch, "/todos/"+tt.todoID, bytes.NewBuffer(body))
 			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
@@ -468,20 +1387,139 @@ func TestTodoHandler_UpdateTodo(t *testing.T) {
 	}
 }
 
+func TestTodoHandler_UpdateTodo_NotModified(t *testing.T) {
+	for _, changed := range []bool{true, false} {
+		mockService := &mocks.MockTodoService{
+			UpdateTodoFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error) {
+				return &models.Todo{ID: req.ID, Title: *req.Title}, changed, nil
+			},
+		}
+		handler := NewTodoHandler(mockService, 1, testPagination)
+
+		router := gin.New()
+		router.PATCH("/todos/:id", func(c *gin.Context) {
+			c.Set("userID", uint(1))
+			handler.UpdateTodo(c)
+		})
+
+		req, _ := http.NewRequest(http.MethodPatch, "/todos/1", strings.NewReader(`{"title":"Same"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("UpdateTodo() status = %v, want %v", w.Code, http.StatusOK)
+		}
+		var response map[string]any
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if notModified, _ := response["not_modified"].(bool); notModified != !changed {
+			t.Errorf("UpdateTodo() not_modified = %v, want %v", response["not_modified"], !changed)
+		}
+	}
+}
+
+func TestTodoHandler_PutVsPatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		requestBody    map[string]interface{}
+		wantReplace    bool
+		expectedStatus int
+	}{
+		{
+			name:           "PATCH with omitted description",
+			method:         http.MethodPatch,
+			requestBody:    map[string]interface{}{"title": "New title", "category_id": 1},
+			wantReplace:    false,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "PUT with omitted description",
+			method:         http.MethodPut,
+			requestBody:    map[string]interface{}{"title": "New title", "category_id": 1},
+			wantReplace:    true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "PATCH with only description",
+			method:         http.MethodPatch,
+			requestBody:    map[string]interface{}{"description": "Just this"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "PUT without title",
+			method:         http.MethodPut,
+			requestBody:    map[string]interface{}{"description": "Just this", "category_id": 1},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "PUT without category_id",
+			method:         http.MethodPut,
+			requestBody:    map[string]interface{}{"title": "New title"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *dto.UpdateTodoRequest
+			mockService := &mocks.MockTodoService{
+				UpdateTodoFunc: func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error) {
+					got = &req
+					return &models.Todo{ID: req.ID}, true, nil
+				},
+			}
+			handler := NewTodoHandler(mockService, 1, testPagination)
+
+			router := gin.New()
+			router.PUT("/todos/:id", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.ReplaceTodo(c)
+			})
+			router.PATCH("/todos/:id", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.UpdateTodo(c)
+			})
+
+			body, _ := json.Marshal(tt.requestBody)
+			req, _ := http.NewRequest(tt.method, "/todos/1", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("%s status = %v, want %v, body=%s", tt.method, w.Code, tt.expectedStatus, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				if got != nil {
+					t.Errorf("%s called the service despite a validation error", tt.method)
+				}
+				return
+			}
+			if got.Replace != tt.wantReplace {
+				t.Errorf("%s Replace = %v, want %v", tt.method, got.Replace, tt.wantReplace)
+			}
+			if _, ok := tt.requestBody["description"]; !ok && got.Description != nil {
+				t.Errorf("%s Description = %q, want nil for an omitted description", tt.method, *got.Description)
+			}
+		})
+	}
+}
+
 func TestTodoHandler_DeleteTodo(t *testing.T) {
 	tests := []struct {
 		name           string
 		todoID         string
 		userID         uint
-		deleteFunc     func(ctx context.Context, req dto.DeleteTodoRequest) error
+		deleteFunc     func(ctx context.Context, req dto.DeleteTodoRequest) (*dto.DeleteTodoResponse, error)
 		expectedStatus int
 	}{
 		{
 			name:   "successful deletion",
 			todoID: "1",
 			userID: 1,
-			deleteFunc: func(ctx context.Context, req dto.DeleteTodoRequest) error {
-				return nil
+			deleteFunc: func(ctx context.Context, req dto.DeleteTodoRequest) (*dto.DeleteTodoResponse, error) {
+				return &dto.DeleteTodoResponse{UndoToken: "undo"}, nil
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -489,8 +1527,8 @@ func TestTodoHandler_DeleteTodo(t *testing.T) {
 			name:   "not found",
 			todoID: "999",
 			userID: 1,
-			deleteFunc: func(ctx context.Context, req dto.DeleteTodoRequest) error {
-				return services.ErrTodoNotFound
+			deleteFunc: func(ctx context.Context, req dto.DeleteTodoRequest) (*dto.DeleteTodoResponse, error) {
+				return nil, services.ErrTodoNotFound
 			},
 			expectedStatus: http.StatusNotFound,
 		},
@@ -498,8 +1536,8 @@ func TestTodoHandler_DeleteTodo(t *testing.T) {
 			name:   "forbidden - different user",
 			todoID: "1",
 			userID: 2,
-			deleteFunc: func(ctx context.Context, req dto.DeleteTodoRequest) error {
-				return services.ErrForbidden
+			deleteFunc: func(ctx context.Context, req dto.DeleteTodoRequest) (*dto.DeleteTodoResponse, error) {
+				return nil, services.ErrForbidden
 			},
 			expectedStatus: http.StatusForbidden,
 		},
@@ -510,7 +1548,7 @@ func TestTodoHandler_DeleteTodo(t *testing.T) {
 			mockService := &mocks.MockTodoService{
 				DeleteTodoFunc: tt.deleteFunc,
 			}
-			handler := NewTodoHandler(mockService)
+			handler := NewTodoHandler(mockService, 1, testPagination)
 
 			router := gin.New()
 			router.DELETE("/todos/:id", func(c *gin.Context) {
@@ -528,3 +1566,47 @@ func TestTodoHandler_DeleteTodo(t *testing.T) {
 		})
 	}
 }
+
+func TestTodoHandler_CompleteAllInCategory(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		serviceErr     error
+		wantCompleted  bool
+		expectedStatus int
+	}{
+		{name: "empty body completes todos", wantCompleted: true, expectedStatus: http.StatusOK},
+		{name: "reopen todos", body: `{"completed":false}`, wantCompleted: false, expectedStatus: http.StatusOK},
+		{name: "read-only share", serviceErr: services.ErrNoWritePermission, wantCompleted: true, expectedStatus: http.StatusForbidden},
+		{name: "category not found", serviceErr: services.ErrCategoryNotFound, wantCompleted: true, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mocks.MockTodoService{
+				CompleteAllInCategoryFunc: func(ctx context.Context, userID, categoryID uint, completed bool) (int64, error) {
+					if completed != tt.wantCompleted {
+						t.Errorf("CompleteAllInCategory() completed = %v, want %v", completed, tt.wantCompleted)
+					}
+					return 2, tt.serviceErr
+				},
+			}
+			handler := NewTodoHandler(mockService, 1, testPagination)
+
+			router := gin.New()
+			router.POST("/categories/:id/complete-all", func(c *gin.Context) {
+				c.Set("userID", uint(1))
+				handler.CompleteAllInCategory(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodPost, "/categories/1/complete-all", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("CompleteAllInCategory() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
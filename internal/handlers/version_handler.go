@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"net/http"
+
+	"todo-app/internal/version"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version reports which build is running
+func Version(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"version":    version.Version,
+		"commit":     version.Commit,
+		"build_time": version.BuildTime,
+	})
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestVersion(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/version", Version)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/version", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/version: expected 200, got %d", w.Code)
+	}
+
+	var response map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	// Without -ldflags the build falls back to the defaults
+	want := map[string]string{"version": "dev", "commit": "unknown", "build_time": "unknown"}
+	for field, value := range want {
+		if response[field] != value {
+			t.Errorf("%s = %q, want %q", field, response[field], value)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"todo-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenHeader carries the shared secret for admin endpoints
+const AdminTokenHeader = "X-Admin-Token"
+
+// RequireAdminToken only lets through requests whose X-Admin-Token matches token.
+// With no token configured the admin endpoints are disabled and respond 404.
+func RequireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Admin endpoints are disabled",
+			})
+			c.Abort()
+			return
+		}
+
+		provided := c.GetHeader(AdminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"message": "Invalid admin token",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AdminMiddleware only lets through users flagged as admins. It reads the user set by LoadUser,
+// so it must run after it.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("user")
+		user, ok := value.(*models.User)
+		if !exists || !ok {
+			// Only reachable when the route is missing LoadUser
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "User not available in request context",
+			})
+			c.Abort()
+			return
+		}
+
+		if !user.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"message": "Admin access required",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todo-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireAdminToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		configured     string
+		provided       string
+		expectedStatus int
+	}{
+		{name: "matching token", configured: "secret-token", provided: "secret-token", expectedStatus: http.StatusOK},
+		{name: "wrong token", configured: "secret-token", provided: "guess", expectedStatus: http.StatusForbidden},
+		{name: "missing token", configured: "secret-token", provided: "", expectedStatus: http.StatusForbidden},
+		{name: "disabled", configured: "", provided: "", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/admin", RequireAdminToken(tt.configured), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/admin", nil)
+			if tt.provided != "" {
+				req.Header.Set(AdminTokenHeader, tt.provided)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("RequireAdminToken() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		user           *models.User
+		expectedStatus int
+	}{
+		{name: "admin", user: &models.User{ID: 1, IsAdmin: true}, expectedStatus: http.StatusOK},
+		{name: "not an admin", user: &models.User{ID: 2}, expectedStatus: http.StatusForbidden},
+		{name: "route without LoadUser", expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached := false
+			router := gin.New()
+			router.GET("/admin/users", func(c *gin.Context) {
+				if tt.user != nil {
+					c.Set("user", tt.user)
+				}
+				c.Next()
+			}, AdminMiddleware(), func(c *gin.Context) {
+				reached = true
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("AdminMiddleware() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if reached != (tt.expectedStatus == http.StatusOK) {
+				t.Errorf("handler reached = %v, want %v", reached, tt.expectedStatus == http.StatusOK)
+			}
+		})
+	}
+}
@@ -1,25 +1,45 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
+	"todo-app/internal/models"
+	"todo-app/internal/services"
 	"todo-app/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT token and sets user ID in context
-func AuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
+// APITokenAuthenticator resolves a personal access token to its active token record, returning
+// services.ErrInvalidAPIToken when the token is unknown or revoked
+type APITokenAuthenticator interface {
+	AuthenticateToken(ctx context.Context, secret string) (*models.APIToken, error)
+}
+
+// AuthMiddleware validates a JWT or, when tokens is non-nil, a personal access token
+// and sets the user ID (and a token's scopes) in context. Without an Authorization header
+// the JWT is read from the auth cookie set by login in cookie mode.
+func AuthMiddleware(jwtManager *utils.JWTManager, tokens APITokenAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get the Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Authorization header is required",
-			})
-			c.Abort()
+			// Browsers in cookie mode send the JWT as a cookie instead of a header
+			cookie, err := c.Cookie(utils.AuthCookieName)
+			if err != nil || cookie == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"message": "Authorization header is required",
+				})
+				c.Abort()
+				return
+			}
+
+			validateJWT(c, jwtManager, cookie)
 			return
 		}
 
@@ -36,21 +56,79 @@ func AuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		// Validate the token
-		claims, err := jwtManager.ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
+		// Personal access tokens are looked up by hash instead of being parsed as JWTs
+		if tokens != nil && utils.IsAPIToken(tokenString) {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+			defer cancel()
+
+			token, err := tokens.AuthenticateToken(ctx, tokenString)
+			if err != nil {
+				// Only a token that does not resolve is the client's fault; a failed lookup must not log them out
+				if errors.Is(err, services.ErrInvalidAPIToken) {
+					c.JSON(http.StatusUnauthorized, gin.H{
+						"success": false,
+						"message": "Invalid or revoked token",
+					})
+					c.Abort()
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"success": false,
+					"message": "Failed to authenticate token",
+				})
+				c.Abort()
+				return
+			}
+
+			c.Set("userID", token.UserID)
+			c.Set("tokenScopes", token.Scopes)
+			c.Next()
+			return
+		}
+
+		validateJWT(c, jwtManager, tokenString)
+	}
+}
+
+// validateJWT validates a login JWT and sets its user ID in context for downstream handlers
+func validateJWT(c *gin.Context, jwtManager *utils.JWTManager, tokenString string) {
+	claims, err := jwtManager.ValidateToken(tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Invalid or expired token",
+			"error":   err.Error(),
+		})
+		c.Abort()
+		return
+	}
+
+	c.Set("userID", claims.UserID)
+
+	c.Next()
+}
+
+// RequireScope rejects personal access tokens whose scopes do not cover resource
+// Reads (GET, HEAD) need "<resource>:read" or "<resource>:write"; anything else needs "<resource>:write".
+// Requests authenticated with a JWT carry no scopes and always pass.
+func RequireScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("tokenScopes")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		write := c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead
+		if !utils.ScopesAllow(value.([]string), resource, write) {
+			c.JSON(http.StatusForbidden, gin.H{
 				"success": false,
-				"message": "Invalid or expired token",
-				"error":   err.Error(),
+				"message": "Token does not have the required scope",
 			})
 			c.Abort()
 			return
 		}
 
-		// Set the user ID in context for downstream handlers
-		c.Set("userID", claims.UserID)
-
 		c.Next()
 	}
 }
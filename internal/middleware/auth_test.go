@@ -1,10 +1,15 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"todo-app/internal/models"
+	"todo-app/internal/services"
 	"todo-app/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -73,7 +78,7 @@ func TestAuthMiddleware(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			router := gin.New()
-			router.Use(AuthMiddleware(jwtManager))
+			router.Use(AuthMiddleware(jwtManager, nil))
 			router.GET("/protected", func(c *gin.Context) {
 				userID, exists := c.Get("userID")
 				if !exists {
@@ -109,7 +114,7 @@ func TestAuthMiddleware_UserIDInContext(t *testing.T) {
 	token, _ := jwtManager.GenerateToken(42)
 
 	router := gin.New()
-	router.Use(AuthMiddleware(jwtManager))
+	router.Use(AuthMiddleware(jwtManager, nil))
 
 	var capturedUserID uint
 	router.GET("/protected", func(c *gin.Context) {
@@ -132,3 +137,133 @@ func TestAuthMiddleware_UserIDInContext(t *testing.T) {
 		t.Errorf("Expected userID 42, got %v", capturedUserID)
 	}
 }
+
+// fakeAPITokens authenticates tokens from an in-memory map keyed by secret
+// A nil entry stands for a token whose lookup fails, as it would when the database is down
+type fakeAPITokens map[string]*models.APIToken
+
+func (f fakeAPITokens) AuthenticateToken(ctx context.Context, secret string) (*models.APIToken, error) {
+	token, ok := f[secret]
+	if ok && token == nil {
+		return nil, errors.New("failed to look up token: connection refused")
+	}
+	if !ok || token.RevokedAt != nil {
+		return nil, services.ErrInvalidAPIToken
+	}
+	return token, nil
+}
+
+func TestAuthMiddleware_APIToken(t *testing.T) {
+	jwtManager, err := utils.NewJWTManager("test-secret-key")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	jwtToken, _ := jwtManager.GenerateToken(1)
+
+	tokens := fakeAPITokens{
+		"tdo_full":     {ID: 1, UserID: 7, Scopes: []string{}},
+		"tdo_readonly": {ID: 2, UserID: 7, Scopes: []string{"todos:read"}},
+		"tdo_lookup":   nil,
+	}
+
+	router := gin.New()
+	router.Use(AuthMiddleware(jwtManager, tokens), RequireScope("todos"))
+	handler := func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		c.JSON(http.StatusOK, gin.H{"userID": userID})
+	}
+	router.GET("/todos", handler)
+	router.POST("/todos", handler)
+
+	do := func(method, bearer string) int {
+		req, _ := http.NewRequest(method, "/todos", nil)
+		req.Header.Set("Authorization", "Bearer "+bearer)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		bearer string
+		want   int
+	}{
+		{name: "unscoped token authenticates", method: http.MethodPost, bearer: "tdo_full", want: http.StatusOK},
+		{name: "read scope allows GET", method: http.MethodGet, bearer: "tdo_readonly", want: http.StatusOK},
+		{name: "read scope rejects POST", method: http.MethodPost, bearer: "tdo_readonly", want: http.StatusForbidden},
+		{name: "unknown token", method: http.MethodGet, bearer: "tdo_unknown", want: http.StatusUnauthorized},
+		{name: "failed lookup is not a bad token", method: http.MethodGet, bearer: "tdo_lookup", want: http.StatusInternalServerError},
+		{name: "JWT still accepted and unscoped", method: http.MethodPost, bearer: jwtToken, want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := do(tt.method, tt.bearer); got != tt.want {
+				t.Errorf("%s /todos with %s: status = %d, want %d", tt.method, tt.bearer, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("revoked token is rejected", func(t *testing.T) {
+		revokedAt := time.Now()
+		tokens["tdo_full"].RevokedAt = &revokedAt
+		if got := do(http.MethodGet, "tdo_full"); got != http.StatusUnauthorized {
+			t.Errorf("GET /todos with revoked token: status = %d, want %d", got, http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestAuthMiddleware_TokenSources(t *testing.T) {
+	jwtManager, err := utils.NewJWTManager("test-secret-key")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	headerToken, _ := jwtManager.GenerateToken(1)
+	cookieToken, _ := jwtManager.GenerateToken(2)
+
+	tests := []struct {
+		name           string
+		authHeader     string
+		cookie         string
+		expectedStatus int
+		expectedUserID uint
+	}{
+		{name: "header only", authHeader: "Bearer " + headerToken, expectedStatus: http.StatusOK, expectedUserID: 1},
+		{name: "cookie only", cookie: cookieToken, expectedStatus: http.StatusOK, expectedUserID: 2},
+		{name: "header wins over cookie", authHeader: "Bearer " + headerToken, cookie: cookieToken, expectedStatus: http.StatusOK, expectedUserID: 1},
+		{name: "invalid cookie", cookie: "invalid.token.here", expectedStatus: http.StatusUnauthorized},
+		{name: "neither", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AuthMiddleware(jwtManager, nil))
+
+			var capturedUserID uint
+			router.GET("/protected", func(c *gin.Context) {
+				capturedUserID = c.GetUint("userID")
+				c.Status(http.StatusOK)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: utils.AuthCookieName, Value: tt.cookie})
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("AuthMiddleware() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if capturedUserID != tt.expectedUserID {
+				t.Errorf("AuthMiddleware() userID = %v, want %v", capturedUserID, tt.expectedUserID)
+			}
+		})
+	}
+}
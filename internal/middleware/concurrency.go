@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// userSlots counts each user's in-flight requests; a user with none has no entry, so the map only
+// holds users with a request running
+type userSlots struct {
+	mu       sync.Mutex
+	limit    int
+	inFlight map[uint]int
+}
+
+// acquire takes one of userID's slots, reporting false if all limit are in use
+func (s *userSlots) acquire(userID uint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inFlight[userID] >= s.limit {
+		return false
+	}
+	s.inFlight[userID]++
+	return true
+}
+
+// release gives back a slot taken by acquire
+func (s *userSlots) release(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.inFlight[userID] <= 1 {
+		delete(s.inFlight, userID)
+		return
+	}
+	s.inFlight[userID]--
+}
+
+// LimitConcurrentPerUser rejects a request with 429 when its user already has limit requests in flight.
+// It must run after AuthMiddleware; requests without a user ID are let through. Use one instance for every
+// route group so the limit covers all of a user's requests, and a limit of zero or less disables it.
+func LimitConcurrentPerUser(limit int) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	slots := &userSlots{limit: limit, inFlight: make(map[uint]int)}
+
+	return func(c *gin.Context) {
+		value, exists := c.Get("userID")
+		userID, ok := value.(uint)
+		if !exists || !ok {
+			c.Next()
+			return
+		}
+
+		if !slots.acquire(userID) {
+			c.Header("Retry-After", "1")
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"message": "Too many concurrent requests",
+			})
+			c.Abort()
+			return
+		}
+		// Deferred so a panicking handler still frees its slot on the way to the recovery middleware
+		defer slots.release(userID)
+
+		c.Next()
+	}
+}
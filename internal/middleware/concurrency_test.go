@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newConcurrencyRouter serves GET /work, which blocks until release is closed, and GET /panic as user
+// ?user=N (default 1), both behind LimitConcurrentPerUser(limit)
+func newConcurrencyRouter(limit int, entered chan<- struct{}, release <-chan struct{}) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.CustomRecovery(func(c *gin.Context, err any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	router.Use(func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.DefaultQuery("user", "1"), 10, 64)
+		if err == nil {
+			c.Set("userID", uint(userID))
+		}
+		c.Next()
+	})
+	router.Use(LimitConcurrentPerUser(limit))
+	router.GET("/work", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.Status(http.StatusOK)
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("handler failed")
+	})
+	return router
+}
+
+func serve(router *gin.Engine, path string) int {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code
+}
+
+func TestLimitConcurrentPerUser_RejectsRequestOverLimit(t *testing.T) {
+	const limit = 3
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	router := newConcurrencyRouter(limit, entered, release)
+
+	var wg sync.WaitGroup
+	codes := make([]int, limit)
+	for i := 0; i < limit; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = serve(router, "/work")
+		}(i)
+	}
+	for i := 0; i < limit; i++ {
+		<-entered
+	}
+
+	// All of user 1's slots are taken, so the next request is turned away without reaching the handler
+	if code := serve(router, "/work"); code != http.StatusTooManyRequests {
+		t.Errorf("request %d: status = %d, want %d", limit+1, code, http.StatusTooManyRequests)
+	}
+
+	// Other users have their own slots
+	go func() { <-entered }()
+	done := make(chan int)
+	go func() { done <- serve(router, "/work?user=2") }()
+
+	close(release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("other user: status = %d, want %d", code, http.StatusOK)
+	}
+	wg.Wait()
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("request %d: status = %d, want %d", i+1, code, http.StatusOK)
+		}
+	}
+
+	// Finished requests give their slots back
+	go func() { <-entered }()
+	if code := serve(router, "/work"); code != http.StatusOK {
+		t.Errorf("after release: status = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestLimitConcurrentPerUser_ReleasesSlotOnPanic(t *testing.T) {
+	router := newConcurrencyRouter(1, make(chan struct{}, 1), closedChannel())
+
+	for i := 0; i < 3; i++ {
+		if code := serve(router, "/panic"); code != http.StatusInternalServerError {
+			t.Fatalf("panic request %d: status = %d, want %d", i+1, code, http.StatusInternalServerError)
+		}
+	}
+	if code := serve(router, "/work"); code != http.StatusOK {
+		t.Errorf("after panics: status = %d, want %d (slot leaked)", code, http.StatusOK)
+	}
+}
+
+func TestLimitConcurrentPerUser_Disabled(t *testing.T) {
+	entered := make(chan struct{}, 2)
+	release := make(chan struct{})
+	router := newConcurrencyRouter(0, entered, release)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if code := serve(router, "/work"); code != http.StatusOK {
+				t.Errorf("status = %d, want %d", code, http.StatusOK)
+			}
+		}()
+	}
+	<-entered
+	<-entered
+	close(release)
+	wg.Wait()
+}
+
+func closedChannel() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
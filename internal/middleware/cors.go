@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// corsAllowedMethods and corsAllowedHeaders are what browsers may send cross-origin by default
+var (
+	corsAllowedMethods = []string{"POST", "OPTIONS", "GET", "PUT", "PATCH", "DELETE"}
+	corsAllowedHeaders = []string{
+		"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept",
+		"origin", "Cache-Control", "X-Requested-With", "X-Custom-Header",
+	}
+)
+
+// CORSConfig is a CORS policy: the methods and headers browsers may use cross-origin, and how long
+// (rounded down to whole seconds, zero omits the header) they may cache a preflight answer
+type CORSConfig struct {
+	AllowedMethods []string
+	AllowedHeaders []string
+	MaxAge         time.Duration
+}
+
+// DefaultCORSConfig is the broad policy applied to the whole router
+func DefaultCORSConfig(maxAge time.Duration) CORSConfig {
+	return CORSConfig{AllowedMethods: corsAllowedMethods, AllowedHeaders: corsAllowedHeaders, MaxAge: maxAge}
+}
+
+// ReadOnlyCORSConfig only lets browsers read cross-origin (GET and the OPTIONS preflight), with the default headers
+func ReadOnlyCORSConfig(maxAge time.Duration) CORSConfig {
+	return CORSConfig{AllowedMethods: []string{"GET", "OPTIONS"}, AllowedHeaders: corsAllowedHeaders, MaxAge: maxAge}
+}
+
+// CORS applies DefaultCORSConfig to every request, including those that match no route.
+// Preflights for groups set up with GroupCORS are left to the group's own policy.
+func CORS(maxAge time.Duration) gin.HandlerFunc {
+	fallback := CORSWithConfig(DefaultCORSConfig(maxAge))
+
+	return func(c *gin.Context) {
+		// Only GroupCORS registers OPTIONS routes, so a matched OPTIONS route is a preflight the group answers.
+		// This relies on CORS being registered before the routes, which makes it part of each route's chain.
+		if c.Request.Method == http.MethodOptions && c.FullPath() != "" {
+			c.Next()
+			return
+		}
+		fallback(c)
+	}
+}
+
+// GroupCORS gives a route group its own CORS policy in place of the router-wide one: it answers the group's
+// preflights and replaces the CORS headers on its other responses. Call it before adding the group's
+// middleware and routes, so preflights are answered before authentication runs.
+func GroupCORS(group *gin.RouterGroup, cfg CORSConfig) {
+	group.Use(CORSWithConfig(cfg))
+	// Gives preflights a route to match; CORSWithConfig answers them before this handler is reached
+	group.OPTIONS("/*path", func(c *gin.Context) {})
+}
+
+// CORSWithConfig allows cross-origin requests under cfg and answers preflight OPTIONS requests with 204.
+// A preflight that names its method or headers gets back only those it asked for that are allowed,
+// and cfg.MaxAge lets browsers cache the answer.
+func CORSWithConfig(cfg CORSConfig) gin.HandlerFunc {
+	allMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAgeSeconds := strconv.Itoa(int(cfg.MaxAge / time.Second))
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("Access-Control-Allow-Origin", "*")
+		header.Set("Access-Control-Allow-Credentials", "true")
+
+		if c.Request.Method != http.MethodOptions {
+			header.Set("Access-Control-Allow-Headers", allHeaders)
+			header.Set("Access-Control-Allow-Methods", allMethods)
+			c.Next()
+			return
+		}
+
+		// The answer depends on what the preflight asked for, so caches must key on it
+		header.Del("Vary")
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+		header.Del("Access-Control-Allow-Methods")
+		header.Del("Access-Control-Allow-Headers")
+
+		if method := c.GetHeader("Access-Control-Request-Method"); method == "" {
+			header.Set("Access-Control-Allow-Methods", allMethods)
+		} else if slices.Contains(cfg.AllowedMethods, strings.ToUpper(method)) {
+			header.Set("Access-Control-Allow-Methods", strings.ToUpper(method))
+		}
+
+		if requested := c.GetHeader("Access-Control-Request-Headers"); requested == "" {
+			header.Set("Access-Control-Allow-Headers", allHeaders)
+		} else if allowed := allowedRequestHeaders(cfg.AllowedHeaders, requested); allowed != "" {
+			header.Set("Access-Control-Allow-Headers", allowed)
+		}
+
+		if cfg.MaxAge >= time.Second {
+			header.Set("Access-Control-Max-Age", maxAgeSeconds)
+		}
+
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// allowedRequestHeaders returns the comma-separated headers from a preflight's
+// Access-Control-Request-Headers that are in allowedHeaders (compared case-insensitively)
+func allowedRequestHeaders(allowedHeaders []string, requested string) string {
+	var allowed []string
+	for _, name := range strings.Split(requested, ",") {
+		name = strings.TrimSpace(name)
+		if slices.ContainsFunc(allowedHeaders, func(h string) bool { return strings.EqualFold(h, name) }) {
+			allowed = append(allowed, name)
+		}
+	}
+	return strings.Join(allowed, ", ")
+}
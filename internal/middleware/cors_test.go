@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupCORSRouter(maxAge time.Duration) *gin.Engine {
+	router := gin.New()
+	router.Use(CORS(maxAge))
+	router.GET("/todos", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+	return router
+}
+
+func TestCORS_PreflightMaxAge(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		want   string
+	}{
+		{name: "ten minutes", maxAge: 10 * time.Minute, want: "600"},
+		{name: "rounded down to seconds", maxAge: 1500 * time.Millisecond, want: "1"},
+		{name: "disabled", maxAge: 0, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodOptions, "/todos", nil)
+			w := httptest.NewRecorder()
+			setupCORSRouter(tt.maxAge).ServeHTTP(w, req)
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("Expected status 204, got %v", w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Max-Age"); got != tt.want {
+				t.Errorf("Access-Control-Max-Age = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCORS_PreflightEchoesRequested(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		headers     string
+		wantMethods string
+		wantHeaders string
+	}{
+		{
+			name:        "nothing requested",
+			wantMethods: "POST, OPTIONS, GET, PUT, PATCH, DELETE",
+			wantHeaders: "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Custom-Header",
+		},
+		{
+			name:        "allowed method and headers",
+			method:      "patch",
+			headers:     "authorization, Content-Type",
+			wantMethods: "PATCH",
+			wantHeaders: "authorization, Content-Type",
+		},
+		{
+			name:        "unknown header dropped",
+			method:      "GET",
+			headers:     "Authorization, X-Secret",
+			wantMethods: "GET",
+			wantHeaders: "Authorization",
+		},
+		{
+			name:        "disallowed method and headers",
+			method:      "TRACE",
+			headers:     "X-Secret",
+			wantMethods: "",
+			wantHeaders: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodOptions, "/todos", nil)
+			if tt.method != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.method)
+			}
+			if tt.headers != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.headers)
+			}
+			w := httptest.NewRecorder()
+			setupCORSRouter(time.Minute).ServeHTTP(w, req)
+
+			if got := w.Header().Get("Access-Control-Allow-Methods"); got != tt.wantMethods {
+				t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, tt.wantMethods)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Headers"); got != tt.wantHeaders {
+				t.Errorf("Access-Control-Allow-Headers = %q, want %q", got, tt.wantHeaders)
+			}
+		})
+	}
+}
+
+func TestCORS_SimpleRequestPassesThrough(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "/todos", nil)
+	w := httptest.NewRecorder()
+	setupCORSRouter(time.Minute).ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status OK, got %v", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, "*")
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "" {
+		t.Errorf("Access-Control-Max-Age = %q on a non-preflight request, want none", got)
+	}
+}
+
+func TestGroupCORS_ReadOnlyGroup(t *testing.T) {
+	router := setupCORSRouter(10 * time.Minute)
+	reports := router.Group("/reports")
+	GroupCORS(reports, ReadOnlyCORSConfig(time.Minute))
+	reports.Use(func(c *gin.Context) {
+		// Stands in for authentication, which preflights must never reach
+		c.AbortWithStatus(http.StatusUnauthorized)
+	})
+	reports.GET("/weekly", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	})
+
+	tests := []struct {
+		name        string
+		path        string
+		method      string
+		wantMethods string
+		wantMaxAge  string
+	}{
+		{name: "group preflight", path: "/reports/weekly", wantMethods: "GET, OPTIONS", wantMaxAge: "60"},
+		{name: "group preflight for a write", path: "/reports/weekly", method: "DELETE", wantMethods: "", wantMaxAge: "60"},
+		{name: "group preflight for an unknown path", path: "/reports/other", wantMethods: "GET, OPTIONS", wantMaxAge: "60"},
+		{name: "other paths keep the default", path: "/todos", wantMethods: "POST, OPTIONS, GET, PUT, PATCH, DELETE", wantMaxAge: "600"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodOptions, tt.path, nil)
+			if tt.method != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.method)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("Expected status 204, got %v", w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Methods"); got != tt.wantMethods {
+				t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, tt.wantMethods)
+			}
+			if got := w.Header().Get("Access-Control-Max-Age"); got != tt.wantMaxAge {
+				t.Errorf("Access-Control-Max-Age = %q, want %q", got, tt.wantMaxAge)
+			}
+		})
+	}
+}
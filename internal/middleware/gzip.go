@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipMinLength is the smallest response body (in bytes) worth compressing
+const gzipMinLength = 1024
+
+// Gzip compresses response bodies for clients that send Accept-Encoding: gzip.
+// Responses smaller than gzipMinLength are sent uncompressed, and streaming
+// responses (SSE or anything that calls Flush) are passed through untouched.
+func Gzip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !acceptsGzip(c.Request) {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+
+		gw := &gzipResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = gw
+		defer gw.finish()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether the request advertises gzip support
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(enc), ";")
+		if strings.EqualFold(name, "gzip") {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the body so the compression decision can be made
+// once the full size is known
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	buf       bytes.Buffer
+	streaming bool
+}
+
+// Write buffers the body unless the response is being streamed
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.streaming && strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+		w.startStreaming()
+	}
+	if w.streaming {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+// WriteString buffers the body unless the response is being streamed
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush switches the writer to pass-through mode so streamed chunks reach the client immediately
+func (w *gzipResponseWriter) Flush() {
+	if !w.streaming {
+		w.startStreaming()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// startStreaming writes any buffered bytes uncompressed and disables buffering
+func (w *gzipResponseWriter) startStreaming() {
+	w.streaming = true
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+// finish writes the buffered body, compressing it when it is large enough
+func (w *gzipResponseWriter) finish() {
+	if w.streaming || w.buf.Len() == 0 {
+		return
+	}
+
+	if w.buf.Len() < gzipMinLength || w.Header().Get("Content-Encoding") != "" {
+		w.ResponseWriter.Write(w.buf.Bytes())
+		return
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Del("Content-Length")
+
+	gz := gzip.NewWriter(w.ResponseWriter)
+	gz.Write(w.buf.Bytes())
+	gz.Close()
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupGzipRouter(items int) *gin.Engine {
+	router := gin.New()
+	router.Use(Gzip())
+	router.GET("/todos", func(c *gin.Context) {
+		todos := make([]gin.H, 0, items)
+		for i := 0; i < items; i++ {
+			todos = append(todos, gin.H{"id": i, "title": "Todo item", "description": "Some description text"})
+		}
+		c.JSON(http.StatusOK, gin.H{"success": true, "data": todos})
+	})
+	return router
+}
+
+func TestGzip_LargeListResponseCompressed(t *testing.T) {
+	router := setupGzipRouter(200)
+
+	req, _ := http.NewRequest(http.MethodGet, "/todos", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status OK, got %v", w.Code)
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Expected Content-Encoding gzip, got %q", got)
+	}
+
+	gr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("Failed to create gzip reader: %v", err)
+	}
+	body, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("Failed to decompress body: %v", err)
+	}
+
+	var response struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		t.Fatalf("Failed to decode decompressed body: %v", err)
+	}
+	if len(response.Data) != 200 {
+		t.Errorf("Expected 200 items, got %d", len(response.Data))
+	}
+}
+
+func TestGzip_SmallResponseNotCompressed(t *testing.T) {
+	router := setupGzipRouter(1)
+
+	req, _ := http.NewRequest(http.MethodGet, "/todos", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Expected no Content-Encoding for small response, got %q", got)
+	}
+	if !json.Valid(w.Body.Bytes()) {
+		t.Errorf("Expected plain JSON body, got %s", w.Body.String())
+	}
+}
+
+func TestGzip_NoAcceptEncoding(t *testing.T) {
+	router := setupGzipRouter(200)
+
+	req, _ := http.NewRequest(http.MethodGet, "/todos", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Expected no Content-Encoding without Accept-Encoding, got %q", got)
+	}
+	if !json.Valid(w.Body.Bytes()) {
+		t.Error("Expected plain JSON body")
+	}
+}
+
+func TestGzip_StreamingPassThrough(t *testing.T) {
+	router := gin.New()
+	router.Use(Gzip())
+	router.GET("/events", func(c *gin.Context) {
+		c.SSEvent("message", "hello")
+		c.Writer.Flush()
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Expected streaming response to be uncompressed, got %q", got)
+	}
+	if w.Body.Len() == 0 {
+		t.Error("Expected streamed body to be written")
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireJSON rejects POST, PUT and PATCH requests that carry a body whose Content-Type is not
+// application/json with 415. Requests without a body pass through, since many writes (cleanup,
+// purge, complete-all) take their input from the URL alone.
+func RequireJSON() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		// A chunked body reports an unknown length of -1
+		if c.Request.ContentLength == 0 {
+			c.Next()
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != "application/json" {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{
+				"success": false,
+				"message": "Content-Type must be application/json",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireJSON(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		contentType    string
+		body           string
+		expectedStatus int
+	}{
+		{name: "json body", method: http.MethodPost, contentType: "application/json", body: `{"title":"x"}`, expectedStatus: http.StatusOK},
+		{name: "json with charset", method: http.MethodPut, contentType: "application/json; charset=utf-8", body: `{}`, expectedStatus: http.StatusOK},
+		{name: "plain text body", method: http.MethodPost, contentType: "text/plain", body: "title=x", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "form body", method: http.MethodPatch, contentType: "application/x-www-form-urlencoded", body: "title=x", expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "missing content type", method: http.MethodPost, body: `{}`, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "empty body", method: http.MethodPost, expectedStatus: http.StatusOK},
+		{name: "delete", method: http.MethodDelete, contentType: "text/plain", body: "x", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(RequireJSON())
+			router.Handle(tt.method, "/todos", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, "/todos", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("RequireJSON() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
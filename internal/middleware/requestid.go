@@ -11,10 +11,13 @@ import (
 )
 
 // RequestIDMiddleware injects a request ID into the context and response header
+// A valid inbound X-Request-Id is kept so the request can be traced across services; anything else is replaced
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Generate UUID
-		rid := uuid.New().String()
+		rid := c.GetHeader("X-Request-Id")
+		if !isValidRequestID(rid) {
+			rid = uuid.New().String()
+		}
 
 		// Add to request context using typed key to avoid collisions
 		ctx := context.WithValue(c.Request.Context(), utils.RequestIDKey, rid)
@@ -31,3 +34,13 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// isValidRequestID reports whether rid is a UUID in its canonical 36 character form
+// uuid.Parse also accepts braced, URN and unhyphenated forms, which are not echoed back as-is
+func isValidRequestID(rid string) bool {
+	if len(rid) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(rid)
+	return err == nil
+}
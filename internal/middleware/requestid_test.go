@@ -118,3 +118,48 @@ func TestRequestIDMiddleware_UniqueIDs(t *testing.T) {
 		requestIDs[requestID] = true
 	}
 }
+
+func TestRequestIDMiddleware_InboundID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		inbound  string
+		wantKept bool
+	}{
+		{name: "valid uuid is preserved", inbound: "3f2b8c1e-6d4a-4f5e-9a7b-2c1d0e9f8a7b", wantKept: true},
+		{name: "uppercase uuid is preserved", inbound: "3F2B8C1E-6D4A-4F5E-9A7B-2C1D0E9F8A7B", wantKept: true},
+		{name: "arbitrary string is replaced", inbound: "not-a-uuid", wantKept: false},
+		{name: "unhyphenated uuid is replaced", inbound: "3f2b8c1e6d4a4f5e9a7b2c1d0e9f8a7b", wantKept: false},
+		{name: "uuid with trailing data is replaced", inbound: "3f2b8c1e-6d4a-4f5e-9a7b-2c1d0e9f8a7b-extra", wantKept: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(RequestIDMiddleware())
+
+			var contextID string
+			router.GET("/test", func(c *gin.Context) {
+				contextID = utils.GetRequestID(c.Request.Context())
+				c.JSON(http.StatusOK, gin.H{"status": "ok"})
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set("X-Request-Id", tt.inbound)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			headerID := w.Header().Get("X-Request-Id")
+			if contextID != headerID {
+				t.Errorf("Context requestID (%s) doesn't match header (%s)", contextID, headerID)
+			}
+			if kept := headerID == tt.inbound; kept != tt.wantKept {
+				t.Errorf("X-Request-Id = %q for inbound %q, kept = %v, want %v", headerID, tt.inbound, kept, tt.wantKept)
+			}
+			if !tt.wantKept && len(headerID) != 36 {
+				t.Errorf("Expected a generated UUID (36 chars), got %q", headerID)
+			}
+		})
+	}
+}
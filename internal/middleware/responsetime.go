@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseTimeHeader reports how long the server spent on a request, in milliseconds
+const ResponseTimeHeader = "X-Response-Time"
+
+// ResponseTime sets X-Response-Time on every response. Headers cannot change once the body starts,
+// so the duration runs until the response is first written, which covers all handler work.
+// Register it before other middleware so their time is included too.
+func ResponseTime() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rw := &responseTimeWriter{ResponseWriter: c.Writer, start: time.Now()}
+		c.Writer = rw
+
+		c.Next()
+
+		// Responses without a body are only written out by gin after the chain returns
+		if !rw.Written() {
+			rw.setHeader()
+		}
+	}
+}
+
+// responseTimeWriter sets the response time header just before the status line is sent
+type responseTimeWriter struct {
+	gin.ResponseWriter
+	start time.Time
+}
+
+// setHeader records the time elapsed since the request started
+func (w *responseTimeWriter) setHeader() {
+	elapsed := float64(time.Since(w.start).Microseconds()) / 1000
+	w.Header().Set(ResponseTimeHeader, strconv.FormatFloat(elapsed, 'f', 3, 64))
+}
+
+// Flush sets the header before a streamed response is first flushed
+func (w *responseTimeWriter) Flush() {
+	if !w.Written() {
+		w.setHeader()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// WriteHeaderNow sets the header before the status line is sent
+func (w *responseTimeWriter) WriteHeaderNow() {
+	if !w.Written() {
+		w.setHeader()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write sets the header before the first body bytes are sent
+func (w *responseTimeWriter) Write(data []byte) (int, error) {
+	if !w.Written() {
+		w.setHeader()
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString sets the header before the first body bytes are sent
+func (w *responseTimeWriter) WriteString(s string) (int, error) {
+	if !w.Written() {
+		w.setHeader()
+	}
+	return w.ResponseWriter.WriteString(s)
+}
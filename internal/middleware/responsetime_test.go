@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestResponseTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+	}{
+		{
+			name: "json body",
+			handler: func(c *gin.Context) {
+				time.Sleep(5 * time.Millisecond)
+				c.JSON(http.StatusOK, gin.H{"success": true})
+			},
+		},
+		{
+			name: "no body",
+			handler: func(c *gin.Context) {
+				time.Sleep(5 * time.Millisecond)
+				c.Status(http.StatusNoContent)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(ResponseTime())
+			router.GET("/slow", tt.handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			header := w.Header().Get(ResponseTimeHeader)
+			if header == "" {
+				t.Fatalf("%s header missing", ResponseTimeHeader)
+			}
+			ms, err := strconv.ParseFloat(header, 64)
+			if err != nil {
+				t.Fatalf("%s = %q, not a number: %v", ResponseTimeHeader, header, err)
+			}
+			if ms < 5 {
+				t.Errorf("%s = %v, want at least the 5ms the handler took", ResponseTimeHeader, ms)
+			}
+		})
+	}
+}
+
+func TestResponseTime_WithGzip(t *testing.T) {
+	router := gin.New()
+	router.Use(ResponseTime(), Gzip())
+	router.GET("/items", func(c *gin.Context) {
+		c.String(http.StatusOK, string(make([]byte, 2*gzipMinLength)))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", w.Header().Get("Content-Encoding"))
+	}
+	if _, err := strconv.ParseFloat(w.Header().Get(ResponseTimeHeader), 64); err != nil {
+		t.Errorf("%s = %q, want a number", ResponseTimeHeader, w.Header().Get(ResponseTimeHeader))
+	}
+}
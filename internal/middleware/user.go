@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"todo-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserLoader fetches a user by ID
+type UserLoader interface {
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+}
+
+// LoadUser fetches the authenticated user once and sets it in context as "user", so handlers that need
+// more than the user ID do not query for it again. It must run after AuthMiddleware and is opt-in per
+// route, since most handlers only need the ID and would pay for an extra query.
+func LoadUser(users UserLoader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			// Only reachable when the route is missing AuthMiddleware
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "User not available in request context",
+			})
+			c.Abort()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		user, err := users.GetByID(ctx, userID.(uint))
+		if err != nil {
+			// A valid token for a user that has since been removed is no longer a valid login
+			if errors.Is(err, sql.ErrNoRows) {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"message": "User no longer exists",
+				})
+				c.Abort()
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Failed to load user",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("user", user)
+		c.Next()
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"todo-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type stubUserLoader struct {
+	calls int
+	user  *models.User
+	err   error
+}
+
+func (s *stubUserLoader) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.user, nil
+}
+
+func TestLoadUser(t *testing.T) {
+	tests := []struct {
+		name           string
+		setUserID      bool
+		loader         *stubUserLoader
+		expectedStatus int
+	}{
+		{
+			name:           "user loaded into context",
+			setUserID:      true,
+			loader:         &stubUserLoader{user: &models.User{ID: 7, Name: "Test User", Email: "test@example.com"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "user no longer exists",
+			setUserID:      true,
+			loader:         &stubUserLoader{err: sql.ErrNoRows},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "lookup fails",
+			setUserID:      true,
+			loader:         &stubUserLoader{err: errors.New("connection refused")},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "route without auth middleware",
+			loader:         &stubUserLoader{},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *models.User
+			router := gin.New()
+			router.GET("/me", func(c *gin.Context) {
+				if tt.setUserID {
+					c.Set("userID", uint(7))
+				}
+				c.Next()
+			}, LoadUser(tt.loader), func(c *gin.Context) {
+				value, _ := c.Get("user")
+				got, _ = value.(*models.User)
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("LoadUser() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if got == nil || got.ID != 7 || got.Email != "test@example.com" {
+				t.Errorf("handler got user %+v, want the loaded user", got)
+			}
+			if tt.loader.calls != 1 {
+				t.Errorf("GetByID() called %d times, want 1", tt.loader.calls)
+			}
+		})
+	}
+}
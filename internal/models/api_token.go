@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// APIToken is a personal access token used by scripts instead of a login session
+// Only the SHA-256 hash of the token is stored; the token itself is shown once at creation
+type APIToken struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Name      string     `json:"name"`
+	TokenHash string     `json:"-"`
+	Scopes    []string   `json:"scopes"` // Empty grants the same access as a login session
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -17,14 +17,27 @@ func (p Permission) IsValid() bool {
 	return p == PermissionRead || p == PermissionWrite
 }
 
+// CategoryAction is something done to a category itself rather than to its todos
+type CategoryAction string
+
+// Category actions are reserved to the owner: a share, even with write permission,
+// only grants access to the category's todos
+const (
+	CanUpdateCategory CategoryAction = "update_category"
+	CanDeleteCategory CategoryAction = "delete_category"
+	CanManageShares   CategoryAction = "manage_shares"
+)
+
 // Category represents a category owned by a user
 type Category struct {
-	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	OwnerID   uint      `json:"owner_id"`
-	Todos     []Todo    `json:"todos,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                     uint       `json:"id"`
+	Name                   string     `json:"name"`
+	OwnerID                uint       `json:"owner_id"`
+	AllowDuplicateTitles   bool       `json:"allow_duplicate_titles"`
+	DefaultSharePermission Permission `json:"default_share_permission"` // Given to new shares that name no permission
+	Todos                  []Todo     `json:"todos,omitempty"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
 }
 
 // CategoryShare represents a category shared with a user
@@ -60,20 +73,50 @@ type SharedCategoryWithOwner struct {
 	OwnerEmail string     `json:"owner_email"`
 }
 
+// CategoryWithShares is a category the user owns together with everyone it is shared with
+type CategoryWithShares struct {
+	ID     uint                    `json:"id"`
+	Name   string                  `json:"name"`
+	Shares []CategoryShareWithUser `json:"shares"`
+}
+
+// GrantedMember is a user with access to at least one of the owner's categories, with every grant they hold
+type GrantedMember struct {
+	UserID     uint          `json:"user_id"`
+	Name       string        `json:"name"`
+	Email      string        `json:"email"`
+	Categories []MemberGrant `json:"categories"`
+}
+
+// MemberGrant is one category a member was granted and their permission on it
+type MemberGrant struct {
+	CategoryID   uint       `json:"category_id"`
+	CategoryName string     `json:"category_name"`
+	Permission   Permission `json:"permission"`
+}
+
+// WritableCategory is a category the user can add todos to
+// Permission is "owner" for owned categories and "write" for shared ones
+type WritableCategory struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	Permission string `json:"permission"`
+}
+
 // CategoryWithTodosRow represents a flat row from the grouped query
 // Each row contains one category with one todo (or no todo if category is empty)
 type CategoryWithTodosRow struct {
-	CategoryID        uint    `json:"category_id"`
-	CategoryName      string  `json:"category_name"`
-	CategoryOwnerID   uint    `json:"category_owner_id"`
-	CategoryOwnerName string  `json:"category_owner_name"`
-	UserPermission    string  `json:"user_permission"`
-	TodoID            uint    `json:"todo_id"`
-	TodoTitle         string  `json:"todo_title"`
-	TodoDescription   string  `json:"todo_description"`
-	TodoCompleted     bool    `json:"todo_completed"`
-	TodoCreatedBy     uint    `json:"todo_created_by"`
-	TodoCreatorName   string  `json:"todo_creator_name"`
-	TodoCreatedAt     *string `json:"todo_created_at"`
-	TodoUpdatedAt     *string `json:"todo_updated_at"`
+	CategoryID        uint       `json:"category_id"`
+	CategoryName      string     `json:"category_name"`
+	CategoryOwnerID   uint       `json:"category_owner_id"`
+	CategoryOwnerName string     `json:"category_owner_name"`
+	UserPermission    string     `json:"user_permission"`
+	TodoID            uint       `json:"todo_id"`
+	TodoTitle         string     `json:"todo_title"`
+	TodoDescription   string     `json:"todo_description"`
+	TodoCompleted     bool       `json:"todo_completed"`
+	TodoCreatedBy     uint       `json:"todo_created_by"`
+	TodoCreatorName   string     `json:"todo_creator_name"`
+	TodoCreatedAt     *time.Time `json:"todo_created_at"`
+	TodoUpdatedAt     *time.Time `json:"todo_updated_at"`
 }
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// Comment is a note left on a todo by anyone who can read it, e.g. to discuss a shared todo
+type Comment struct {
+	ID        uint      `json:"id"`
+	TodoID    uint      `json:"todo_id"`
+	AuthorID  uint      `json:"author_id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -6,14 +6,58 @@ import (
 
 // Todo represents the todo model (pure data structure)
 type Todo struct {
-	ID          uint       `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	CategoryID  uint       `json:"category_id"`
-	Completed   bool       `json:"completed"`
-	UserID      uint       `json:"user_id"`
-	CreatedBy   uint       `json:"created_by"`
-	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID           uint       `json:"id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	CategoryID   uint       `json:"category_id"`
+	Completed    bool       `json:"completed"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	RemindAt     *time.Time `json:"remind_at,omitempty"`
+	ReminderSent bool       `json:"reminder_sent"`
+	UserID       uint       `json:"user_id"`
+	CreatedBy    uint       `json:"created_by"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TodoChange identifies a todo changed since a sync point; a deleted todo is a tombstone with no other data
+type TodoChange struct {
+	ID        uint      `json:"id"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Deleted   bool      `json:"deleted"`
+}
+
+// CompletionCount is the number of todos completed on a single day
+type CompletionCount struct {
+	Day   time.Time `json:"day"`
+	Count int64     `json:"count"`
+}
+
+// TodoStatusCounts is the number of todos in a set, split by completion
+type TodoStatusCounts struct {
+	Total     int64 `json:"total"`
+	Completed int64 `json:"completed"`
+	Open      int64 `json:"open"`
+}
+
+// TodoSort is a "field:direction" ordering for the todo list
+type TodoSort string
+
+const (
+	TodoSortCreatedAtDesc TodoSort = "created_at:desc"
+	TodoSortCreatedAtAsc  TodoSort = "created_at:asc"
+	TodoSortUpdatedAtDesc TodoSort = "updated_at:desc"
+	TodoSortUpdatedAtAsc  TodoSort = "updated_at:asc"
+	TodoSortTitleAsc      TodoSort = "title:asc"
+	TodoSortTitleDesc     TodoSort = "title:desc"
+)
+
+// IsValid checks if the sort is one of the supported orderings
+func (s TodoSort) IsValid() bool {
+	switch s {
+	case TodoSortCreatedAtDesc, TodoSortCreatedAtAsc, TodoSortUpdatedAtDesc, TodoSortUpdatedAtAsc, TodoSortTitleAsc, TodoSortTitleDesc:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// TodoEventAction identifies the kind of change recorded in a todo's history
+type TodoEventAction string
+
+const (
+	TodoEventCreate  TodoEventAction = "create"
+	TodoEventUpdate  TodoEventAction = "update"
+	TodoEventDelete  TodoEventAction = "delete"
+	TodoEventRestore TodoEventAction = "restore"
+)
+
+// TodoEvent is an append-only history entry describing who changed a todo and how
+type TodoEvent struct {
+	ID            uint            `json:"id"`
+	TodoID        uint            `json:"todo_id"`
+	ActorID       uint            `json:"actor_id"`
+	Action        TodoEventAction `json:"action"`
+	ChangedFields []string        `json:"changed_fields"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
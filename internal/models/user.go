@@ -9,7 +9,9 @@ type User struct {
 	ID        uint      `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
-	Password  string    `json:"-"` // "-" hides password from JSON
+	Password  string    `json:"-"`        // "-" hides password from JSON
+	Timezone  string    `json:"timezone"` // IANA name used for day boundaries, "UTC" by default
+	IsAdmin   bool      `json:"is_admin"` // may use the /api/admin user tools
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"todo-app/db"
+	"todo-app/internal/models"
+)
+
+// Ensure SQLAPITokenRepository implements APITokenRepository
+var _ APITokenRepository = (*SQLAPITokenRepository)(nil)
+
+// SQLAPITokenRepository implements APITokenRepository using sqlc-generated queries
+type SQLAPITokenRepository struct {
+	queries *db.Queries
+}
+
+// NewSQLAPITokenRepository creates a new APITokenRepository with the provided queries instance
+func NewSQLAPITokenRepository(queries *db.Queries) APITokenRepository {
+	return &SQLAPITokenRepository{queries: queries}
+}
+
+// toModelAPIToken converts db.ApiToken to models.APIToken
+func toModelAPIToken(t db.ApiToken) models.APIToken {
+	scopes := []string{}
+	if t.Scopes != "" {
+		scopes = strings.Split(t.Scopes, ",")
+	}
+	var revokedAt *time.Time
+	if t.RevokedAt.Valid {
+		revokedAt = &t.RevokedAt.Time
+	}
+	return models.APIToken{
+		ID:        uint(t.ID),
+		UserID:    uint(t.UserID),
+		Name:      t.Name,
+		TokenHash: t.TokenHash,
+		Scopes:    scopes,
+		RevokedAt: revokedAt,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
+// CreateAPIToken inserts a new token and fills in its generated fields
+func (r *SQLAPITokenRepository) CreateAPIToken(ctx context.Context, token *models.APIToken) error {
+	if r.queries == nil {
+		return sql.ErrConnDone
+	}
+
+	id, err := r.queries.CreateAPIToken(ctx, db.CreateAPITokenParams{
+		UserID:    uint64(token.UserID),
+		Name:      token.Name,
+		TokenHash: token.TokenHash,
+		Scopes:    strings.Join(token.Scopes, ","),
+	})
+	if err != nil {
+		return err
+	}
+
+	t, err := r.queries.GetAPITokenByID(ctx, uint64(id))
+	if err != nil {
+		return err
+	}
+	*token = toModelAPIToken(t)
+	return nil
+}
+
+// GetActiveAPITokenByHash retrieves a non-revoked token by the hash of its value
+func (r *SQLAPITokenRepository) GetActiveAPITokenByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	t, err := r.queries.GetActiveAPITokenByHash(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	token := toModelAPIToken(t)
+	return &token, nil
+}
+
+// ListAPITokens retrieves all of a user's tokens, including revoked ones, newest first
+func (r *SQLAPITokenRepository) ListAPITokens(ctx context.Context, userID uint) ([]models.APIToken, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	items, err := r.queries.ListAPITokensByUser(ctx, uint64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make([]models.APIToken, 0, len(items))
+	for _, it := range items {
+		tokens = append(tokens, toModelAPIToken(it))
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken revokes one of the user's active tokens, reporting false if none matched
+func (r *SQLAPITokenRepository) RevokeAPIToken(ctx context.Context, id, userID uint) (bool, error) {
+	if r.queries == nil {
+		return false, sql.ErrConnDone
+	}
+
+	affected, err := r.queries.RevokeAPIToken(ctx, db.RevokeAPITokenParams{
+		ID:     uint64(id),
+		UserID: uint64(userID),
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
@@ -24,11 +24,13 @@ func NewSQLCategoryRepository(queries *db.Queries) CategoryRepository {
 // toModelCategory converts db.Category to models.Category
 func toModelCategory(c db.Category) models.Category {
 	return models.Category{
-		ID:        uint(c.ID),
-		Name:      c.Name,
-		OwnerID:   uint(c.OwnerID),
-		CreatedAt: c.CreatedAt,
-		UpdatedAt: c.UpdatedAt,
+		ID:                     uint(c.ID),
+		Name:                   c.Name,
+		OwnerID:                uint(c.OwnerID),
+		AllowDuplicateTitles:   c.AllowDuplicateTitles,
+		DefaultSharePermission: models.Permission(c.DefaultSharePermission),
+		CreatedAt:              c.CreatedAt,
+		UpdatedAt:              c.UpdatedAt,
 	}
 }
 
@@ -39,8 +41,9 @@ func (r *SQLCategoryRepository) CreateCategory(ctx context.Context, category *mo
 	}
 
 	id, err := r.queries.CreateCategory(ctx, db.CreateCategoryParams{
-		Name:    category.Name,
-		OwnerID: uint64(category.OwnerID),
+		Name:                   category.Name,
+		OwnerID:                uint64(category.OwnerID),
+		DefaultSharePermission: db.CategoriesDefaultSharePermission(category.DefaultSharePermission),
 	})
 	if err != nil {
 		return err
@@ -87,6 +90,33 @@ func (r *SQLCategoryRepository) GetCategoriesByOwnerID(ctx context.Context, owne
 	return categories, nil
 }
 
+// GetCategoriesByIDs retrieves the categories with the given IDs in a single query
+// IDs that do not exist are skipped, and the result is in no particular order
+func (r *SQLCategoryRepository) GetCategoriesByIDs(ctx context.Context, ids []uint) ([]models.Category, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+	if len(ids) == 0 {
+		return []models.Category{}, nil
+	}
+
+	categoryIDs := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		categoryIDs = append(categoryIDs, uint64(id))
+	}
+
+	items, err := r.queries.GetCategoriesByIDs(ctx, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]models.Category, 0, len(items))
+	for _, item := range items {
+		categories = append(categories, toModelCategory(item))
+	}
+	return categories, nil
+}
+
 // GetCategoryByNameAndOwner retrieves a category by name and owner ID
 func (r *SQLCategoryRepository) GetCategoryByNameAndOwner(ctx context.Context, ownerID uint, name string) (*models.Category, error) {
 	if r.queries == nil {
@@ -111,8 +141,10 @@ func (r *SQLCategoryRepository) UpdateCategory(ctx context.Context, category *mo
 	}
 
 	err := r.queries.UpdateCategory(ctx, db.UpdateCategoryParams{
-		Name: category.Name,
-		ID:   uint64(category.ID),
+		Name:                   category.Name,
+		AllowDuplicateTitles:   category.AllowDuplicateTitles,
+		DefaultSharePermission: db.CategoriesDefaultSharePermission(category.DefaultSharePermission),
+		ID:                     uint64(category.ID),
 	})
 	if err != nil {
 		return err
@@ -127,10 +159,53 @@ func (r *SQLCategoryRepository) UpdateCategory(ctx context.Context, category *mo
 	return nil
 }
 
-// DeleteCategory deletes a category from the database
+// DeleteCategory soft deletes a category along with its todos
 func (r *SQLCategoryRepository) DeleteCategory(ctx context.Context, id uint) error {
 	if r.queries == nil {
 		return sql.ErrConnDone
 	}
-	return r.queries.DeleteCategory(ctx, uint64(id))
+	return r.queries.SoftDeleteCategory(ctx, uint64(id))
+}
+
+// GetEmptyCategoriesByOwnerID retrieves the owner's categories that have no live todos and no shares
+func (r *SQLCategoryRepository) GetEmptyCategoriesByOwnerID(ctx context.Context, ownerID uint) ([]models.Category, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	items, err := r.queries.GetEmptyCategoriesByOwnerID(ctx, uint64(ownerID))
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]models.Category, 0, len(items))
+	for _, item := range items {
+		categories = append(categories, toModelCategory(item))
+	}
+	return categories, nil
+}
+
+// DeleteEmptyCategory soft deletes a category only if it still has no live todos and no shares,
+// reporting whether it was deleted
+func (r *SQLCategoryRepository) DeleteEmptyCategory(ctx context.Context, id uint) (bool, error) {
+	if r.queries == nil {
+		return false, sql.ErrConnDone
+	}
+
+	rows, err := r.queries.SoftDeleteEmptyCategory(ctx, uint64(id))
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// LockCategory locks the category row until the surrounding transaction ends
+// Writers that check the category's todo limit take this lock first, so their counts cannot interleave
+func (r *SQLCategoryRepository) LockCategory(ctx context.Context, id uint) error {
+	if r.queries == nil {
+		return sql.ErrConnDone
+	}
+
+	_, err := r.queries.LockCategory(ctx, uint64(id))
+	return err
 }
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"todo-app/db"
 	"todo-app/internal/models"
@@ -87,15 +88,30 @@ func (r *SQLCategoryShareRepository) GetCategoryShareByCategoryAndUser(ctx conte
 	return &share, nil
 }
 
-// GetSharesForCategory retrieves all shares for a category with user details
-func (r *SQLCategoryShareRepository) GetSharesForCategory(ctx context.Context, categoryID uint) ([]models.CategoryShareWithUser, error) {
+// GetSharesForCategory retrieves a page of shares for a category with user details.
+// sortBy "email" orders by recipient email, anything else orders newest first.
+func (r *SQLCategoryShareRepository) GetSharesForCategory(ctx context.Context, categoryID uint, sortBy string, page, pageSize int) ([]models.CategoryShareWithUser, int64, error) {
 	if r.queries == nil {
-		return nil, sql.ErrConnDone
+		return nil, 0, sql.ErrConnDone
 	}
 
-	items, err := r.queries.GetSharesForCategory(ctx, uint64(categoryID))
+	total, err := r.queries.CountSharesForCategory(ctx, uint64(categoryID))
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []models.CategoryShareWithUser{}, total, nil
+	}
+
+	limit, offset := limitOffset(page, pageSize)
+	items, err := r.queries.GetSharesForCategory(ctx, db.GetSharesForCategoryParams{
+		CategoryID: uint64(categoryID),
+		SortBy:     sortBy,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
 	shares := make([]models.CategoryShareWithUser, 0, len(items))
@@ -110,20 +126,107 @@ func (r *SQLCategoryShareRepository) GetSharesForCategory(ctx context.Context, c
 			SharedWithUserEmail: item.SharedWithUserEmail,
 		})
 	}
-	return shares, nil
+	return shares, total, nil
 }
 
-// GetSharedCategoriesForUser retrieves all categories shared with a user
-func (r *SQLCategoryShareRepository) GetSharedCategoriesForUser(ctx context.Context, userID uint) ([]models.SharedCategoryWithOwner, error) {
+// GetCategoriesSharedByOwner retrieves the owner's categories that have at least one share, ordered by
+// name, each with its recipients ordered by email
+func (r *SQLCategoryShareRepository) GetCategoriesSharedByOwner(ctx context.Context, ownerID uint) ([]models.CategoryWithShares, error) {
 	if r.queries == nil {
 		return nil, sql.ErrConnDone
 	}
 
-	items, err := r.queries.GetSharedCategoriesForUser(ctx, uint64(userID))
+	items, err := r.queries.GetSharesByOwner(ctx, uint64(ownerID))
 	if err != nil {
 		return nil, err
 	}
 
+	// Rows arrive sorted by category, so each category's shares are contiguous
+	categories := make([]models.CategoryWithShares, 0)
+	for _, item := range items {
+		if len(categories) == 0 || categories[len(categories)-1].ID != uint(item.CategoryID) {
+			categories = append(categories, models.CategoryWithShares{
+				ID:     uint(item.CategoryID),
+				Name:   item.CategoryName,
+				Shares: []models.CategoryShareWithUser{},
+			})
+		}
+		current := &categories[len(categories)-1]
+		current.Shares = append(current.Shares, models.CategoryShareWithUser{
+			ID:                  uint(item.ID),
+			CategoryID:          uint(item.CategoryID),
+			SharedWithUserID:    uint(item.SharedWithUserID),
+			Permission:          models.Permission(item.Permission),
+			CreatedAt:           item.CreatedAt,
+			SharedWithUserName:  item.SharedWithUserName,
+			SharedWithUserEmail: item.SharedWithUserEmail,
+		})
+	}
+	return categories, nil
+}
+
+// GetGrantedMembersByOwner retrieves every user with a share on one of the owner's categories, once each and
+// ordered by email, with the categories they were granted ordered by name
+func (r *SQLCategoryShareRepository) GetGrantedMembersByOwner(ctx context.Context, ownerID uint) ([]models.GrantedMember, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	items, err := r.queries.GetGrantedMembersByOwner(ctx, uint64(ownerID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows arrive sorted by user, so each user's grants are contiguous
+	members := make([]models.GrantedMember, 0)
+	for _, item := range items {
+		if len(members) == 0 || members[len(members)-1].UserID != uint(item.UserID) {
+			members = append(members, models.GrantedMember{
+				UserID:     uint(item.UserID),
+				Name:       item.UserName,
+				Email:      item.UserEmail,
+				Categories: []models.MemberGrant{},
+			})
+		}
+		current := &members[len(members)-1]
+		current.Categories = append(current.Categories, models.MemberGrant{
+			CategoryID:   uint(item.CategoryID),
+			CategoryName: item.CategoryName,
+			Permission:   models.Permission(item.Permission),
+		})
+	}
+	return members, nil
+}
+
+// GetSharedCategoriesForUser retrieves a page of the categories shared with a user, ordered by name,
+// and the total number of matching shares. An empty permission matches every share.
+func (r *SQLCategoryShareRepository) GetSharedCategoriesForUser(ctx context.Context, userID uint, permission models.Permission, page, pageSize int) ([]models.SharedCategoryWithOwner, int64, error) {
+	if r.queries == nil {
+		return nil, 0, sql.ErrConnDone
+	}
+
+	total, err := r.queries.CountSharedCategoriesForUser(ctx, db.CountSharedCategoriesForUserParams{
+		SharedWithUserID: uint64(userID),
+		Permission:       string(permission),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []models.SharedCategoryWithOwner{}, total, nil
+	}
+
+	limit, offset := limitOffset(page, pageSize)
+	items, err := r.queries.GetSharedCategoriesForUser(ctx, db.GetSharedCategoriesForUserParams{
+		SharedWithUserID: uint64(userID),
+		Permission:       string(permission),
+		Limit:            limit,
+		Offset:           offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
 	categories := make([]models.SharedCategoryWithOwner, 0, len(items))
 	for _, item := range items {
 		categories = append(categories, models.SharedCategoryWithOwner{
@@ -137,7 +240,7 @@ func (r *SQLCategoryShareRepository) GetSharedCategoriesForUser(ctx context.Cont
 			OwnerEmail: item.OwnerEmail,
 		})
 	}
-	return categories, nil
+	return categories, total, nil
 }
 
 // UpdateCategorySharePermission updates the permission for a share
@@ -171,6 +274,14 @@ func (r *SQLCategoryShareRepository) DeleteCategoryShareByUserAndCategory(ctx co
 	})
 }
 
+// DeleteAllSharesForCategory removes every share of a category in a single DELETE and returns how many were removed
+func (r *SQLCategoryShareRepository) DeleteAllSharesForCategory(ctx context.Context, categoryID uint) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+	return r.queries.DeleteAllSharesForCategory(ctx, uint64(categoryID))
+}
+
 // GetUserPermissionForCategory gets the user's permission for a category
 func (r *SQLCategoryShareRepository) GetUserPermissionForCategory(ctx context.Context, userID, categoryID uint) (string, error) {
 	if r.queries == nil {
@@ -197,16 +308,15 @@ func (r *SQLCategoryShareRepository) GetUserPermissionForCategory(ctx context.Co
 }
 
 // GetTodosGroupedByCategory retrieves all todos grouped by categories accessible to the user
-func (r *SQLCategoryShareRepository) GetTodosGroupedByCategory(ctx context.Context, userID uint) ([]models.CategoryWithTodosRow, error) {
+// scope "owned" or "shared" limits the categories to one side; any other value returns both
+func (r *SQLCategoryShareRepository) GetTodosGroupedByCategory(ctx context.Context, userID uint, scope string) ([]models.CategoryWithTodosRow, error) {
 	if r.queries == nil {
 		return nil, sql.ErrConnDone
 	}
 
 	items, err := r.queries.GetTodosGroupedByCategory(ctx, db.GetTodosGroupedByCategoryParams{
-		OwnerID:            uint64(userID),
-		SharedWithUserID:   uint64(userID),
-		OwnerID_2:          uint64(userID),
-		SharedWithUserID_2: uint64(userID),
+		UserID: uint64(userID),
+		Scope:  scope,
 	})
 	if err != nil {
 		return nil, err
@@ -228,14 +338,12 @@ func (r *SQLCategoryShareRepository) GetTodosGroupedByCategory(ctx context.Conte
 			}
 		}
 
-		var createdAt, updatedAt *string
+		var createdAt, updatedAt *time.Time
 		if item.TodoCreatedAt.Valid {
-			t := item.TodoCreatedAt.Time.Format("2006-01-02T15:04:05Z")
-			createdAt = &t
+			createdAt = &item.TodoCreatedAt.Time
 		}
 		if item.TodoUpdatedAt.Valid {
-			t := item.TodoUpdatedAt.Time.Format("2006-01-02T15:04:05Z")
-			updatedAt = &t
+			updatedAt = &item.TodoUpdatedAt.Time
 		}
 
 		rows = append(rows, models.CategoryWithTodosRow{
@@ -256,3 +364,25 @@ func (r *SQLCategoryShareRepository) GetTodosGroupedByCategory(ctx context.Conte
 	}
 	return rows, nil
 }
+
+// GetWritableCategoriesForUser retrieves the owned and write-shared categories of a user without their todos
+func (r *SQLCategoryShareRepository) GetWritableCategoriesForUser(ctx context.Context, userID uint) ([]models.WritableCategory, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	items, err := r.queries.GetWritableCategoriesForUser(ctx, uint64(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make([]models.WritableCategory, 0, len(items))
+	for _, item := range items {
+		categories = append(categories, models.WritableCategory{
+			ID:         uint(item.ID),
+			Name:       item.Name,
+			Permission: item.Permission,
+		})
+	}
+	return categories, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"todo-app/db"
+	"todo-app/internal/models"
+)
+
+// Ensure SQLCommentRepository implements CommentRepository
+var _ CommentRepository = (*SQLCommentRepository)(nil)
+
+// SQLCommentRepository implements CommentRepository using sqlc-generated queries
+type SQLCommentRepository struct {
+	queries *db.Queries
+}
+
+// NewSQLCommentRepository creates a new CommentRepository with the provided queries instance
+func NewSQLCommentRepository(queries *db.Queries) CommentRepository {
+	return &SQLCommentRepository{queries: queries}
+}
+
+// toModelComment converts db.TodoComment to models.Comment
+func toModelComment(c db.TodoComment) models.Comment {
+	return models.Comment{
+		ID:        uint(c.ID),
+		TodoID:    uint(c.TodoID),
+		AuthorID:  uint(c.AuthorID),
+		Body:      c.Body,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+// CreateComment inserts a new comment and fills in its generated fields
+func (r *SQLCommentRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	if r.queries == nil {
+		return sql.ErrConnDone
+	}
+
+	id, err := r.queries.CreateTodoComment(ctx, db.CreateTodoCommentParams{
+		TodoID:   uint64(comment.TodoID),
+		AuthorID: uint64(comment.AuthorID),
+		Body:     comment.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	created, err := r.queries.GetTodoCommentByID(ctx, uint64(id))
+	if err != nil {
+		return err
+	}
+	*comment = toModelComment(created)
+	return nil
+}
+
+// GetCommentByID retrieves a comment by ID, returning sql.ErrNoRows if there is none
+func (r *SQLCommentRepository) GetCommentByID(ctx context.Context, id uint) (*models.Comment, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	c, err := r.queries.GetTodoCommentByID(ctx, uint64(id))
+	if err != nil {
+		return nil, err
+	}
+	comment := toModelComment(c)
+	return &comment, nil
+}
+
+// GetCommentsByTodoID retrieves a todo's comments, oldest first, with pagination
+func (r *SQLCommentRepository) GetCommentsByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]models.Comment, int64, error) {
+	if r.queries == nil {
+		return nil, 0, sql.ErrConnDone
+	}
+
+	total, err := r.queries.CountTodoCommentsByTodoID(ctx, uint64(todoID))
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []models.Comment{}, total, nil
+	}
+
+	limit, offset := limitOffset(page, pageSize)
+	items, err := r.queries.GetTodoCommentsByTodoID(ctx, db.GetTodoCommentsByTodoIDParams{
+		TodoID: uint64(todoID),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments := make([]models.Comment, 0, len(items))
+	for _, it := range items {
+		comments = append(comments, toModelComment(it))
+	}
+	return comments, total, nil
+}
+
+// DeleteComment permanently removes a comment, reporting false if it did not exist
+func (r *SQLCommentRepository) DeleteComment(ctx context.Context, id uint) (bool, error) {
+	if r.queries == nil {
+		return false, sql.ErrConnDone
+	}
+
+	affected, err := r.queries.DeleteTodoComment(ctx, uint64(id))
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrDuplicateEmail is returned by CreateUser when the email is already taken, including when another
+// registration inserted it between the caller's existence check and this insert
+var ErrDuplicateEmail = errors.New("email already exists")
+
+// mysqlDuplicateEntry is the MySQL error number for a unique key violation (ER_DUP_ENTRY)
+const mysqlDuplicateEntry = 1062
+
+// isDuplicateKeyError reports whether err is a MySQL unique key violation
+func isDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
@@ -2,18 +2,82 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"todo-app/internal/models"
 )
 
+// TodoCreatorFilter narrows todo listings by who created them. Zero fields are ignored,
+// so the zero value matches todos from every creator.
+type TodoCreatorFilter struct {
+	CreatedBy    uint // only todos created by this user
+	NotCreatedBy uint // only todos not created by this user
+}
+
+// TodoKeyset identifies the last todo of a page listed newest first (created_at DESC, id DESC);
+// the next page holds the todos that sort after it
+type TodoKeyset struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
 // TodoRepository defines persistence operations for todos
 type TodoRepository interface {
 	CreateTodo(ctx context.Context, todo *models.Todo) error
-	GetTodos(ctx context.Context, userID uint, page, pageSize int) ([]models.Todo, int64, error)
-	GetTodosByCategoryID(ctx context.Context, categoryID uint, page, pageSize int) ([]models.Todo, int64, error)
+	GetTodos(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error)
+	CountTodos(ctx context.Context, userID uint, completed *bool) (int64, error)
+	GetTodosByCategoryID(ctx context.Context, categoryID uint, creator TodoCreatorFilter, page, pageSize int) ([]models.Todo, int64, error)
+	GetTodosByCategoryIDAfter(ctx context.Context, categoryID uint, creator TodoCreatorFilter, after *TodoKeyset, limit int) ([]models.Todo, error)
+	CountTodosInCategory(ctx context.Context, categoryID uint) (int64, error)
+	CountTodosByStatusInCategory(ctx context.Context, categoryID uint) (models.TodoStatusCounts, error)
 	GetTodoByID(ctx context.Context, id uint) (*models.Todo, error)
+	GetTodoByIDIncludingDeleted(ctx context.Context, id uint) (*models.Todo, error)
+	GetTodosByIDs(ctx context.Context, ids []uint) ([]models.Todo, error)
+	GetTodosInAccessibleCategories(ctx context.Context, userID uint, categoryIDs []uint) ([]models.Todo, error)
+	GetTodosInCategories(ctx context.Context, userID uint, categoryIDs []uint, page, pageSize int) ([]models.Todo, int64, error)
 	UpdateTodo(ctx context.Context, todo *models.Todo) error
+	TouchTodo(ctx context.Context, id uint) (*models.Todo, error)
 	DeleteTodo(ctx context.Context, id uint) error
+	RestoreTodo(ctx context.Context, id uint) (bool, error)
+	GetDeletedTodos(ctx context.Context, userID, categoryID uint) ([]models.Todo, error)
+	RestoreTodos(ctx context.Context, ids []uint) (int64, error)
+	HasTodoWithTitle(ctx context.Context, categoryID uint, title string) (bool, error)
+	GetTodoIDsInCategory(ctx context.Context, categoryID uint, completed *bool) ([]uint, error)
+	MoveTodos(ctx context.Context, ids []uint, toCategoryID, toOwnerID uint) (int64, error)
+	SetCompleted(ctx context.Context, ids []uint, completed bool) (int64, error)
+	GetDueReminders(ctx context.Context, now time.Time, limit int) ([]models.Todo, error)
+	GetRecentTodos(ctx context.Context, userID uint, limit int) ([]models.Todo, error)
+	GetTodoChangesSince(ctx context.Context, userID uint, since time.Time) ([]models.TodoChange, error)
+	GetTodosChangedSince(ctx context.Context, userID uint, since time.Time) ([]models.Todo, error)
+	MarkReminderSent(ctx context.Context, id uint, now time.Time) (bool, error)
+	ReleaseReminder(ctx context.Context, id uint) error
+	PurgeDeletedTodosBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+	CountCompletedTodosBefore(ctx context.Context, userID uint, before time.Time) (int64, error)
+	CountCompletedTodosByDay(ctx context.Context, userID uint, from, to time.Time, loc *time.Location) ([]models.CompletionCount, error)
+	GetCompletedTodoIDsBefore(ctx context.Context, userID uint, before time.Time) ([]uint, error)
+	DeleteTodos(ctx context.Context, ids []uint) (int64, error)
+}
+
+// TodoEventRepository defines persistence operations for the append-only todo history
+type TodoEventRepository interface {
+	CreateTodoEvent(ctx context.Context, event *models.TodoEvent) error
+	GetTodoEvents(ctx context.Context, todoID uint, page, pageSize int) ([]models.TodoEvent, int64, error)
+}
+
+// CommentRepository defines persistence operations for comments on todos
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment *models.Comment) error
+	GetCommentByID(ctx context.Context, id uint) (*models.Comment, error)
+	GetCommentsByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]models.Comment, int64, error)
+	DeleteComment(ctx context.Context, id uint) (bool, error)
+}
+
+// APITokenRepository defines persistence operations for personal access tokens
+type APITokenRepository interface {
+	CreateAPIToken(ctx context.Context, token *models.APIToken) error
+	GetActiveAPITokenByHash(ctx context.Context, tokenHash string) (*models.APIToken, error)
+	ListAPITokens(ctx context.Context, userID uint) ([]models.APIToken, error)
+	RevokeAPIToken(ctx context.Context, id, userID uint) (bool, error)
 }
 
 // UserRepository defines persistence operations for users
@@ -21,6 +85,11 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUserByID(ctx context.Context, id uint) (*models.User, error)
+	GetUsersByIDs(ctx context.Context, ids []uint) ([]models.User, error)
+	UpdateUserTimezone(ctx context.Context, id uint, timezone string) error
+	GetUsers(ctx context.Context, page, pageSize int) ([]models.User, int64, error)
+	SetAdminByEmail(ctx context.Context, email string) (bool, error)
+	NormalizeEmails(ctx context.Context) (int64, error)
 }
 
 // CategoryRepository defines persistence operations for categories
@@ -28,9 +97,13 @@ type CategoryRepository interface {
 	CreateCategory(ctx context.Context, category *models.Category) error
 	GetCategoryByID(ctx context.Context, id uint) (*models.Category, error)
 	GetCategoriesByOwnerID(ctx context.Context, ownerID uint) ([]models.Category, error)
+	GetCategoriesByIDs(ctx context.Context, ids []uint) ([]models.Category, error)
 	GetCategoryByNameAndOwner(ctx context.Context, ownerID uint, name string) (*models.Category, error)
 	UpdateCategory(ctx context.Context, category *models.Category) error
 	DeleteCategory(ctx context.Context, id uint) error
+	GetEmptyCategoriesByOwnerID(ctx context.Context, ownerID uint) ([]models.Category, error)
+	DeleteEmptyCategory(ctx context.Context, id uint) (bool, error)
+	LockCategory(ctx context.Context, id uint) error
 }
 
 // CategoryShareRepository defines persistence operations for category shares
@@ -38,11 +111,31 @@ type CategoryShareRepository interface {
 	CreateCategoryShare(ctx context.Context, share *models.CategoryShare) error
 	GetCategoryShareByID(ctx context.Context, id uint) (*models.CategoryShare, error)
 	GetCategoryShareByCategoryAndUser(ctx context.Context, categoryID, userID uint) (*models.CategoryShare, error)
-	GetSharesForCategory(ctx context.Context, categoryID uint) ([]models.CategoryShareWithUser, error)
-	GetSharedCategoriesForUser(ctx context.Context, userID uint) ([]models.SharedCategoryWithOwner, error)
+	GetSharesForCategory(ctx context.Context, categoryID uint, sortBy string, page, pageSize int) ([]models.CategoryShareWithUser, int64, error)
+	GetSharedCategoriesForUser(ctx context.Context, userID uint, permission models.Permission, page, pageSize int) ([]models.SharedCategoryWithOwner, int64, error)
 	UpdateCategorySharePermission(ctx context.Context, id uint, permission models.Permission) error
 	DeleteCategoryShare(ctx context.Context, id uint) error
 	DeleteCategoryShareByUserAndCategory(ctx context.Context, categoryID, userID uint) error
+	DeleteAllSharesForCategory(ctx context.Context, categoryID uint) (int64, error)
 	GetUserPermissionForCategory(ctx context.Context, userID, categoryID uint) (string, error)
-	GetTodosGroupedByCategory(ctx context.Context, userID uint) ([]models.CategoryWithTodosRow, error)
+	GetTodosGroupedByCategory(ctx context.Context, userID uint, scope string) ([]models.CategoryWithTodosRow, error)
+	GetWritableCategoriesForUser(ctx context.Context, userID uint) ([]models.WritableCategory, error)
+	GetCategoriesSharedByOwner(ctx context.Context, ownerID uint) ([]models.CategoryWithShares, error)
+	GetGrantedMembersByOwner(ctx context.Context, ownerID uint) ([]models.GrantedMember, error)
+}
+
+// RepoSet groups the repositories available inside a transaction
+type RepoSet struct {
+	Todos          TodoRepository
+	Categories     CategoryRepository
+	CategoryShares CategoryShareRepository
+	TodoEvents     TodoEventRepository
+	Users          UserRepository
+}
+
+// TxManager runs multi-statement operations atomically
+type TxManager interface {
+	// WithinTx calls fn with repositories bound to one transaction, committing if fn returns nil
+	// and rolling back otherwise
+	WithinTx(ctx context.Context, fn func(repos RepoSet) error) error
 }
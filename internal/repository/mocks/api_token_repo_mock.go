@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"context"
+	"database/sql"
+
+	"todo-app/internal/models"
+	"todo-app/internal/repository"
+)
+
+// Ensure MockAPITokenRepository implements APITokenRepository
+var _ repository.APITokenRepository = (*MockAPITokenRepository)(nil)
+
+// MockAPITokenRepository is a mock implementation of APITokenRepository for testing
+type MockAPITokenRepository struct {
+	CreateAPITokenFunc          func(ctx context.Context, token *models.APIToken) error
+	GetActiveAPITokenByHashFunc func(ctx context.Context, tokenHash string) (*models.APIToken, error)
+	ListAPITokensFunc           func(ctx context.Context, userID uint) ([]models.APIToken, error)
+	RevokeAPITokenFunc          func(ctx context.Context, id, userID uint) (bool, error)
+}
+
+// CreateAPIToken calls the mock function
+func (m *MockAPITokenRepository) CreateAPIToken(ctx context.Context, token *models.APIToken) error {
+	if m.CreateAPITokenFunc != nil {
+		return m.CreateAPITokenFunc(ctx, token)
+	}
+	return nil
+}
+
+// GetActiveAPITokenByHash calls the mock function
+func (m *MockAPITokenRepository) GetActiveAPITokenByHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	if m.GetActiveAPITokenByHashFunc != nil {
+		return m.GetActiveAPITokenByHashFunc(ctx, tokenHash)
+	}
+	return nil, sql.ErrNoRows
+}
+
+// ListAPITokens calls the mock function
+func (m *MockAPITokenRepository) ListAPITokens(ctx context.Context, userID uint) ([]models.APIToken, error) {
+	if m.ListAPITokensFunc != nil {
+		return m.ListAPITokensFunc(ctx, userID)
+	}
+	return []models.APIToken{}, nil
+}
+
+// RevokeAPIToken calls the mock function
+func (m *MockAPITokenRepository) RevokeAPIToken(ctx context.Context, id, userID uint) (bool, error) {
+	if m.RevokeAPITokenFunc != nil {
+		return m.RevokeAPITokenFunc(ctx, id, userID)
+	}
+	return true, nil
+}
@@ -12,12 +12,16 @@ var _ repository.CategoryRepository = (*MockCategoryRepository)(nil)
 
 // MockCategoryRepository is a mock implementation of CategoryRepository for testing
 type MockCategoryRepository struct {
-	CreateCategoryFunc          func(ctx context.Context, category *models.Category) error
-	GetCategoryByIDFunc         func(ctx context.Context, id uint) (*models.Category, error)
-	GetCategoriesByOwnerIDFunc  func(ctx context.Context, ownerID uint) ([]models.Category, error)
-	GetCategoryByNameAndOwnerFunc func(ctx context.Context, ownerID uint, name string) (*models.Category, error)
-	UpdateCategoryFunc          func(ctx context.Context, category *models.Category) error
-	DeleteCategoryFunc          func(ctx context.Context, id uint) error
+	CreateCategoryFunc              func(ctx context.Context, category *models.Category) error
+	GetCategoryByIDFunc             func(ctx context.Context, id uint) (*models.Category, error)
+	GetCategoriesByOwnerIDFunc      func(ctx context.Context, ownerID uint) ([]models.Category, error)
+	GetCategoriesByIDsFunc          func(ctx context.Context, ids []uint) ([]models.Category, error)
+	GetCategoryByNameAndOwnerFunc   func(ctx context.Context, ownerID uint, name string) (*models.Category, error)
+	UpdateCategoryFunc              func(ctx context.Context, category *models.Category) error
+	DeleteCategoryFunc              func(ctx context.Context, id uint) error
+	GetEmptyCategoriesByOwnerIDFunc func(ctx context.Context, ownerID uint) ([]models.Category, error)
+	DeleteEmptyCategoryFunc         func(ctx context.Context, id uint) (bool, error)
+	LockCategoryFunc                func(ctx context.Context, id uint) error
 }
 
 // CreateCategory calls the mock function
@@ -44,6 +48,14 @@ func (m *MockCategoryRepository) GetCategoriesByOwnerID(ctx context.Context, own
 	return []models.Category{}, nil
 }
 
+// GetCategoriesByIDs calls the mock function
+func (m *MockCategoryRepository) GetCategoriesByIDs(ctx context.Context, ids []uint) ([]models.Category, error) {
+	if m.GetCategoriesByIDsFunc != nil {
+		return m.GetCategoriesByIDsFunc(ctx, ids)
+	}
+	return []models.Category{}, nil
+}
+
 // GetCategoryByNameAndOwner calls the mock function
 func (m *MockCategoryRepository) GetCategoryByNameAndOwner(ctx context.Context, ownerID uint, name string) (*models.Category, error) {
 	if m.GetCategoryByNameAndOwnerFunc != nil {
@@ -67,3 +79,27 @@ func (m *MockCategoryRepository) DeleteCategory(ctx context.Context, id uint) er
 	}
 	return nil
 }
+
+// GetEmptyCategoriesByOwnerID calls the mock function
+func (m *MockCategoryRepository) GetEmptyCategoriesByOwnerID(ctx context.Context, ownerID uint) ([]models.Category, error) {
+	if m.GetEmptyCategoriesByOwnerIDFunc != nil {
+		return m.GetEmptyCategoriesByOwnerIDFunc(ctx, ownerID)
+	}
+	return []models.Category{}, nil
+}
+
+// DeleteEmptyCategory calls the mock function
+func (m *MockCategoryRepository) DeleteEmptyCategory(ctx context.Context, id uint) (bool, error) {
+	if m.DeleteEmptyCategoryFunc != nil {
+		return m.DeleteEmptyCategoryFunc(ctx, id)
+	}
+	return true, nil
+}
+
+// LockCategory calls the mock function
+func (m *MockCategoryRepository) LockCategory(ctx context.Context, id uint) error {
+	if m.LockCategoryFunc != nil {
+		return m.LockCategoryFunc(ctx, id)
+	}
+	return nil
+}
@@ -15,13 +15,17 @@ type MockCategoryShareRepository struct {
 	CreateCategoryShareFunc                  func(ctx context.Context, share *models.CategoryShare) error
 	GetCategoryShareByIDFunc                 func(ctx context.Context, id uint) (*models.CategoryShare, error)
 	GetCategoryShareByCategoryAndUserFunc    func(ctx context.Context, categoryID, userID uint) (*models.CategoryShare, error)
-	GetSharesForCategoryFunc                 func(ctx context.Context, categoryID uint) ([]models.CategoryShareWithUser, error)
-	GetSharedCategoriesForUserFunc           func(ctx context.Context, userID uint) ([]models.SharedCategoryWithOwner, error)
+	GetSharesForCategoryFunc                 func(ctx context.Context, categoryID uint, sortBy string, page, pageSize int) ([]models.CategoryShareWithUser, int64, error)
+	GetSharedCategoriesForUserFunc           func(ctx context.Context, userID uint, permission models.Permission, page, pageSize int) ([]models.SharedCategoryWithOwner, int64, error)
 	UpdateCategorySharePermissionFunc        func(ctx context.Context, id uint, permission models.Permission) error
 	DeleteCategoryShareFunc                  func(ctx context.Context, id uint) error
 	DeleteCategoryShareByUserAndCategoryFunc func(ctx context.Context, categoryID, userID uint) error
+	DeleteAllSharesForCategoryFunc           func(ctx context.Context, categoryID uint) (int64, error)
 	GetUserPermissionForCategoryFunc         func(ctx context.Context, userID, categoryID uint) (string, error)
-	GetTodosGroupedByCategoryFunc            func(ctx context.Context, userID uint) ([]models.CategoryWithTodosRow, error)
+	GetTodosGroupedByCategoryFunc            func(ctx context.Context, userID uint, scope string) ([]models.CategoryWithTodosRow, error)
+	GetWritableCategoriesForUserFunc         func(ctx context.Context, userID uint) ([]models.WritableCategory, error)
+	GetCategoriesSharedByOwnerFunc           func(ctx context.Context, ownerID uint) ([]models.CategoryWithShares, error)
+	GetGrantedMembersByOwnerFunc             func(ctx context.Context, ownerID uint) ([]models.GrantedMember, error)
 }
 
 // CreateCategoryShare calls the mock function
@@ -49,19 +53,19 @@ func (m *MockCategoryShareRepository) GetCategoryShareByCategoryAndUser(ctx cont
 }
 
 // GetSharesForCategory calls the mock function
-func (m *MockCategoryShareRepository) GetSharesForCategory(ctx context.Context, categoryID uint) ([]models.CategoryShareWithUser, error) {
+func (m *MockCategoryShareRepository) GetSharesForCategory(ctx context.Context, categoryID uint, sortBy string, page, pageSize int) ([]models.CategoryShareWithUser, int64, error) {
 	if m.GetSharesForCategoryFunc != nil {
-		return m.GetSharesForCategoryFunc(ctx, categoryID)
+		return m.GetSharesForCategoryFunc(ctx, categoryID, sortBy, page, pageSize)
 	}
-	return []models.CategoryShareWithUser{}, nil
+	return []models.CategoryShareWithUser{}, 0, nil
 }
 
 // GetSharedCategoriesForUser calls the mock function
-func (m *MockCategoryShareRepository) GetSharedCategoriesForUser(ctx context.Context, userID uint) ([]models.SharedCategoryWithOwner, error) {
+func (m *MockCategoryShareRepository) GetSharedCategoriesForUser(ctx context.Context, userID uint, permission models.Permission, page, pageSize int) ([]models.SharedCategoryWithOwner, int64, error) {
 	if m.GetSharedCategoriesForUserFunc != nil {
-		return m.GetSharedCategoriesForUserFunc(ctx, userID)
+		return m.GetSharedCategoriesForUserFunc(ctx, userID, permission, page, pageSize)
 	}
-	return []models.SharedCategoryWithOwner{}, nil
+	return []models.SharedCategoryWithOwner{}, 0, nil
 }
 
 // UpdateCategorySharePermission calls the mock function
@@ -88,6 +92,14 @@ func (m *MockCategoryShareRepository) DeleteCategoryShareByUserAndCategory(ctx c
 	return nil
 }
 
+// DeleteAllSharesForCategory calls the mock function
+func (m *MockCategoryShareRepository) DeleteAllSharesForCategory(ctx context.Context, categoryID uint) (int64, error) {
+	if m.DeleteAllSharesForCategoryFunc != nil {
+		return m.DeleteAllSharesForCategoryFunc(ctx, categoryID)
+	}
+	return 0, nil
+}
+
 // GetUserPermissionForCategory calls the mock function
 func (m *MockCategoryShareRepository) GetUserPermissionForCategory(ctx context.Context, userID, categoryID uint) (string, error) {
 	if m.GetUserPermissionForCategoryFunc != nil {
@@ -97,9 +109,33 @@ func (m *MockCategoryShareRepository) GetUserPermissionForCategory(ctx context.C
 }
 
 // GetTodosGroupedByCategory calls the mock function
-func (m *MockCategoryShareRepository) GetTodosGroupedByCategory(ctx context.Context, userID uint) ([]models.CategoryWithTodosRow, error) {
+func (m *MockCategoryShareRepository) GetTodosGroupedByCategory(ctx context.Context, userID uint, scope string) ([]models.CategoryWithTodosRow, error) {
 	if m.GetTodosGroupedByCategoryFunc != nil {
-		return m.GetTodosGroupedByCategoryFunc(ctx, userID)
+		return m.GetTodosGroupedByCategoryFunc(ctx, userID, scope)
 	}
 	return []models.CategoryWithTodosRow{}, nil
 }
+
+// GetWritableCategoriesForUser calls the mock function
+func (m *MockCategoryShareRepository) GetWritableCategoriesForUser(ctx context.Context, userID uint) ([]models.WritableCategory, error) {
+	if m.GetWritableCategoriesForUserFunc != nil {
+		return m.GetWritableCategoriesForUserFunc(ctx, userID)
+	}
+	return []models.WritableCategory{}, nil
+}
+
+// GetCategoriesSharedByOwner calls the mock function
+func (m *MockCategoryShareRepository) GetCategoriesSharedByOwner(ctx context.Context, ownerID uint) ([]models.CategoryWithShares, error) {
+	if m.GetCategoriesSharedByOwnerFunc != nil {
+		return m.GetCategoriesSharedByOwnerFunc(ctx, ownerID)
+	}
+	return []models.CategoryWithShares{}, nil
+}
+
+// GetGrantedMembersByOwner calls the mock function
+func (m *MockCategoryShareRepository) GetGrantedMembersByOwner(ctx context.Context, ownerID uint) ([]models.GrantedMember, error) {
+	if m.GetGrantedMembersByOwnerFunc != nil {
+		return m.GetGrantedMembersByOwnerFunc(ctx, ownerID)
+	}
+	return []models.GrantedMember{}, nil
+}
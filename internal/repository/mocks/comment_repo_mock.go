@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"context"
+	"database/sql"
+
+	"todo-app/internal/models"
+	"todo-app/internal/repository"
+)
+
+// Ensure MockCommentRepository implements CommentRepository
+var _ repository.CommentRepository = (*MockCommentRepository)(nil)
+
+// MockCommentRepository is a mock implementation of CommentRepository for testing
+type MockCommentRepository struct {
+	CreateCommentFunc       func(ctx context.Context, comment *models.Comment) error
+	GetCommentByIDFunc      func(ctx context.Context, id uint) (*models.Comment, error)
+	GetCommentsByTodoIDFunc func(ctx context.Context, todoID uint, page, pageSize int) ([]models.Comment, int64, error)
+	DeleteCommentFunc       func(ctx context.Context, id uint) (bool, error)
+}
+
+// CreateComment calls the mock function
+func (m *MockCommentRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	if m.CreateCommentFunc != nil {
+		return m.CreateCommentFunc(ctx, comment)
+	}
+	return nil
+}
+
+// GetCommentByID calls the mock function
+func (m *MockCommentRepository) GetCommentByID(ctx context.Context, id uint) (*models.Comment, error) {
+	if m.GetCommentByIDFunc != nil {
+		return m.GetCommentByIDFunc(ctx, id)
+	}
+	return nil, sql.ErrNoRows
+}
+
+// GetCommentsByTodoID calls the mock function
+func (m *MockCommentRepository) GetCommentsByTodoID(ctx context.Context, todoID uint, page, pageSize int) ([]models.Comment, int64, error) {
+	if m.GetCommentsByTodoIDFunc != nil {
+		return m.GetCommentsByTodoIDFunc(ctx, todoID, page, pageSize)
+	}
+	return []models.Comment{}, 0, nil
+}
+
+// DeleteComment calls the mock function
+func (m *MockCommentRepository) DeleteComment(ctx context.Context, id uint) (bool, error) {
+	if m.DeleteCommentFunc != nil {
+		return m.DeleteCommentFunc(ctx, id)
+	}
+	return true, nil
+}
@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"context"
+
+	"todo-app/internal/models"
+	"todo-app/internal/repository"
+)
+
+// Ensure MockTodoEventRepository implements TodoEventRepository
+var _ repository.TodoEventRepository = (*MockTodoEventRepository)(nil)
+
+// MockTodoEventRepository is a mock implementation of TodoEventRepository for testing
+type MockTodoEventRepository struct {
+	CreateTodoEventFunc func(ctx context.Context, event *models.TodoEvent) error
+	GetTodoEventsFunc   func(ctx context.Context, todoID uint, page, pageSize int) ([]models.TodoEvent, int64, error)
+}
+
+// CreateTodoEvent calls the mock function
+func (m *MockTodoEventRepository) CreateTodoEvent(ctx context.Context, event *models.TodoEvent) error {
+	if m.CreateTodoEventFunc != nil {
+		return m.CreateTodoEventFunc(ctx, event)
+	}
+	return nil
+}
+
+// GetTodoEvents calls the mock function
+func (m *MockTodoEventRepository) GetTodoEvents(ctx context.Context, todoID uint, page, pageSize int) ([]models.TodoEvent, int64, error) {
+	if m.GetTodoEventsFunc != nil {
+		return m.GetTodoEventsFunc(ctx, todoID, page, pageSize)
+	}
+	return []models.TodoEvent{}, 0, nil
+}
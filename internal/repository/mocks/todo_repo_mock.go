@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"todo-app/internal/models"
 	"todo-app/internal/repository"
@@ -12,12 +13,39 @@ var _ repository.TodoRepository = (*MockTodoRepository)(nil)
 
 // MockTodoRepository is a mock implementation of TodoRepository for testing
 type MockTodoRepository struct {
-	CreateTodoFunc           func(ctx context.Context, todo *models.Todo) error
-	GetTodosFunc             func(ctx context.Context, userID uint, page, pageSize int) ([]models.Todo, int64, error)
-	GetTodosByCategoryIDFunc func(ctx context.Context, categoryID uint, page, pageSize int) ([]models.Todo, int64, error)
-	GetTodoByIDFunc          func(ctx context.Context, id uint) (*models.Todo, error)
-	UpdateTodoFunc           func(ctx context.Context, todo *models.Todo) error
-	DeleteTodoFunc           func(ctx context.Context, id uint) error
+	CreateTodoFunc                     func(ctx context.Context, todo *models.Todo) error
+	GetTodosFunc                       func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error)
+	CountTodosFunc                     func(ctx context.Context, userID uint, completed *bool) (int64, error)
+	GetTodosByCategoryIDFunc           func(ctx context.Context, categoryID uint, creator repository.TodoCreatorFilter, page, pageSize int) ([]models.Todo, int64, error)
+	GetTodosByCategoryIDAfterFunc      func(ctx context.Context, categoryID uint, creator repository.TodoCreatorFilter, after *repository.TodoKeyset, limit int) ([]models.Todo, error)
+	CountTodosInCategoryFunc           func(ctx context.Context, categoryID uint) (int64, error)
+	CountTodosByStatusInCategoryFunc   func(ctx context.Context, categoryID uint) (models.TodoStatusCounts, error)
+	GetTodoByIDFunc                    func(ctx context.Context, id uint) (*models.Todo, error)
+	GetTodoByIDIncludingDeletedFunc    func(ctx context.Context, id uint) (*models.Todo, error)
+	GetTodosByIDsFunc                  func(ctx context.Context, ids []uint) ([]models.Todo, error)
+	GetTodosInAccessibleCategoriesFunc func(ctx context.Context, userID uint, categoryIDs []uint) ([]models.Todo, error)
+	GetTodosInCategoriesFunc           func(ctx context.Context, userID uint, categoryIDs []uint, page, pageSize int) ([]models.Todo, int64, error)
+	UpdateTodoFunc                     func(ctx context.Context, todo *models.Todo) error
+	DeleteTodoFunc                     func(ctx context.Context, id uint) error
+	RestoreTodoFunc                    func(ctx context.Context, id uint) (bool, error)
+	TouchTodoFunc                      func(ctx context.Context, id uint) (*models.Todo, error)
+	GetDeletedTodosFunc                func(ctx context.Context, userID, categoryID uint) ([]models.Todo, error)
+	RestoreTodosFunc                   func(ctx context.Context, ids []uint) (int64, error)
+	HasTodoWithTitleFunc               func(ctx context.Context, categoryID uint, title string) (bool, error)
+	GetTodoIDsInCategoryFunc           func(ctx context.Context, categoryID uint, completed *bool) ([]uint, error)
+	MoveTodosFunc                      func(ctx context.Context, ids []uint, toCategoryID, toOwnerID uint) (int64, error)
+	CountCompletedTodosBeforeFunc      func(ctx context.Context, userID uint, before time.Time) (int64, error)
+	CountCompletedTodosByDayFunc       func(ctx context.Context, userID uint, from, to time.Time, loc *time.Location) ([]models.CompletionCount, error)
+	GetCompletedTodoIDsBeforeFunc      func(ctx context.Context, userID uint, before time.Time) ([]uint, error)
+	DeleteTodosFunc                    func(ctx context.Context, ids []uint) (int64, error)
+	SetCompletedFunc                   func(ctx context.Context, ids []uint, completed bool) (int64, error)
+	GetDueRemindersFunc                func(ctx context.Context, now time.Time, limit int) ([]models.Todo, error)
+	GetRecentTodosFunc                 func(ctx context.Context, userID uint, limit int) ([]models.Todo, error)
+	GetTodoChangesSinceFunc            func(ctx context.Context, userID uint, since time.Time) ([]models.TodoChange, error)
+	GetTodosChangedSinceFunc           func(ctx context.Context, userID uint, since time.Time) ([]models.Todo, error)
+	MarkReminderSentFunc               func(ctx context.Context, id uint, now time.Time) (bool, error)
+	ReleaseReminderFunc                func(ctx context.Context, id uint) error
+	PurgeDeletedTodosBeforeFunc        func(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 // CreateTodo calls the mock function
@@ -29,21 +57,53 @@ func (m *MockTodoRepository) CreateTodo(ctx context.Context, todo *models.Todo)
 }
 
 // GetTodos calls the mock function
-func (m *MockTodoRepository) GetTodos(ctx context.Context, userID uint, page, pageSize int) ([]models.Todo, int64, error) {
+func (m *MockTodoRepository) GetTodos(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error) {
 	if m.GetTodosFunc != nil {
-		return m.GetTodosFunc(ctx, userID, page, pageSize)
+		return m.GetTodosFunc(ctx, userID, sortBy, page, pageSize)
 	}
 	return []models.Todo{}, 0, nil
 }
 
+// CountTodos calls the mock function
+func (m *MockTodoRepository) CountTodos(ctx context.Context, userID uint, completed *bool) (int64, error) {
+	if m.CountTodosFunc != nil {
+		return m.CountTodosFunc(ctx, userID, completed)
+	}
+	return 0, nil
+}
+
 // GetTodosByCategoryID calls the mock function
-func (m *MockTodoRepository) GetTodosByCategoryID(ctx context.Context, categoryID uint, page, pageSize int) ([]models.Todo, int64, error) {
+func (m *MockTodoRepository) GetTodosByCategoryID(ctx context.Context, categoryID uint, creator repository.TodoCreatorFilter, page, pageSize int) ([]models.Todo, int64, error) {
 	if m.GetTodosByCategoryIDFunc != nil {
-		return m.GetTodosByCategoryIDFunc(ctx, categoryID, page, pageSize)
+		return m.GetTodosByCategoryIDFunc(ctx, categoryID, creator, page, pageSize)
 	}
 	return []models.Todo{}, 0, nil
 }
 
+// GetTodosByCategoryIDAfter calls the mock function
+func (m *MockTodoRepository) GetTodosByCategoryIDAfter(ctx context.Context, categoryID uint, creator repository.TodoCreatorFilter, after *repository.TodoKeyset, limit int) ([]models.Todo, error) {
+	if m.GetTodosByCategoryIDAfterFunc != nil {
+		return m.GetTodosByCategoryIDAfterFunc(ctx, categoryID, creator, after, limit)
+	}
+	return []models.Todo{}, nil
+}
+
+// CountTodosInCategory calls the mock function
+func (m *MockTodoRepository) CountTodosInCategory(ctx context.Context, categoryID uint) (int64, error) {
+	if m.CountTodosInCategoryFunc != nil {
+		return m.CountTodosInCategoryFunc(ctx, categoryID)
+	}
+	return 0, nil
+}
+
+// CountTodosByStatusInCategory calls the mock function
+func (m *MockTodoRepository) CountTodosByStatusInCategory(ctx context.Context, categoryID uint) (models.TodoStatusCounts, error) {
+	if m.CountTodosByStatusInCategoryFunc != nil {
+		return m.CountTodosByStatusInCategoryFunc(ctx, categoryID)
+	}
+	return models.TodoStatusCounts{}, nil
+}
+
 // GetTodoByID calls the mock function
 func (m *MockTodoRepository) GetTodoByID(ctx context.Context, id uint) (*models.Todo, error) {
 	if m.GetTodoByIDFunc != nil {
@@ -52,6 +112,22 @@ func (m *MockTodoRepository) GetTodoByID(ctx context.Context, id uint) (*models.
 	return nil, nil
 }
 
+// GetTodoByIDIncludingDeleted calls the mock function
+func (m *MockTodoRepository) GetTodoByIDIncludingDeleted(ctx context.Context, id uint) (*models.Todo, error) {
+	if m.GetTodoByIDIncludingDeletedFunc != nil {
+		return m.GetTodoByIDIncludingDeletedFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+// GetTodosByIDs calls the mock function
+func (m *MockTodoRepository) GetTodosByIDs(ctx context.Context, ids []uint) ([]models.Todo, error) {
+	if m.GetTodosByIDsFunc != nil {
+		return m.GetTodosByIDsFunc(ctx, ids)
+	}
+	return []models.Todo{}, nil
+}
+
 // UpdateTodo calls the mock function
 func (m *MockTodoRepository) UpdateTodo(ctx context.Context, todo *models.Todo) error {
 	if m.UpdateTodoFunc != nil {
@@ -67,3 +143,171 @@ func (m *MockTodoRepository) DeleteTodo(ctx context.Context, id uint) error {
 	}
 	return nil
 }
+
+// RestoreTodo calls the mock function
+func (m *MockTodoRepository) RestoreTodo(ctx context.Context, id uint) (bool, error) {
+	if m.RestoreTodoFunc != nil {
+		return m.RestoreTodoFunc(ctx, id)
+	}
+	return true, nil
+}
+
+// TouchTodo calls the mock function
+func (m *MockTodoRepository) TouchTodo(ctx context.Context, id uint) (*models.Todo, error) {
+	if m.TouchTodoFunc != nil {
+		return m.TouchTodoFunc(ctx, id)
+	}
+	return &models.Todo{ID: id, UpdatedAt: time.Now()}, nil
+}
+
+// HasTodoWithTitle calls the mock function
+func (m *MockTodoRepository) HasTodoWithTitle(ctx context.Context, categoryID uint, title string) (bool, error) {
+	if m.HasTodoWithTitleFunc != nil {
+		return m.HasTodoWithTitleFunc(ctx, categoryID, title)
+	}
+	return false, nil
+}
+
+// GetTodoIDsInCategory calls the mock function
+func (m *MockTodoRepository) GetTodoIDsInCategory(ctx context.Context, categoryID uint, completed *bool) ([]uint, error) {
+	if m.GetTodoIDsInCategoryFunc != nil {
+		return m.GetTodoIDsInCategoryFunc(ctx, categoryID, completed)
+	}
+	return []uint{}, nil
+}
+
+// MoveTodos calls the mock function
+func (m *MockTodoRepository) MoveTodos(ctx context.Context, ids []uint, toCategoryID, toOwnerID uint) (int64, error) {
+	if m.MoveTodosFunc != nil {
+		return m.MoveTodosFunc(ctx, ids, toCategoryID, toOwnerID)
+	}
+	return int64(len(ids)), nil
+}
+
+// CountCompletedTodosBefore calls the mock function
+func (m *MockTodoRepository) CountCompletedTodosBefore(ctx context.Context, userID uint, before time.Time) (int64, error) {
+	if m.CountCompletedTodosBeforeFunc != nil {
+		return m.CountCompletedTodosBeforeFunc(ctx, userID, before)
+	}
+	return 0, nil
+}
+
+// CountCompletedTodosByDay calls the mock function
+func (m *MockTodoRepository) CountCompletedTodosByDay(ctx context.Context, userID uint, from, to time.Time, loc *time.Location) ([]models.CompletionCount, error) {
+	if m.CountCompletedTodosByDayFunc != nil {
+		return m.CountCompletedTodosByDayFunc(ctx, userID, from, to, loc)
+	}
+	return []models.CompletionCount{}, nil
+}
+
+// GetCompletedTodoIDsBefore calls the mock function
+func (m *MockTodoRepository) GetCompletedTodoIDsBefore(ctx context.Context, userID uint, before time.Time) ([]uint, error) {
+	if m.GetCompletedTodoIDsBeforeFunc != nil {
+		return m.GetCompletedTodoIDsBeforeFunc(ctx, userID, before)
+	}
+	return []uint{}, nil
+}
+
+// DeleteTodos calls the mock function
+func (m *MockTodoRepository) DeleteTodos(ctx context.Context, ids []uint) (int64, error) {
+	if m.DeleteTodosFunc != nil {
+		return m.DeleteTodosFunc(ctx, ids)
+	}
+	return int64(len(ids)), nil
+}
+
+// SetCompleted calls the mock function
+func (m *MockTodoRepository) SetCompleted(ctx context.Context, ids []uint, completed bool) (int64, error) {
+	if m.SetCompletedFunc != nil {
+		return m.SetCompletedFunc(ctx, ids, completed)
+	}
+	return int64(len(ids)), nil
+}
+
+// GetDueReminders calls the mock function
+func (m *MockTodoRepository) GetDueReminders(ctx context.Context, now time.Time, limit int) ([]models.Todo, error) {
+	if m.GetDueRemindersFunc != nil {
+		return m.GetDueRemindersFunc(ctx, now, limit)
+	}
+	return []models.Todo{}, nil
+}
+
+// MarkReminderSent calls the mock function
+func (m *MockTodoRepository) MarkReminderSent(ctx context.Context, id uint, now time.Time) (bool, error) {
+	if m.MarkReminderSentFunc != nil {
+		return m.MarkReminderSentFunc(ctx, id, now)
+	}
+	return true, nil
+}
+
+// ReleaseReminder calls the mock function
+func (m *MockTodoRepository) ReleaseReminder(ctx context.Context, id uint) error {
+	if m.ReleaseReminderFunc != nil {
+		return m.ReleaseReminderFunc(ctx, id)
+	}
+	return nil
+}
+
+// PurgeDeletedTodosBefore calls the mock function
+func (m *MockTodoRepository) PurgeDeletedTodosBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if m.PurgeDeletedTodosBeforeFunc != nil {
+		return m.PurgeDeletedTodosBeforeFunc(ctx, before, limit)
+	}
+	return 0, nil
+}
+
+// GetTodoChangesSince calls the mock function
+func (m *MockTodoRepository) GetTodoChangesSince(ctx context.Context, userID uint, since time.Time) ([]models.TodoChange, error) {
+	if m.GetTodoChangesSinceFunc != nil {
+		return m.GetTodoChangesSinceFunc(ctx, userID, since)
+	}
+	return []models.TodoChange{}, nil
+}
+
+// GetTodosChangedSince calls the mock function
+func (m *MockTodoRepository) GetTodosChangedSince(ctx context.Context, userID uint, since time.Time) ([]models.Todo, error) {
+	if m.GetTodosChangedSinceFunc != nil {
+		return m.GetTodosChangedSinceFunc(ctx, userID, since)
+	}
+	return []models.Todo{}, nil
+}
+
+// GetRecentTodos calls the mock function
+func (m *MockTodoRepository) GetRecentTodos(ctx context.Context, userID uint, limit int) ([]models.Todo, error) {
+	if m.GetRecentTodosFunc != nil {
+		return m.GetRecentTodosFunc(ctx, userID, limit)
+	}
+	return []models.Todo{}, nil
+}
+
+// GetDeletedTodos calls the mock function
+func (m *MockTodoRepository) GetDeletedTodos(ctx context.Context, userID, categoryID uint) ([]models.Todo, error) {
+	if m.GetDeletedTodosFunc != nil {
+		return m.GetDeletedTodosFunc(ctx, userID, categoryID)
+	}
+	return []models.Todo{}, nil
+}
+
+// RestoreTodos calls the mock function
+func (m *MockTodoRepository) RestoreTodos(ctx context.Context, ids []uint) (int64, error) {
+	if m.RestoreTodosFunc != nil {
+		return m.RestoreTodosFunc(ctx, ids)
+	}
+	return int64(len(ids)), nil
+}
+
+// GetTodosInAccessibleCategories calls the mock function
+func (m *MockTodoRepository) GetTodosInAccessibleCategories(ctx context.Context, userID uint, categoryIDs []uint) ([]models.Todo, error) {
+	if m.GetTodosInAccessibleCategoriesFunc != nil {
+		return m.GetTodosInAccessibleCategoriesFunc(ctx, userID, categoryIDs)
+	}
+	return []models.Todo{}, nil
+}
+
+// GetTodosInCategories calls the mock function
+func (m *MockTodoRepository) GetTodosInCategories(ctx context.Context, userID uint, categoryIDs []uint, page, pageSize int) ([]models.Todo, int64, error) {
+	if m.GetTodosInCategoriesFunc != nil {
+		return m.GetTodosInCategoriesFunc(ctx, userID, categoryIDs, page, pageSize)
+	}
+	return []models.Todo{}, 0, nil
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"context"
+
+	"todo-app/internal/repository"
+)
+
+// Ensure MockTxManager implements TxManager
+var _ repository.TxManager = (*MockTxManager)(nil)
+
+// MockTxManager is a mock implementation of TxManager for testing
+// By default WithinTx calls fn with Repos, so tests can pass the same mocks they give the service
+type MockTxManager struct {
+	Repos        repository.RepoSet
+	WithinTxFunc func(ctx context.Context, fn func(repos repository.RepoSet) error) error
+}
+
+// WithinTx calls the mock function
+func (m *MockTxManager) WithinTx(ctx context.Context, fn func(repos repository.RepoSet) error) error {
+	if m.WithinTxFunc != nil {
+		return m.WithinTxFunc(ctx, fn)
+	}
+	return fn(m.Repos)
+}
@@ -12,9 +12,14 @@ var _ repository.UserRepository = (*MockUserRepository)(nil)
 
 // MockUserRepository is a mock implementation of UserRepository for testing
 type MockUserRepository struct {
-	CreateUserFunc     func(ctx context.Context, user *models.User) error
-	GetUserByEmailFunc func(ctx context.Context, email string) (*models.User, error)
-	GetUserByIDFunc    func(ctx context.Context, id uint) (*models.User, error)
+	CreateUserFunc         func(ctx context.Context, user *models.User) error
+	GetUserByEmailFunc     func(ctx context.Context, email string) (*models.User, error)
+	GetUserByIDFunc        func(ctx context.Context, id uint) (*models.User, error)
+	GetUsersByIDsFunc      func(ctx context.Context, ids []uint) ([]models.User, error)
+	UpdateUserTimezoneFunc func(ctx context.Context, id uint, timezone string) error
+	GetUsersFunc           func(ctx context.Context, page, pageSize int) ([]models.User, int64, error)
+	SetAdminByEmailFunc    func(ctx context.Context, email string) (bool, error)
+	NormalizeEmailsFunc    func(ctx context.Context) (int64, error)
 }
 
 // CreateUser calls the mock function
@@ -40,3 +45,43 @@ func (m *MockUserRepository) GetUserByID(ctx context.Context, id uint) (*models.
 	}
 	return nil, nil
 }
+
+// GetUsersByIDs calls the mock function
+func (m *MockUserRepository) GetUsersByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
+	if m.GetUsersByIDsFunc != nil {
+		return m.GetUsersByIDsFunc(ctx, ids)
+	}
+	return []models.User{}, nil
+}
+
+// UpdateUserTimezone calls the mock function
+func (m *MockUserRepository) UpdateUserTimezone(ctx context.Context, id uint, timezone string) error {
+	if m.UpdateUserTimezoneFunc != nil {
+		return m.UpdateUserTimezoneFunc(ctx, id, timezone)
+	}
+	return nil
+}
+
+// GetUsers calls the mock function
+func (m *MockUserRepository) GetUsers(ctx context.Context, page, pageSize int) ([]models.User, int64, error) {
+	if m.GetUsersFunc != nil {
+		return m.GetUsersFunc(ctx, page, pageSize)
+	}
+	return []models.User{}, 0, nil
+}
+
+// SetAdminByEmail calls the mock function
+func (m *MockUserRepository) SetAdminByEmail(ctx context.Context, email string) (bool, error) {
+	if m.SetAdminByEmailFunc != nil {
+		return m.SetAdminByEmailFunc(ctx, email)
+	}
+	return false, nil
+}
+
+// NormalizeEmails calls the mock function
+func (m *MockUserRepository) NormalizeEmails(ctx context.Context) (int64, error) {
+	if m.NormalizeEmailsFunc != nil {
+		return m.NormalizeEmailsFunc(ctx)
+	}
+	return 0, nil
+}
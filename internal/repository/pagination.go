@@ -0,0 +1,6 @@
+package repository
+
+// limitOffset converts a 1-based page and page size into LIMIT and OFFSET query arguments
+func limitOffset(page, pageSize int) (limit, offset int32) {
+	return int32(pageSize), int32((page - 1) * pageSize)
+}
@@ -0,0 +1,28 @@
+package repository
+
+import "testing"
+
+func TestLimitOffset(t *testing.T) {
+	tests := []struct {
+		name       string
+		page       int
+		pageSize   int
+		wantLimit  int32
+		wantOffset int32
+	}{
+		{name: "first page", page: 1, pageSize: 10, wantLimit: 10, wantOffset: 0},
+		{name: "second page", page: 2, pageSize: 10, wantLimit: 10, wantOffset: 10},
+		{name: "later page", page: 5, pageSize: 25, wantLimit: 25, wantOffset: 100},
+		{name: "single item pages", page: 3, pageSize: 1, wantLimit: 1, wantOffset: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, offset := limitOffset(tt.page, tt.pageSize)
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("limitOffset(%d, %d) = (%d, %d), want (%d, %d)",
+					tt.page, tt.pageSize, limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
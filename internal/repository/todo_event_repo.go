@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"todo-app/db"
+	"todo-app/internal/models"
+)
+
+// Ensure SQLTodoEventRepository implements TodoEventRepository
+var _ TodoEventRepository = (*SQLTodoEventRepository)(nil)
+
+// SQLTodoEventRepository implements TodoEventRepository using sqlc-generated queries
+type SQLTodoEventRepository struct {
+	queries *db.Queries
+}
+
+// NewSQLTodoEventRepository creates a new TodoEventRepository with the provided queries instance
+func NewSQLTodoEventRepository(queries *db.Queries) TodoEventRepository {
+	return &SQLTodoEventRepository{queries: queries}
+}
+
+// toModelTodoEvent converts db.TodoEvent to models.TodoEvent
+func toModelTodoEvent(e db.TodoEvent) models.TodoEvent {
+	fields := []string{}
+	if e.ChangedFields != "" {
+		fields = strings.Split(e.ChangedFields, ",")
+	}
+	return models.TodoEvent{
+		ID:            uint(e.ID),
+		TodoID:        uint(e.TodoID),
+		ActorID:       uint(e.ActorID),
+		Action:        models.TodoEventAction(e.Action),
+		ChangedFields: fields,
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+// CreateTodoEvent appends a history entry for a todo
+func (r *SQLTodoEventRepository) CreateTodoEvent(ctx context.Context, event *models.TodoEvent) error {
+	if r.queries == nil {
+		return sql.ErrConnDone
+	}
+
+	return r.queries.CreateTodoEvent(ctx, db.CreateTodoEventParams{
+		TodoID:        uint64(event.TodoID),
+		ActorID:       uint64(event.ActorID),
+		Action:        db.TodoEventsAction(event.Action),
+		ChangedFields: strings.Join(event.ChangedFields, ","),
+	})
+}
+
+// GetTodoEvents retrieves a todo's history, newest first, with pagination
+func (r *SQLTodoEventRepository) GetTodoEvents(ctx context.Context, todoID uint, page, pageSize int) ([]models.TodoEvent, int64, error) {
+	if r.queries == nil {
+		return nil, 0, sql.ErrConnDone
+	}
+
+	total, err := r.queries.CountTodoEventsByTodoID(ctx, uint64(todoID))
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []models.TodoEvent{}, total, nil
+	}
+
+	limit, offset := limitOffset(page, pageSize)
+	items, err := r.queries.GetTodoEventsByTodoID(ctx, db.GetTodoEventsByTodoIDParams{
+		TodoID: uint64(todoID),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	events := make([]models.TodoEvent, 0, len(items))
+	for _, it := range items {
+		events = append(events, toModelTodoEvent(it))
+	}
+	return events, total, nil
+}
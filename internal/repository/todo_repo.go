@@ -28,22 +28,41 @@ func toModelTodo(t db.Todo) models.Todo {
 	if t.Description.Valid {
 		d = t.Description.String
 	}
+	var completedAt *time.Time
+	if t.CompletedAt.Valid {
+		completedAt = &t.CompletedAt.Time
+	}
+	var remindAt *time.Time
+	if t.RemindAt.Valid {
+		remindAt = &t.RemindAt.Time
+	}
 	var deletedAt *time.Time
 	if t.DeletedAt.Valid {
 		deletedAt = &t.DeletedAt.Time
 	}
 	return models.Todo{
-		ID:          uint(t.ID),
-		Title:       t.Title,
-		Description: d,
-		CategoryID:  uint(t.CategoryID),
-		Completed:   t.Completed,
-		UserID:      uint(t.UserID),
-		CreatedBy:   uint(t.CreatedBy),
-		DeletedAt:   deletedAt,
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
+		ID:           uint(t.ID),
+		Title:        t.Title,
+		Description:  d,
+		CategoryID:   uint(t.CategoryID),
+		Completed:    t.Completed,
+		CompletedAt:  completedAt,
+		RemindAt:     remindAt,
+		ReminderSent: t.ReminderSent,
+		UserID:       uint(t.UserID),
+		CreatedBy:    uint(t.CreatedBy),
+		DeletedAt:    deletedAt,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
+}
+
+// nullTime converts an optional time to a nullable query argument
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
 	}
+	return sql.NullTime{Time: *t, Valid: true}
 }
 
 // CreateTodo inserts a new todo into the database
@@ -58,6 +77,7 @@ func (r *SQLTodoRepository) CreateTodo(ctx context.Context, todo *models.Todo) e
 		Description: sql.NullString{String: todo.Description, Valid: todo.Description != ""},
 		CategoryID:  uint64(todo.CategoryID),
 		Completed:   todo.Completed,
+		RemindAt:    nullTime(todo.RemindAt),
 		UserID:      uint64(todo.UserID),
 		CreatedBy:   uint64(todo.CreatedBy),
 	})
@@ -75,7 +95,7 @@ func (r *SQLTodoRepository) CreateTodo(ctx context.Context, todo *models.Todo) e
 }
 
 // GetTodos retrieves todos created by the specific user with pagination
-func (r *SQLTodoRepository) GetTodos(ctx context.Context, userID uint, page, pageSize int) ([]models.Todo, int64, error) {
+func (r *SQLTodoRepository) GetTodos(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error) {
 	if r.queries == nil {
 		return nil, 0, sql.ErrConnDone
 	}
@@ -89,13 +109,12 @@ func (r *SQLTodoRepository) GetTodos(ctx context.Context, userID uint, page, pag
 		return []models.Todo{}, total, nil
 	}
 
-	// Calculate offset
-	offset := int32((page - 1) * pageSize)
-	limit := int32(pageSize)
+	limit, offset := limitOffset(page, pageSize)
 
 	// Get todos where user_id == userID
 	items, err := r.queries.GetTodosByUserIDWithPagination(ctx, db.GetTodosByUserIDWithPaginationParams{
 		UserID: uint64(userID),
+		SortBy: string(sortBy),
 		Limit:  limit,
 		Offset: offset,
 	})
@@ -110,14 +129,42 @@ func (r *SQLTodoRepository) GetTodos(ctx context.Context, userID uint, page, pag
 	return todos, total, nil
 }
 
-// GetTodosByCategoryID retrieves todos for a specific category with pagination
-func (r *SQLTodoRepository) GetTodosByCategoryID(ctx context.Context, categoryID uint, page, pageSize int) ([]models.Todo, int64, error) {
+// CountTodos counts the user's non-deleted todos, optionally only those with the given completed state
+func (r *SQLTodoRepository) CountTodos(ctx context.Context, userID uint, completed *bool) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+
+	status := sql.NullBool{}
+	if completed != nil {
+		status = sql.NullBool{Bool: *completed, Valid: true}
+	}
+	return r.queries.CountTodosByUserIDAndStatus(ctx, db.CountTodosByUserIDAndStatusParams{
+		UserID:    uint64(userID),
+		Completed: status,
+	})
+}
+
+// nullableUserID converts an optional user ID filter to a nullable query argument
+func nullableUserID(id uint) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id > 0}
+}
+
+// GetTodosByCategoryID retrieves todos for a specific category, optionally filtered by creator, with pagination
+func (r *SQLTodoRepository) GetTodosByCategoryID(ctx context.Context, categoryID uint, creator TodoCreatorFilter, page, pageSize int) ([]models.Todo, int64, error) {
 	if r.queries == nil {
 		return nil, 0, sql.ErrConnDone
 	}
 
+	createdBy := nullableUserID(creator.CreatedBy)
+	notCreatedBy := nullableUserID(creator.NotCreatedBy)
+
 	// Count total matching records
-	total, err := r.queries.CountTodosByCategoryID(ctx, uint64(categoryID))
+	total, err := r.queries.CountTodosByCategoryID(ctx, db.CountTodosByCategoryIDParams{
+		CategoryID:   uint64(categoryID),
+		CreatedBy:    createdBy,
+		NotCreatedBy: notCreatedBy,
+	})
 	if err != nil {
 		return nil, 0, err
 	}
@@ -125,14 +172,14 @@ func (r *SQLTodoRepository) GetTodosByCategoryID(ctx context.Context, categoryID
 		return []models.Todo{}, total, nil
 	}
 
-	// Calculate offset
-	offset := int32((page - 1) * pageSize)
-	limit := int32(pageSize)
+	limit, offset := limitOffset(page, pageSize)
 
 	items, err := r.queries.GetTodosByCategoryID(ctx, db.GetTodosByCategoryIDParams{
-		CategoryID: uint64(categoryID),
-		Limit:      limit,
-		Offset:     offset,
+		CategoryID:   uint64(categoryID),
+		CreatedBy:    createdBy,
+		NotCreatedBy: notCreatedBy,
+		Limit:        limit,
+		Offset:       offset,
 	})
 	if err != nil {
 		return nil, 0, err
@@ -145,6 +192,141 @@ func (r *SQLTodoRepository) GetTodosByCategoryID(ctx context.Context, categoryID
 	return todos, total, nil
 }
 
+// GetTodosByCategoryIDAfter retrieves up to limit of a category's todos, newest first, that come after the
+// given keyset (from the newest when after is nil). Unlike offsets, a keyset page does not shift when todos
+// are added or removed in front of it.
+func (r *SQLTodoRepository) GetTodosByCategoryIDAfter(ctx context.Context, categoryID uint, creator TodoCreatorFilter, after *TodoKeyset, limit int) ([]models.Todo, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	params := db.GetTodosByCategoryIDAfterParams{
+		CategoryID:   uint64(categoryID),
+		CreatedBy:    nullableUserID(creator.CreatedBy),
+		NotCreatedBy: nullableUserID(creator.NotCreatedBy),
+		Limit:        int32(limit),
+	}
+	if after != nil {
+		params.AfterCreatedAt = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		params.AfterID = uint64(after.ID)
+	}
+
+	items, err := r.queries.GetTodosByCategoryIDAfter(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]models.Todo, 0, len(items))
+	for _, it := range items {
+		todos = append(todos, toModelTodo(it))
+	}
+	return todos, nil
+}
+
+// GetTodosInAccessibleCategories retrieves the non-deleted todos of the given categories in one query, newest first
+// within each category. Categories the user neither owns nor is shared on are skipped by the query itself.
+func (r *SQLTodoRepository) GetTodosInAccessibleCategories(ctx context.Context, userID uint, categoryIDs []uint) ([]models.Todo, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+	if len(categoryIDs) == 0 {
+		return []models.Todo{}, nil
+	}
+
+	ids := make([]uint64, len(categoryIDs))
+	for i, id := range categoryIDs {
+		ids[i] = uint64(id)
+	}
+
+	items, err := r.queries.GetTodosInAccessibleCategories(ctx, db.GetTodosInAccessibleCategoriesParams{
+		UserID:      uint64(userID),
+		CategoryIds: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]models.Todo, 0, len(items))
+	for _, it := range items {
+		todos = append(todos, toModelTodo(it))
+	}
+	return todos, nil
+}
+
+// GetTodosInCategories retrieves a page of the non-deleted todos in any of the given categories, newest first.
+// Categories the user neither owns nor is shared on are skipped by the query itself, so they add nothing to the total.
+func (r *SQLTodoRepository) GetTodosInCategories(ctx context.Context, userID uint, categoryIDs []uint, page, pageSize int) ([]models.Todo, int64, error) {
+	if r.queries == nil {
+		return nil, 0, sql.ErrConnDone
+	}
+	if len(categoryIDs) == 0 {
+		return []models.Todo{}, 0, nil
+	}
+
+	ids := make([]uint64, len(categoryIDs))
+	for i, id := range categoryIDs {
+		ids[i] = uint64(id)
+	}
+
+	total, err := r.queries.CountTodosInCategories(ctx, db.CountTodosInCategoriesParams{
+		UserID:      uint64(userID),
+		CategoryIds: ids,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []models.Todo{}, total, nil
+	}
+
+	limit, offset := limitOffset(page, pageSize)
+
+	items, err := r.queries.GetTodosInCategoriesWithPagination(ctx, db.GetTodosInCategoriesWithPaginationParams{
+		UserID:      uint64(userID),
+		CategoryIds: ids,
+		Limit:       limit,
+		Offset:      offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	todos := make([]models.Todo, 0, len(items))
+	for _, it := range items {
+		todos = append(todos, toModelTodo(it))
+	}
+	return todos, total, nil
+}
+
+// CountTodosInCategory counts the non-deleted todos in a category
+func (r *SQLTodoRepository) CountTodosInCategory(ctx context.Context, categoryID uint) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+
+	return r.queries.CountTodosByCategoryID(ctx, db.CountTodosByCategoryIDParams{
+		CategoryID: uint64(categoryID),
+	})
+}
+
+// CountTodosByStatusInCategory counts the non-deleted todos in a category, split into completed and open,
+// with a single aggregate query
+func (r *SQLTodoRepository) CountTodosByStatusInCategory(ctx context.Context, categoryID uint) (models.TodoStatusCounts, error) {
+	if r.queries == nil {
+		return models.TodoStatusCounts{}, sql.ErrConnDone
+	}
+
+	row, err := r.queries.CountTodosByStatusInCategory(ctx, uint64(categoryID))
+	if err != nil {
+		return models.TodoStatusCounts{}, err
+	}
+	return models.TodoStatusCounts{
+		Total:     row.Total,
+		Completed: row.Completed,
+		Open:      row.Total - row.Completed,
+	}, nil
+}
+
 // GetTodoByID retrieves a single todo by its ID
 func (r *SQLTodoRepository) GetTodoByID(ctx context.Context, id uint) (*models.Todo, error) {
 	if r.queries == nil {
@@ -159,6 +341,47 @@ func (r *SQLTodoRepository) GetTodoByID(ctx context.Context, id uint) (*models.T
 	return &todo, nil
 }
 
+// GetTodoByIDIncludingDeleted retrieves a single todo by its ID even if it has been soft deleted
+func (r *SQLTodoRepository) GetTodoByIDIncludingDeleted(ctx context.Context, id uint) (*models.Todo, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	t, err := r.queries.GetTodoByIDIncludingDeleted(ctx, uint64(id))
+	if err != nil {
+		return nil, err
+	}
+	todo := toModelTodo(t)
+	return &todo, nil
+}
+
+// GetTodosByIDs retrieves the non-deleted todos with the given IDs in a single query
+// IDs that do not exist are skipped, and the result is in no particular order
+func (r *SQLTodoRepository) GetTodosByIDs(ctx context.Context, ids []uint) ([]models.Todo, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+	if len(ids) == 0 {
+		return []models.Todo{}, nil
+	}
+
+	todoIDs := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		todoIDs = append(todoIDs, uint64(id))
+	}
+
+	items, err := r.queries.GetTodosByIDs(ctx, todoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]models.Todo, 0, len(items))
+	for _, item := range items {
+		todos = append(todos, toModelTodo(item))
+	}
+	return todos, nil
+}
+
 // UpdateTodo updates an existing todo
 func (r *SQLTodoRepository) UpdateTodo(ctx context.Context, todo *models.Todo) error {
 	if r.queries == nil {
@@ -166,11 +389,13 @@ func (r *SQLTodoRepository) UpdateTodo(ctx context.Context, todo *models.Todo) e
 	}
 
 	err := r.queries.UpdateTodo(ctx, db.UpdateTodoParams{
-		Title:       todo.Title,
-		Description: sql.NullString{String: todo.Description, Valid: todo.Description != ""},
-		CategoryID:  uint64(todo.CategoryID),
-		Completed:   todo.Completed,
-		ID:          uint64(todo.ID),
+		Title:        todo.Title,
+		Description:  sql.NullString{String: todo.Description, Valid: todo.Description != ""},
+		CategoryID:   uint64(todo.CategoryID),
+		Completed:    todo.Completed,
+		RemindAt:     nullTime(todo.RemindAt),
+		ReminderSent: todo.ReminderSent,
+		ID:           uint64(todo.ID),
 	})
 	if err != nil {
 		return err
@@ -185,6 +410,187 @@ func (r *SQLTodoRepository) UpdateTodo(ctx context.Context, todo *models.Todo) e
 	return nil
 }
 
+// TouchTodo sets a live todo's updated_at to now, leaving its other fields alone, and returns the todo.
+// It returns sql.ErrNoRows if the todo does not exist or is deleted.
+func (r *SQLTodoRepository) TouchTodo(ctx context.Context, id uint) (*models.Todo, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	if err := r.queries.TouchTodo(ctx, uint64(id)); err != nil {
+		return nil, err
+	}
+
+	touched, err := r.queries.GetTodoByID(ctx, uint64(id))
+	if err != nil {
+		return nil, err
+	}
+	todo := toModelTodo(touched)
+	return &todo, nil
+}
+
+// HasTodoWithTitle reports whether a non-deleted todo with the given title exists in a category
+func (r *SQLTodoRepository) HasTodoWithTitle(ctx context.Context, categoryID uint, title string) (bool, error) {
+	if r.queries == nil {
+		return false, sql.ErrConnDone
+	}
+
+	count, err := r.queries.CountTodosByCategoryAndTitle(ctx, db.CountTodosByCategoryAndTitleParams{
+		CategoryID: uint64(categoryID),
+		Title:      title,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetTodoIDsInCategory returns the IDs of a category's non-deleted todos, optionally only those with the given
+// completed state, and locks them until the surrounding transaction ends
+func (r *SQLTodoRepository) GetTodoIDsInCategory(ctx context.Context, categoryID uint, completed *bool) ([]uint, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	status := sql.NullBool{}
+	if completed != nil {
+		status = sql.NullBool{Bool: *completed, Valid: true}
+	}
+	rows, err := r.queries.GetTodoIDsInCategory(ctx, db.GetTodoIDsInCategoryParams{
+		CategoryID: uint64(categoryID),
+		Completed:  status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, id := range rows {
+		ids[i] = uint(id)
+	}
+	return ids, nil
+}
+
+// MoveTodos reassigns the given non-deleted todos to another category and owner and returns how many moved
+func (r *SQLTodoRepository) MoveTodos(ctx context.Context, ids []uint, toCategoryID, toOwnerID uint) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	dbIDs := make([]uint64, len(ids))
+	for i, id := range ids {
+		dbIDs[i] = uint64(id)
+	}
+	return r.queries.MoveTodosByIDs(ctx, db.MoveTodosByIDsParams{
+		TargetCategoryID: uint64(toCategoryID),
+		TargetOwnerID:    uint64(toOwnerID),
+		Ids:              dbIDs,
+	})
+}
+
+// SetCompleted sets the completed state of the given non-deleted todos in a single UPDATE and returns how many
+// todos changed state
+func (r *SQLTodoRepository) SetCompleted(ctx context.Context, ids []uint, completed bool) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	dbIDs := make([]uint64, len(ids))
+	for i, id := range ids {
+		dbIDs[i] = uint64(id)
+	}
+	return r.queries.SetTodosCompletedByIDs(ctx, db.SetTodosCompletedByIDsParams{
+		Completed: completed,
+		Ids:       dbIDs,
+	})
+}
+
+// CountCompletedTodosBefore counts a user's non-deleted todos completed before the cutoff
+func (r *SQLTodoRepository) CountCompletedTodosBefore(ctx context.Context, userID uint, before time.Time) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+
+	return r.queries.CountCompletedTodosBefore(ctx, db.CountCompletedTodosBeforeParams{
+		UserID:      uint64(userID),
+		CompletedAt: sql.NullTime{Time: before, Valid: true},
+	})
+}
+
+// CountCompletedTodosByDay counts a user's non-deleted todos completed in [from, to), one entry per day with completions
+// Days are calendar days in loc, each reported as its midnight in loc, in ascending order
+func (r *SQLTodoRepository) CountCompletedTodosByDay(ctx context.Context, userID uint, from, to time.Time, loc *time.Location) ([]models.CompletionCount, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	times, err := r.queries.ListCompletionTimes(ctx, db.ListCompletionTimesParams{
+		UserID:        uint64(userID),
+		CompletedFrom: sql.NullTime{Time: from, Valid: true},
+		CompletedTo:   sql.NullTime{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Timestamps arrive in order, so each day's completions are contiguous
+	var counts []models.CompletionCount
+	for _, t := range times {
+		local := t.Time.In(loc)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		if n := len(counts); n > 0 && counts[n-1].Day.Equal(day) {
+			counts[n-1].Count++
+			continue
+		}
+		counts = append(counts, models.CompletionCount{Day: day, Count: 1})
+	}
+	return counts, nil
+}
+
+// GetCompletedTodoIDsBefore returns the IDs of a user's non-deleted todos completed before the cutoff and locks
+// them until the surrounding transaction ends
+func (r *SQLTodoRepository) GetCompletedTodoIDsBefore(ctx context.Context, userID uint, before time.Time) ([]uint, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	rows, err := r.queries.GetCompletedTodoIDsBefore(ctx, db.GetCompletedTodoIDsBeforeParams{
+		UserID:      uint64(userID),
+		CompletedAt: sql.NullTime{Time: before, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, id := range rows {
+		ids[i] = uint(id)
+	}
+	return ids, nil
+}
+
+// DeleteTodos soft deletes the given todos and returns how many were deleted; todos already deleted are skipped
+func (r *SQLTodoRepository) DeleteTodos(ctx context.Context, ids []uint) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	dbIDs := make([]uint64, len(ids))
+	for i, id := range ids {
+		dbIDs[i] = uint64(id)
+	}
+	return r.queries.SoftDeleteTodosByIDs(ctx, dbIDs)
+}
+
 // DeleteTodo soft deletes a todo from the database
 func (r *SQLTodoRepository) DeleteTodo(ctx context.Context, id uint) error {
 	if r.queries == nil {
@@ -192,3 +598,184 @@ func (r *SQLTodoRepository) DeleteTodo(ctx context.Context, id uint) error {
 	}
 	return r.queries.SoftDeleteTodo(ctx, uint64(id))
 }
+
+// RestoreTodo clears the soft delete of a todo, reporting false if it was not deleted or its
+// category has been deleted since
+func (r *SQLTodoRepository) RestoreTodo(ctx context.Context, id uint) (bool, error) {
+	if r.queries == nil {
+		return false, sql.ErrConnDone
+	}
+
+	affected, err := r.queries.RestoreTodo(ctx, uint64(id))
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// GetDeletedTodos returns the soft-deleted todos whose category is still live, locking them for the rest of
+// the transaction. A categoryID of 0 selects the user's own todos, otherwise every todo in that category
+func (r *SQLTodoRepository) GetDeletedTodos(ctx context.Context, userID, categoryID uint) ([]models.Todo, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	items, err := r.queries.GetDeletedTodos(ctx, db.GetDeletedTodosParams{
+		CategoryID: uint64(categoryID),
+		UserID:     uint64(userID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]models.Todo, 0, len(items))
+	for _, it := range items {
+		todos = append(todos, toModelTodo(it))
+	}
+	return todos, nil
+}
+
+// RestoreTodos clears the soft delete of the given todos whose category is still live and returns how many were restored
+func (r *SQLTodoRepository) RestoreTodos(ctx context.Context, ids []uint) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	dbIDs := make([]uint64, len(ids))
+	for i, id := range ids {
+		dbIDs[i] = uint64(id)
+	}
+	return r.queries.RestoreTodosByIDs(ctx, dbIDs)
+}
+
+// GetDueReminders retrieves up to limit open, non-deleted todos whose unsent reminder is due at or before now
+func (r *SQLTodoRepository) GetDueReminders(ctx context.Context, now time.Time, limit int) ([]models.Todo, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	items, err := r.queries.GetDueReminders(ctx, db.GetDueRemindersParams{
+		RemindAt: sql.NullTime{Time: now, Valid: true},
+		Limit:    int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]models.Todo, 0, len(items))
+	for _, it := range items {
+		todos = append(todos, toModelTodo(it))
+	}
+	return todos, nil
+}
+
+// GetRecentTodos retrieves up to limit of the todos a user can access, most recently updated first
+func (r *SQLTodoRepository) GetRecentTodos(ctx context.Context, userID uint, limit int) ([]models.Todo, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	items, err := r.queries.GetRecentTodos(ctx, db.GetRecentTodosParams{
+		UserID: uint64(userID),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]models.Todo, 0, len(items))
+	for _, it := range items {
+		todos = append(todos, toModelTodo(it))
+	}
+	return todos, nil
+}
+
+// GetTodoChangesSince lists the IDs of the user's accessible todos changed at or after since, oldest change
+// first, including soft-deleted todos as tombstones
+func (r *SQLTodoRepository) GetTodoChangesSince(ctx context.Context, userID uint, since time.Time) ([]models.TodoChange, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	rows, err := r.queries.GetTodoChangesSince(ctx, db.GetTodoChangesSinceParams{
+		UserID: uint64(userID),
+		Since:  since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]models.TodoChange, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, models.TodoChange{
+			ID:        uint(row.ID),
+			UpdatedAt: row.UpdatedAt,
+			Deleted:   row.DeletedAt.Valid,
+		})
+	}
+	return changes, nil
+}
+
+// GetTodosChangedSince lists the user's accessible todos changed after since, oldest change first, including
+// soft-deleted todos; changes from the current second are left for the next call
+func (r *SQLTodoRepository) GetTodosChangedSince(ctx context.Context, userID uint, since time.Time) ([]models.Todo, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+
+	items, err := r.queries.GetTodosChangedSince(ctx, db.GetTodosChangedSinceParams{
+		UserID: uint64(userID),
+		Since:  since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make([]models.Todo, 0, len(items))
+	for _, it := range items {
+		todos = append(todos, toModelTodo(it))
+	}
+	return todos, nil
+}
+
+// PurgeDeletedTodosBefore permanently removes up to limit todos soft-deleted before the cutoff
+// and returns how many were removed
+func (r *SQLTodoRepository) PurgeDeletedTodosBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+
+	return r.queries.PurgeDeletedTodosBefore(ctx, db.PurgeDeletedTodosBeforeParams{
+		DeletedAt: sql.NullTime{Time: before, Valid: true},
+		Limit:     int32(limit),
+	})
+}
+
+// MarkReminderSent flags a todo's reminder as sent. It reports false when the reminder was already
+// marked, which lets concurrent dispatchers claim each reminder exactly once, or is no longer due at now.
+func (r *SQLTodoRepository) MarkReminderSent(ctx context.Context, id uint, now time.Time) (bool, error) {
+	if r.queries == nil {
+		return false, sql.ErrConnDone
+	}
+
+	affected, err := r.queries.MarkReminderSent(ctx, db.MarkReminderSentParams{
+		ID:       uint64(id),
+		RemindAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ReleaseReminder clears a claimed reminder's sent flag so a later dispatch delivers it again
+func (r *SQLTodoRepository) ReleaseReminder(ctx context.Context, id uint) error {
+	if r.queries == nil {
+		return sql.ErrConnDone
+	}
+
+	return r.queries.ReleaseReminder(ctx, uint64(id))
+}
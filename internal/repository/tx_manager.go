@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"todo-app/db"
+)
+
+// Ensure SQLTxManager implements TxManager
+var _ TxManager = (*SQLTxManager)(nil)
+
+// SQLTxManager implements TxManager with database/sql transactions
+type SQLTxManager struct {
+	db *db.DB
+}
+
+// NewSQLTxManager creates a new TxManager that begins transactions on the provided database
+func NewSQLTxManager(database *db.DB) TxManager {
+	return &SQLTxManager{db: database}
+}
+
+// WithinTx begins a transaction, calls fn with repositories bound to it, and commits if fn returns nil
+// Any error from fn, or a panic, rolls the transaction back; the error is returned unchanged so callers
+// can still match sentinels with errors.Is
+func (m *SQLTxManager) WithinTx(ctx context.Context, fn func(repos RepoSet) error) error {
+	if m.db == nil || m.db.SQL == nil {
+		return sql.ErrConnDone
+	}
+
+	// Read committed, so a count taken after locking a row sees every write committed before the lock was granted
+	// rather than a snapshot from the transaction's first read
+	tx, err := m.db.SQL.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			// A rollback after a failed commit is harmless; database/sql reports sql.ErrTxDone
+			_ = tx.Rollback()
+		}
+	}()
+
+	q := m.db.QueriesForTx(tx)
+	if err := fn(RepoSet{
+		Todos:          NewSQLTodoRepository(q),
+		Categories:     NewSQLCategoryRepository(q),
+		CategoryShares: NewSQLCategoryShareRepository(q),
+		TodoEvents:     NewSQLTodoEventRepository(q),
+		Users:          NewSQLUserRepository(q),
+	}); err != nil {
+		return err
+	}
+
+	committed = true
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
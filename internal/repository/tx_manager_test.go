@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"todo-app/db"
+)
+
+// txRecorder is a database/sql driver that only supports transactions and records how each one ended
+type txRecorder struct {
+	commits   int
+	rollbacks int
+	isolation driver.IsolationLevel
+}
+
+func (r *txRecorder) Open(name string) (driver.Conn, error) { return &txRecorderConn{r: r}, nil }
+
+type txRecorderConn struct{ r *txRecorder }
+
+func (c *txRecorderConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("txRecorder does not run statements")
+}
+func (c *txRecorderConn) Close() error              { return nil }
+func (c *txRecorderConn) Begin() (driver.Tx, error) { return &txRecorderTx{r: c.r}, nil }
+func (c *txRecorderConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.r.isolation = opts.Isolation
+	return c.Begin()
+}
+
+type txRecorderTx struct{ r *txRecorder }
+
+func (t *txRecorderTx) Commit() error   { t.r.commits++; return nil }
+func (t *txRecorderTx) Rollback() error { t.r.rollbacks++; return nil }
+
+func newTestTxManager(t *testing.T) (TxManager, *txRecorder) {
+	t.Helper()
+	recorder := &txRecorder{}
+	sql.Register("txrecorder-"+t.Name(), recorder)
+	sqlDB, err := sql.Open("txrecorder-"+t.Name(), "")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return NewSQLTxManager(&db.DB{SQL: sqlDB, Queries: db.New(sqlDB)}), recorder
+}
+
+func TestSQLTxManager_CommitsOnSuccess(t *testing.T) {
+	txManager, recorder := newTestTxManager(t)
+
+	err := txManager.WithinTx(context.Background(), func(repos RepoSet) error {
+		if repos.Categories == nil || repos.CategoryShares == nil || repos.Todos == nil {
+			t.Error("WithinTx() passed an incomplete RepoSet")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithinTx() error = %v", err)
+	}
+	if recorder.commits != 1 || recorder.rollbacks != 0 {
+		t.Errorf("commits = %d, rollbacks = %d, want 1 and 0", recorder.commits, recorder.rollbacks)
+	}
+	if sql.IsolationLevel(recorder.isolation) != sql.LevelReadCommitted {
+		t.Errorf("isolation = %v, want %v", sql.IsolationLevel(recorder.isolation), sql.LevelReadCommitted)
+	}
+}
+
+func TestSQLTxManager_RollsBackOnError(t *testing.T) {
+	txManager, recorder := newTestTxManager(t)
+	injected := errors.New("second statement failed")
+
+	err := txManager.WithinTx(context.Background(), func(repos RepoSet) error {
+		return injected
+	})
+	if !errors.Is(err, injected) {
+		t.Fatalf("WithinTx() error = %v, want the injected error", err)
+	}
+	if recorder.commits != 0 || recorder.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want 0 and 1", recorder.commits, recorder.rollbacks)
+	}
+}
+
+func TestSQLTxManager_RollsBackOnPanic(t *testing.T) {
+	txManager, recorder := newTestTxManager(t)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("WithinTx() swallowed the panic")
+			}
+		}()
+		_ = txManager.WithinTx(context.Background(), func(repos RepoSet) error {
+			panic("boom")
+		})
+	}()
+
+	if recorder.commits != 0 || recorder.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want 0 and 1", recorder.commits, recorder.rollbacks)
+	}
+}
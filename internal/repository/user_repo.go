@@ -28,6 +28,8 @@ func toModelUser(u db.User) models.User {
 		Name:      u.Name,
 		Email:     u.Email,
 		Password:  u.Password,
+		Timezone:  u.Timezone,
+		IsAdmin:   u.IsAdmin,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
@@ -44,8 +46,12 @@ func (r *SQLUserRepository) CreateUser(ctx context.Context, user *models.User) e
 		Name:     user.Name,
 		Email:    user.Email,
 		Password: user.Password,
+		IsAdmin:  user.IsAdmin,
 	})
 	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrDuplicateEmail
+		}
 		return err
 	}
 
@@ -58,7 +64,7 @@ func (r *SQLUserRepository) CreateUser(ctx context.Context, user *models.User) e
 	return nil
 }
 
-// GetUserByEmail retrieves a user by their email address
+// GetUserByEmail retrieves a user by their email address, which must already be normalized
 func (r *SQLUserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	if r.queries == nil {
 		return nil, sql.ErrConnDone
@@ -72,6 +78,33 @@ func (r *SQLUserRepository) GetUserByEmail(ctx context.Context, email string) (*
 	return &user, nil
 }
 
+// GetUsersByIDs retrieves the users with the given IDs in a single query
+// IDs that do not exist are skipped, and the result is in no particular order
+func (r *SQLUserRepository) GetUsersByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
+	if r.queries == nil {
+		return nil, sql.ErrConnDone
+	}
+	if len(ids) == 0 {
+		return []models.User{}, nil
+	}
+
+	userIDs := make([]uint64, 0, len(ids))
+	for _, id := range ids {
+		userIDs = append(userIDs, uint64(id))
+	}
+
+	items, err := r.queries.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]models.User, 0, len(items))
+	for _, item := range items {
+		users = append(users, toModelUser(item))
+	}
+	return users, nil
+}
+
 // GetUserByID retrieves a user by their ID
 func (r *SQLUserRepository) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
 	if r.queries == nil {
@@ -85,3 +118,69 @@ func (r *SQLUserRepository) GetUserByID(ctx context.Context, id uint) (*models.U
 	user := toModelUser(u)
 	return &user, nil
 }
+
+// UpdateUserTimezone sets the IANA timezone used for a user's day boundaries
+func (r *SQLUserRepository) UpdateUserTimezone(ctx context.Context, id uint, timezone string) error {
+	if r.queries == nil {
+		return sql.ErrConnDone
+	}
+
+	return r.queries.UpdateUserTimezone(ctx, db.UpdateUserTimezoneParams{
+		Timezone: timezone,
+		ID:       uint64(id),
+	})
+}
+
+// GetUsers retrieves a page of all users, oldest account first, along with the total number of users
+func (r *SQLUserRepository) GetUsers(ctx context.Context, page, pageSize int) ([]models.User, int64, error) {
+	if r.queries == nil {
+		return nil, 0, sql.ErrConnDone
+	}
+
+	total, err := r.queries.CountUsers(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []models.User{}, total, nil
+	}
+
+	limit, offset := limitOffset(page, pageSize)
+	items, err := r.queries.ListUsers(ctx, db.ListUsersParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users := make([]models.User, 0, len(items))
+	for _, item := range items {
+		users = append(users, toModelUser(item))
+	}
+	return users, total, nil
+}
+
+// SetAdminByEmail makes the user with the given (normalized) email an admin
+// It reports false when there is no such user or they already are one
+func (r *SQLUserRepository) SetAdminByEmail(ctx context.Context, email string) (bool, error) {
+	if r.queries == nil {
+		return false, sql.ErrConnDone
+	}
+
+	rows, err := r.queries.SetUserAdminByEmail(ctx, email)
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// NormalizeEmails lowercases and trims every email stored before registration normalized them and returns
+// how many changed. Once every row is normalized it changes nothing, so it is safe to run on each start.
+func (r *SQLUserRepository) NormalizeEmails(ctx context.Context) (int64, error) {
+	if r.queries == nil {
+		return 0, sql.ErrConnDone
+	}
+
+	return r.queries.NormalizeUserEmails(ctx)
+}
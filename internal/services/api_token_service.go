@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/models"
+	"todo-app/internal/repository"
+	"todo-app/pkg/utils"
+)
+
+// Common errors for personal access token operations
+var (
+	ErrAPITokenNameRequired = errors.New("token name is required")
+	ErrInvalidAPITokenScope = errors.New("invalid token scope")
+	ErrAPITokenNotFound     = errors.New("token not found")
+	ErrInvalidAPIToken      = errors.New("invalid or revoked token")
+)
+
+// Ensure APITokenServiceImpl implements APITokenService
+var _ APITokenService = (*APITokenServiceImpl)(nil)
+
+// APITokenServiceImpl handles personal access token business logic
+type APITokenServiceImpl struct {
+	repo repository.APITokenRepository
+}
+
+// NewAPITokenService creates a new APITokenService with the provided repository
+func NewAPITokenService(repo repository.APITokenRepository) APITokenService {
+	return &APITokenServiceImpl{repo: repo}
+}
+
+// CreateToken generates a token, stores only its hash and returns the secret once
+func (s *APITokenServiceImpl) CreateToken(ctx context.Context, req dto.CreateAPITokenRequest) (*dto.CreateAPITokenResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrAPITokenNameRequired
+	}
+
+	scopes := []string{}
+	seen := make(map[string]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !utils.IsValidAPITokenScope(scope) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAPITokenScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	secret, err := utils.GenerateAPIToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token := &models.APIToken{
+		UserID:    req.UserID,
+		Name:      name,
+		TokenHash: utils.HashAPIToken(secret),
+		Scopes:    scopes,
+	}
+	if err := s.repo.CreateAPIToken(ctx, token); err != nil {
+		return nil, fmt.Errorf("failed to create token: %w", err)
+	}
+
+	return &dto.CreateAPITokenResponse{
+		Token:  token,
+		Secret: secret,
+	}, nil
+}
+
+// ListTokens retrieves all of a user's tokens (without their secrets)
+func (s *APITokenServiceImpl) ListTokens(ctx context.Context, userID uint) ([]models.APIToken, error) {
+	return s.repo.ListAPITokens(ctx, userID)
+}
+
+// RevokeToken revokes one of the user's active tokens
+func (s *APITokenServiceImpl) RevokeToken(ctx context.Context, userID, tokenID uint) error {
+	revoked, err := s.repo.RevokeAPIToken(ctx, tokenID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	if !revoked {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// AuthenticateToken resolves a bearer value to its active token
+func (s *APITokenServiceImpl) AuthenticateToken(ctx context.Context, secret string) (*models.APIToken, error) {
+	token, err := s.repo.GetActiveAPITokenByHash(ctx, utils.HashAPIToken(secret))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidAPIToken
+		}
+		return nil, fmt.Errorf("failed to look up token: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/models"
+	"todo-app/internal/repository/mocks"
+)
+
+// newInMemoryAPITokenRepo returns a mock repository that keeps tokens in memory so revocation is observable
+func newInMemoryAPITokenRepo() *mocks.MockAPITokenRepository {
+	tokens := map[uint]*models.APIToken{}
+	return &mocks.MockAPITokenRepository{
+		CreateAPITokenFunc: func(ctx context.Context, token *models.APIToken) error {
+			token.ID = uint(len(tokens) + 1)
+			stored := *token
+			tokens[token.ID] = &stored
+			return nil
+		},
+		GetActiveAPITokenByHashFunc: func(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+			for _, token := range tokens {
+				if token.TokenHash == tokenHash && token.RevokedAt == nil {
+					found := *token
+					return &found, nil
+				}
+			}
+			return nil, sql.ErrNoRows
+		},
+		RevokeAPITokenFunc: func(ctx context.Context, id, userID uint) (bool, error) {
+			token, ok := tokens[id]
+			if !ok || token.UserID != userID || token.RevokedAt != nil {
+				return false, nil
+			}
+			now := token.CreatedAt
+			token.RevokedAt = &now
+			return true, nil
+		},
+	}
+}
+
+func TestAPITokenService_AuthenticateAndRevoke(t *testing.T) {
+	service := NewAPITokenService(newInMemoryAPITokenRepo())
+	ctx := context.Background()
+
+	created, err := service.CreateToken(ctx, dto.CreateAPITokenRequest{
+		UserID: 1,
+		Name:   "backup script",
+		Scopes: []string{"todos:read", "TODOS:READ"},
+	})
+	if err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	if created.Token.TokenHash == created.Secret {
+		t.Error("CreateToken() stored the token in plain text")
+	}
+	if len(created.Token.Scopes) != 1 || created.Token.Scopes[0] != "todos:read" {
+		t.Errorf("CreateToken() scopes = %v, want [todos:read]", created.Token.Scopes)
+	}
+
+	token, err := service.AuthenticateToken(ctx, created.Secret)
+	if err != nil {
+		t.Fatalf("AuthenticateToken() error = %v", err)
+	}
+	if token.UserID != 1 {
+		t.Errorf("AuthenticateToken() user = %d, want 1", token.UserID)
+	}
+
+	// Another user cannot revoke the token
+	if err := service.RevokeToken(ctx, 2, created.Token.ID); !errors.Is(err, ErrAPITokenNotFound) {
+		t.Errorf("RevokeToken() by other user error = %v, want %v", err, ErrAPITokenNotFound)
+	}
+
+	if err := service.RevokeToken(ctx, 1, created.Token.ID); err != nil {
+		t.Fatalf("RevokeToken() error = %v", err)
+	}
+	if _, err := service.AuthenticateToken(ctx, created.Secret); !errors.Is(err, ErrInvalidAPIToken) {
+		t.Errorf("AuthenticateToken() after revoke error = %v, want %v", err, ErrInvalidAPIToken)
+	}
+}
+
+func TestAPITokenService_CreateToken_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     dto.CreateAPITokenRequest
+		wantErr error
+	}{
+		{name: "blank name", req: dto.CreateAPITokenRequest{UserID: 1, Name: "  "}, wantErr: ErrAPITokenNameRequired},
+		{name: "unknown scope", req: dto.CreateAPITokenRequest{UserID: 1, Name: "ci", Scopes: []string{"admin"}}, wantErr: ErrInvalidAPITokenScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAPITokenService(newInMemoryAPITokenRepo())
+			_, err := service.CreateToken(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateToken() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"todo-app/internal/dto"
 	"todo-app/internal/models"
@@ -15,8 +18,19 @@ import (
 var (
 	ErrEmailAlreadyRegistered = errors.New("email already registered")
 	ErrInvalidCredentials     = errors.New("invalid email or password")
+	ErrEmailDomainBlocked     = errors.New("registrations from this email domain are not allowed")
+	ErrInvalidTimezone        = errors.New("timezone must be an IANA name such as Europe/Berlin")
 )
 
+// AuthConfig holds auth settings
+type AuthConfig struct {
+	BcryptCost          int
+	BlockedEmailDomains []string // compared case-insensitively against the part after "@"
+	// RegisterReturnsLoginOnExisting lets a registration for an existing email with that account's
+	// password log in instead of failing, so clients can safely retry a register whose response was lost
+	RegisterReturnsLoginOnExisting bool
+}
+
 // Ensure AuthServiceImpl implements AuthService
 var _ AuthService = (*AuthServiceImpl)(nil)
 
@@ -24,26 +38,53 @@ var _ AuthService = (*AuthServiceImpl)(nil)
 type AuthServiceImpl struct {
 	repo       repository.UserRepository
 	jwtManager *utils.JWTManager
+	config     AuthConfig
 }
 
-// NewAuthService creates a new AuthService with the provided repository and JWT manager
-func NewAuthService(repo repository.UserRepository, jwtManager *utils.JWTManager) AuthService {
+// NewAuthService creates a new AuthService with the provided repository, JWT manager and auth config
+func NewAuthService(repo repository.UserRepository, jwtManager *utils.JWTManager, config AuthConfig) AuthService {
 	return &AuthServiceImpl{
 		repo:       repo,
 		jwtManager: jwtManager,
+		config:     config,
 	}
 }
 
+// normalizeEmail trims and lowercases an email so casing never splits or locks out an account
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// isEmailDomainBlocked reports whether the email's domain is on the configured block list
+func (s *AuthServiceImpl) isEmailDomainBlocked(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, blocked := range s.config.BlockedEmailDomains {
+		if strings.EqualFold(domain, blocked) {
+			return true
+		}
+	}
+	return false
+}
+
 // RegisterUser handles complete user registration workflow
 func (s *AuthServiceImpl) RegisterUser(ctx context.Context, req dto.RegisterRequest) (*dto.AuthResponse, error) {
+	req.Email = normalizeEmail(req.Email)
+	if s.isEmailDomainBlocked(req.Email) {
+		return nil, ErrEmailDomainBlocked
+	}
+
 	// Check if user already exists
 	existingUser, _ := s.repo.GetUserByEmail(ctx, req.Email)
 	if existingUser != nil {
-		return nil, ErrEmailAlreadyRegistered
+		return s.replayRegistration(existingUser, req.Password)
 	}
 
 	// Hash the password
-	hashedPassword, err := utils.HashPassword(req.Password)
+	hashedPassword, err := utils.HashPassword(req.Password, s.config.BcryptCost)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -55,7 +96,16 @@ func (s *AuthServiceImpl) RegisterUser(ctx context.Context, req dto.RegisterRequ
 		Password: hashedPassword,
 	}
 
+	// The existence check above can race another registration, so the insert's unique key is the final word
 	if err := s.repo.CreateUser(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrDuplicateEmail) {
+			// Typically a retry racing the original request, which replays the same way
+			winner, lookupErr := s.repo.GetUserByEmail(ctx, req.Email)
+			if lookupErr != nil {
+				return nil, ErrEmailAlreadyRegistered
+			}
+			return s.replayRegistration(winner, req.Password)
+		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
@@ -71,10 +121,30 @@ func (s *AuthServiceImpl) RegisterUser(ctx context.Context, req dto.RegisterRequ
 	}, nil
 }
 
+// replayRegistration answers a registration for an email that already has an account. With
+// RegisterReturnsLoginOnExisting set and the account's password, it logs the user in; otherwise,
+// and always when the password differs, the email is reported as taken.
+func (s *AuthServiceImpl) replayRegistration(user *models.User, password string) (*dto.AuthResponse, error) {
+	if !s.config.RegisterReturnsLoginOnExisting || !utils.CheckPassword(password, user.Password) {
+		return nil, ErrEmailAlreadyRegistered
+	}
+
+	token, err := s.jwtManager.GenerateToken(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &dto.AuthResponse{
+		User:     user,
+		Token:    token,
+		Existing: true,
+	}, nil
+}
+
 // LoginUser handles user authentication workflow
 func (s *AuthServiceImpl) LoginUser(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error) {
 	// Find user by email
-	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	user, err := s.repo.GetUserByEmail(ctx, normalizeEmail(req.Email))
 	if err != nil {
 		return nil, ErrInvalidCredentials
 	}
@@ -100,3 +170,37 @@ func (s *AuthServiceImpl) LoginUser(ctx context.Context, req dto.LoginRequest) (
 func (s *AuthServiceImpl) GetByID(ctx context.Context, id uint) (*models.User, error) {
 	return s.repo.GetUserByID(ctx, id)
 }
+
+// UpdateProfile applies the provided profile fields and returns the updated user
+func (s *AuthServiceImpl) UpdateProfile(ctx context.Context, req dto.UpdateProfileRequest) (*models.User, error) {
+	if req.Timezone != nil {
+		if _, err := loadTimezone(*req.Timezone); err != nil {
+			return nil, err
+		}
+		if err := s.repo.UpdateUserTimezone(ctx, req.UserID, *req.Timezone); err != nil {
+			return nil, fmt.Errorf("failed to update timezone: %w", err)
+		}
+	}
+
+	user, err := s.repo.GetUserByID(ctx, req.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	return user, nil
+}
+
+// loadTimezone resolves an IANA timezone name. "Local" is rejected because it names the server's zone,
+// and an empty name because time.LoadLocation would silently treat it as UTC.
+func loadTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
@@ -8,8 +8,11 @@ import (
 
 	"todo-app/internal/dto"
 	"todo-app/internal/models"
+	"todo-app/internal/repository"
 	"todo-app/internal/repository/mocks"
 	"todo-app/pkg/utils"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestAuthService_RegisterUser(t *testing.T) {
@@ -59,6 +62,22 @@ func TestAuthService_RegisterUser(t *testing.T) {
 			wantErr:          true,
 			expectedErrorMsg: "email already registered",
 		},
+		{
+			name: "email registered between check and insert",
+			request: dto.RegisterRequest{
+				Name:     "John Doe",
+				Email:    "racer@example.com",
+				Password: "password123",
+			},
+			getByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+				return nil, errors.New("not found") // Not there yet when checked
+			},
+			createUserFunc: func(ctx context.Context, user *models.User) error {
+				return repository.ErrDuplicateEmail // Another registration won the insert
+			},
+			wantErr:          true,
+			expectedErrorMsg: "email already registered",
+		},
 		{
 			name: "database error",
 			request: dto.RegisterRequest{
@@ -82,7 +101,7 @@ func TestAuthService_RegisterUser(t *testing.T) {
 				GetUserByEmailFunc: tt.getByEmailFunc,
 				CreateUserFunc:     tt.createUserFunc,
 			}
-			service := NewAuthService(mockRepo, jwtManager)
+			service := NewAuthService(mockRepo, jwtManager, AuthConfig{BcryptCost: bcrypt.MinCost})
 
 			response, err := service.RegisterUser(context.Background(), tt.request)
 
@@ -115,6 +134,113 @@ func TestAuthService_RegisterUser(t *testing.T) {
 	}
 }
 
+func TestAuthService_RegisterUser_BlockedEmailDomains(t *testing.T) {
+	jwtManager, err := utils.NewJWTManager("test-secret-key")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{name: "blocked domain", email: "spam@mailinator.com", wantErr: ErrEmailDomainBlocked},
+		{name: "blocked domain is case-insensitive", email: "spam@MailInator.COM", wantErr: ErrEmailDomainBlocked},
+		{name: "allowed domain", email: "john@example.com"},
+		{name: "subdomain of blocked domain is allowed", email: "john@eu.mailinator.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			mockRepo := &mocks.MockUserRepository{
+				GetUserByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+					return nil, errors.New("not found")
+				},
+				CreateUserFunc: func(ctx context.Context, user *models.User) error {
+					created = true
+					user.ID = 1
+					return nil
+				},
+			}
+			service := NewAuthService(mockRepo, jwtManager, AuthConfig{
+				BcryptCost:          bcrypt.MinCost,
+				BlockedEmailDomains: []string{"mailinator.com", "tempmail.dev"},
+			})
+
+			_, err := service.RegisterUser(context.Background(), dto.RegisterRequest{
+				Name:     "John Doe",
+				Email:    tt.email,
+				Password: "password123",
+			})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("RegisterUser() error = %v, want %v", err, tt.wantErr)
+				}
+				if created {
+					t.Error("RegisterUser() created a user for a blocked domain")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("RegisterUser() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthService_EmailCaseInsensitive(t *testing.T) {
+	jwtManager, err := utils.NewJWTManager("test-secret-key")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+
+	// In-memory repository keyed by the email exactly as the service passes it
+	users := map[string]*models.User{}
+	mockRepo := &mocks.MockUserRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			if user, ok := users[email]; ok {
+				return user, nil
+			}
+			return nil, errors.New("not found")
+		},
+		CreateUserFunc: func(ctx context.Context, user *models.User) error {
+			user.ID = uint(len(users) + 1)
+			users[user.Email] = user
+			return nil
+		},
+	}
+	service := NewAuthService(mockRepo, jwtManager, AuthConfig{BcryptCost: bcrypt.MinCost})
+	ctx := context.Background()
+
+	registered, err := service.RegisterUser(ctx, dto.RegisterRequest{
+		Name:     "John Doe",
+		Email:    " John@Example.com",
+		Password: "password123",
+	})
+	if err != nil {
+		t.Fatalf("RegisterUser() error = %v", err)
+	}
+	if registered.User.Email != "john@example.com" {
+		t.Errorf("RegisterUser() stored email = %q, want %q", registered.User.Email, "john@example.com")
+	}
+
+	if _, err := service.LoginUser(ctx, dto.LoginRequest{Email: "JOHN@example.COM", Password: "password123"}); err != nil {
+		t.Errorf("LoginUser() with different casing error = %v", err)
+	}
+
+	_, err = service.RegisterUser(ctx, dto.RegisterRequest{
+		Name:     "Johnny",
+		Email:    "JOHN@EXAMPLE.COM",
+		Password: "password456",
+	})
+	if !errors.Is(err, ErrEmailAlreadyRegistered) {
+		t.Errorf("RegisterUser() with different casing error = %v, want %v", err, ErrEmailAlreadyRegistered)
+	}
+}
+
 func TestAuthService_LoginUser(t *testing.T) {
 	// Create JWT manager for testing
 	jwtManager, err := utils.NewJWTManager("test-secret-key")
@@ -123,7 +249,7 @@ func TestAuthService_LoginUser(t *testing.T) {
 	}
 
 	// Hash a test password
-	hashedPassword, _ := utils.HashPassword("password123")
+	hashedPassword, _ := utils.HashPassword("password123", bcrypt.MinCost)
 
 	tests := []struct {
 		name             string
@@ -184,7 +310,7 @@ func TestAuthService_LoginUser(t *testing.T) {
 			mockRepo := &mocks.MockUserRepository{
 				GetUserByEmailFunc: tt.getByEmailFunc,
 			}
-			service := NewAuthService(mockRepo, jwtManager)
+			service := NewAuthService(mockRepo, jwtManager, AuthConfig{BcryptCost: bcrypt.MinCost})
 
 			response, err := service.LoginUser(context.Background(), tt.request)
 
@@ -257,7 +383,7 @@ func TestAuthService_GetByID(t *testing.T) {
 			mockRepo := &mocks.MockUserRepository{
 				GetUserByIDFunc: tt.mockFunc,
 			}
-			service := NewAuthService(mockRepo, jwtManager)
+			service := NewAuthService(mockRepo, jwtManager, AuthConfig{BcryptCost: bcrypt.MinCost})
 
 			user, err := service.GetByID(context.Background(), tt.userID)
 
@@ -272,3 +398,126 @@ func TestAuthService_GetByID(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthService_UpdateProfile_Timezone(t *testing.T) {
+	jwtManager, err := utils.NewJWTManager("test-secret-key")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		timezone string
+		wantErr  error
+	}{
+		{name: "valid zone", timezone: "America/New_York"},
+		{name: "UTC", timezone: "UTC"},
+		{name: "unknown zone", timezone: "Mars/Olympus_Mons", wantErr: ErrInvalidTimezone},
+		{name: "server local zone", timezone: "Local", wantErr: ErrInvalidTimezone},
+		{name: "empty", timezone: "", wantErr: ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := "UTC"
+			mockRepo := &mocks.MockUserRepository{
+				UpdateUserTimezoneFunc: func(ctx context.Context, id uint, timezone string) error {
+					stored = timezone
+					return nil
+				},
+				GetUserByIDFunc: func(ctx context.Context, id uint) (*models.User, error) {
+					return &models.User{ID: id, Timezone: stored}, nil
+				},
+			}
+			service := NewAuthService(mockRepo, jwtManager, AuthConfig{BcryptCost: bcrypt.MinCost})
+
+			tz := tt.timezone
+			user, err := service.UpdateProfile(context.Background(), dto.UpdateProfileRequest{UserID: 1, Timezone: &tz})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateProfile() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if stored != "UTC" {
+					t.Errorf("invalid timezone was stored as %q", stored)
+				}
+				return
+			}
+			if user.Timezone != tt.timezone {
+				t.Errorf("UpdateProfile() timezone = %q, want %q", user.Timezone, tt.timezone)
+			}
+		})
+	}
+}
+
+func TestAuthService_RegisterUser_ReplayExisting(t *testing.T) {
+	jwtManager, err := utils.NewJWTManager("test-secret-key")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	hashed, err := utils.HashPassword("password123", bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		password   string
+		raceInsert bool // the account appears between the existence check and the insert
+		wantErr    error
+	}{
+		{name: "matching password logs in", enabled: true, password: "password123"},
+		{name: "matching password after losing the insert race", enabled: true, password: "password123", raceInsert: true},
+		{name: "different password keeps the conflict", enabled: true, password: "wrong-password", wantErr: ErrEmailAlreadyRegistered},
+		{name: "disabled keeps the conflict", enabled: false, password: "password123", wantErr: ErrEmailAlreadyRegistered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := &models.User{ID: 7, Name: "John Doe", Email: "john@example.com", Password: hashed}
+			lookups := 0
+			mockRepo := &mocks.MockUserRepository{
+				GetUserByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+					lookups++
+					if tt.raceInsert && lookups == 1 {
+						return nil, errors.New("not found")
+					}
+					return existing, nil
+				},
+				CreateUserFunc: func(ctx context.Context, user *models.User) error {
+					if !tt.raceInsert {
+						t.Error("CreateUser() called for an existing account")
+					}
+					return repository.ErrDuplicateEmail
+				},
+			}
+			service := NewAuthService(mockRepo, jwtManager, AuthConfig{
+				BcryptCost:                     bcrypt.MinCost,
+				RegisterReturnsLoginOnExisting: tt.enabled,
+			})
+
+			response, err := service.RegisterUser(context.Background(), dto.RegisterRequest{
+				Name:     "John Doe",
+				Email:    "John@Example.com",
+				Password: tt.password,
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RegisterUser() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !response.Existing || response.User.ID != existing.ID {
+				t.Errorf("RegisterUser() = existing %v user %d, want existing true user %d", response.Existing, response.User.ID, existing.ID)
+			}
+			claims, err := jwtManager.ValidateToken(response.Token)
+			if err != nil {
+				t.Fatalf("RegisterUser() token invalid: %v", err)
+			}
+			if claims.UserID != existing.ID {
+				t.Errorf("RegisterUser() token user = %d, want %d", claims.UserID, existing.ID)
+			}
+		})
+	}
+}
@@ -20,6 +20,14 @@ var (
 	ErrCannotShareWithSelf = errors.New("cannot share category with yourself")
 	ErrShareAlreadyExists  = errors.New("category is already shared with this user")
 	ErrShareNotFound       = errors.New("share not found")
+	ErrSameCategory        = errors.New("source and target category must be different")
+	ErrInvalidPermission   = errors.New("permission must be 'read' or 'write'")
+)
+
+// Sort orders accepted by GetSharesForCategory
+const (
+	ShareSortCreatedAt = "created_at" // newest share first (default)
+	ShareSortEmail     = "email"      // shared user's email, A-Z
 )
 
 // Ensure CategoryServiceImpl implements CategoryService
@@ -31,25 +39,42 @@ type CategoryServiceImpl struct {
 	categoryShareRepo repository.CategoryShareRepository
 	userRepo          repository.UserRepository
 	todoRepo          repository.TodoRepository
+	txManager         repository.TxManager
+	pagination        PaginationConfig
+	limits            LimitsConfig
 }
 
-// NewCategoryService creates a new CategoryService with the provided repositories
+// NewCategoryService creates a new CategoryService with the provided repositories, pagination and limits config.
+// txManager runs the operations that must change several tables atomically.
 func NewCategoryService(
 	categoryRepo repository.CategoryRepository,
 	categoryShareRepo repository.CategoryShareRepository,
 	userRepo repository.UserRepository,
 	todoRepo repository.TodoRepository,
+	txManager repository.TxManager,
+	pagination PaginationConfig,
+	limits LimitsConfig,
 ) CategoryService {
 	return &CategoryServiceImpl{
 		categoryRepo:      categoryRepo,
 		categoryShareRepo: categoryShareRepo,
 		userRepo:          userRepo,
 		todoRepo:          todoRepo,
+		txManager:         txManager,
+		pagination:        pagination,
+		limits:            limits,
 	}
 }
 
 // CreateCategory creates a new category for a user
 func (s *CategoryServiceImpl) CreateCategory(ctx context.Context, req dto.CreateCategoryRequest) (*models.Category, error) {
+	if req.DefaultSharePermission == "" {
+		req.DefaultSharePermission = models.PermissionRead
+	}
+	if !req.DefaultSharePermission.IsValid() {
+		return nil, ErrInvalidPermission
+	}
+
 	// Check if category with same name exists for this user
 	existing, err := s.categoryRepo.GetCategoryByNameAndOwner(ctx, req.OwnerID, req.Name)
 	if err == nil && existing != nil {
@@ -61,8 +86,10 @@ func (s *CategoryServiceImpl) CreateCategory(ctx context.Context, req dto.Create
 	}
 
 	category := &models.Category{
-		Name:    req.Name,
-		OwnerID: req.OwnerID,
+		Name:                   req.Name,
+		OwnerID:                req.OwnerID,
+		AllowDuplicateTitles:   true,
+		DefaultSharePermission: req.DefaultSharePermission,
 	}
 
 	if err := s.categoryRepo.CreateCategory(ctx, category); err != nil {
@@ -73,24 +100,56 @@ func (s *CategoryServiceImpl) CreateCategory(ctx context.Context, req dto.Create
 }
 
 // GetCategories retrieves all categories owned by a user
-func (s *CategoryServiceImpl) GetCategories(ctx context.Context, userID uint) ([]models.Category, error) {
+// With includeTodos unset the todo queries are skipped and categories carry no todos
+func (s *CategoryServiceImpl) GetCategories(ctx context.Context, userID uint, includeTodos bool) ([]models.Category, error) {
 	categories, err := s.categoryRepo.GetCategoriesByOwnerID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch categories: %w", err)
 	}
+	if !includeTodos {
+		return categories, nil
+	}
 
-	// For each category, fetch todos belonging to that category (owner-created todos)
+	ids := make([]uint, len(categories))
 	for i := range categories {
-		todos, _, err := s.todoRepo.GetTodosByCategoryID(ctx, categories[i].ID, 1, 1000)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch todos for category %d: %w", categories[i].ID, err)
-		}
-		categories[i].Todos = todos
+		ids[i] = categories[i].ID
+	}
+	todosByCategory, err := s.loadCategoryTodos(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range categories {
+		categories[i].Todos = todosByCategory[categories[i].ID]
 	}
 
 	return categories, nil
 }
 
+// categoryTodosLimit caps how many todos each category carries when categories are listed with their todos
+const categoryTodosLimit = 1000
+
+// loadCategoryTodos loads the todos of the given categories in one query, keyed by category ID
+// The query only returns todos from categories the user owns or is shared on, so a category ID that reached
+// here by mistake yields an empty list rather than someone else's todos. Every requested ID gets a non-nil list.
+func (s *CategoryServiceImpl) loadCategoryTodos(ctx context.Context, userID uint, categoryIDs []uint) (map[uint][]models.Todo, error) {
+	todos, err := s.todoRepo.GetTodosInAccessibleCategories(ctx, userID, categoryIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch category todos: %w", err)
+	}
+
+	byCategory := make(map[uint][]models.Todo, len(categoryIDs))
+	for _, id := range categoryIDs {
+		byCategory[id] = []models.Todo{}
+	}
+	for _, todo := range todos {
+		list, requested := byCategory[todo.CategoryID]
+		if requested && len(list) < categoryTodosLimit {
+			byCategory[todo.CategoryID] = append(list, todo)
+		}
+	}
+	return byCategory, nil
+}
+
 // GetCategoryByID retrieves a category by ID with ownership verification
 func (s *CategoryServiceImpl) GetCategoryByID(ctx context.Context, categoryID, userID uint) (*models.Category, error) {
 	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
@@ -116,18 +175,13 @@ func (s *CategoryServiceImpl) GetCategoryByID(ctx context.Context, categoryID, u
 
 // UpdateCategory updates a category with ownership verification
 func (s *CategoryServiceImpl) UpdateCategory(ctx context.Context, req dto.UpdateCategoryRequest) (*models.Category, error) {
-	// Fetch existing category
-	category, err := s.categoryRepo.GetCategoryByID(ctx, req.ID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrCategoryNotFound
-		}
-		return nil, fmt.Errorf("failed to fetch category: %w", err)
+	if req.DefaultSharePermission != nil && !req.DefaultSharePermission.IsValid() {
+		return nil, ErrInvalidPermission
 	}
 
-	// Verify ownership - only owner can update
-	if category.OwnerID != req.UserID {
-		return nil, ErrCategoryForbidden
+	category, err := s.authorizeCategoryAction(ctx, req.ID, req.UserID, models.CanUpdateCategory)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if new name conflicts with existing category
@@ -143,6 +197,12 @@ func (s *CategoryServiceImpl) UpdateCategory(ctx context.Context, req dto.Update
 
 	// Update the category
 	category.Name = req.Name
+	if req.AllowDuplicateTitles != nil {
+		category.AllowDuplicateTitles = *req.AllowDuplicateTitles
+	}
+	if req.DefaultSharePermission != nil {
+		category.DefaultSharePermission = *req.DefaultSharePermission
+	}
 	if err := s.categoryRepo.UpdateCategory(ctx, category); err != nil {
 		return nil, fmt.Errorf("failed to update category: %w", err)
 	}
@@ -152,65 +212,53 @@ func (s *CategoryServiceImpl) UpdateCategory(ctx context.Context, req dto.Update
 
 // DeleteCategory deletes a category with ownership verification
 func (s *CategoryServiceImpl) DeleteCategory(ctx context.Context, categoryID, userID uint) error {
-	// Fetch existing category
-	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ErrCategoryNotFound
-		}
-		return fmt.Errorf("failed to fetch category: %w", err)
-	}
-
-	// Verify ownership - only owner can delete
-	if category.OwnerID != userID {
-		return ErrCategoryForbidden
-	}
-
-	// Delete the category (cascades to shares and todos via FK)
-	if err := s.categoryRepo.DeleteCategory(ctx, categoryID); err != nil {
-		return fmt.Errorf("failed to delete category: %w", err)
+	if _, err := s.authorizeCategoryAction(ctx, categoryID, userID, models.CanDeleteCategory); err != nil {
+		return err
 	}
 
-	return nil
+	// Soft delete the category and its todos and drop its shares in one transaction, so a failure never
+	// leaves sharers attached to a deleted category; the name becomes free to reuse straight away
+	return s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		// Locked first, so these are exactly the todos the category delete takes with it
+		ids, err := repos.Todos.GetTodoIDsInCategory(ctx, categoryID, nil)
+		if err != nil {
+			return fmt.Errorf("failed to fetch category todos: %w", err)
+		}
+		if err := repos.Categories.DeleteCategory(ctx, categoryID); err != nil {
+			return fmt.Errorf("failed to delete category: %w", err)
+		}
+		if _, err := repos.CategoryShares.DeleteAllSharesForCategory(ctx, categoryID); err != nil {
+			return fmt.Errorf("failed to remove category shares: %w", err)
+		}
+		return recordEvents(ctx, repos.TodoEvents, ids, userID, models.TodoEventDelete, nil)
+	})
 }
 
 // ShareCategory shares a category with another user
-func (s *CategoryServiceImpl) ShareCategory(ctx context.Context, req dto.ShareCategoryRequest) (*models.CategoryShare, error) {
-	// Verify category exists and user is owner
-	category, err := s.categoryRepo.GetCategoryByID(ctx, req.CategoryID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrCategoryNotFound
-		}
-		return nil, fmt.Errorf("failed to fetch category: %w", err)
-	}
-
-	if category.OwnerID != req.OwnerID {
-		return nil, ErrCategoryForbidden
+// An empty permission falls back to the category's DefaultSharePermission
+// NameCollision is set when the recipient owns a category with the same name; the share is still made
+func (s *CategoryServiceImpl) ShareCategory(ctx context.Context, req dto.ShareCategoryRequest) (*dto.ShareCategoryResponse, error) {
+	if req.Permission != "" && !req.Permission.IsValid() {
+		return nil, ErrInvalidPermission
 	}
 
-	// Find user to share with by email
-	shareWithUser, err := s.userRepo.GetUserByEmail(ctx, req.ShareWithEmail)
+	category, shareWithUser, existing, err := s.resolveShareTarget(ctx, req.CategoryID, req.OwnerID, req.ShareWithEmail)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrUserNotFound
-		}
-		return nil, fmt.Errorf("failed to find user: %w", err)
+		return nil, err
 	}
-
-	// Cannot share with yourself
-	if shareWithUser.ID == req.OwnerID {
-		return nil, ErrCannotShareWithSelf
-	}
-
-	// Check if share already exists
-	existing, err := s.categoryShareRepo.GetCategoryShareByCategoryAndUser(ctx, req.CategoryID, shareWithUser.ID)
-	if err == nil && existing != nil {
+	if existing != nil {
 		return nil, ErrShareAlreadyExists
 	}
+	if req.Permission == "" {
+		req.Permission = category.DefaultSharePermission
+	}
+
+	// Warn when the recipient would see two categories with the same name
+	sameName, err := s.categoryRepo.GetCategoryByNameAndOwner(ctx, shareWithUser.ID, category.Name)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("failed to check existing share: %w", err)
+		return nil, fmt.Errorf("failed to check recipient categories: %w", err)
 	}
+	nameCollision := err == nil && sameName != nil
 
 	// Create the share
 	share := &models.CategoryShare{
@@ -223,26 +271,105 @@ func (s *CategoryServiceImpl) ShareCategory(ctx context.Context, req dto.ShareCa
 		return nil, fmt.Errorf("failed to create share: %w", err)
 	}
 
-	return share, nil
+	return &dto.ShareCategoryResponse{Share: share, NameCollision: nameCollision}, nil
 }
 
-// UnshareCategory removes sharing of a category with a user
-func (s *CategoryServiceImpl) UnshareCategory(ctx context.Context, req dto.UnshareCategoryRequest) error {
-	// Verify category exists and user is owner
-	category, err := s.categoryRepo.GetCategoryByID(ctx, req.CategoryID)
+// UpsertShare shares a category with another user, or changes the permission of an existing share.
+// created reports whether a new share was made; repeating the same request is a no-op.
+func (s *CategoryServiceImpl) UpsertShare(ctx context.Context, req dto.ShareCategoryRequest) (share *models.CategoryShare, created bool, err error) {
+	if !req.Permission.IsValid() {
+		return nil, false, ErrInvalidPermission
+	}
+
+	_, shareWithUser, existing, err := s.resolveShareTarget(ctx, req.CategoryID, req.OwnerID, req.ShareWithEmail)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if existing != nil {
+		if existing.Permission != req.Permission {
+			if err := s.categoryShareRepo.UpdateCategorySharePermission(ctx, existing.ID, req.Permission); err != nil {
+				return nil, false, fmt.Errorf("failed to update share permission: %w", err)
+			}
+			existing.Permission = req.Permission
+		}
+		return existing, false, nil
+	}
+
+	share = &models.CategoryShare{
+		CategoryID:       req.CategoryID,
+		SharedWithUserID: shareWithUser.ID,
+		Permission:       req.Permission,
+	}
+	if err := s.categoryShareRepo.CreateCategoryShare(ctx, share); err != nil {
+		return nil, false, fmt.Errorf("failed to create share: %w", err)
+	}
+	return share, true, nil
+}
+
+// PreviewShare reports which user an email resolves to and whether the category is already shared
+// with them, without creating a share. An unknown email is reported as not found rather than an error.
+func (s *CategoryServiceImpl) PreviewShare(ctx context.Context, req dto.SharePreviewRequest) (*dto.SharePreviewResponse, error) {
+	_, shareWithUser, existing, err := s.resolveShareTarget(ctx, req.CategoryID, req.OwnerID, req.ShareWithEmail)
+	if errors.Is(err, ErrUserNotFound) {
+		return &dto.SharePreviewResponse{Found: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &dto.SharePreviewResponse{
+		Found: true,
+		User:  &dto.SharePreviewUser{ID: shareWithUser.ID, Name: shareWithUser.Name},
+	}
+	if existing != nil {
+		preview.AlreadyShared = true
+		preview.Permission = existing.Permission
+	}
+	return preview, nil
+}
+
+// resolveShareTarget verifies that ownerID owns the category and looks up the user email belongs to,
+// along with their existing share of the category (nil when there is none)
+func (s *CategoryServiceImpl) resolveShareTarget(ctx context.Context, categoryID, ownerID uint, email string) (*models.Category, *models.User, *models.CategoryShare, error) {
+	category, err := s.authorizeCategoryAction(ctx, categoryID, ownerID, models.CanManageShares)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// Find user to share with by email
+	shareWithUser, err := s.userRepo.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, nil, ErrUserNotFound
+		}
+		return nil, nil, nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// Cannot share with yourself
+	if shareWithUser.ID == ownerID {
+		return nil, nil, nil, ErrCannotShareWithSelf
+	}
+
+	// Check if share already exists
+	existing, err := s.categoryShareRepo.GetCategoryShareByCategoryAndUser(ctx, categoryID, shareWithUser.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return ErrCategoryNotFound
+			return category, shareWithUser, nil, nil
 		}
-		return fmt.Errorf("failed to fetch category: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to check existing share: %w", err)
 	}
+	return category, shareWithUser, existing, nil
+}
 
-	if category.OwnerID != req.OwnerID {
-		return ErrCategoryForbidden
+// UnshareCategory removes sharing of a category with a user
+func (s *CategoryServiceImpl) UnshareCategory(ctx context.Context, req dto.UnshareCategoryRequest) error {
+	if _, err := s.authorizeCategoryAction(ctx, req.CategoryID, req.OwnerID, models.CanManageShares); err != nil {
+		return err
 	}
 
 	// Verify share exists
-	_, err = s.categoryShareRepo.GetCategoryShareByCategoryAndUser(ctx, req.CategoryID, req.SharedWithUserID)
+	_, err := s.categoryShareRepo.GetCategoryShareByCategoryAndUser(ctx, req.CategoryID, req.SharedWithUserID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ErrShareNotFound
@@ -258,19 +385,28 @@ func (s *CategoryServiceImpl) UnshareCategory(ctx context.Context, req dto.Unsha
 	return nil
 }
 
+// UnshareAll removes every share of a category in one statement and returns how many were removed
+func (s *CategoryServiceImpl) UnshareAll(ctx context.Context, categoryID, ownerID uint) (int64, error) {
+	if _, err := s.authorizeCategoryAction(ctx, categoryID, ownerID, models.CanManageShares); err != nil {
+		return 0, err
+	}
+
+	removed, err := s.categoryShareRepo.DeleteAllSharesForCategory(ctx, categoryID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete shares: %w", err)
+	}
+
+	return removed, nil
+}
+
 // UpdateSharePermission changes the permission of a shared category
 func (s *CategoryServiceImpl) UpdateSharePermission(ctx context.Context, req dto.UpdateSharePermissionRequest) error {
-	// Verify category exists and user is owner
-	category, err := s.categoryRepo.GetCategoryByID(ctx, req.CategoryID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ErrCategoryNotFound
-		}
-		return fmt.Errorf("failed to fetch category: %w", err)
+	if !req.Permission.IsValid() {
+		return ErrInvalidPermission
 	}
 
-	if category.OwnerID != req.OwnerID {
-		return ErrCategoryForbidden
+	if _, err := s.authorizeCategoryAction(ctx, req.CategoryID, req.OwnerID, models.CanManageShares); err != nil {
+		return err
 	}
 
 	// Verify share exists
@@ -290,50 +426,185 @@ func (s *CategoryServiceImpl) UpdateSharePermission(ctx context.Context, req dto
 	return nil
 }
 
-// GetSharesForCategory gets all shares for a category (owner only)
-func (s *CategoryServiceImpl) GetSharesForCategory(ctx context.Context, categoryID, userID uint) ([]models.CategoryShareWithUser, error) {
-	// Verify category exists and user is owner
-	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrCategoryNotFound
+// BulkUpdateSharePermissions checks ownership once, then applies every permission change in one transaction
+// Users the category is not shared with are reported in NotFound instead of failing the batch
+func (s *CategoryServiceImpl) BulkUpdateSharePermissions(ctx context.Context, req dto.BulkUpdateSharePermissionsRequest) (*dto.BulkUpdateSharePermissionsResponse, error) {
+	for _, update := range req.Updates {
+		if !update.Permission.IsValid() {
+			return nil, ErrInvalidPermission
 		}
-		return nil, fmt.Errorf("failed to fetch category: %w", err)
 	}
 
-	if category.OwnerID != userID {
-		return nil, ErrCategoryForbidden
+	if _, err := s.authorizeCategoryAction(ctx, req.CategoryID, req.OwnerID, models.CanManageShares); err != nil {
+		return nil, err
 	}
 
-	shares, err := s.categoryShareRepo.GetSharesForCategory(ctx, categoryID)
+	response := &dto.BulkUpdateSharePermissionsResponse{Updated: []uint{}, NotFound: []uint{}}
+	err := s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		for _, update := range req.Updates {
+			share, err := repos.CategoryShares.GetCategoryShareByCategoryAndUser(ctx, req.CategoryID, update.UserID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					response.NotFound = append(response.NotFound, update.UserID)
+					continue
+				}
+				return fmt.Errorf("failed to fetch share: %w", err)
+			}
+
+			if err := repos.CategoryShares.UpdateCategorySharePermission(ctx, share.ID, update.Permission); err != nil {
+				return fmt.Errorf("failed to update share permission: %w", err)
+			}
+			response.Updated = append(response.Updated, update.UserID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GetSharesForCategory gets a page of shares for a category (owner only)
+// sortBy is ShareSortCreatedAt or ShareSortEmail; an empty value uses ShareSortCreatedAt
+func (s *CategoryServiceImpl) GetSharesForCategory(ctx context.Context, categoryID, userID uint, sortBy string, page, pageSize int) (*dto.ShareListResponse, error) {
+	if _, err := s.authorizeCategoryAction(ctx, categoryID, userID, models.CanManageShares); err != nil {
+		return nil, err
+	}
+
+	// Normalize pagination parameters using config values
+	page = max(page, 1)
+	if pageSize < 1 {
+		pageSize = s.pagination.DefaultPageSize
+	}
+	pageSize = min(pageSize, s.pagination.MaxPageSize)
+
+	// Sorting happens in the query so that each page is a slice of the sorted list
+	if sortBy != ShareSortEmail {
+		sortBy = ShareSortCreatedAt
+	}
+
+	shares, total, err := s.categoryShareRepo.GetSharesForCategory(ctx, categoryID, sortBy, page, pageSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch shares: %w", err)
 	}
 
-	return shares, nil
+	totalPages := (total + int64(pageSize) - 1) / int64(pageSize)
+
+	return &dto.ShareListResponse{
+		Shares:     shares,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
 }
 
-// GetSharedCategories gets all categories shared with a user
-func (s *CategoryServiceImpl) GetSharedCategories(ctx context.Context, userID uint) ([]models.SharedCategoryWithOwner, error) {
-	categories, err := s.categoryShareRepo.GetSharedCategoriesForUser(ctx, userID)
+// GetSharedCategories gets a page of the categories shared with a user, ordered by name
+// An empty permission returns every share; otherwise only shares at that level are returned
+func (s *CategoryServiceImpl) GetSharedCategories(ctx context.Context, userID uint, permission models.Permission, page, pageSize int, includeTodos bool) (*dto.SharedCategoryListResponse, error) {
+	// Normalize pagination parameters using config values
+	page = max(page, 1)
+	if pageSize < 1 {
+		pageSize = s.pagination.DefaultPageSize
+	}
+	pageSize = min(pageSize, s.pagination.MaxPageSize)
+
+	// Filtering happens in the query so that each page and the total only cover matching shares
+	categories, total, err := s.categoryShareRepo.GetSharedCategoriesForUser(ctx, userID, permission, page, pageSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch shared categories: %w", err)
 	}
 
-	// Populate todos for each shared category
-	for i := range categories {
-		todos, _, err := s.todoRepo.GetTodosByCategoryID(ctx, categories[i].ID, 1, 1000)
+	// Populate todos for the shared categories on this page
+	if includeTodos {
+		ids := make([]uint, len(categories))
+		for i := range categories {
+			ids[i] = categories[i].ID
+		}
+		todosByCategory, err := s.loadCategoryTodos(ctx, userID, ids)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch todos for shared category %d: %w", categories[i].ID, err)
+			return nil, err
+		}
+		for i := range categories {
+			categories[i].Todos = todosByCategory[categories[i].ID]
 		}
-		categories[i].Todos = todos
 	}
 
+	totalPages := (total + int64(pageSize) - 1) / int64(pageSize)
+
+	return &dto.SharedCategoryListResponse{
+		Categories: categories,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetWritableCategories lists the owned and write-shared categories a user can add todos to
+// Todos are not loaded, so it is cheap enough for a "move to" picker
+func (s *CategoryServiceImpl) GetWritableCategories(ctx context.Context, userID uint) ([]models.WritableCategory, error) {
+	categories, err := s.categoryShareRepo.GetWritableCategoriesForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch writable categories: %w", err)
+	}
+	return categories, nil
+}
+
+// GetCategoriesSharedByMe lists the categories the user owns that are shared with at least one other
+// user, each with its recipients and their permissions
+func (s *CategoryServiceImpl) GetCategoriesSharedByMe(ctx context.Context, userID uint) ([]models.CategoryWithShares, error) {
+	categories, err := s.categoryShareRepo.GetCategoriesSharedByOwner(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch shared-out categories: %w", err)
+	}
 	return categories, nil
 }
 
+// GetGrantedMembers lists every user the owner has shared at least one category with, ordered by email,
+// each once with the categories they were granted and their permission on each
+func (s *CategoryServiceImpl) GetGrantedMembers(ctx context.Context, userID uint) ([]models.GrantedMember, error) {
+	members, err := s.categoryShareRepo.GetGrantedMembersByOwner(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch granted members: %w", err)
+	}
+	return members, nil
+}
+
+// authorizeCategoryAction fetches a category and checks that userID may perform action on it.
+// Every CategoryAction is owner-only, so sharers, write permission included, get ErrCategoryForbidden.
+func (s *CategoryServiceImpl) authorizeCategoryAction(ctx context.Context, categoryID, userID uint, action models.CategoryAction) (*models.Category, error) {
+	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch category: %w", err)
+	}
+
+	if category.OwnerID != userID {
+		return nil, fmt.Errorf("%w: %s is reserved to the owner", ErrCategoryForbidden, action)
+	}
+
+	return category, nil
+}
+
 // GetUserPermissionForCategory checks what permission a user has for a category
+// Returns "owner", "write", "read" or "none"
 func (s *CategoryServiceImpl) GetUserPermissionForCategory(ctx context.Context, userID, categoryID uint) (string, error) {
+	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrCategoryNotFound
+		}
+		return "", fmt.Errorf("failed to fetch category: %w", err)
+	}
+
+	if category.OwnerID == userID {
+		return "owner", nil
+	}
+
 	permission, err := s.categoryShareRepo.GetUserPermissionForCategory(ctx, userID, categoryID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -341,5 +612,104 @@ func (s *CategoryServiceImpl) GetUserPermissionForCategory(ctx context.Context,
 		}
 		return "", fmt.Errorf("failed to fetch permission: %w", err)
 	}
+	if permission == "" {
+		return "none", nil
+	}
 	return permission, nil
 }
+
+// getWritableCategory fetches a category and verifies the user owns it or has write access
+func (s *CategoryServiceImpl) getWritableCategory(ctx context.Context, userID, categoryID uint) (*models.Category, error) {
+	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch category: %w", err)
+	}
+
+	if category.OwnerID == userID {
+		return category, nil
+	}
+
+	permission, err := s.categoryShareRepo.GetUserPermissionForCategory(ctx, userID, categoryID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to fetch permission: %w", err)
+	}
+	if permission != string(models.PermissionWrite) {
+		return nil, ErrCategoryForbidden
+	}
+
+	return category, nil
+}
+
+// MoveTodos moves all todos from one category to another in one transaction
+// The user needs ownership or write access on both categories; moved todos are reassigned to the target owner
+// and each gets a history entry
+func (s *CategoryServiceImpl) MoveTodos(ctx context.Context, userID, fromCategoryID, toCategoryID uint) (int64, error) {
+	if fromCategoryID == toCategoryID {
+		return 0, ErrSameCategory
+	}
+
+	if _, err := s.getWritableCategory(ctx, userID, fromCategoryID); err != nil {
+		return 0, err
+	}
+
+	target, err := s.getWritableCategory(ctx, userID, toCategoryID)
+	if err != nil {
+		return 0, err
+	}
+
+	var moved int64
+	err = s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		ids, err := repos.Todos.GetTodoIDsInCategory(ctx, fromCategoryID, nil)
+		if err != nil {
+			return fmt.Errorf("failed to fetch todos to move: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		// The move is all or nothing, so the whole source category must fit in the target
+		if err := checkCategoryCapacity(ctx, repos, s.limits.MaxTodosPerCategory, toCategoryID, int64(len(ids))); err != nil {
+			return err
+		}
+
+		if moved, err = repos.Todos.MoveTodos(ctx, ids, toCategoryID, target.OwnerID); err != nil {
+			return fmt.Errorf("failed to move todos: %w", err)
+		}
+
+		// The IDs are locked, so every one of them moved and gets a history entry
+		return recordEvents(ctx, repos.TodoEvents, ids, userID, models.TodoEventUpdate, []string{"category_id"})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+// CleanupEmptyCategories soft deletes the user's own categories that have no live todos and no shares,
+// such as those auto-created for todos that later moved or were deleted
+// With dryRun set, nothing is modified and the categories that would be removed are returned
+func (s *CategoryServiceImpl) CleanupEmptyCategories(ctx context.Context, userID uint, dryRun bool) ([]models.Category, error) {
+	empty, err := s.categoryRepo.GetEmptyCategoriesByOwnerID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch empty categories: %w", err)
+	}
+	if dryRun {
+		return empty, nil
+	}
+
+	removed := make([]models.Category, 0, len(empty))
+	for _, category := range empty {
+		// A category that gained a todo or share since it was listed is kept
+		deleted, err := s.categoryRepo.DeleteEmptyCategory(ctx, category.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete empty category: %w", err)
+		}
+		if deleted {
+			removed = append(removed, category)
+		}
+	}
+	return removed, nil
+}
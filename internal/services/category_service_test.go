@@ -4,10 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"reflect"
+	"slices"
 	"testing"
 
 	"todo-app/internal/dto"
 	"todo-app/internal/models"
+	"todo-app/internal/repository"
 	"todo-app/internal/repository/mocks"
 )
 
@@ -27,7 +30,17 @@ func createTestCategoryService(
 	}
 	// Provide a default mock todo repo so service can fetch todos for categories
 	todoRepo := &mocks.MockTodoRepository{}
-	return NewCategoryService(categoryRepo, categoryShareRepo, userRepo, todoRepo)
+	return NewCategoryService(categoryRepo, categoryShareRepo, userRepo, todoRepo, mockTx(categoryRepo, categoryShareRepo, todoRepo), PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100}, LimitsConfig{})
+}
+
+// mockTx runs transactional work directly against the given mocks
+func mockTx(categoryRepo *mocks.MockCategoryRepository, categoryShareRepo *mocks.MockCategoryShareRepository, todoRepo *mocks.MockTodoRepository) *mocks.MockTxManager {
+	return &mocks.MockTxManager{Repos: repository.RepoSet{
+		Todos:          todoRepo,
+		Categories:     categoryRepo,
+		CategoryShares: categoryShareRepo,
+		TodoEvents:     &mocks.MockTodoEventRepository{},
+	}}
 }
 
 func TestCategoryService_CreateCategory(t *testing.T) {
@@ -287,6 +300,256 @@ func TestCategoryService_DeleteCategory(t *testing.T) {
 	}
 }
 
+func TestCategoryService_DeleteCategory_Transaction(t *testing.T) {
+	injected := errors.New("lock wait timeout")
+
+	tests := []struct {
+		name         string
+		unshareErr   error
+		wantRollback bool
+	}{
+		{name: "commits when both steps succeed"},
+		{name: "rolls back when removing shares fails", unshareErr: injected, wantRollback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryDeleted := false
+			categoryRepo := &mocks.MockCategoryRepository{
+				GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+					return &models.Category{ID: id, Name: "Work", OwnerID: 1}, nil
+				},
+				DeleteCategoryFunc: func(ctx context.Context, id uint) error {
+					categoryDeleted = true
+					return nil
+				},
+			}
+			shareRepo := &mocks.MockCategoryShareRepository{
+				DeleteAllSharesForCategoryFunc: func(ctx context.Context, categoryID uint) (int64, error) {
+					return 0, tt.unshareErr
+				},
+			}
+
+			// The category's two todos are deleted with it and each gets a delete entry in its history
+			todoRepo := &mocks.MockTodoRepository{
+				GetTodoIDsInCategoryFunc: func(ctx context.Context, categoryID uint, completed *bool) ([]uint, error) {
+					return []uint{10, 11}, nil
+				},
+			}
+			var events []models.TodoEvent
+			eventRepo := &mocks.MockTodoEventRepository{
+				CreateTodoEventFunc: func(ctx context.Context, event *models.TodoEvent) error {
+					events = append(events, *event)
+					return nil
+				},
+			}
+
+			rolledBack := false
+			txManager := &mocks.MockTxManager{
+				WithinTxFunc: func(ctx context.Context, fn func(repos repository.RepoSet) error) error {
+					err := fn(repository.RepoSet{Todos: todoRepo, Categories: categoryRepo, CategoryShares: shareRepo, TodoEvents: eventRepo})
+					rolledBack = err != nil
+					return err
+				},
+			}
+			service := NewCategoryService(categoryRepo, &mocks.MockCategoryShareRepository{}, &mocks.MockUserRepository{}, &mocks.MockTodoRepository{},
+				txManager, PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100}, LimitsConfig{})
+
+			err := service.DeleteCategory(context.Background(), 1, 1)
+			if tt.wantRollback && !errors.Is(err, injected) {
+				t.Errorf("DeleteCategory() error = %v, want the injected error", err)
+			}
+			if !tt.wantRollback && err != nil {
+				t.Errorf("DeleteCategory() unexpected error = %v", err)
+			}
+			if rolledBack != tt.wantRollback {
+				t.Errorf("rolled back = %v, want %v", rolledBack, tt.wantRollback)
+			}
+			if !categoryDeleted {
+				t.Error("DeleteCategory() did not delete the category inside the transaction")
+			}
+			if !tt.wantRollback {
+				if len(events) != 2 {
+					t.Fatalf("DeleteCategory() recorded %d history events, want 2", len(events))
+				}
+				for _, event := range events {
+					if event.ActorID != 1 || event.Action != models.TodoEventDelete {
+						t.Errorf("DeleteCategory() recorded %+v, want a delete by user 1", event)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCategoryService_SharersCannotChangeCategory(t *testing.T) {
+	// User 1 owns category 1; user 2 has a write share and user 3 a read share
+	permissions := map[uint]models.Permission{2: models.PermissionWrite, 3: models.PermissionRead}
+
+	actions := []struct {
+		name string
+		run  func(service CategoryService, userID uint) error
+	}{
+		{
+			name: "delete",
+			run: func(service CategoryService, userID uint) error {
+				return service.DeleteCategory(context.Background(), 1, userID)
+			},
+		},
+		{
+			name: "rename",
+			run: func(service CategoryService, userID uint) error {
+				_, err := service.UpdateCategory(context.Background(), dto.UpdateCategoryRequest{ID: 1, UserID: userID, Name: "Renamed"})
+				return err
+			},
+		},
+		{
+			name: "unshare all",
+			run: func(service CategoryService, userID uint) error {
+				_, err := service.UnshareAll(context.Background(), 1, userID)
+				return err
+			},
+		},
+		{
+			name: "change share permission",
+			run: func(service CategoryService, userID uint) error {
+				return service.UpdateSharePermission(context.Background(), dto.UpdateSharePermissionRequest{
+					CategoryID: 1, OwnerID: userID, SharedWithUserID: 3, Permission: models.PermissionWrite,
+				})
+			},
+		},
+	}
+
+	for _, action := range actions {
+		for userID, permission := range permissions {
+			t.Run(action.name+" as "+string(permission)+" sharer", func(t *testing.T) {
+				changed := false
+				categoryRepo := &mocks.MockCategoryRepository{
+					GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+						return &models.Category{ID: id, Name: "Work", OwnerID: 1}, nil
+					},
+					UpdateCategoryFunc: func(ctx context.Context, category *models.Category) error {
+						changed = true
+						return nil
+					},
+					DeleteCategoryFunc: func(ctx context.Context, id uint) error {
+						changed = true
+						return nil
+					},
+				}
+				categoryShareRepo := &mocks.MockCategoryShareRepository{
+					GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+						return string(permissions[userID]), nil
+					},
+					DeleteAllSharesForCategoryFunc: func(ctx context.Context, categoryID uint) (int64, error) {
+						changed = true
+						return 0, nil
+					},
+					UpdateCategorySharePermissionFunc: func(ctx context.Context, id uint, permission models.Permission) error {
+						changed = true
+						return nil
+					},
+				}
+				service := createTestCategoryService(categoryRepo, categoryShareRepo, nil)
+
+				if err := action.run(service, userID); !errors.Is(err, ErrCategoryForbidden) {
+					t.Fatalf("%s by sharer error = %v, want ErrCategoryForbidden", action.name, err)
+				}
+				if changed {
+					t.Errorf("%s by sharer changed the category", action.name)
+				}
+			})
+		}
+	}
+}
+
+func TestCategoryService_PreviewShare(t *testing.T) {
+	tests := []struct {
+		name          string
+		email         string
+		existingShare *models.CategoryShare
+		want          dto.SharePreviewResponse
+		wantErr       error
+	}{
+		{
+			name:  "found",
+			email: "user2@test.com",
+			want:  dto.SharePreviewResponse{Found: true, User: &dto.SharePreviewUser{ID: 2, Name: "User Two"}},
+		},
+		{
+			name:          "already shared",
+			email:         "user2@test.com",
+			existingShare: &models.CategoryShare{ID: 7, CategoryID: 1, SharedWithUserID: 2, Permission: models.PermissionWrite},
+			want:          dto.SharePreviewResponse{Found: true, User: &dto.SharePreviewUser{ID: 2, Name: "User Two"}, AlreadyShared: true, Permission: models.PermissionWrite},
+		},
+		{
+			// Emails are stored normalized, so the lookup is normalized too
+			name:  "mixed case",
+			email: " User2@Test.com",
+			want:  dto.SharePreviewResponse{Found: true, User: &dto.SharePreviewUser{ID: 2, Name: "User Two"}},
+		},
+		{
+			name:  "not found",
+			email: "typo@test.com",
+			want:  dto.SharePreviewResponse{Found: false},
+		},
+		{
+			name:    "self",
+			email:   "owner@test.com",
+			wantErr: ErrCannotShareWithSelf,
+		},
+	}
+
+	users := map[string]*models.User{
+		"owner@test.com": {ID: 1, Name: "Owner", Email: "owner@test.com"},
+		"user2@test.com": {ID: 2, Name: "User Two", Email: "user2@test.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryRepo := &mocks.MockCategoryRepository{
+				GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+					return &models.Category{ID: 1, Name: "Work", OwnerID: 1}, nil
+				},
+			}
+			userRepo := &mocks.MockUserRepository{
+				GetUserByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+					if user, ok := users[email]; ok {
+						return user, nil
+					}
+					return nil, sql.ErrNoRows
+				},
+			}
+			created := false
+			categoryShareRepo := &mocks.MockCategoryShareRepository{
+				GetCategoryShareByCategoryAndUserFunc: func(ctx context.Context, categoryID, userID uint) (*models.CategoryShare, error) {
+					if tt.existingShare == nil {
+						return nil, sql.ErrNoRows
+					}
+					return tt.existingShare, nil
+				},
+				CreateCategoryShareFunc: func(ctx context.Context, share *models.CategoryShare) error {
+					created = true
+					return nil
+				},
+			}
+
+			service := createTestCategoryService(categoryRepo, categoryShareRepo, userRepo)
+			preview, err := service.PreviewShare(context.Background(), dto.SharePreviewRequest{CategoryID: 1, OwnerID: 1, ShareWithEmail: tt.email})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PreviewShare() error = %v, want %v", err, tt.wantErr)
+			}
+			if created {
+				t.Error("PreviewShare() created a share")
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(*preview, tt.want) {
+				t.Errorf("PreviewShare() = %+v, want %+v", *preview, tt.want)
+			}
+		})
+	}
+}
+
 func TestCategoryService_ShareCategory(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -337,6 +600,15 @@ func TestCategoryService_ShareCategory(t *testing.T) {
 			existingShare: &models.CategoryShare{ID: 1, CategoryID: 1, SharedWithUserID: 2},
 			wantErr:       true,
 		},
+		{
+			name:            "invalid permission",
+			req:             dto.ShareCategoryRequest{CategoryID: 1, OwnerID: 1, ShareWithEmail: "user2@test.com", Permission: "admin"},
+			category:        &models.Category{ID: 1, Name: "Work", OwnerID: 1},
+			shareWithUser:   &models.User{ID: 2, Email: "user2@test.com"},
+			getShareErr:     sql.ErrNoRows,
+			wantErr:         true,
+			expectedErrType: ErrInvalidPermission,
+		},
 	}
 
 	for _, tt := range tests {
@@ -380,6 +652,10 @@ func TestCategoryService_ShareCategory(t *testing.T) {
 				return
 			}
 
+			if tt.expectedErrType != nil && !errors.Is(err, tt.expectedErrType) {
+				t.Errorf("ShareCategory() error = %v, want %v", err, tt.expectedErrType)
+			}
+
 			if !tt.wantErr && share == nil {
 				t.Error("ShareCategory() returned nil share")
 			}
@@ -387,6 +663,153 @@ func TestCategoryService_ShareCategory(t *testing.T) {
 	}
 }
 
+func TestCategoryService_ShareCategory_NameCollision(t *testing.T) {
+	tests := []struct {
+		name          string
+		recipientHas  *models.Category
+		recipientErr  error
+		wantCollision bool
+	}{
+		{name: "recipient owns a same-named category", recipientHas: &models.Category{ID: 7, Name: "Work", OwnerID: 2}, wantCollision: true},
+		{name: "recipient has no such category", recipientErr: sql.ErrNoRows, wantCollision: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookedUpOwner uint
+			var lookedUpName string
+			categoryRepo := &mocks.MockCategoryRepository{
+				GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+					return &models.Category{ID: id, Name: "Work", OwnerID: 1}, nil
+				},
+				GetCategoryByNameAndOwnerFunc: func(ctx context.Context, ownerID uint, name string) (*models.Category, error) {
+					lookedUpOwner, lookedUpName = ownerID, name
+					return tt.recipientHas, tt.recipientErr
+				},
+			}
+			userRepo := &mocks.MockUserRepository{
+				GetUserByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+					return &models.User{ID: 2, Email: email}, nil
+				},
+			}
+			categoryShareRepo := &mocks.MockCategoryShareRepository{
+				GetCategoryShareByCategoryAndUserFunc: func(ctx context.Context, categoryID, userID uint) (*models.CategoryShare, error) {
+					return nil, sql.ErrNoRows
+				},
+			}
+
+			service := createTestCategoryService(categoryRepo, categoryShareRepo, userRepo)
+			response, err := service.ShareCategory(context.Background(), dto.ShareCategoryRequest{
+				CategoryID: 1, OwnerID: 1, ShareWithEmail: "user2@test.com", Permission: models.PermissionWrite,
+			})
+			if err != nil {
+				t.Fatalf("ShareCategory() error = %v", err)
+			}
+
+			if response.Share == nil {
+				t.Error("ShareCategory() did not create the share")
+			}
+			if response.NameCollision != tt.wantCollision {
+				t.Errorf("ShareCategory() NameCollision = %v, want %v", response.NameCollision, tt.wantCollision)
+			}
+			if lookedUpOwner != 2 || lookedUpName != "Work" {
+				t.Errorf("looked up category %q of user %d, want %q of user 2", lookedUpName, lookedUpOwner, "Work")
+			}
+		})
+	}
+}
+
+func TestCategoryService_ShareCategory_DefaultPermission(t *testing.T) {
+	tests := []struct {
+		name            string
+		categoryDefault models.Permission
+		requested       models.Permission
+		wantPermission  models.Permission
+	}{
+		{name: "omitted permission uses a write default", categoryDefault: models.PermissionWrite, wantPermission: models.PermissionWrite},
+		{name: "omitted permission uses a read default", categoryDefault: models.PermissionRead, wantPermission: models.PermissionRead},
+		{name: "explicit permission overrides the default", categoryDefault: models.PermissionWrite, requested: models.PermissionRead, wantPermission: models.PermissionRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryRepo := &mocks.MockCategoryRepository{
+				GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+					return &models.Category{ID: id, Name: "Team", OwnerID: 1, DefaultSharePermission: tt.categoryDefault}, nil
+				},
+				GetCategoryByNameAndOwnerFunc: func(ctx context.Context, ownerID uint, name string) (*models.Category, error) {
+					return nil, sql.ErrNoRows
+				},
+			}
+			userRepo := &mocks.MockUserRepository{
+				GetUserByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+					return &models.User{ID: 2, Email: email}, nil
+				},
+			}
+			var stored models.Permission
+			categoryShareRepo := &mocks.MockCategoryShareRepository{
+				GetCategoryShareByCategoryAndUserFunc: func(ctx context.Context, categoryID, userID uint) (*models.CategoryShare, error) {
+					return nil, sql.ErrNoRows
+				},
+				CreateCategoryShareFunc: func(ctx context.Context, share *models.CategoryShare) error {
+					stored = share.Permission
+					return nil
+				},
+			}
+
+			service := createTestCategoryService(categoryRepo, categoryShareRepo, userRepo)
+			response, err := service.ShareCategory(context.Background(), dto.ShareCategoryRequest{
+				CategoryID: 1, OwnerID: 1, ShareWithEmail: "user2@test.com", Permission: tt.requested,
+			})
+			if err != nil {
+				t.Fatalf("ShareCategory() error = %v", err)
+			}
+			if stored != tt.wantPermission || response.Share.Permission != tt.wantPermission {
+				t.Errorf("ShareCategory() stored %q, returned %q, want %q", stored, response.Share.Permission, tt.wantPermission)
+			}
+		})
+	}
+}
+
+func TestCategoryService_CreateCategory_DefaultSharePermission(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested models.Permission
+		want      models.Permission
+		wantErr   error
+	}{
+		{name: "defaults to read", want: models.PermissionRead},
+		{name: "write", requested: models.PermissionWrite, want: models.PermissionWrite},
+		{name: "invalid", requested: "admin", wantErr: ErrInvalidPermission},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored models.Permission
+			categoryRepo := &mocks.MockCategoryRepository{
+				GetCategoryByNameAndOwnerFunc: func(ctx context.Context, ownerID uint, name string) (*models.Category, error) {
+					return nil, sql.ErrNoRows
+				},
+				CreateCategoryFunc: func(ctx context.Context, category *models.Category) error {
+					stored = category.DefaultSharePermission
+					return nil
+				},
+			}
+
+			service := createTestCategoryService(categoryRepo, nil, nil)
+			_, err := service.CreateCategory(context.Background(), dto.CreateCategoryRequest{
+				Name: "Team", OwnerID: 1, DefaultSharePermission: tt.requested,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateCategory() error = %v, want %v", err, tt.wantErr)
+			}
+			if stored != tt.want {
+				t.Errorf("CreateCategory() stored default %q, want %q", stored, tt.want)
+			}
+		})
+	}
+}
+
 func TestCategoryService_UnshareCategory(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -459,6 +882,130 @@ func TestCategoryService_UnshareCategory(t *testing.T) {
 	}
 }
 
+func TestCategoryService_UnshareAll(t *testing.T) {
+	shares := map[uint]bool{2: true, 3: true, 4: true}
+
+	categoryRepo := &mocks.MockCategoryRepository{
+		GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+			return &models.Category{ID: id, Name: "Work", OwnerID: 1}, nil
+		},
+	}
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		DeleteAllSharesForCategoryFunc: func(ctx context.Context, categoryID uint) (int64, error) {
+			removed := int64(len(shares))
+			shares = map[uint]bool{}
+			return removed, nil
+		},
+	}
+	service := createTestCategoryService(categoryRepo, categoryShareRepo, nil)
+
+	if _, err := service.UnshareAll(context.Background(), 1, 2); !errors.Is(err, ErrCategoryForbidden) {
+		t.Fatalf("UnshareAll() by non-owner error = %v, want ErrCategoryForbidden", err)
+	}
+	if len(shares) != 3 {
+		t.Fatalf("non-owner removed shares, %d left", len(shares))
+	}
+
+	removed, err := service.UnshareAll(context.Background(), 1, 1)
+	if err != nil {
+		t.Fatalf("UnshareAll() error = %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("UnshareAll() removed = %d, want 3", removed)
+	}
+	if len(shares) != 0 {
+		t.Errorf("expected all shares removed, %d left", len(shares))
+	}
+}
+
+func TestCategoryService_UpsertShare(t *testing.T) {
+	// The share repository keeps the one share in memory so the second call sees the first
+	var stored *models.CategoryShare
+	creates, updates := 0, 0
+	categoryRepo := &mocks.MockCategoryRepository{
+		GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+			return &models.Category{ID: id, Name: "Work", OwnerID: 1}, nil
+		},
+	}
+	userRepo := &mocks.MockUserRepository{
+		GetUserByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			return &models.User{ID: 2, Email: email}, nil
+		},
+	}
+	shareRepo := &mocks.MockCategoryShareRepository{
+		GetCategoryShareByCategoryAndUserFunc: func(ctx context.Context, categoryID, userID uint) (*models.CategoryShare, error) {
+			if stored == nil {
+				return nil, sql.ErrNoRows
+			}
+			share := *stored
+			return &share, nil
+		},
+		CreateCategoryShareFunc: func(ctx context.Context, share *models.CategoryShare) error {
+			creates++
+			share.ID = 10
+			stored = share
+			return nil
+		},
+		UpdateCategorySharePermissionFunc: func(ctx context.Context, id uint, permission models.Permission) error {
+			updates++
+			stored.Permission = permission
+			return nil
+		},
+	}
+	service := createTestCategoryService(categoryRepo, shareRepo, userRepo)
+	req := dto.ShareCategoryRequest{CategoryID: 1, OwnerID: 1, ShareWithEmail: "user2@test.com", Permission: models.PermissionRead}
+
+	share, created, err := service.UpsertShare(context.Background(), req)
+	if err != nil {
+		t.Fatalf("UpsertShare() first call error = %v", err)
+	}
+	if !created || share.Permission != models.PermissionRead {
+		t.Errorf("UpsertShare() first call = (%+v, created %v), want a new read share", share, created)
+	}
+
+	req.Permission = models.PermissionWrite
+	share, created, err = service.UpsertShare(context.Background(), req)
+	if err != nil {
+		t.Fatalf("UpsertShare() second call error = %v, want no conflict", err)
+	}
+	if created || share.ID != 10 || share.Permission != models.PermissionWrite {
+		t.Errorf("UpsertShare() second call = (%+v, created %v), want share 10 updated to write", share, created)
+	}
+
+	// Repeating the same request changes nothing
+	if _, created, err = service.UpsertShare(context.Background(), req); err != nil || created {
+		t.Errorf("UpsertShare() repeat = (created %v, %v), want an unchanged existing share", created, err)
+	}
+	if creates != 1 || updates != 1 {
+		t.Errorf("got %d creates and %d updates, want 1 and 1", creates, updates)
+	}
+}
+
+func TestCategoryService_UpdateSharePermission_InvalidPermission(t *testing.T) {
+	updated := false
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		UpdateCategorySharePermissionFunc: func(ctx context.Context, id uint, permission models.Permission) error {
+			updated = true
+			return nil
+		},
+	}
+
+	service := createTestCategoryService(nil, categoryShareRepo, nil)
+	err := service.UpdateSharePermission(context.Background(), dto.UpdateSharePermissionRequest{
+		CategoryID:       1,
+		OwnerID:          1,
+		SharedWithUserID: 2,
+		Permission:       models.Permission("owner"),
+	})
+
+	if !errors.Is(err, ErrInvalidPermission) {
+		t.Errorf("UpdateSharePermission() error = %v, want %v", err, ErrInvalidPermission)
+	}
+	if updated {
+		t.Error("UpdateSharePermission() persisted an invalid permission")
+	}
+}
+
 func TestCategoryService_GetCategories(t *testing.T) {
 	t.Run("returns user categories", func(t *testing.T) {
 		categoryRepo := &mocks.MockCategoryRepository{
@@ -471,7 +1018,7 @@ func TestCategoryService_GetCategories(t *testing.T) {
 		}
 
 		service := createTestCategoryService(categoryRepo, nil, nil)
-		categories, err := service.GetCategories(context.Background(), 1)
+		categories, err := service.GetCategories(context.Background(), 1, true)
 
 		if err != nil {
 			t.Errorf("GetCategories() error = %v", err)
@@ -489,7 +1036,7 @@ func TestCategoryService_GetCategories(t *testing.T) {
 		}
 
 		service := createTestCategoryService(categoryRepo, nil, nil)
-		categories, err := service.GetCategories(context.Background(), 1)
+		categories, err := service.GetCategories(context.Background(), 1, true)
 
 		if err != nil {
 			t.Errorf("GetCategories() error = %v", err)
@@ -500,6 +1047,90 @@ func TestCategoryService_GetCategories(t *testing.T) {
 	})
 }
 
+func TestCategoryService_GetSharedCategories_LoadsTodosForUser(t *testing.T) {
+	var gotUserID uint
+	var gotIDs []uint
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodosInAccessibleCategoriesFunc: func(ctx context.Context, userID uint, categoryIDs []uint) ([]models.Todo, error) {
+			gotUserID, gotIDs = userID, categoryIDs
+			// A todo from a category that was not asked for must never be attached anywhere
+			return []models.Todo{{ID: 1, CategoryID: 3}, {ID: 2, CategoryID: 9}}, nil
+		},
+	}
+	shareRepo := &mocks.MockCategoryShareRepository{
+		GetSharedCategoriesForUserFunc: func(ctx context.Context, userID uint, permission models.Permission, page, pageSize int) ([]models.SharedCategoryWithOwner, int64, error) {
+			return []models.SharedCategoryWithOwner{{ID: 3, Name: "Team"}, {ID: 4, Name: "Empty"}}, 2, nil
+		},
+	}
+	categoryRepo := &mocks.MockCategoryRepository{}
+	service := NewCategoryService(categoryRepo, shareRepo, &mocks.MockUserRepository{}, todoRepo, mockTx(categoryRepo, shareRepo, todoRepo), PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100}, LimitsConfig{})
+
+	shared, err := service.GetSharedCategories(context.Background(), 2, "", 1, 10, true)
+	if err != nil {
+		t.Fatalf("GetSharedCategories() error = %v", err)
+	}
+
+	if gotUserID != 2 || !reflect.DeepEqual(gotIDs, []uint{3, 4}) {
+		t.Errorf("loader called for user %d with %v, want user 2 with [3 4]", gotUserID, gotIDs)
+	}
+	if todos := shared.Categories[0].Todos; len(todos) != 1 || todos[0].ID != 1 {
+		t.Errorf("Team todos = %+v, want only todo 1", todos)
+	}
+	if todos := shared.Categories[1].Todos; todos == nil || len(todos) != 0 {
+		t.Errorf("Empty todos = %#v, want an empty list", todos)
+	}
+}
+
+func TestCategoryService_GetCategories_WithoutTodos(t *testing.T) {
+	todoQueries := 0
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodosInAccessibleCategoriesFunc: func(ctx context.Context, userID uint, categoryIDs []uint) ([]models.Todo, error) {
+			todoQueries++
+			return []models.Todo{{ID: 1, CategoryID: categoryIDs[0]}}, nil
+		},
+	}
+	categoryRepo := &mocks.MockCategoryRepository{
+		GetCategoriesByOwnerIDFunc: func(ctx context.Context, ownerID uint) ([]models.Category, error) {
+			return []models.Category{{ID: 1, Name: "Work", OwnerID: ownerID}, {ID: 2, Name: "Home", OwnerID: ownerID}}, nil
+		},
+	}
+	shareRepo := &mocks.MockCategoryShareRepository{
+		GetSharedCategoriesForUserFunc: func(ctx context.Context, userID uint, permission models.Permission, page, pageSize int) ([]models.SharedCategoryWithOwner, int64, error) {
+			return []models.SharedCategoryWithOwner{{ID: 3, Name: "Team"}}, 1, nil
+		},
+	}
+	service := NewCategoryService(categoryRepo, shareRepo, &mocks.MockUserRepository{}, todoRepo, mockTx(categoryRepo, shareRepo, todoRepo), PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100}, LimitsConfig{})
+
+	owned, err := service.GetCategories(context.Background(), 1, false)
+	if err != nil {
+		t.Fatalf("GetCategories() error = %v", err)
+	}
+	shared, err := service.GetSharedCategories(context.Background(), 1, "", 1, 10, false)
+	if err != nil {
+		t.Fatalf("GetSharedCategories() error = %v", err)
+	}
+
+	if todoQueries != 0 {
+		t.Errorf("ran %d todo queries, want none when todos are excluded", todoQueries)
+	}
+	if len(owned) != 2 || len(shared.Categories) != 1 {
+		t.Fatalf("got %d owned and %d shared categories, want 2 and 1", len(owned), len(shared.Categories))
+	}
+	for _, category := range owned {
+		if category.Todos != nil {
+			t.Errorf("category %d todos = %v, want none", category.ID, category.Todos)
+		}
+	}
+
+	// Including todos loads them for every category in a single query
+	if _, err := service.GetCategories(context.Background(), 1, true); err != nil {
+		t.Fatalf("GetCategories() error = %v", err)
+	}
+	if todoQueries != 1 {
+		t.Errorf("ran %d todo queries, want 1 when todos are included", todoQueries)
+	}
+}
+
 func TestCategoryService_GetSharesForCategory(t *testing.T) {
 	t.Run("owner can get shares", func(t *testing.T) {
 		categoryRepo := &mocks.MockCategoryRepository{
@@ -509,21 +1140,21 @@ func TestCategoryService_GetSharesForCategory(t *testing.T) {
 		}
 
 		categoryShareRepo := &mocks.MockCategoryShareRepository{
-			GetSharesForCategoryFunc: func(ctx context.Context, categoryID uint) ([]models.CategoryShareWithUser, error) {
+			GetSharesForCategoryFunc: func(ctx context.Context, categoryID uint, sortBy string, page, pageSize int) ([]models.CategoryShareWithUser, int64, error) {
 				return []models.CategoryShareWithUser{
 					{ID: 1, CategoryID: 1, SharedWithUserID: 2, SharedWithUserEmail: "user2@test.com"},
-				}, nil
+				}, 1, nil
 			},
 		}
 
 		service := createTestCategoryService(categoryRepo, categoryShareRepo, nil)
-		shares, err := service.GetSharesForCategory(context.Background(), 1, 1)
+		resp, err := service.GetSharesForCategory(context.Background(), 1, 1, "", 1, 10)
 
 		if err != nil {
-			t.Errorf("GetSharesForCategory() error = %v", err)
+			t.Fatalf("GetSharesForCategory() error = %v", err)
 		}
-		if len(shares) != 1 {
-			t.Errorf("GetSharesForCategory() returned %d shares, want 1", len(shares))
+		if len(resp.Shares) != 1 || resp.Total != 1 || resp.TotalPages != 1 {
+			t.Errorf("GetSharesForCategory() = %d shares, total %d, pages %d, want 1, 1, 1", len(resp.Shares), resp.Total, resp.TotalPages)
 		}
 	})
 
@@ -535,10 +1166,450 @@ func TestCategoryService_GetSharesForCategory(t *testing.T) {
 		}
 
 		service := createTestCategoryService(categoryRepo, nil, nil)
-		_, err := service.GetSharesForCategory(context.Background(), 1, 2) // userID 2 is not owner
+		_, err := service.GetSharesForCategory(context.Background(), 1, 2, "", 1, 10) // userID 2 is not owner
 
 		if err == nil {
 			t.Error("GetSharesForCategory() expected error for non-owner")
 		}
 	})
+
+	t.Run("sort and pagination", func(t *testing.T) {
+		categoryRepo := &mocks.MockCategoryRepository{
+			GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+				return &models.Category{ID: 1, Name: "Work", OwnerID: 1}, nil
+			},
+		}
+
+		var gotSort string
+		var gotPage, gotPageSize int
+		categoryShareRepo := &mocks.MockCategoryShareRepository{
+			GetSharesForCategoryFunc: func(ctx context.Context, categoryID uint, sortBy string, page, pageSize int) ([]models.CategoryShareWithUser, int64, error) {
+				gotSort, gotPage, gotPageSize = sortBy, page, pageSize
+				return []models.CategoryShareWithUser{}, 250, nil
+			},
+		}
+		service := createTestCategoryService(categoryRepo, categoryShareRepo, nil)
+
+		tests := []struct {
+			name           string
+			sortBy         string
+			page           int
+			pageSize       int
+			wantSort       string
+			wantPage       int
+			wantPageSize   int
+			wantTotalPages int64
+		}{
+			{name: "defaults", wantSort: ShareSortCreatedAt, wantPage: 1, wantPageSize: 10, wantTotalPages: 25},
+			{name: "email sort", sortBy: ShareSortEmail, page: 3, pageSize: 20, wantSort: ShareSortEmail, wantPage: 3, wantPageSize: 20, wantTotalPages: 13},
+			{name: "page size capped", sortBy: ShareSortCreatedAt, page: 2, pageSize: 500, wantSort: ShareSortCreatedAt, wantPage: 2, wantPageSize: 100, wantTotalPages: 3},
+		}
+
+		for _, tt := range tests {
+			resp, err := service.GetSharesForCategory(context.Background(), 1, 1, tt.sortBy, tt.page, tt.pageSize)
+			if err != nil {
+				t.Fatalf("%s: GetSharesForCategory() error = %v", tt.name, err)
+			}
+			if gotSort != tt.wantSort || gotPage != tt.wantPage || gotPageSize != tt.wantPageSize {
+				t.Errorf("%s: repository called with (%q, %d, %d), want (%q, %d, %d)",
+					tt.name, gotSort, gotPage, gotPageSize, tt.wantSort, tt.wantPage, tt.wantPageSize)
+			}
+			if resp.Total != 250 || resp.TotalPages != tt.wantTotalPages {
+				t.Errorf("%s: total = %d, total_pages = %d, want 250, %d", tt.name, resp.Total, resp.TotalPages, tt.wantTotalPages)
+			}
+		}
+	})
+}
+
+func TestCategoryService_GetSharedCategories(t *testing.T) {
+	// The mock applies the permission filter and paging the way the query does
+	shared := []models.SharedCategoryWithOwner{
+		{ID: 1, Name: "Alpha", OwnerID: 2, Permission: models.PermissionRead},
+		{ID: 2, Name: "Beta", OwnerID: 3, Permission: models.PermissionWrite},
+		{ID: 3, Name: "Gamma", OwnerID: 2, Permission: models.PermissionRead},
+	}
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		GetSharedCategoriesForUserFunc: func(ctx context.Context, userID uint, permission models.Permission, page, pageSize int) ([]models.SharedCategoryWithOwner, int64, error) {
+			var matching []models.SharedCategoryWithOwner
+			for _, category := range shared {
+				if permission == "" || category.Permission == permission {
+					matching = append(matching, category)
+				}
+			}
+			start := min((page-1)*pageSize, len(matching))
+			end := min(start+pageSize, len(matching))
+			return append([]models.SharedCategoryWithOwner{}, matching[start:end]...), int64(len(matching)), nil
+		},
+	}
+
+	tests := []struct {
+		name           string
+		permission     models.Permission
+		page           int
+		pageSize       int
+		wantIDs        []uint
+		wantTotal      int64
+		wantTotalPages int64
+	}{
+		{
+			name:           "no filter, first page",
+			page:           1,
+			pageSize:       2,
+			wantIDs:        []uint{1, 2},
+			wantTotal:      3,
+			wantTotalPages: 2,
+		},
+		{
+			name:           "no filter, second page",
+			page:           2,
+			pageSize:       2,
+			wantIDs:        []uint{3},
+			wantTotal:      3,
+			wantTotalPages: 2,
+		},
+		{
+			name:           "write filter",
+			permission:     models.PermissionWrite,
+			page:           1,
+			pageSize:       2,
+			wantIDs:        []uint{2},
+			wantTotal:      1,
+			wantTotalPages: 1,
+		},
+		{
+			name:           "defaults applied",
+			permission:     models.PermissionRead,
+			wantIDs:        []uint{1, 3},
+			wantTotal:      2,
+			wantTotalPages: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := createTestCategoryService(nil, categoryShareRepo, nil)
+			response, err := service.GetSharedCategories(context.Background(), 1, tt.permission, tt.page, tt.pageSize, true)
+
+			if err != nil {
+				t.Fatalf("GetSharedCategories() error = %v", err)
+			}
+			if response.Total != tt.wantTotal || response.TotalPages != tt.wantTotalPages {
+				t.Errorf("GetSharedCategories() total = %d, total pages = %d, want %d and %d", response.Total, response.TotalPages, tt.wantTotal, tt.wantTotalPages)
+			}
+			if len(response.Categories) != len(tt.wantIDs) {
+				t.Fatalf("GetSharedCategories() returned %d categories, want %d", len(response.Categories), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if response.Categories[i].ID != id {
+					t.Errorf("GetSharedCategories()[%d].ID = %v, want %v", i, response.Categories[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestCategoryService_GetUserPermissionForCategory(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         uint
+		getErr         error
+		permission     string
+		wantPermission string
+		wantErr        error
+	}{
+		{
+			name:           "owner",
+			userID:         1,
+			wantPermission: "owner",
+		},
+		{
+			name:           "shared with write",
+			userID:         2,
+			permission:     "write",
+			wantPermission: "write",
+		},
+		{
+			name:           "no access",
+			userID:         3,
+			permission:     "none",
+			wantPermission: "none",
+		},
+		{
+			name:    "category not found",
+			userID:  1,
+			getErr:  sql.ErrNoRows,
+			wantErr: ErrCategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryRepo := &mocks.MockCategoryRepository{
+				GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+					if tt.getErr != nil {
+						return nil, tt.getErr
+					}
+					return &models.Category{ID: id, Name: "Work", OwnerID: 1}, nil
+				},
+			}
+			categoryShareRepo := &mocks.MockCategoryShareRepository{
+				GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+					return tt.permission, nil
+				},
+			}
+
+			service := createTestCategoryService(categoryRepo, categoryShareRepo, nil)
+			permission, err := service.GetUserPermissionForCategory(context.Background(), tt.userID, 1)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetUserPermissionForCategory() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+			if permission != tt.wantPermission {
+				t.Errorf("GetUserPermissionForCategory() = %v, want %v", permission, tt.wantPermission)
+			}
+		})
+	}
+}
+
+func TestCategoryService_MoveTodos(t *testing.T) {
+	// Category 1 is owned by user 1; category 2 is owned by user 2 and shared with user 1
+	categoryRepo := &mocks.MockCategoryRepository{
+		GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+			switch id {
+			case 1:
+				return &models.Category{ID: 1, Name: "Inbox", OwnerID: 1}, nil
+			case 2:
+				return &models.Category{ID: 2, Name: "Team", OwnerID: 2}, nil
+			}
+			return nil, sql.ErrNoRows
+		},
+	}
+
+	tests := []struct {
+		name            string
+		toCategoryID    uint
+		sharePermission string
+		wantMoved       int64
+		wantErr         error
+	}{
+		{
+			name:            "move into shared write category",
+			toCategoryID:    2,
+			sharePermission: "write",
+			wantMoved:       3,
+		},
+		{
+			name:            "read share cannot receive todos",
+			toCategoryID:    2,
+			sharePermission: "read",
+			wantErr:         ErrCategoryForbidden,
+		},
+		{
+			name:         "same category",
+			toCategoryID: 1,
+			wantErr:      ErrSameCategory,
+		},
+		{
+			name:         "target not found",
+			toCategoryID: 99,
+			wantErr:      ErrCategoryNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryShareRepo := &mocks.MockCategoryShareRepository{
+				GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+					return tt.sharePermission, nil
+				},
+			}
+			todoRepo := &mocks.MockTodoRepository{
+				GetTodoIDsInCategoryFunc: func(ctx context.Context, categoryID uint, completed *bool) ([]uint, error) {
+					if categoryID != 1 || completed != nil {
+						t.Errorf("GetTodoIDsInCategory(%d, %v), want (1, nil)", categoryID, completed)
+					}
+					return []uint{10, 11, 12}, nil
+				},
+				MoveTodosFunc: func(ctx context.Context, ids []uint, toCategoryID, toOwnerID uint) (int64, error) {
+					if toCategoryID != 2 || toOwnerID != 2 {
+						t.Errorf("MoveTodos(%v, %d, %d), want target (2, 2)", ids, toCategoryID, toOwnerID)
+					}
+					return int64(len(ids)), nil
+				},
+			}
+			var events []models.TodoEvent
+			eventRepo := &mocks.MockTodoEventRepository{
+				CreateTodoEventFunc: func(ctx context.Context, event *models.TodoEvent) error {
+					events = append(events, *event)
+					return nil
+				},
+			}
+			txManager := &mocks.MockTxManager{Repos: repository.RepoSet{Todos: todoRepo, TodoEvents: eventRepo}}
+
+			service := NewCategoryService(categoryRepo, categoryShareRepo, &mocks.MockUserRepository{}, todoRepo, txManager, PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100}, LimitsConfig{})
+			moved, err := service.MoveTodos(context.Background(), 1, 1, tt.toCategoryID)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MoveTodos() error = %v, want %v", err, tt.wantErr)
+			}
+			if moved != tt.wantMoved {
+				t.Errorf("MoveTodos() moved = %d, want %d", moved, tt.wantMoved)
+			}
+			if int64(len(events)) != tt.wantMoved {
+				t.Fatalf("MoveTodos() recorded %d history events, want %d", len(events), tt.wantMoved)
+			}
+			for _, event := range events {
+				if event.ActorID != 1 || event.Action != models.TodoEventUpdate || !slices.Equal(event.ChangedFields, []string{"category_id"}) {
+					t.Errorf("MoveTodos() recorded %+v, want a category_id update by user 1", event)
+				}
+			}
+		})
+	}
+}
+
+func TestCategoryService_MoveTodos_TodoLimit(t *testing.T) {
+	categoryRepo := &mocks.MockCategoryRepository{
+		GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+			return &models.Category{ID: id, Name: "Category", OwnerID: 1}, nil
+		},
+	}
+	// Source category 1 holds 3 todos and target category 2 holds 8
+	counts := map[uint]int64{1: 3, 2: 8}
+
+	tests := []struct {
+		name     string
+		maxTodos int
+		wantErr  error
+	}{
+		{name: "everything fits", maxTodos: 11},
+		{name: "would exceed limit", maxTodos: 10, wantErr: ErrTodoLimitReached},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moved := false
+			todoRepo := &mocks.MockTodoRepository{
+				CountTodosInCategoryFunc: func(ctx context.Context, categoryID uint) (int64, error) {
+					return counts[categoryID], nil
+				},
+				GetTodoIDsInCategoryFunc: func(ctx context.Context, categoryID uint, completed *bool) ([]uint, error) {
+					return []uint{10, 11, 12}, nil
+				},
+				MoveTodosFunc: func(ctx context.Context, ids []uint, toCategoryID, toOwnerID uint) (int64, error) {
+					moved = true
+					return int64(len(ids)), nil
+				},
+			}
+
+			service := NewCategoryService(categoryRepo, &mocks.MockCategoryShareRepository{}, &mocks.MockUserRepository{}, todoRepo, mockTx(categoryRepo, &mocks.MockCategoryShareRepository{}, todoRepo),
+				PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100}, LimitsConfig{MaxTodosPerCategory: tt.maxTodos})
+			_, err := service.MoveTodos(context.Background(), 1, 1, 2)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MoveTodos() error = %v, want %v", err, tt.wantErr)
+			}
+			if moved != (tt.wantErr == nil) {
+				t.Errorf("MoveTodos() moved = %v, want %v", moved, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestCategoryService_BulkUpdateSharePermissions(t *testing.T) {
+	categoryRepo := &mocks.MockCategoryRepository{
+		GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+			return &models.Category{ID: id, Name: "Work", OwnerID: 1}, nil
+		},
+	}
+	shares := map[uint]*models.CategoryShare{
+		2: {ID: 20, CategoryID: 1, SharedWithUserID: 2, Permission: models.PermissionRead},
+		3: {ID: 30, CategoryID: 1, SharedWithUserID: 3, Permission: models.PermissionWrite},
+	}
+	updated := map[uint]models.Permission{}
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		GetCategoryShareByCategoryAndUserFunc: func(ctx context.Context, categoryID, userID uint) (*models.CategoryShare, error) {
+			if share, ok := shares[userID]; ok {
+				return share, nil
+			}
+			return nil, sql.ErrNoRows
+		},
+		UpdateCategorySharePermissionFunc: func(ctx context.Context, id uint, permission models.Permission) error {
+			updated[id] = permission
+			return nil
+		},
+	}
+
+	service := createTestCategoryService(categoryRepo, categoryShareRepo, nil)
+	response, err := service.BulkUpdateSharePermissions(context.Background(), dto.BulkUpdateSharePermissionsRequest{
+		CategoryID: 1,
+		OwnerID:    1,
+		Updates: []dto.SharePermissionUpdate{
+			{UserID: 2, Permission: models.PermissionWrite},
+			{UserID: 3, Permission: models.PermissionRead},
+			{UserID: 4, Permission: models.PermissionWrite},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BulkUpdateSharePermissions() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(response.Updated, []uint{2, 3}) {
+		t.Errorf("BulkUpdateSharePermissions() Updated = %v, want [2 3]", response.Updated)
+	}
+	if !reflect.DeepEqual(response.NotFound, []uint{4}) {
+		t.Errorf("BulkUpdateSharePermissions() NotFound = %v, want [4]", response.NotFound)
+	}
+	want := map[uint]models.Permission{20: models.PermissionWrite, 30: models.PermissionRead}
+	if !reflect.DeepEqual(updated, want) {
+		t.Errorf("BulkUpdateSharePermissions() persisted %v, want %v", updated, want)
+	}
+}
+
+func TestCategoryService_CleanupEmptyCategories(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		wantIDs     []uint
+		wantDeletes []uint
+	}{
+		{name: "dry run lists without deleting", dryRun: true, wantIDs: []uint{2, 3}},
+		// Category 3 gained a todo after it was listed, so the guarded delete keeps it
+		{name: "deletes and skips categories no longer empty", dryRun: false, wantIDs: []uint{2}, wantDeletes: []uint{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deletes []uint
+			categoryRepo := &mocks.MockCategoryRepository{
+				GetEmptyCategoriesByOwnerIDFunc: func(ctx context.Context, ownerID uint) ([]models.Category, error) {
+					if ownerID != 1 {
+						t.Errorf("GetEmptyCategoriesByOwnerID(%d), want owner 1", ownerID)
+					}
+					return []models.Category{{ID: 2, Name: "Old", OwnerID: 1}, {ID: 3, Name: "Stale", OwnerID: 1}}, nil
+				},
+				DeleteEmptyCategoryFunc: func(ctx context.Context, id uint) (bool, error) {
+					deletes = append(deletes, id)
+					return id != 3, nil
+				},
+			}
+
+			service := createTestCategoryService(categoryRepo, nil, nil)
+			removed, err := service.CleanupEmptyCategories(context.Background(), 1, tt.dryRun)
+			if err != nil {
+				t.Fatalf("CleanupEmptyCategories() error = %v", err)
+			}
+
+			var gotIDs []uint
+			for _, category := range removed {
+				gotIDs = append(gotIDs, category.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("CleanupEmptyCategories() ids = %v, want %v", gotIDs, tt.wantIDs)
+			}
+			if !reflect.DeepEqual(deletes, tt.wantDeletes) {
+				t.Errorf("DeleteEmptyCategory calls = %v, want %v", deletes, tt.wantDeletes)
+			}
+		})
+	}
 }
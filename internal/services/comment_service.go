@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/models"
+	"todo-app/internal/repository"
+)
+
+// Common errors for comment operations
+var (
+	ErrCommentNotFound     = errors.New("comment not found")
+	ErrCommentBodyRequired = errors.New("comment body is required")
+	ErrCommentDeleteDenied = errors.New("only the comment's author or the category owner can delete it")
+)
+
+// Ensure CommentServiceImpl implements CommentService
+var _ CommentService = (*CommentServiceImpl)(nil)
+
+// CommentServiceImpl handles the business logic of comments on todos
+type CommentServiceImpl struct {
+	repo              repository.CommentRepository
+	todoRepo          repository.TodoRepository
+	categoryRepo      repository.CategoryRepository
+	categoryShareRepo repository.CategoryShareRepository
+	pagination        PaginationConfig
+}
+
+// NewCommentService creates a new CommentService with the provided repositories
+func NewCommentService(
+	repo repository.CommentRepository,
+	todoRepo repository.TodoRepository,
+	categoryRepo repository.CategoryRepository,
+	categoryShareRepo repository.CategoryShareRepository,
+	pagination PaginationConfig,
+) CommentService {
+	return &CommentServiceImpl{
+		repo:              repo,
+		todoRepo:          todoRepo,
+		categoryRepo:      categoryRepo,
+		categoryShareRepo: categoryShareRepo,
+		pagination:        pagination,
+	}
+}
+
+// readableTodo fetches a live todo the user can at least read
+func (s *CommentServiceImpl) readableTodo(ctx context.Context, userID, todoID uint) (*models.Todo, error) {
+	todo, err := s.todoRepo.GetTodoByID(ctx, todoID)
+	if err != nil {
+		return nil, ErrTodoNotFound
+	}
+	if err := checkCategoryAccess(ctx, s.categoryRepo, s.categoryShareRepo, userID, todo.CategoryID, false); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// CreateComment adds a comment to a todo for any user who can read it
+func (s *CommentServiceImpl) CreateComment(ctx context.Context, req dto.CreateCommentRequest) (*models.Comment, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrCommentBodyRequired
+	}
+
+	if _, err := s.readableTodo(ctx, req.UserID, req.TodoID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		TodoID:   req.TodoID,
+		AuthorID: req.UserID,
+		Body:     body,
+	}
+	if err := s.repo.CreateComment(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+	return comment, nil
+}
+
+// GetComments retrieves a todo's comments, oldest first, for a user who can read the todo
+func (s *CommentServiceImpl) GetComments(ctx context.Context, todoID, userID uint, page, pageSize int) (*dto.CommentListResponse, error) {
+	if _, err := s.readableTodo(ctx, userID, todoID); err != nil {
+		return nil, err
+	}
+
+	// Normalize pagination parameters using config values
+	page = max(page, 1)
+	if pageSize < 1 {
+		pageSize = s.pagination.DefaultPageSize
+	}
+	pageSize = min(pageSize, s.pagination.MaxPageSize)
+
+	comments, total, err := s.repo.GetCommentsByTodoID(ctx, todoID, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comments: %w", err)
+	}
+
+	totalPages := (total + int64(pageSize) - 1) / int64(pageSize)
+
+	return &dto.CommentListResponse{
+		Comments:   comments,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// DeleteComment removes a comment from a todo. Authors may delete their own comments while they can still
+// read the todo; anyone else's comment can only be deleted by the owner of the todo's category.
+func (s *CommentServiceImpl) DeleteComment(ctx context.Context, req dto.DeleteCommentRequest) error {
+	todo, err := s.readableTodo(ctx, req.UserID, req.TodoID)
+	if err != nil {
+		return err
+	}
+
+	comment, err := s.repo.GetCommentByID(ctx, req.CommentID)
+	if err != nil || comment.TodoID != todo.ID {
+		return ErrCommentNotFound
+	}
+
+	if comment.AuthorID != req.UserID {
+		category, err := s.categoryRepo.GetCategoryByID(ctx, todo.CategoryID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch category: %w", err)
+		}
+		if category.OwnerID != req.UserID {
+			return ErrCommentDeleteDenied
+		}
+	}
+
+	deleted, err := s.repo.DeleteComment(ctx, comment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	if !deleted {
+		return ErrCommentNotFound
+	}
+	return nil
+}
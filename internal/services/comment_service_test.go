@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/models"
+	"todo-app/internal/repository/mocks"
+)
+
+// newTestCommentService serves todo 1 in category 1, owned by user 1 and shared with user 2 (write) and
+// user 3 (read). User 4 has no access.
+func newTestCommentService(commentRepo *mocks.MockCommentRepository) CommentService {
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+			if id != 1 {
+				return nil, sql.ErrNoRows
+			}
+			return &models.Todo{ID: 1, CategoryID: 1, UserID: 1}, nil
+		},
+	}
+	shareRepo := &mocks.MockCategoryShareRepository{
+		GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+			switch userID {
+			case 2:
+				return "write", nil
+			case 3:
+				return "read", nil
+			}
+			return "", sql.ErrNoRows
+		},
+	}
+	return NewCommentService(commentRepo, todoRepo, defaultCategoryMock(1), shareRepo, PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100})
+}
+
+func TestCommentService_CreateComment(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         dto.CreateCommentRequest
+		expectedErr error
+	}{
+		{name: "owner", req: dto.CreateCommentRequest{TodoID: 1, UserID: 1, Body: "Done by Friday?"}},
+		{name: "read sharer", req: dto.CreateCommentRequest{TodoID: 1, UserID: 3, Body: "  Sounds good  "}},
+		{name: "no access", req: dto.CreateCommentRequest{TodoID: 1, UserID: 4, Body: "Hello"}, expectedErr: ErrForbidden},
+		{name: "missing todo", req: dto.CreateCommentRequest{TodoID: 9, UserID: 1, Body: "Hello"}, expectedErr: ErrTodoNotFound},
+		{name: "blank body", req: dto.CreateCommentRequest{TodoID: 1, UserID: 1, Body: "   "}, expectedErr: ErrCommentBodyRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *models.Comment
+			service := newTestCommentService(&mocks.MockCommentRepository{
+				CreateCommentFunc: func(ctx context.Context, comment *models.Comment) error {
+					comment.ID = 10
+					created = comment
+					return nil
+				},
+			})
+
+			comment, err := service.CreateComment(context.Background(), tt.req)
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("CreateComment() error = %v, want %v", err, tt.expectedErr)
+				}
+				if created != nil {
+					t.Error("CreateComment() stored a comment it should have rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateComment() unexpected error = %v", err)
+			}
+			if comment.AuthorID != tt.req.UserID || comment.TodoID != 1 || comment.Body == "" || comment.Body[0] == ' ' {
+				t.Errorf("CreateComment() = %+v, want a trimmed comment by user %d on todo 1", comment, tt.req.UserID)
+			}
+		})
+	}
+}
+
+func TestCommentService_GetComments(t *testing.T) {
+	var gotPageSize int
+	service := newTestCommentService(&mocks.MockCommentRepository{
+		GetCommentsByTodoIDFunc: func(ctx context.Context, todoID uint, page, pageSize int) ([]models.Comment, int64, error) {
+			gotPageSize = pageSize
+			return []models.Comment{{ID: 10, TodoID: todoID, AuthorID: 2, Body: "First"}}, 101, nil
+		},
+	})
+
+	response, err := service.GetComments(context.Background(), 1, 3, 1, 500)
+	if err != nil {
+		t.Fatalf("GetComments() read sharer error = %v", err)
+	}
+	if gotPageSize != 100 || response.PageSize != 100 || response.TotalPages != 2 || len(response.Comments) != 1 {
+		t.Errorf("GetComments() = %+v with repository page size %d, want one comment, page size 100 and 2 pages", response, gotPageSize)
+	}
+
+	if _, err := service.GetComments(context.Background(), 1, 4, 1, 10); !errors.Is(err, ErrForbidden) {
+		t.Errorf("GetComments() without access error = %v, want %v", err, ErrForbidden)
+	}
+	if _, err := service.GetComments(context.Background(), 9, 1, 1, 10); !errors.Is(err, ErrTodoNotFound) {
+		t.Errorf("GetComments() missing todo error = %v, want %v", err, ErrTodoNotFound)
+	}
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+	// Comment 10 is user 3's on todo 1, comment 11 belongs to another todo
+	comments := map[uint]*models.Comment{
+		10: {ID: 10, TodoID: 1, AuthorID: 3, Body: "Mine"},
+		11: {ID: 11, TodoID: 2, AuthorID: 3, Body: "Elsewhere"},
+	}
+
+	tests := []struct {
+		name        string
+		userID      uint
+		commentID   uint
+		expectedErr error
+	}{
+		{name: "author with read access", userID: 3, commentID: 10},
+		{name: "category owner deletes another user's comment", userID: 1, commentID: 10},
+		{name: "write sharer cannot delete another user's comment", userID: 2, commentID: 10, expectedErr: ErrCommentDeleteDenied},
+		{name: "no access", userID: 4, commentID: 10, expectedErr: ErrForbidden},
+		{name: "comment on another todo", userID: 1, commentID: 11, expectedErr: ErrCommentNotFound},
+		{name: "missing comment", userID: 1, commentID: 99, expectedErr: ErrCommentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			service := newTestCommentService(&mocks.MockCommentRepository{
+				GetCommentByIDFunc: func(ctx context.Context, id uint) (*models.Comment, error) {
+					comment, ok := comments[id]
+					if !ok {
+						return nil, sql.ErrNoRows
+					}
+					found := *comment
+					return &found, nil
+				},
+				DeleteCommentFunc: func(ctx context.Context, id uint) (bool, error) {
+					deleted = true
+					return true, nil
+				},
+			})
+
+			err := service.DeleteComment(context.Background(), dto.DeleteCommentRequest{TodoID: 1, CommentID: tt.commentID, UserID: tt.userID})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("DeleteComment() error = %v, want %v", err, tt.expectedErr)
+			}
+			if deleted != (tt.expectedErr == nil) {
+				t.Errorf("DeleteComment() deleted = %v, want %v", deleted, tt.expectedErr == nil)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"todo-app/internal/dto"
 	"todo-app/internal/models"
@@ -13,22 +14,78 @@ type TodoService interface {
 	CreateTodo(ctx context.Context, req dto.CreateTodoRequest) (*models.Todo, error)
 
 	// GetTodos retrieves todos for a user with pagination
-	GetTodos(ctx context.Context, userID uint, page, pageSize int) (*dto.TodoListResponse, error)
+	GetTodos(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error)
 
-	// GetTodosByCategoryID retrieves todos filtered by category ID with pagination
-	GetTodosByCategoryID(ctx context.Context, categoryID uint, page, pageSize int) (*dto.TodoListResponse, error)
+	// CountTodos counts the user's todos, optionally only completed or only open ones
+	CountTodos(ctx context.Context, userID uint, completed *bool) (int64, error)
 
-	// GetTodosGroupedByCategory retrieves all accessible todos grouped by category
-	GetTodosGroupedByCategory(ctx context.Context, userID uint) (*dto.TodosGroupedByCategoryResponse, error)
+	// GetTodosByCategoryID retrieves a category's todos, optionally filtered by creator, with permission verification
+	GetTodosByCategoryID(ctx context.Context, req dto.ListCategoryTodosRequest, page, pageSize int) (*dto.TodoListResponse, error)
+
+	// GetCategoryTodosAfter retrieves a keyset page of a category's todos that follows cursor (empty for the first page)
+	GetCategoryTodosAfter(ctx context.Context, req dto.ListCategoryTodosRequest, cursor string, pageSize int) (*dto.TodoCursorPage, error)
+
+	// CountCategoryTodos counts a category's todos as total, completed and open, with permission verification
+	CountCategoryTodos(ctx context.Context, userID, categoryID uint) (*models.TodoStatusCounts, error)
+
+	// GetTodosInCategories retrieves the todos in any of the given categories the user can read, with pagination
+	GetTodosInCategories(ctx context.Context, userID uint, categoryIDs []uint, page, pageSize int) (*dto.TodoListResponse, error)
+
+	// GetTodosGroupedByCategory retrieves accessible todos grouped by category, limited to owned, shared or all categories
+	// perCategory > 0 keeps only the newest todos of each category and sets a cursor for the rest
+	GetTodosGroupedByCategory(ctx context.Context, userID uint, scope string, perCategory int) (*dto.TodosGroupedByCategoryResponse, error)
 
 	// GetTodoByID retrieves a single todo with ownership/permission verification
 	GetTodoByID(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error)
 
+	// GetTodosByIDs retrieves the readable todos among ids in request order, reporting missing and forbidden IDs
+	GetTodosByIDs(ctx context.Context, userID uint, ids []uint) (*dto.BatchGetTodosResponse, error)
+
+	// ExpandTodos inlines the requested related objects into todos the caller already fetched
+	ExpandTodos(ctx context.Context, todos []models.Todo, expand dto.TodoExpand) ([]dto.ExpandedTodo, error)
+
 	// UpdateTodo handles todo update with ownership/permission verification
-	UpdateTodo(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error)
+	// changed is false when every provided field already had its value; nothing is written then
+	UpdateTodo(ctx context.Context, req dto.UpdateTodoRequest) (todo *models.Todo, changed bool, err error)
+
+	// TouchTodo sets a todo's updated_at to now without changing anything else (requires write permission)
+	TouchTodo(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error)
+
+	// DeleteTodo handles todo soft deletion with ownership/permission verification and returns an undo token
+	DeleteTodo(ctx context.Context, req dto.DeleteTodoRequest) (*dto.DeleteTodoResponse, error)
+
+	// UndoDelete restores a todo deleted moments ago using the token DeleteTodo returned
+	UndoDelete(ctx context.Context, req dto.UndoDeleteRequest) (*models.Todo, error)
+
+	// RestoreAllTodos restores the user's trashed todos, or every trashed todo in one category (requires write permission)
+	RestoreAllTodos(ctx context.Context, userID, categoryID uint) (int64, error)
+
+	// BulkMoveTodos moves the todos of one category that match a filter into another (requires write permission on both)
+	BulkMoveTodos(ctx context.Context, req dto.BulkMoveTodosRequest) (int64, error)
 
-	// DeleteTodo handles todo soft deletion with ownership/permission verification
-	DeleteTodo(ctx context.Context, req dto.DeleteTodoRequest) error
+	// GetTodoPermissions reports whether the user can read, write and delete a todo
+	GetTodoPermissions(ctx context.Context, req dto.GetTodoRequest) (*dto.TodoPermissions, error)
+
+	// GetTodoHistory retrieves a todo's change history (newest first) with permission verification
+	GetTodoHistory(ctx context.Context, req dto.GetTodoRequest, page, pageSize int) (*dto.TodoHistoryResponse, error)
+
+	// CleanupCompletedTodos soft deletes the user's todos completed before the cutoff (or counts them on dry run)
+	CleanupCompletedTodos(ctx context.Context, req dto.CleanupTodosRequest) (int64, error)
+
+	// GetRecentTodos lists up to limit of the user's accessible todos, most recently updated first
+	GetRecentTodos(ctx context.Context, userID uint, limit int) ([]models.Todo, error)
+
+	// GetTodoChanges lists the IDs of the user's accessible todos changed at or after since, with tombstones for deleted ones
+	GetTodoChanges(ctx context.Context, userID uint, since time.Time) ([]models.TodoChange, error)
+
+	// SyncTodos returns the user's accessible todos created, updated and deleted since a cursor, plus the next cursor
+	SyncTodos(ctx context.Context, userID uint, cursor string) (*dto.TodoSyncResponse, error)
+
+	// GetCompletionReport counts the user's completed todos per day over an inclusive date range, zero-filled
+	GetCompletionReport(ctx context.Context, req dto.CompletionReportRequest) (*dto.CompletionReport, error)
+
+	// CompleteAllInCategory sets the completed state of every todo in a category (requires write permission)
+	CompleteAllInCategory(ctx context.Context, userID, categoryID uint, completed bool) (int64, error)
 }
 
 // AuthService defines the contract for auth business logic
@@ -41,6 +98,36 @@ type AuthService interface {
 
 	// GetByID retrieves a user by ID (for internal use)
 	GetByID(ctx context.Context, id uint) (*models.User, error)
+
+	// UpdateProfile updates the user's profile settings, such as their timezone
+	UpdateProfile(ctx context.Context, req dto.UpdateProfileRequest) (*models.User, error)
+}
+
+// APITokenService defines the contract for personal access token business logic
+type APITokenService interface {
+	// CreateToken creates a named, optionally scoped token and returns its secret once
+	CreateToken(ctx context.Context, req dto.CreateAPITokenRequest) (*dto.CreateAPITokenResponse, error)
+
+	// ListTokens retrieves all of a user's tokens, newest first
+	ListTokens(ctx context.Context, userID uint) ([]models.APIToken, error)
+
+	// RevokeToken revokes one of the user's active tokens
+	RevokeToken(ctx context.Context, userID, tokenID uint) error
+
+	// AuthenticateToken resolves a bearer value to its active (non-revoked) token
+	AuthenticateToken(ctx context.Context, secret string) (*models.APIToken, error)
+}
+
+// CommentService defines the contract for the business logic of comments on todos
+type CommentService interface {
+	// CreateComment adds a comment to a todo the user can read
+	CreateComment(ctx context.Context, req dto.CreateCommentRequest) (*models.Comment, error)
+
+	// GetComments retrieves a todo's comments, oldest first, with pagination (requires read permission)
+	GetComments(ctx context.Context, todoID, userID uint, page, pageSize int) (*dto.CommentListResponse, error)
+
+	// DeleteComment removes a comment; only its author or the category owner may delete it
+	DeleteComment(ctx context.Context, req dto.DeleteCommentRequest) error
 }
 
 // CategoryService defines the contract for category business logic
@@ -48,8 +135,8 @@ type CategoryService interface {
 	// CreateCategory creates a new category for a user
 	CreateCategory(ctx context.Context, req dto.CreateCategoryRequest) (*models.Category, error)
 
-	// GetCategories retrieves all categories owned by a user
-	GetCategories(ctx context.Context, userID uint) ([]models.Category, error)
+	// GetCategories retrieves all categories owned by a user, with their todos when includeTodos is set
+	GetCategories(ctx context.Context, userID uint, includeTodos bool) ([]models.Category, error)
 
 	// GetCategoryByID retrieves a category by ID with ownership verification
 	GetCategoryByID(ctx context.Context, categoryID, userID uint) (*models.Category, error)
@@ -60,21 +147,62 @@ type CategoryService interface {
 	// DeleteCategory deletes a category with ownership verification
 	DeleteCategory(ctx context.Context, categoryID, userID uint) error
 
-	// ShareCategory shares a category with another user
-	ShareCategory(ctx context.Context, req dto.ShareCategoryRequest) (*models.CategoryShare, error)
+	// ShareCategory shares a category with another user, flagging when they own a category with the same name
+	ShareCategory(ctx context.Context, req dto.ShareCategoryRequest) (*dto.ShareCategoryResponse, error)
+
+	// UpsertShare creates a share or updates the permission of an existing one, reporting whether it was created
+	UpsertShare(ctx context.Context, req dto.ShareCategoryRequest) (*models.CategoryShare, bool, error)
+
+	// PreviewShare resolves the user a share would go to without creating it
+	PreviewShare(ctx context.Context, req dto.SharePreviewRequest) (*dto.SharePreviewResponse, error)
 
 	// UnshareCategory removes sharing of a category with a user
 	UnshareCategory(ctx context.Context, req dto.UnshareCategoryRequest) error
 
+	// UnshareAll removes every share of a category (owner only) and returns how many were removed
+	UnshareAll(ctx context.Context, categoryID, ownerID uint) (int64, error)
+
 	// UpdateSharePermission changes the permission of a shared category
 	UpdateSharePermission(ctx context.Context, req dto.UpdateSharePermissionRequest) error
 
-	// GetSharesForCategory gets all shares for a category (owner only)
-	GetSharesForCategory(ctx context.Context, categoryID, userID uint) ([]models.CategoryShareWithUser, error)
+	// BulkUpdateSharePermissions changes the permission of several shares of a category in one transaction
+	BulkUpdateSharePermissions(ctx context.Context, req dto.BulkUpdateSharePermissionsRequest) (*dto.BulkUpdateSharePermissionsResponse, error)
 
-	// GetSharedCategories gets all categories shared with a user
-	GetSharedCategories(ctx context.Context, userID uint) ([]models.SharedCategoryWithOwner, error)
+	// GetSharesForCategory gets a page of shares for a category (owner only), newest first or by email
+	GetSharesForCategory(ctx context.Context, categoryID, userID uint, sortBy string, page, pageSize int) (*dto.ShareListResponse, error)
+
+	// GetSharedCategories gets a page of the categories shared with a user, optionally filtered by permission,
+	// with their todos when includeTodos is set
+	GetSharedCategories(ctx context.Context, userID uint, permission models.Permission, page, pageSize int, includeTodos bool) (*dto.SharedCategoryListResponse, error)
 
 	// GetUserPermissionForCategory checks what permission a user has for a category
 	GetUserPermissionForCategory(ctx context.Context, userID, categoryID uint) (string, error)
+
+	// GetWritableCategories lists the owned and write-shared categories a user can add todos to
+	GetWritableCategories(ctx context.Context, userID uint) ([]models.WritableCategory, error)
+
+	// GetCategoriesSharedByMe lists the user's own categories that are shared, with their recipients
+	GetCategoriesSharedByMe(ctx context.Context, userID uint) ([]models.CategoryWithShares, error)
+
+	// GetGrantedMembers lists everyone with access to the user's own categories, once each, with their grants
+	GetGrantedMembers(ctx context.Context, userID uint) ([]models.GrantedMember, error)
+
+	// MoveTodos moves all todos from one category to another (write access required on both)
+	MoveTodos(ctx context.Context, userID, fromCategoryID, toCategoryID uint) (int64, error)
+
+	// CleanupEmptyCategories removes the user's categories with no live todos and no shares, returning them;
+	// with dryRun set nothing is removed
+	CleanupEmptyCategories(ctx context.Context, userID uint, dryRun bool) ([]models.Category, error)
+}
+
+// UserService defines the contract for administering users
+type UserService interface {
+	// ListUsers retrieves a page of all users, oldest account first (for admins)
+	ListUsers(ctx context.Context, page, pageSize int) (*dto.UserListResponse, error)
+
+	// SeedAdmin makes the user with the given email an admin, reporting whether anyone was promoted
+	SeedAdmin(ctx context.Context, email string) (bool, error)
+
+	// NormalizeEmails lowercases and trims stored emails so lookups match the normalized form, returning how many changed
+	NormalizeEmails(ctx context.Context) (int64, error)
 }
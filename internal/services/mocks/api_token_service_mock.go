@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"context"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/models"
+	"todo-app/internal/services"
+)
+
+// Ensure MockAPITokenService implements APITokenService
+var _ services.APITokenService = (*MockAPITokenService)(nil)
+
+// MockAPITokenService is a mock implementation of APITokenService for testing
+type MockAPITokenService struct {
+	CreateTokenFunc       func(ctx context.Context, req dto.CreateAPITokenRequest) (*dto.CreateAPITokenResponse, error)
+	ListTokensFunc        func(ctx context.Context, userID uint) ([]models.APIToken, error)
+	RevokeTokenFunc       func(ctx context.Context, userID, tokenID uint) error
+	AuthenticateTokenFunc func(ctx context.Context, secret string) (*models.APIToken, error)
+}
+
+// CreateToken calls the mock function
+func (m *MockAPITokenService) CreateToken(ctx context.Context, req dto.CreateAPITokenRequest) (*dto.CreateAPITokenResponse, error) {
+	if m.CreateTokenFunc != nil {
+		return m.CreateTokenFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+// ListTokens calls the mock function
+func (m *MockAPITokenService) ListTokens(ctx context.Context, userID uint) ([]models.APIToken, error) {
+	if m.ListTokensFunc != nil {
+		return m.ListTokensFunc(ctx, userID)
+	}
+	return []models.APIToken{}, nil
+}
+
+// RevokeToken calls the mock function
+func (m *MockAPITokenService) RevokeToken(ctx context.Context, userID, tokenID uint) error {
+	if m.RevokeTokenFunc != nil {
+		return m.RevokeTokenFunc(ctx, userID, tokenID)
+	}
+	return nil
+}
+
+// AuthenticateToken calls the mock function
+func (m *MockAPITokenService) AuthenticateToken(ctx context.Context, secret string) (*models.APIToken, error) {
+	if m.AuthenticateTokenFunc != nil {
+		return m.AuthenticateTokenFunc(ctx, secret)
+	}
+	return nil, nil
+}
@@ -13,9 +13,10 @@ var _ services.AuthService = (*MockAuthService)(nil)
 
 // MockAuthService is a mock implementation of AuthService for testing
 type MockAuthService struct {
-	RegisterUserFunc func(ctx context.Context, req dto.RegisterRequest) (*dto.AuthResponse, error)
-	LoginUserFunc    func(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error)
-	GetByIDFunc      func(ctx context.Context, id uint) (*models.User, error)
+	RegisterUserFunc  func(ctx context.Context, req dto.RegisterRequest) (*dto.AuthResponse, error)
+	LoginUserFunc     func(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error)
+	GetByIDFunc       func(ctx context.Context, id uint) (*models.User, error)
+	UpdateProfileFunc func(ctx context.Context, req dto.UpdateProfileRequest) (*models.User, error)
 }
 
 // RegisterUser calls the mock function
@@ -41,3 +42,11 @@ func (m *MockAuthService) GetByID(ctx context.Context, id uint) (*models.User, e
 	}
 	return nil, nil
 }
+
+// UpdateProfile calls the mock function
+func (m *MockAuthService) UpdateProfile(ctx context.Context, req dto.UpdateProfileRequest) (*models.User, error) {
+	if m.UpdateProfileFunc != nil {
+		return m.UpdateProfileFunc(ctx, req)
+	}
+	return nil, nil
+}
@@ -14,16 +14,25 @@ var _ services.CategoryService = (*MockCategoryService)(nil)
 // MockCategoryService is a mock implementation of CategoryService for testing
 type MockCategoryService struct {
 	CreateCategoryFunc               func(ctx context.Context, req dto.CreateCategoryRequest) (*models.Category, error)
-	GetCategoriesFunc                func(ctx context.Context, userID uint) ([]models.Category, error)
+	GetCategoriesFunc                func(ctx context.Context, userID uint, includeTodos bool) ([]models.Category, error)
 	GetCategoryByIDFunc              func(ctx context.Context, categoryID, userID uint) (*models.Category, error)
 	UpdateCategoryFunc               func(ctx context.Context, req dto.UpdateCategoryRequest) (*models.Category, error)
 	DeleteCategoryFunc               func(ctx context.Context, categoryID, userID uint) error
-	ShareCategoryFunc                func(ctx context.Context, req dto.ShareCategoryRequest) (*models.CategoryShare, error)
+	PreviewShareFunc                 func(ctx context.Context, req dto.SharePreviewRequest) (*dto.SharePreviewResponse, error)
+	ShareCategoryFunc                func(ctx context.Context, req dto.ShareCategoryRequest) (*dto.ShareCategoryResponse, error)
+	UpsertShareFunc                  func(ctx context.Context, req dto.ShareCategoryRequest) (*models.CategoryShare, bool, error)
 	UnshareCategoryFunc              func(ctx context.Context, req dto.UnshareCategoryRequest) error
+	UnshareAllFunc                   func(ctx context.Context, categoryID, ownerID uint) (int64, error)
 	UpdateSharePermissionFunc        func(ctx context.Context, req dto.UpdateSharePermissionRequest) error
-	GetSharesForCategoryFunc         func(ctx context.Context, categoryID, userID uint) ([]models.CategoryShareWithUser, error)
-	GetSharedCategoriesFunc          func(ctx context.Context, userID uint) ([]models.SharedCategoryWithOwner, error)
+	BulkUpdateSharePermissionsFunc   func(ctx context.Context, req dto.BulkUpdateSharePermissionsRequest) (*dto.BulkUpdateSharePermissionsResponse, error)
+	GetSharesForCategoryFunc         func(ctx context.Context, categoryID, userID uint, sortBy string, page, pageSize int) (*dto.ShareListResponse, error)
+	GetSharedCategoriesFunc          func(ctx context.Context, userID uint, permission models.Permission, page, pageSize int, includeTodos bool) (*dto.SharedCategoryListResponse, error)
 	GetUserPermissionForCategoryFunc func(ctx context.Context, userID, categoryID uint) (string, error)
+	GetWritableCategoriesFunc        func(ctx context.Context, userID uint) ([]models.WritableCategory, error)
+	GetCategoriesSharedByMeFunc      func(ctx context.Context, userID uint) ([]models.CategoryWithShares, error)
+	GetGrantedMembersFunc            func(ctx context.Context, userID uint) ([]models.GrantedMember, error)
+	MoveTodosFunc                    func(ctx context.Context, userID, fromCategoryID, toCategoryID uint) (int64, error)
+	CleanupEmptyCategoriesFunc       func(ctx context.Context, userID uint, dryRun bool) ([]models.Category, error)
 }
 
 // CreateCategory calls the mock function
@@ -35,9 +44,9 @@ func (m *MockCategoryService) CreateCategory(ctx context.Context, req dto.Create
 }
 
 // GetCategories calls the mock function
-func (m *MockCategoryService) GetCategories(ctx context.Context, userID uint) ([]models.Category, error) {
+func (m *MockCategoryService) GetCategories(ctx context.Context, userID uint, includeTodos bool) ([]models.Category, error) {
 	if m.GetCategoriesFunc != nil {
-		return m.GetCategoriesFunc(ctx, userID)
+		return m.GetCategoriesFunc(ctx, userID, includeTodos)
 	}
 	return []models.Category{}, nil
 }
@@ -66,12 +75,28 @@ func (m *MockCategoryService) DeleteCategory(ctx context.Context, categoryID, us
 	return nil
 }
 
+// PreviewShare calls the mock function
+func (m *MockCategoryService) PreviewShare(ctx context.Context, req dto.SharePreviewRequest) (*dto.SharePreviewResponse, error) {
+	if m.PreviewShareFunc != nil {
+		return m.PreviewShareFunc(ctx, req)
+	}
+	return &dto.SharePreviewResponse{}, nil
+}
+
+// UpsertShare calls the mock function
+func (m *MockCategoryService) UpsertShare(ctx context.Context, req dto.ShareCategoryRequest) (*models.CategoryShare, bool, error) {
+	if m.UpsertShareFunc != nil {
+		return m.UpsertShareFunc(ctx, req)
+	}
+	return &models.CategoryShare{ID: 1}, true, nil
+}
+
 // ShareCategory calls the mock function
-func (m *MockCategoryService) ShareCategory(ctx context.Context, req dto.ShareCategoryRequest) (*models.CategoryShare, error) {
+func (m *MockCategoryService) ShareCategory(ctx context.Context, req dto.ShareCategoryRequest) (*dto.ShareCategoryResponse, error) {
 	if m.ShareCategoryFunc != nil {
 		return m.ShareCategoryFunc(ctx, req)
 	}
-	return &models.CategoryShare{}, nil
+	return &dto.ShareCategoryResponse{Share: &models.CategoryShare{}}, nil
 }
 
 // UnshareCategory calls the mock function
@@ -82,6 +107,14 @@ func (m *MockCategoryService) UnshareCategory(ctx context.Context, req dto.Unsha
 	return nil
 }
 
+// UnshareAll calls the mock function
+func (m *MockCategoryService) UnshareAll(ctx context.Context, categoryID, ownerID uint) (int64, error) {
+	if m.UnshareAllFunc != nil {
+		return m.UnshareAllFunc(ctx, categoryID, ownerID)
+	}
+	return 0, nil
+}
+
 // UpdateSharePermission calls the mock function
 func (m *MockCategoryService) UpdateSharePermission(ctx context.Context, req dto.UpdateSharePermissionRequest) error {
 	if m.UpdateSharePermissionFunc != nil {
@@ -90,20 +123,28 @@ func (m *MockCategoryService) UpdateSharePermission(ctx context.Context, req dto
 	return nil
 }
 
+// BulkUpdateSharePermissions calls the mock function
+func (m *MockCategoryService) BulkUpdateSharePermissions(ctx context.Context, req dto.BulkUpdateSharePermissionsRequest) (*dto.BulkUpdateSharePermissionsResponse, error) {
+	if m.BulkUpdateSharePermissionsFunc != nil {
+		return m.BulkUpdateSharePermissionsFunc(ctx, req)
+	}
+	return &dto.BulkUpdateSharePermissionsResponse{}, nil
+}
+
 // GetSharesForCategory calls the mock function
-func (m *MockCategoryService) GetSharesForCategory(ctx context.Context, categoryID, userID uint) ([]models.CategoryShareWithUser, error) {
+func (m *MockCategoryService) GetSharesForCategory(ctx context.Context, categoryID, userID uint, sortBy string, page, pageSize int) (*dto.ShareListResponse, error) {
 	if m.GetSharesForCategoryFunc != nil {
-		return m.GetSharesForCategoryFunc(ctx, categoryID, userID)
+		return m.GetSharesForCategoryFunc(ctx, categoryID, userID, sortBy, page, pageSize)
 	}
-	return []models.CategoryShareWithUser{}, nil
+	return &dto.ShareListResponse{}, nil
 }
 
 // GetSharedCategories calls the mock function
-func (m *MockCategoryService) GetSharedCategories(ctx context.Context, userID uint) ([]models.SharedCategoryWithOwner, error) {
+func (m *MockCategoryService) GetSharedCategories(ctx context.Context, userID uint, permission models.Permission, page, pageSize int, includeTodos bool) (*dto.SharedCategoryListResponse, error) {
 	if m.GetSharedCategoriesFunc != nil {
-		return m.GetSharedCategoriesFunc(ctx, userID)
+		return m.GetSharedCategoriesFunc(ctx, userID, permission, page, pageSize, includeTodos)
 	}
-	return []models.SharedCategoryWithOwner{}, nil
+	return &dto.SharedCategoryListResponse{Categories: []models.SharedCategoryWithOwner{}, Page: 1}, nil
 }
 
 // GetUserPermissionForCategory calls the mock function
@@ -113,3 +154,43 @@ func (m *MockCategoryService) GetUserPermissionForCategory(ctx context.Context,
 	}
 	return "none", nil
 }
+
+// GetWritableCategories calls the mock function
+func (m *MockCategoryService) GetWritableCategories(ctx context.Context, userID uint) ([]models.WritableCategory, error) {
+	if m.GetWritableCategoriesFunc != nil {
+		return m.GetWritableCategoriesFunc(ctx, userID)
+	}
+	return []models.WritableCategory{}, nil
+}
+
+// GetCategoriesSharedByMe calls the mock function
+func (m *MockCategoryService) GetCategoriesSharedByMe(ctx context.Context, userID uint) ([]models.CategoryWithShares, error) {
+	if m.GetCategoriesSharedByMeFunc != nil {
+		return m.GetCategoriesSharedByMeFunc(ctx, userID)
+	}
+	return []models.CategoryWithShares{}, nil
+}
+
+// GetGrantedMembers calls the mock function
+func (m *MockCategoryService) GetGrantedMembers(ctx context.Context, userID uint) ([]models.GrantedMember, error) {
+	if m.GetGrantedMembersFunc != nil {
+		return m.GetGrantedMembersFunc(ctx, userID)
+	}
+	return []models.GrantedMember{}, nil
+}
+
+// MoveTodos calls the mock function
+func (m *MockCategoryService) MoveTodos(ctx context.Context, userID, fromCategoryID, toCategoryID uint) (int64, error) {
+	if m.MoveTodosFunc != nil {
+		return m.MoveTodosFunc(ctx, userID, fromCategoryID, toCategoryID)
+	}
+	return 0, nil
+}
+
+// CleanupEmptyCategories calls the mock function
+func (m *MockCategoryService) CleanupEmptyCategories(ctx context.Context, userID uint, dryRun bool) ([]models.Category, error) {
+	if m.CleanupEmptyCategoriesFunc != nil {
+		return m.CleanupEmptyCategoriesFunc(ctx, userID, dryRun)
+	}
+	return []models.Category{}, nil
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/models"
+	"todo-app/internal/services"
+)
+
+// Ensure MockCommentService implements CommentService
+var _ services.CommentService = (*MockCommentService)(nil)
+
+// MockCommentService is a mock implementation of CommentService for testing
+type MockCommentService struct {
+	CreateCommentFunc func(ctx context.Context, req dto.CreateCommentRequest) (*models.Comment, error)
+	GetCommentsFunc   func(ctx context.Context, todoID, userID uint, page, pageSize int) (*dto.CommentListResponse, error)
+	DeleteCommentFunc func(ctx context.Context, req dto.DeleteCommentRequest) error
+}
+
+// CreateComment calls the mock function
+func (m *MockCommentService) CreateComment(ctx context.Context, req dto.CreateCommentRequest) (*models.Comment, error) {
+	if m.CreateCommentFunc != nil {
+		return m.CreateCommentFunc(ctx, req)
+	}
+	return &models.Comment{TodoID: req.TodoID, AuthorID: req.UserID, Body: req.Body}, nil
+}
+
+// GetComments calls the mock function
+func (m *MockCommentService) GetComments(ctx context.Context, todoID, userID uint, page, pageSize int) (*dto.CommentListResponse, error) {
+	if m.GetCommentsFunc != nil {
+		return m.GetCommentsFunc(ctx, todoID, userID, page, pageSize)
+	}
+	return &dto.CommentListResponse{Comments: []models.Comment{}, Page: page, PageSize: pageSize}, nil
+}
+
+// DeleteComment calls the mock function
+func (m *MockCommentService) DeleteComment(ctx context.Context, req dto.DeleteCommentRequest) error {
+	if m.DeleteCommentFunc != nil {
+		return m.DeleteCommentFunc(ctx, req)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"todo-app/internal/dto"
 	"todo-app/internal/models"
@@ -14,12 +15,30 @@ var _ services.TodoService = (*MockTodoService)(nil)
 // MockTodoService is a mock implementation of TodoService for testing
 type MockTodoService struct {
 	CreateTodoFunc                func(ctx context.Context, req dto.CreateTodoRequest) (*models.Todo, error)
-	GetTodosFunc                  func(ctx context.Context, userID uint, page, pageSize int) (*dto.TodoListResponse, error)
-	GetTodosByCategoryIDFunc      func(ctx context.Context, categoryID uint, page, pageSize int) (*dto.TodoListResponse, error)
-	GetTodosGroupedByCategoryFunc func(ctx context.Context, userID uint) (*dto.TodosGroupedByCategoryResponse, error)
+	GetTodosFunc                  func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error)
+	CountTodosFunc                func(ctx context.Context, userID uint, completed *bool) (int64, error)
+	GetTodosByCategoryIDFunc      func(ctx context.Context, req dto.ListCategoryTodosRequest, page, pageSize int) (*dto.TodoListResponse, error)
+	GetCategoryTodosAfterFunc     func(ctx context.Context, req dto.ListCategoryTodosRequest, cursor string, pageSize int) (*dto.TodoCursorPage, error)
+	CountCategoryTodosFunc        func(ctx context.Context, userID, categoryID uint) (*models.TodoStatusCounts, error)
+	GetTodosInCategoriesFunc      func(ctx context.Context, userID uint, categoryIDs []uint, page, pageSize int) (*dto.TodoListResponse, error)
+	GetTodosGroupedByCategoryFunc func(ctx context.Context, userID uint, scope string, perCategory int) (*dto.TodosGroupedByCategoryResponse, error)
 	GetTodoByIDFunc               func(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error)
-	UpdateTodoFunc                func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error)
-	DeleteTodoFunc                func(ctx context.Context, req dto.DeleteTodoRequest) error
+	GetTodosByIDsFunc             func(ctx context.Context, userID uint, ids []uint) (*dto.BatchGetTodosResponse, error)
+	ExpandTodosFunc               func(ctx context.Context, todos []models.Todo, expand dto.TodoExpand) ([]dto.ExpandedTodo, error)
+	UpdateTodoFunc                func(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error)
+	DeleteTodoFunc                func(ctx context.Context, req dto.DeleteTodoRequest) (*dto.DeleteTodoResponse, error)
+	UndoDeleteFunc                func(ctx context.Context, req dto.UndoDeleteRequest) (*models.Todo, error)
+	RestoreAllTodosFunc           func(ctx context.Context, userID, categoryID uint) (int64, error)
+	BulkMoveTodosFunc             func(ctx context.Context, req dto.BulkMoveTodosRequest) (int64, error)
+	CleanupCompletedTodosFunc     func(ctx context.Context, req dto.CleanupTodosRequest) (int64, error)
+	TouchTodoFunc                 func(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error)
+	GetTodoPermissionsFunc        func(ctx context.Context, req dto.GetTodoRequest) (*dto.TodoPermissions, error)
+	GetTodoHistoryFunc            func(ctx context.Context, req dto.GetTodoRequest, page, pageSize int) (*dto.TodoHistoryResponse, error)
+	GetCompletionReportFunc       func(ctx context.Context, req dto.CompletionReportRequest) (*dto.CompletionReport, error)
+	GetRecentTodosFunc            func(ctx context.Context, userID uint, limit int) ([]models.Todo, error)
+	GetTodoChangesFunc            func(ctx context.Context, userID uint, since time.Time) ([]models.TodoChange, error)
+	SyncTodosFunc                 func(ctx context.Context, userID uint, cursor string) (*dto.TodoSyncResponse, error)
+	CompleteAllInCategoryFunc     func(ctx context.Context, userID, categoryID uint, completed bool) (int64, error)
 }
 
 // CreateTodo calls the mock function
@@ -31,9 +50,9 @@ func (m *MockTodoService) CreateTodo(ctx context.Context, req dto.CreateTodoRequ
 }
 
 // GetTodos calls the mock function
-func (m *MockTodoService) GetTodos(ctx context.Context, userID uint, page, pageSize int) (*dto.TodoListResponse, error) {
+func (m *MockTodoService) GetTodos(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error) {
 	if m.GetTodosFunc != nil {
-		return m.GetTodosFunc(ctx, userID, page, pageSize)
+		return m.GetTodosFunc(ctx, userID, sortBy, page, pageSize)
 	}
 	return &dto.TodoListResponse{
 		Todos:      []models.Todo{},
@@ -44,10 +63,48 @@ func (m *MockTodoService) GetTodos(ctx context.Context, userID uint, page, pageS
 	}, nil
 }
 
+// GetCategoryTodosAfter calls the mock function
+func (m *MockTodoService) GetCategoryTodosAfter(ctx context.Context, req dto.ListCategoryTodosRequest, cursor string, pageSize int) (*dto.TodoCursorPage, error) {
+	if m.GetCategoryTodosAfterFunc != nil {
+		return m.GetCategoryTodosAfterFunc(ctx, req, cursor, pageSize)
+	}
+	return &dto.TodoCursorPage{Todos: []models.Todo{}, PageSize: 10}, nil
+}
+
+// CountTodos calls the mock function
+func (m *MockTodoService) CountTodos(ctx context.Context, userID uint, completed *bool) (int64, error) {
+	if m.CountTodosFunc != nil {
+		return m.CountTodosFunc(ctx, userID, completed)
+	}
+	return 0, nil
+}
+
 // GetTodosByCategoryID calls the mock function
-func (m *MockTodoService) GetTodosByCategoryID(ctx context.Context, categoryID uint, page, pageSize int) (*dto.TodoListResponse, error) {
+func (m *MockTodoService) GetTodosByCategoryID(ctx context.Context, req dto.ListCategoryTodosRequest, page, pageSize int) (*dto.TodoListResponse, error) {
 	if m.GetTodosByCategoryIDFunc != nil {
-		return m.GetTodosByCategoryIDFunc(ctx, categoryID, page, pageSize)
+		return m.GetTodosByCategoryIDFunc(ctx, req, page, pageSize)
+	}
+	return &dto.TodoListResponse{
+		Todos:      []models.Todo{},
+		Total:      0,
+		Page:       1,
+		PageSize:   10,
+		TotalPages: 0,
+	}, nil
+}
+
+// CountCategoryTodos calls the mock function
+func (m *MockTodoService) CountCategoryTodos(ctx context.Context, userID, categoryID uint) (*models.TodoStatusCounts, error) {
+	if m.CountCategoryTodosFunc != nil {
+		return m.CountCategoryTodosFunc(ctx, userID, categoryID)
+	}
+	return &models.TodoStatusCounts{}, nil
+}
+
+// GetTodosInCategories calls the mock function
+func (m *MockTodoService) GetTodosInCategories(ctx context.Context, userID uint, categoryIDs []uint, page, pageSize int) (*dto.TodoListResponse, error) {
+	if m.GetTodosInCategoriesFunc != nil {
+		return m.GetTodosInCategoriesFunc(ctx, userID, categoryIDs, page, pageSize)
 	}
 	return &dto.TodoListResponse{
 		Todos:      []models.Todo{},
@@ -66,28 +123,144 @@ func (m *MockTodoService) GetTodoByID(ctx context.Context, req dto.GetTodoReques
 	return nil, nil
 }
 
+// GetTodosByIDs calls the mock function
+func (m *MockTodoService) GetTodosByIDs(ctx context.Context, userID uint, ids []uint) (*dto.BatchGetTodosResponse, error) {
+	if m.GetTodosByIDsFunc != nil {
+		return m.GetTodosByIDsFunc(ctx, userID, ids)
+	}
+	return &dto.BatchGetTodosResponse{Todos: []models.Todo{}, NotFound: []uint{}, Forbidden: []uint{}}, nil
+}
+
+// ExpandTodos calls the mock function, returning the todos without related objects by default
+func (m *MockTodoService) ExpandTodos(ctx context.Context, todos []models.Todo, expand dto.TodoExpand) ([]dto.ExpandedTodo, error) {
+	if m.ExpandTodosFunc != nil {
+		return m.ExpandTodosFunc(ctx, todos, expand)
+	}
+	expanded := make([]dto.ExpandedTodo, len(todos))
+	for i, todo := range todos {
+		expanded[i].Todo = todo
+	}
+	return expanded, nil
+}
+
 // UpdateTodo calls the mock function
-func (m *MockTodoService) UpdateTodo(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error) {
+func (m *MockTodoService) UpdateTodo(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error) {
 	if m.UpdateTodoFunc != nil {
 		return m.UpdateTodoFunc(ctx, req)
 	}
-	return &models.Todo{}, nil
+	return &models.Todo{}, true, nil
 }
 
 // DeleteTodo calls the mock function
-func (m *MockTodoService) DeleteTodo(ctx context.Context, req dto.DeleteTodoRequest) error {
+func (m *MockTodoService) DeleteTodo(ctx context.Context, req dto.DeleteTodoRequest) (*dto.DeleteTodoResponse, error) {
 	if m.DeleteTodoFunc != nil {
 		return m.DeleteTodoFunc(ctx, req)
 	}
-	return nil
+	return &dto.DeleteTodoResponse{}, nil
+}
+
+// UndoDelete calls the mock function
+func (m *MockTodoService) UndoDelete(ctx context.Context, req dto.UndoDeleteRequest) (*models.Todo, error) {
+	if m.UndoDeleteFunc != nil {
+		return m.UndoDeleteFunc(ctx, req)
+	}
+	return &models.Todo{}, nil
 }
 
 // GetTodosGroupedByCategory calls the mock function
-func (m *MockTodoService) GetTodosGroupedByCategory(ctx context.Context, userID uint) (*dto.TodosGroupedByCategoryResponse, error) {
+func (m *MockTodoService) GetTodosGroupedByCategory(ctx context.Context, userID uint, scope string, perCategory int) (*dto.TodosGroupedByCategoryResponse, error) {
 	if m.GetTodosGroupedByCategoryFunc != nil {
-		return m.GetTodosGroupedByCategoryFunc(ctx, userID)
+		return m.GetTodosGroupedByCategoryFunc(ctx, userID, scope, perCategory)
 	}
 	return &dto.TodosGroupedByCategoryResponse{
 		Categories: []dto.CategoryWithTodos{},
 	}, nil
 }
+
+// CleanupCompletedTodos calls the mock function
+func (m *MockTodoService) CleanupCompletedTodos(ctx context.Context, req dto.CleanupTodosRequest) (int64, error) {
+	if m.CleanupCompletedTodosFunc != nil {
+		return m.CleanupCompletedTodosFunc(ctx, req)
+	}
+	return 0, nil
+}
+
+// GetCompletionReport calls the mock function
+func (m *MockTodoService) GetCompletionReport(ctx context.Context, req dto.CompletionReportRequest) (*dto.CompletionReport, error) {
+	if m.GetCompletionReportFunc != nil {
+		return m.GetCompletionReportFunc(ctx, req)
+	}
+	return &dto.CompletionReport{Days: []dto.DayCompletionCount{}}, nil
+}
+
+// TouchTodo calls the mock function
+func (m *MockTodoService) TouchTodo(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error) {
+	if m.TouchTodoFunc != nil {
+		return m.TouchTodoFunc(ctx, req)
+	}
+	return &models.Todo{ID: req.ID, UserID: req.UserID}, nil
+}
+
+// GetTodoPermissions calls the mock function
+func (m *MockTodoService) GetTodoPermissions(ctx context.Context, req dto.GetTodoRequest) (*dto.TodoPermissions, error) {
+	if m.GetTodoPermissionsFunc != nil {
+		return m.GetTodoPermissionsFunc(ctx, req)
+	}
+	return &dto.TodoPermissions{}, nil
+}
+
+// GetTodoHistory calls the mock function
+func (m *MockTodoService) GetTodoHistory(ctx context.Context, req dto.GetTodoRequest, page, pageSize int) (*dto.TodoHistoryResponse, error) {
+	if m.GetTodoHistoryFunc != nil {
+		return m.GetTodoHistoryFunc(ctx, req, page, pageSize)
+	}
+	return &dto.TodoHistoryResponse{}, nil
+}
+
+// CompleteAllInCategory calls the mock function
+func (m *MockTodoService) CompleteAllInCategory(ctx context.Context, userID, categoryID uint, completed bool) (int64, error) {
+	if m.CompleteAllInCategoryFunc != nil {
+		return m.CompleteAllInCategoryFunc(ctx, userID, categoryID, completed)
+	}
+	return 0, nil
+}
+
+// GetRecentTodos calls the mock function
+func (m *MockTodoService) GetRecentTodos(ctx context.Context, userID uint, limit int) ([]models.Todo, error) {
+	if m.GetRecentTodosFunc != nil {
+		return m.GetRecentTodosFunc(ctx, userID, limit)
+	}
+	return []models.Todo{}, nil
+}
+
+// GetTodoChanges calls the mock function
+func (m *MockTodoService) GetTodoChanges(ctx context.Context, userID uint, since time.Time) ([]models.TodoChange, error) {
+	if m.GetTodoChangesFunc != nil {
+		return m.GetTodoChangesFunc(ctx, userID, since)
+	}
+	return []models.TodoChange{}, nil
+}
+
+// SyncTodos calls the mock function
+func (m *MockTodoService) SyncTodos(ctx context.Context, userID uint, cursor string) (*dto.TodoSyncResponse, error) {
+	if m.SyncTodosFunc != nil {
+		return m.SyncTodosFunc(ctx, userID, cursor)
+	}
+	return &dto.TodoSyncResponse{Created: []models.Todo{}, Updated: []models.Todo{}, Deleted: []uint{}}, nil
+}
+
+// BulkMoveTodos calls the mock function
+func (m *MockTodoService) BulkMoveTodos(ctx context.Context, req dto.BulkMoveTodosRequest) (int64, error) {
+	if m.BulkMoveTodosFunc != nil {
+		return m.BulkMoveTodosFunc(ctx, req)
+	}
+	return 0, nil
+}
+
+// RestoreAllTodos calls the mock function
+func (m *MockTodoService) RestoreAllTodos(ctx context.Context, userID, categoryID uint) (int64, error) {
+	if m.RestoreAllTodosFunc != nil {
+		return m.RestoreAllTodosFunc(ctx, userID, categoryID)
+	}
+	return 0, nil
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/models"
+	"todo-app/internal/services"
+)
+
+// Ensure MockUserService implements UserService
+var _ services.UserService = (*MockUserService)(nil)
+
+// MockUserService is a mock implementation of UserService for testing
+type MockUserService struct {
+	ListUsersFunc       func(ctx context.Context, page, pageSize int) (*dto.UserListResponse, error)
+	SeedAdminFunc       func(ctx context.Context, email string) (bool, error)
+	NormalizeEmailsFunc func(ctx context.Context) (int64, error)
+}
+
+// ListUsers calls the mock function
+func (m *MockUserService) ListUsers(ctx context.Context, page, pageSize int) (*dto.UserListResponse, error) {
+	if m.ListUsersFunc != nil {
+		return m.ListUsersFunc(ctx, page, pageSize)
+	}
+	return &dto.UserListResponse{Users: []models.User{}, Page: page, PageSize: pageSize}, nil
+}
+
+// SeedAdmin calls the mock function
+func (m *MockUserService) SeedAdmin(ctx context.Context, email string) (bool, error) {
+	if m.SeedAdminFunc != nil {
+		return m.SeedAdminFunc(ctx, email)
+	}
+	return false, nil
+}
+
+// NormalizeEmails calls the mock function
+func (m *MockUserService) NormalizeEmails(ctx context.Context) (int64, error) {
+	if m.NormalizeEmailsFunc != nil {
+		return m.NormalizeEmailsFunc(ctx)
+	}
+	return 0, nil
+}
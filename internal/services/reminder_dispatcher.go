@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"todo-app/internal/models"
+	"todo-app/internal/repository"
+)
+
+// reminderBatchSize caps how many due reminders are handled per tick
+const reminderBatchSize = 100
+
+// ReminderNotifier delivers a due reminder to the todo's owner
+type ReminderNotifier interface {
+	NotifyReminder(ctx context.Context, todo models.Todo) error
+}
+
+// LogReminderNotifier writes due reminders to the application log
+type LogReminderNotifier struct{}
+
+// NotifyReminder logs the reminder
+func (LogReminderNotifier) NotifyReminder(ctx context.Context, todo models.Todo) error {
+	log.Printf("Reminder: todo %d %q for user %d is due", todo.ID, todo.Title, todo.UserID)
+	return nil
+}
+
+// ReminderDispatcher periodically publishes todos whose reminder time has passed
+type ReminderDispatcher struct {
+	repo     repository.TodoRepository
+	notifier ReminderNotifier
+	interval time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewReminderDispatcher creates a dispatcher that polls the repository every interval
+func NewReminderDispatcher(repo repository.TodoRepository, notifier ReminderNotifier, interval time.Duration) *ReminderDispatcher {
+	return &ReminderDispatcher{
+		repo:     repo,
+		notifier: notifier,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the dispatch loop in a goroutine until Stop is called
+func (d *ReminderDispatcher) Start() {
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-d.stop:
+				return
+			case now := <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), d.interval)
+				if _, err := d.DispatchDue(ctx, now); err != nil {
+					log.Printf("Reminder dispatch failed: %v", err)
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+// Stop ends the dispatch loop and waits for an in-flight tick to finish.
+// It must only be called after Start.
+func (d *ReminderDispatcher) Stop() {
+	d.once.Do(func() {
+		close(d.stop)
+	})
+	<-d.done
+}
+
+// DispatchDue publishes every reminder due at or before now and returns how many were sent.
+// Each reminder is claimed by marking it sent before the notifier runs, so a reminder picked up
+// by two overlapping dispatches is only published once. A reminder that fails to deliver is
+// released again, so the next dispatch retries it.
+func (d *ReminderDispatcher) DispatchDue(ctx context.Context, now time.Time) (int, error) {
+	todos, err := d.repo.GetDueReminders(ctx, now, reminderBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due reminders: %w", err)
+	}
+
+	sent := 0
+	for _, todo := range todos {
+		claimed, err := d.repo.MarkReminderSent(ctx, todo.ID, now)
+		if err != nil {
+			return sent, fmt.Errorf("failed to mark reminder sent: %w", err)
+		}
+		if !claimed {
+			continue
+		}
+		if err := d.notifier.NotifyReminder(ctx, todo); err != nil {
+			log.Printf("Reminder for todo %d could not be delivered, will retry: %v", todo.ID, err)
+			if err := d.repo.ReleaseReminder(ctx, todo.ID); err != nil {
+				return sent, fmt.Errorf("failed to release reminder: %w", err)
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
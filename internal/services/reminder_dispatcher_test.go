@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-app/internal/models"
+	"todo-app/internal/repository/mocks"
+)
+
+// recordingNotifier records every todo it is asked to notify about, failing while err is set
+type recordingNotifier struct {
+	notified []uint
+	err      error
+}
+
+func (n *recordingNotifier) NotifyReminder(ctx context.Context, todo models.Todo) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.notified = append(n.notified, todo.ID)
+	return nil
+}
+
+func TestReminderDispatcher_DispatchDue_SendsOnce(t *testing.T) {
+	now := time.Now()
+	due := now.Add(-time.Minute)
+	later := now.Add(time.Hour)
+
+	// The mock keeps reminder state so repeated dispatches see what earlier ones marked
+	todos := []models.Todo{
+		{ID: 1, Title: "Due", UserID: 1, RemindAt: &due},
+		{ID: 2, Title: "Not yet due", UserID: 1, RemindAt: &later},
+	}
+	repo := &mocks.MockTodoRepository{
+		GetDueRemindersFunc: func(ctx context.Context, at time.Time, limit int) ([]models.Todo, error) {
+			var result []models.Todo
+			for _, todo := range todos {
+				if !todo.ReminderSent && todo.RemindAt != nil && !todo.RemindAt.After(at) {
+					result = append(result, todo)
+				}
+			}
+			return result, nil
+		},
+		MarkReminderSentFunc: func(ctx context.Context, id uint, at time.Time) (bool, error) {
+			for i := range todos {
+				if todos[i].ID == id && !todos[i].ReminderSent && !todos[i].RemindAt.After(at) {
+					todos[i].ReminderSent = true
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+	}
+	notifier := &recordingNotifier{}
+	dispatcher := NewReminderDispatcher(repo, notifier, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := dispatcher.DispatchDue(context.Background(), now); err != nil {
+			t.Fatalf("DispatchDue() error = %v", err)
+		}
+	}
+
+	if len(notifier.notified) != 1 || notifier.notified[0] != 1 {
+		t.Errorf("notified = %v, want [1]", notifier.notified)
+	}
+	if !todos[0].ReminderSent {
+		t.Error("due reminder was not marked sent")
+	}
+	if todos[1].ReminderSent {
+		t.Error("reminder that is not yet due was marked sent")
+	}
+}
+
+func TestReminderDispatcher_DispatchDue_SkipsClaimedReminder(t *testing.T) {
+	due := time.Now().Add(-time.Minute)
+	repo := &mocks.MockTodoRepository{
+		GetDueRemindersFunc: func(ctx context.Context, at time.Time, limit int) ([]models.Todo, error) {
+			return []models.Todo{{ID: 1, RemindAt: &due}}, nil
+		},
+		// Another dispatcher already marked the reminder between the query and the claim
+		MarkReminderSentFunc: func(ctx context.Context, id uint, at time.Time) (bool, error) {
+			return false, nil
+		},
+	}
+	notifier := &recordingNotifier{}
+	dispatcher := NewReminderDispatcher(repo, notifier, time.Minute)
+
+	sent, err := dispatcher.DispatchDue(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("DispatchDue() error = %v", err)
+	}
+	if sent != 0 || len(notifier.notified) != 0 {
+		t.Errorf("sent = %d, notified = %v, want nothing sent", sent, notifier.notified)
+	}
+}
+
+func TestReminderDispatcher_DispatchDue_RetriesFailedDelivery(t *testing.T) {
+	now := time.Now()
+	due := now.Add(-time.Minute)
+	todo := models.Todo{ID: 1, Title: "Due", UserID: 1, RemindAt: &due}
+	repo := &mocks.MockTodoRepository{
+		GetDueRemindersFunc: func(ctx context.Context, at time.Time, limit int) ([]models.Todo, error) {
+			if todo.ReminderSent {
+				return nil, nil
+			}
+			return []models.Todo{todo}, nil
+		},
+		MarkReminderSentFunc: func(ctx context.Context, id uint, at time.Time) (bool, error) {
+			claimed := !todo.ReminderSent
+			todo.ReminderSent = true
+			return claimed, nil
+		},
+		ReleaseReminderFunc: func(ctx context.Context, id uint) error {
+			todo.ReminderSent = false
+			return nil
+		},
+	}
+	notifier := &recordingNotifier{err: errors.New("mail server down")}
+	dispatcher := NewReminderDispatcher(repo, notifier, time.Minute)
+
+	if sent, err := dispatcher.DispatchDue(context.Background(), now); err != nil || sent != 0 {
+		t.Fatalf("DispatchDue() = %d, %v, want nothing sent", sent, err)
+	}
+	if todo.ReminderSent {
+		t.Fatal("undelivered reminder stayed marked sent")
+	}
+
+	// Once the notifier recovers, the next dispatch delivers it
+	notifier.err = nil
+	if sent, err := dispatcher.DispatchDue(context.Background(), now); err != nil || sent != 1 {
+		t.Fatalf("DispatchDue() = %d, %v, want 1 sent", sent, err)
+	}
+	if !todo.ReminderSent || len(notifier.notified) != 1 {
+		t.Errorf("ReminderSent = %v, notified = %v, want the reminder delivered once", todo.ReminderSent, notifier.notified)
+	}
+}
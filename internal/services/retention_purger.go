@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"todo-app/internal/repository"
+)
+
+// purgeBatchSize caps how many todos are hard-deleted per statement, keeping each delete short
+const purgeBatchSize = 500
+
+// RetentionPurger periodically hard-deletes todos that were soft-deleted longer ago than the retention
+type RetentionPurger struct {
+	repo      repository.TodoRepository
+	retention time.Duration
+	interval  time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewRetentionPurger creates a purger that runs every interval. A retention of 0 keeps deleted todos forever.
+func NewRetentionPurger(repo repository.TodoRepository, retention, interval time.Duration) *RetentionPurger {
+	return &RetentionPurger{
+		repo:      repo,
+		retention: retention,
+		interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start runs the purge loop in a goroutine until Stop is called
+func (p *RetentionPurger) Start() {
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case now := <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+				if purged, err := p.PurgeExpired(ctx, now); err != nil {
+					log.Printf("Retention purge failed: %v", err)
+				} else if purged > 0 {
+					log.Printf("Retention purge removed %d deleted todos", purged)
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+// Stop ends the purge loop and waits for an in-flight run to finish.
+// It must only be called after Start.
+func (p *RetentionPurger) Stop() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+// PurgeExpired hard-deletes, in batches, every todo soft-deleted before now minus the retention
+// and returns how many were removed. It does nothing when retention is disabled.
+func (p *RetentionPurger) PurgeExpired(ctx context.Context, now time.Time) (int64, error) {
+	if p.retention <= 0 {
+		return 0, nil
+	}
+
+	cutoff := now.Add(-p.retention)
+	var purged int64
+	for {
+		n, err := p.repo.PurgeDeletedTodosBefore(ctx, cutoff, purgeBatchSize)
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge deleted todos: %w", err)
+		}
+		purged += n
+		if n < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
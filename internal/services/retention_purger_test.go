@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"todo-app/internal/models"
+	"todo-app/internal/repository/mocks"
+)
+
+func TestRetentionPurger_PurgeExpired(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-40 * 24 * time.Hour)
+	recent := now.Add(-24 * time.Hour)
+
+	// The mock keeps the rows so the test can see which todos were hard-deleted
+	todos := []models.Todo{
+		{ID: 1, Title: "Deleted long ago", DeletedAt: &old},
+		{ID: 2, Title: "Deleted recently", DeletedAt: &recent},
+		{ID: 3, Title: "Not deleted"},
+	}
+	repo := &mocks.MockTodoRepository{
+		PurgeDeletedTodosBeforeFunc: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+			var kept []models.Todo
+			var purged int64
+			for _, todo := range todos {
+				if todo.DeletedAt != nil && todo.DeletedAt.Before(before) && purged < int64(limit) {
+					purged++
+					continue
+				}
+				kept = append(kept, todo)
+			}
+			todos = kept
+			return purged, nil
+		},
+	}
+	purger := NewRetentionPurger(repo, 30*24*time.Hour, time.Hour)
+
+	purged, err := purger.PurgeExpired(context.Background(), now)
+	if err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeExpired() = %d, want 1", purged)
+	}
+	if len(todos) != 2 || todos[0].ID != 2 || todos[1].ID != 3 {
+		t.Errorf("remaining todos = %+v, want the recently deleted and the live todo", todos)
+	}
+}
+
+func TestRetentionPurger_PurgeExpired_Batches(t *testing.T) {
+	remaining := int64(purgeBatchSize*2 + 7)
+	calls := 0
+	repo := &mocks.MockTodoRepository{
+		PurgeDeletedTodosBeforeFunc: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+			calls++
+			n := min(remaining, int64(limit))
+			remaining -= n
+			return n, nil
+		},
+	}
+	purger := NewRetentionPurger(repo, time.Hour, time.Hour)
+
+	purged, err := purger.PurgeExpired(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+	if purged != purgeBatchSize*2+7 || calls != 3 {
+		t.Errorf("PurgeExpired() = %d in %d batches, want %d in 3", purged, calls, purgeBatchSize*2+7)
+	}
+}
+
+func TestRetentionPurger_PurgeExpired_Disabled(t *testing.T) {
+	repo := &mocks.MockTodoRepository{
+		PurgeDeletedTodosBeforeFunc: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+			t.Error("PurgeDeletedTodosBefore() should not be called with retention disabled")
+			return 0, nil
+		},
+	}
+	purger := NewRetentionPurger(repo, 0, time.Hour)
+
+	if purged, err := purger.PurgeExpired(context.Background(), time.Now()); err != nil || purged != 0 {
+		t.Errorf("PurgeExpired() = %d, %v, want 0, nil", purged, err)
+	}
+}
@@ -1,14 +1,21 @@
 package services
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"todo-app/internal/dto"
 	"todo-app/internal/models"
 	"todo-app/internal/repository"
+	"todo-app/pkg/utils"
 )
 
 // Common errors for todo operations
@@ -18,12 +25,74 @@ var (
 	ErrInvalidTodoID     = errors.New("invalid todo id")
 	ErrCategoryRequired  = errors.New("category is required")
 	ErrNoWritePermission = errors.New("you don't have write permission for this category")
+	ErrDuplicateTodo     = errors.New("a todo with this title already exists in this category")
+	ErrInvalidCreator    = errors.New("created_by must be 'me', 'others' or a user id")
+	ErrInvalidScope      = errors.New("scope must be 'owned', 'shared' or 'all'")
+	ErrTodoLimitReached  = errors.New("category has reached the maximum number of todos")
+	ErrInvalidSort       = errors.New("sort_by must be created_at, updated_at or title followed by :asc or :desc")
+	ErrInvalidDateRange  = errors.New("to must not be before from")
+	ErrDateRangeTooLarge = fmt.Errorf("date range must not exceed %d days", MaxReportDays)
+	ErrInvalidUndoToken  = errors.New("undo token is invalid or has expired")
+	ErrInvalidCursor     = errors.New("cursor is invalid")
+)
+
+// FieldTooLongError reports a todo field with more Unicode characters than the configured limit
+type FieldTooLongError struct {
+	Field string
+	Max   int
+}
+
+func (e *FieldTooLongError) Error() string {
+	return fmt.Sprintf("%s must be at most %d characters", e.Field, e.Max)
+}
+
+// Creator filter values accepted by GetTodosByCategoryID
+const (
+	CreatedByMe     = "me"
+	CreatedByOthers = "others"
+)
+
+// MaxReportDays caps how many days a completion report may cover
+const MaxReportDays = 366
+
+// DefaultRecentTodos is how many todos GetRecentTodos returns when no limit is given
+const DefaultRecentTodos = 20
+
+// UndoDeleteWindow is how long the undo token returned by DeleteTodo stays valid
+const UndoDeleteWindow = 30 * time.Second
+
+// Scope values accepted by GetTodosGroupedByCategory
+const (
+	ScopeAll    = "all"
+	ScopeOwned  = "owned"
+	ScopeShared = "shared"
 )
 
 // PaginationConfig holds pagination settings
 type PaginationConfig struct {
 	DefaultPageSize int
 	MaxPageSize     int
+	DefaultTodoSort models.TodoSort // Ordering used by GetTodos when none is requested
+}
+
+// LimitsConfig holds size limits that protect queries over whole categories (zero disables a limit)
+type LimitsConfig struct {
+	MaxTodosPerCategory int
+	MaxRecentTodos      int // Most todos GetRecentTodos returns
+	MaxTitleLen         int // Most Unicode characters a todo title may have
+	MaxDescriptionLen   int // Most Unicode characters a todo description may have
+}
+
+// checkTodoLengths returns a FieldTooLongError when a provided title or description exceeds its limit.
+// The binding tags on the request bodies stay as a fixed upper bound; these limits can only tighten them.
+func checkTodoLengths(limits LimitsConfig, title, description *string) error {
+	if title != nil && limits.MaxTitleLen > 0 && utf8.RuneCountInString(*title) > limits.MaxTitleLen {
+		return &FieldTooLongError{Field: "title", Max: limits.MaxTitleLen}
+	}
+	if description != nil && limits.MaxDescriptionLen > 0 && utf8.RuneCountInString(*description) > limits.MaxDescriptionLen {
+		return &FieldTooLongError{Field: "description", Max: limits.MaxDescriptionLen}
+	}
+	return nil
 }
 
 // Ensure TodoServiceImpl implements TodoService
@@ -34,29 +103,112 @@ type TodoServiceImpl struct {
 	repo              repository.TodoRepository
 	categoryRepo      repository.CategoryRepository
 	categoryShareRepo repository.CategoryShareRepository
+	eventRepo         repository.TodoEventRepository
+	userRepo          repository.UserRepository
+	txManager         repository.TxManager
 	pagination        PaginationConfig
+	limits            LimitsConfig
+	// autoCreateCategories lets CreateTodo create a category by name when none exists
+	autoCreateCategories bool
+	undoTokens           *utils.UndoTokenManager
 }
 
-// NewTodoService creates a new TodoService with the provided repositories and pagination config
+// NewTodoService creates a new TodoService with the provided repositories, pagination and limits config.
+// autoCreateCategories controls whether a todo naming an unknown category creates it, and undoTokens
+// signs the tokens DeleteTodo returns for UndoDelete.
 func NewTodoService(
 	repo repository.TodoRepository,
 	categoryRepo repository.CategoryRepository,
 	categoryShareRepo repository.CategoryShareRepository,
+	eventRepo repository.TodoEventRepository,
+	userRepo repository.UserRepository,
+	txManager repository.TxManager,
 	pagination PaginationConfig,
+	limits LimitsConfig,
+	autoCreateCategories bool,
+	undoTokens *utils.UndoTokenManager,
 ) TodoService {
 	return &TodoServiceImpl{
-		repo:              repo,
-		categoryRepo:      categoryRepo,
-		categoryShareRepo: categoryShareRepo,
-		pagination:        pagination,
+		repo:                 repo,
+		categoryRepo:         categoryRepo,
+		categoryShareRepo:    categoryShareRepo,
+		eventRepo:            eventRepo,
+		userRepo:             userRepo,
+		txManager:            txManager,
+		pagination:           pagination,
+		limits:               limits,
+		autoCreateCategories: autoCreateCategories,
+		undoTokens:           undoTokens,
 	}
 }
 
-// checkCategoryPermission checks if user has at least the required permission for a category
-// Returns the permission level ("owner", "write", "read", "none") and any error
+// checkCategoryCapacity returns ErrTodoLimitReached if adding incoming todos to a category would take it
+// past maxTodos. Soft-deleted todos do not count toward the limit.
+// Callers pass the repositories of the transaction making the change: the category row is locked before
+// counting, so concurrent writers into the same category cannot both pass the check.
+func checkCategoryCapacity(ctx context.Context, repos repository.RepoSet, maxTodos int, categoryID uint, incoming int64) error {
+	if maxTodos <= 0 || incoming <= 0 {
+		return nil
+	}
+
+	if err := repos.Categories.LockCategory(ctx, categoryID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCategoryNotFound
+		}
+		return fmt.Errorf("failed to lock category: %w", err)
+	}
+	count, err := repos.Todos.CountTodosInCategory(ctx, categoryID)
+	if err != nil {
+		return fmt.Errorf("failed to count todos in category: %w", err)
+	}
+	if count+incoming > int64(maxTodos) {
+		return ErrTodoLimitReached
+	}
+	return nil
+}
+
+// recordEvent appends an entry to a todo's history. Callers pass the event repository of the transaction
+// making the change, so the change and its history are committed or rolled back together.
+func recordEvent(ctx context.Context, events repository.TodoEventRepository, todoID, actorID uint, action models.TodoEventAction, changedFields []string) error {
+	event := &models.TodoEvent{
+		TodoID:        todoID,
+		ActorID:       actorID,
+		Action:        action,
+		ChangedFields: changedFields,
+	}
+	if err := events.CreateTodoEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to record todo history: %w", err)
+	}
+	return nil
+}
+
+// recordEvents is recordEvent for a change made to several todos at once, with one entry per todo
+func recordEvents(ctx context.Context, events repository.TodoEventRepository, todoIDs []uint, actorID uint, action models.TodoEventAction, changedFields []string) error {
+	for _, id := range todoIDs {
+		if err := recordEvent(ctx, events, id, actorID, action, changedFields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCategoryPermission checks if user has at least the required permission for a category.
+// A category that does not exist is reported as ErrCategoryNotFound before any permission check,
+// so callers can tell a bad category ID (404) from a category the user cannot access (403).
 func (s *TodoServiceImpl) checkCategoryPermission(ctx context.Context, userID, categoryID uint, requireWrite bool) error {
+	return checkCategoryAccess(ctx, s.categoryRepo, s.categoryShareRepo, userID, categoryID, requireWrite)
+}
+
+// checkCategoryAccess is checkCategoryPermission for services that hold the category repositories themselves
+func checkCategoryAccess(
+	ctx context.Context,
+	categoryRepo repository.CategoryRepository,
+	categoryShareRepo repository.CategoryShareRepository,
+	userID, categoryID uint,
+	requireWrite bool,
+) error {
 	// First check if category exists
-	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
+	category, err := categoryRepo.GetCategoryByID(ctx, categoryID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ErrCategoryNotFound
@@ -70,7 +222,7 @@ func (s *TodoServiceImpl) checkCategoryPermission(ctx context.Context, userID, c
 	}
 
 	// Check shared permission
-	permission, err := s.categoryShareRepo.GetUserPermissionForCategory(ctx, userID, categoryID)
+	permission, err := categoryShareRepo.GetUserPermissionForCategory(ctx, userID, categoryID)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("failed to check permission: %w", err)
 	}
@@ -97,10 +249,19 @@ func (s *TodoServiceImpl) getOrCreateCategory(ctx context.Context, userID uint,
 		return category, nil
 	}
 
+	if !s.autoCreateCategories {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch category: %w", err)
+	}
+
 	// Category doesn't exist, create it
 	newCategory := &models.Category{
-		Name:    categoryName,
-		OwnerID: userID,
+		Name:                   categoryName,
+		OwnerID:                userID,
+		AllowDuplicateTitles:   true,
+		DefaultSharePermission: models.PermissionRead,
 	}
 
 	if err := s.categoryRepo.CreateCategory(ctx, newCategory); err != nil {
@@ -112,6 +273,10 @@ func (s *TodoServiceImpl) getOrCreateCategory(ctx context.Context, userID uint,
 
 // CreateTodo handles todo creation workflow
 func (s *TodoServiceImpl) CreateTodo(ctx context.Context, req dto.CreateTodoRequest) (*models.Todo, error) {
+	if err := checkTodoLengths(s.limits, &req.Title, &req.Description); err != nil {
+		return nil, err
+	}
+
 	var category *models.Category
 
 	if req.CategoryID != nil && *req.CategoryID > 0 {
@@ -139,23 +304,51 @@ func (s *TodoServiceImpl) CreateTodo(ctx context.Context, req dto.CreateTodoRequ
 		}
 	}
 
+	// Categories can opt out of duplicate titles among their non-deleted todos
+	if !category.AllowDuplicateTitles {
+		exists, err := s.repo.HasTodoWithTitle(ctx, category.ID, strings.TrimSpace(req.Title))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for duplicate todo: %w", err)
+		}
+		if exists {
+			return nil, ErrDuplicateTodo
+		}
+	}
+
 	todo := &models.Todo{
 		Title:       req.Title,
 		Description: req.Description,
 		CategoryID:  category.ID,
+		RemindAt:    req.RemindAt,
 		UserID:      req.UserID,
 		CreatedBy:   req.UserID,
 	}
 
-	if err := s.repo.CreateTodo(ctx, todo); err != nil {
-		return nil, fmt.Errorf("failed to create todo: %w", err)
+	err := s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		if err := checkCategoryCapacity(ctx, repos, s.limits.MaxTodosPerCategory, category.ID, 1); err != nil {
+			return err
+		}
+		if err := repos.Todos.CreateTodo(ctx, todo); err != nil {
+			return fmt.Errorf("failed to create todo: %w", err)
+		}
+		return recordEvent(ctx, repos.TodoEvents, todo.ID, req.UserID, models.TodoEventCreate, nil)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return todo, nil
 }
 
 // GetTodos retrieves todos for a user with pagination
-func (s *TodoServiceImpl) GetTodos(ctx context.Context, userID uint, page, pageSize int) (*dto.TodoListResponse, error) {
+func (s *TodoServiceImpl) GetTodos(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) (*dto.TodoListResponse, error) {
+	if sortBy == "" {
+		sortBy = s.pagination.DefaultTodoSort
+	}
+	if !sortBy.IsValid() {
+		return nil, ErrInvalidSort
+	}
+
 	// Normalize pagination parameters using config values
 	page = max(page, 1)
 	if pageSize < 1 {
@@ -163,7 +356,7 @@ func (s *TodoServiceImpl) GetTodos(ctx context.Context, userID uint, page, pageS
 	}
 	pageSize = min(pageSize, s.pagination.MaxPageSize)
 
-	todos, total, err := s.repo.GetTodos(ctx, userID, page, pageSize)
+	todos, total, err := s.repo.GetTodos(ctx, userID, sortBy, page, pageSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch todos: %w", err)
 	}
@@ -180,8 +373,46 @@ func (s *TodoServiceImpl) GetTodos(ctx context.Context, userID uint, page, pageS
 	}, nil
 }
 
-// GetTodosByCategoryID retrieves todos filtered by category ID with pagination
-func (s *TodoServiceImpl) GetTodosByCategoryID(ctx context.Context, categoryID uint, page, pageSize int) (*dto.TodoListResponse, error) {
+// CountTodos counts the todos the list endpoint would return, without fetching them
+func (s *TodoServiceImpl) CountTodos(ctx context.Context, userID uint, completed *bool) (int64, error) {
+	count, err := s.repo.CountTodos(ctx, userID, completed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count todos: %w", err)
+	}
+	return count, nil
+}
+
+// parseCreatorFilter resolves a created_by query value against the calling user
+func parseCreatorFilter(createdBy string, userID uint) (repository.TodoCreatorFilter, error) {
+	switch createdBy {
+	case "":
+		return repository.TodoCreatorFilter{}, nil
+	case CreatedByMe:
+		return repository.TodoCreatorFilter{CreatedBy: userID}, nil
+	case CreatedByOthers:
+		return repository.TodoCreatorFilter{NotCreatedBy: userID}, nil
+	}
+
+	id, err := strconv.ParseUint(createdBy, 10, 64)
+	if err != nil || id == 0 {
+		return repository.TodoCreatorFilter{}, ErrInvalidCreator
+	}
+	return repository.TodoCreatorFilter{CreatedBy: uint(id)}, nil
+}
+
+// GetTodosByCategoryID retrieves a category's todos, optionally filtered by creator, for a user
+// who can read the category
+func (s *TodoServiceImpl) GetTodosByCategoryID(ctx context.Context, req dto.ListCategoryTodosRequest, page, pageSize int) (*dto.TodoListResponse, error) {
+	creator, err := parseCreatorFilter(req.CreatedBy, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filtering by any creator, including an arbitrary user ID, requires read access to the category
+	if err := s.checkCategoryPermission(ctx, req.UserID, req.CategoryID, false); err != nil {
+		return nil, err
+	}
+
 	// Normalize pagination parameters using config values
 	page = max(page, 1)
 	if pageSize < 1 {
@@ -189,7 +420,7 @@ func (s *TodoServiceImpl) GetTodosByCategoryID(ctx context.Context, categoryID u
 	}
 	pageSize = min(pageSize, s.pagination.MaxPageSize)
 
-	todos, total, err := s.repo.GetTodosByCategoryID(ctx, categoryID, page, pageSize)
+	todos, total, err := s.repo.GetTodosByCategoryID(ctx, req.CategoryID, creator, page, pageSize)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch todos by category: %w", err)
 	}
@@ -206,8 +437,95 @@ func (s *TodoServiceImpl) GetTodosByCategoryID(ctx context.Context, categoryID u
 	}, nil
 }
 
+// GetCategoryTodosAfter retrieves a keyset page of a category's todos, newest first, for a user who can read
+// the category. An empty cursor starts from the newest todo, and NextCursor resumes after the last one returned.
+func (s *TodoServiceImpl) GetCategoryTodosAfter(ctx context.Context, req dto.ListCategoryTodosRequest, cursor string, pageSize int) (*dto.TodoCursorPage, error) {
+	creator, err := parseCreatorFilter(req.CreatedBy, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *repository.TodoKeyset
+	if cursor != "" {
+		parsed, err := utils.ParseTodoCursor(cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		after = &repository.TodoKeyset{CreatedAt: parsed.CreatedAt, ID: parsed.ID}
+	}
+
+	if err := s.checkCategoryPermission(ctx, req.UserID, req.CategoryID, false); err != nil {
+		return nil, err
+	}
+
+	if pageSize < 1 {
+		pageSize = s.pagination.DefaultPageSize
+	}
+	pageSize = min(pageSize, s.pagination.MaxPageSize)
+
+	// One extra todo tells whether another page follows without a count query
+	todos, err := s.repo.GetTodosByCategoryIDAfter(ctx, req.CategoryID, creator, after, pageSize+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch todos by category: %w", err)
+	}
+
+	page := &dto.TodoCursorPage{Todos: todos, PageSize: pageSize}
+	if len(todos) > pageSize {
+		page.Todos = todos[:pageSize]
+		last := page.Todos[pageSize-1]
+		page.NextCursor = utils.TodoCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+// CountCategoryTodos counts the todos in a category the user can read, without fetching them
+func (s *TodoServiceImpl) CountCategoryTodos(ctx context.Context, userID, categoryID uint) (*models.TodoStatusCounts, error) {
+	if err := s.checkCategoryPermission(ctx, userID, categoryID, false); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.CountTodosByStatusInCategory(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count category todos: %w", err)
+	}
+	return &counts, nil
+}
+
+// GetTodosInCategories retrieves the todos in any of the given categories, newest first. Categories the user
+// cannot read, or that do not exist, are left out rather than failing the request.
+func (s *TodoServiceImpl) GetTodosInCategories(ctx context.Context, userID uint, categoryIDs []uint, page, pageSize int) (*dto.TodoListResponse, error) {
+	// Normalize pagination parameters using config values
+	page = max(page, 1)
+	if pageSize < 1 {
+		pageSize = s.pagination.DefaultPageSize
+	}
+	pageSize = min(pageSize, s.pagination.MaxPageSize)
+
+	todos, total, err := s.repo.GetTodosInCategories(ctx, userID, categoryIDs, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch todos by categories: %w", err)
+	}
+
+	// Calculate total pages
+	totalPages := (total + int64(pageSize) - 1) / int64(pageSize)
+
+	return &dto.TodoListResponse{
+		Todos:      todos,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
 // GetTodoByID retrieves a single todo with ownership/permission verification
+// With IncludeDeleted a soft-deleted todo is returned too, but only to users with write access to its category;
+// anyone else gets ErrTodoNotFound, exactly as if the flag had not been set
 func (s *TodoServiceImpl) GetTodoByID(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error) {
+	if req.IncludeDeleted {
+		return s.getTodoIncludingDeleted(ctx, req)
+	}
+
 	todo, err := s.repo.GetTodoByID(ctx, req.ID)
 	if err != nil {
 		return nil, ErrTodoNotFound
@@ -221,77 +539,491 @@ func (s *TodoServiceImpl) GetTodoByID(ctx context.Context, req dto.GetTodoReques
 	return todo, nil
 }
 
+// getTodoIncludingDeleted looks a todo up whether or not it is deleted. A live todo needs read permission as usual;
+// a deleted one needs write permission and is otherwise reported as not found, so its existence is not revealed.
+func (s *TodoServiceImpl) getTodoIncludingDeleted(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error) {
+	todo, err := s.repo.GetTodoByIDIncludingDeleted(ctx, req.ID)
+	if err != nil {
+		return nil, ErrTodoNotFound
+	}
+
+	if todo.DeletedAt == nil {
+		if err := s.checkCategoryPermission(ctx, req.UserID, todo.CategoryID, false); err != nil {
+			return nil, err
+		}
+		return todo, nil
+	}
+
+	err = s.checkCategoryPermission(ctx, req.UserID, todo.CategoryID, true)
+	switch {
+	case err == nil:
+		return todo, nil
+	case errors.Is(err, ErrForbidden), errors.Is(err, ErrNoWritePermission), errors.Is(err, ErrCategoryNotFound):
+		return nil, ErrTodoNotFound
+	default:
+		return nil, err
+	}
+}
+
+// GetTodoPermissions reports what the user may do with a todo, based on their permission on its category.
+// Deleting needs the same write permission as editing. A user without access gets all false rather than an error.
+func (s *TodoServiceImpl) GetTodoPermissions(ctx context.Context, req dto.GetTodoRequest) (*dto.TodoPermissions, error) {
+	todo, err := s.repo.GetTodoByID(ctx, req.ID)
+	if err != nil {
+		return nil, ErrTodoNotFound
+	}
+
+	err = s.checkCategoryPermission(ctx, req.UserID, todo.CategoryID, true)
+	switch {
+	case err == nil:
+		return &dto.TodoPermissions{CanRead: true, CanWrite: true, CanDelete: true}, nil
+	case errors.Is(err, ErrNoWritePermission):
+		return &dto.TodoPermissions{CanRead: true}, nil
+	case errors.Is(err, ErrForbidden):
+		return &dto.TodoPermissions{}, nil
+	default:
+		return nil, err
+	}
+}
+
+// GetTodosByIDs retrieves the todos with the given IDs that the user can read, in the order the IDs
+// were given. The todos are loaded in one query and permission is checked once per category;
+// repeated IDs are returned once.
+func (s *TodoServiceImpl) GetTodosByIDs(ctx context.Context, userID uint, ids []uint) (*dto.BatchGetTodosResponse, error) {
+	todos, err := s.repo.GetTodosByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch todos: %w", err)
+	}
+	byID := make(map[uint]models.Todo, len(todos))
+	for _, todo := range todos {
+		byID[todo.ID] = todo
+	}
+
+	response := &dto.BatchGetTodosResponse{
+		Todos:     []models.Todo{},
+		NotFound:  []uint{},
+		Forbidden: []uint{},
+	}
+	readable := make(map[uint]bool)
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		todo, ok := byID[id]
+		if !ok {
+			response.NotFound = append(response.NotFound, id)
+			continue
+		}
+
+		canRead, checked := readable[todo.CategoryID]
+		if !checked {
+			err := s.checkCategoryPermission(ctx, userID, todo.CategoryID, false)
+			if err != nil && !errors.Is(err, ErrForbidden) && !errors.Is(err, ErrCategoryNotFound) {
+				return nil, err
+			}
+			canRead = err == nil
+			readable[todo.CategoryID] = canRead
+		}
+		if !canRead {
+			response.Forbidden = append(response.Forbidden, id)
+			continue
+		}
+		response.Todos = append(response.Todos, todo)
+	}
+
+	return response, nil
+}
+
+// ExpandTodos inlines the requested related objects into todos the caller already fetched.
+// Categories and creators are each batch-loaded in one query however many todos there are.
+func (s *TodoServiceImpl) ExpandTodos(ctx context.Context, todos []models.Todo, expand dto.TodoExpand) ([]dto.ExpandedTodo, error) {
+	expanded := make([]dto.ExpandedTodo, len(todos))
+	for i, todo := range todos {
+		expanded[i].Todo = todo
+	}
+
+	if expand.Category && len(todos) > 0 {
+		seen := make(map[uint]bool, len(todos))
+		ids := make([]uint, 0, len(todos))
+		for _, todo := range todos {
+			if !seen[todo.CategoryID] {
+				seen[todo.CategoryID] = true
+				ids = append(ids, todo.CategoryID)
+			}
+		}
+
+		categories, err := s.categoryRepo.GetCategoriesByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch categories: %w", err)
+		}
+		briefs := make(map[uint]*dto.CategoryBrief, len(categories))
+		for _, category := range categories {
+			briefs[category.ID] = &dto.CategoryBrief{ID: category.ID, Name: category.Name}
+		}
+
+		for i := range expanded {
+			expanded[i].Category = briefs[expanded[i].CategoryID]
+		}
+	}
+
+	if expand.Creator && len(todos) > 0 {
+		seen := make(map[uint]bool, len(todos))
+		ids := make([]uint, 0, len(todos))
+		for _, todo := range todos {
+			if !seen[todo.CreatedBy] {
+				seen[todo.CreatedBy] = true
+				ids = append(ids, todo.CreatedBy)
+			}
+		}
+
+		users, err := s.userRepo.GetUsersByIDs(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch creators: %w", err)
+		}
+		briefs := make(map[uint]*dto.UserBrief, len(users))
+		for _, user := range users {
+			briefs[user.ID] = &dto.UserBrief{ID: user.ID, Name: user.Name, Email: user.Email}
+		}
+
+		for i := range expanded {
+			expanded[i].Creator = briefs[expanded[i].CreatedBy]
+		}
+	}
+
+	return expanded, nil
+}
+
 // UpdateTodo handles todo update with ownership/permission verification
-func (s *TodoServiceImpl) UpdateTodo(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, error) {
+// When every provided field already has the requested value the todo is returned as is with changed false
+func (s *TodoServiceImpl) UpdateTodo(ctx context.Context, req dto.UpdateTodoRequest) (*models.Todo, bool, error) {
+	if err := checkTodoLengths(s.limits, req.Title, req.Description); err != nil {
+		return nil, false, err
+	}
+
 	// Fetch existing todo
 	todo, err := s.repo.GetTodoByID(ctx, req.ID)
 	if err != nil {
-		return nil, ErrTodoNotFound
+		return nil, false, ErrTodoNotFound
 	}
 
 	// Check if user has write permission for the current category
 	if err := s.checkCategoryPermission(ctx, req.UserID, todo.CategoryID, true); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
+	previousCategoryID := todo.CategoryID
+
 	// If changing category, check write permission for the new category
 	if req.CategoryID != nil && *req.CategoryID != todo.CategoryID {
 		if err := s.checkCategoryPermission(ctx, req.UserID, *req.CategoryID, true); err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		// Get new category to update UserID (todo belongs to category owner)
 		newCategory, err := s.categoryRepo.GetCategoryByID(ctx, *req.CategoryID)
 		if err != nil {
-			return nil, ErrCategoryNotFound
+			return nil, false, ErrCategoryNotFound
 		}
 		todo.CategoryID = *req.CategoryID
 		todo.UserID = newCategory.OwnerID
 	}
 
-	// Apply updates (only update fields that are provided)
-	if req.Title != nil && *req.Title != "" {
+	// A replace resets omitted optional fields to their defaults instead of leaving them unchanged
+	if req.Replace {
+		empty, incomplete := "", false
+		if req.Description == nil {
+			req.Description = &empty
+		}
+		if req.Completed == nil {
+			req.Completed = &incomplete
+		}
+	}
+
+	// Apply updates (only update fields that are provided), tracking what actually changed
+	changed := []string{}
+	if todo.CategoryID != previousCategoryID {
+		changed = append(changed, "category_id")
+	}
+	if req.Title != nil && *req.Title != "" && *req.Title != todo.Title {
 		todo.Title = *req.Title
+		changed = append(changed, "title")
 	}
-	if req.Description != nil {
+	if req.Description != nil && *req.Description != todo.Description {
 		todo.Description = *req.Description
+		changed = append(changed, "description")
 	}
-	if req.Completed != nil {
+	if req.Completed != nil && *req.Completed != todo.Completed {
 		todo.Completed = *req.Completed
+		changed = append(changed, "completed")
+	}
+	if remindAtChanged(todo.RemindAt, req.RemindAt, req.Replace) {
+		todo.RemindAt = req.RemindAt
+		todo.ReminderSent = false
+		changed = append(changed, "remind_at")
+	}
+
+	// Sending the values the todo already has is a no-op: no write, no updated_at bump and no history event
+	if len(changed) == 0 {
+		return todo, false, nil
 	}
 
 	// Save updates
-	if err := s.repo.UpdateTodo(ctx, todo); err != nil {
-		return nil, fmt.Errorf("failed to update todo: %w", err)
+	err = s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		if todo.CategoryID != previousCategoryID {
+			if err := checkCategoryCapacity(ctx, repos, s.limits.MaxTodosPerCategory, todo.CategoryID, 1); err != nil {
+				return err
+			}
+		}
+		if err := repos.Todos.UpdateTodo(ctx, todo); err != nil {
+			return fmt.Errorf("failed to update todo: %w", err)
+		}
+		return recordEvent(ctx, repos.TodoEvents, todo.ID, req.UserID, models.TodoEventUpdate, changed)
+	})
+	if err != nil {
+		return nil, false, err
 	}
 
-	return todo, nil
+	return todo, true, nil
+}
+
+// remindAtChanged reports whether an update moves the reminder time. A nil value only clears the
+// reminder on a replace; for a partial update it leaves the reminder unchanged.
+func remindAtChanged(current, requested *time.Time, replace bool) bool {
+	if requested == nil {
+		return replace && current != nil
+	}
+	return current == nil || !current.Equal(*requested)
+}
+
+// TouchTodo bumps a todo's updated_at with write permission verification. No history event is recorded,
+// since none of the todo's fields change.
+func (s *TodoServiceImpl) TouchTodo(ctx context.Context, req dto.GetTodoRequest) (*models.Todo, error) {
+	todo, err := s.repo.GetTodoByID(ctx, req.ID)
+	if err != nil {
+		return nil, ErrTodoNotFound
+	}
+
+	if err := s.checkCategoryPermission(ctx, req.UserID, todo.CategoryID, true); err != nil {
+		return nil, err
+	}
+
+	touched, err := s.repo.TouchTodo(ctx, req.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Deleted between the lookup and the update
+		return nil, ErrTodoNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to touch todo: %w", err)
+	}
+	return touched, nil
 }
 
 // DeleteTodo handles todo soft deletion with ownership/permission verification
-func (s *TodoServiceImpl) DeleteTodo(ctx context.Context, req dto.DeleteTodoRequest) error {
+func (s *TodoServiceImpl) DeleteTodo(ctx context.Context, req dto.DeleteTodoRequest) (*dto.DeleteTodoResponse, error) {
 	// Fetch existing todo
 	todo, err := s.repo.GetTodoByID(ctx, req.ID)
 	if err != nil {
-		return ErrTodoNotFound
+		return nil, ErrTodoNotFound
 	}
 
 	// Check if user has write permission for the category
 	if err := s.checkCategoryPermission(ctx, req.UserID, todo.CategoryID, true); err != nil {
-		return err
+		return nil, err
 	}
 
-	// Soft delete the todo
-	if err := s.repo.DeleteTodo(ctx, req.ID); err != nil {
-		return fmt.Errorf("failed to delete todo: %w", err)
+	// Soft delete the todo, reading back the deletion time the undo token is pinned to
+	var deletedAt time.Time
+	err = s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		if err := repos.Todos.DeleteTodo(ctx, req.ID); err != nil {
+			return fmt.Errorf("failed to delete todo: %w", err)
+		}
+		deleted, err := repos.Todos.GetTodoByIDIncludingDeleted(ctx, req.ID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch deleted todo: %w", err)
+		}
+		if deleted.DeletedAt == nil {
+			return ErrTodoNotFound
+		}
+		deletedAt = *deleted.DeletedAt
+		return recordEvent(ctx, repos.TodoEvents, req.ID, req.UserID, models.TodoEventDelete, nil)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	expiresAt := time.Now().Add(UndoDeleteWindow)
+	token, err := s.undoTokens.GenerateUndoToken(req.UserID, req.ID, deletedAt, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate undo token: %w", err)
+	}
+
+	return &dto.DeleteTodoResponse{UndoToken: token, UndoExpiresAt: expiresAt}, nil
+}
+
+// UndoDelete restores the todo named by an undo token from DeleteTodo
+// The token must have been issued to the same user and not be past its expiry, and it only undoes the
+// deletion it was issued for: once the todo is restored, or deleted again by anyone, it returns ErrTodoNotFound.
+// Write permission on the category is checked again, since a share may have been revoked since the delete.
+// A restore that would push the category past its todo limit returns ErrTodoLimitReached.
+func (s *TodoServiceImpl) UndoDelete(ctx context.Context, req dto.UndoDeleteRequest) (*models.Todo, error) {
+	token, err := s.undoTokens.ParseUndoToken(req.UndoToken)
+	if err != nil || token.UserID != req.UserID || time.Now().After(token.ExpiresAt) {
+		return nil, ErrInvalidUndoToken
+	}
+
+	err = s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		todo, err := repos.Todos.GetTodoByIDIncludingDeleted(ctx, token.TodoID)
+		if err != nil || todo.DeletedAt == nil || !todo.DeletedAt.Equal(token.DeletedAt) {
+			return ErrTodoNotFound
+		}
+		if err := checkCategoryAccess(ctx, repos.Categories, repos.CategoryShares, req.UserID, todo.CategoryID, true); err != nil {
+			return err
+		}
+		// The category may have filled up since the delete
+		if err := checkCategoryCapacity(ctx, repos, s.limits.MaxTodosPerCategory, todo.CategoryID, 1); err != nil {
+			return err
+		}
+
+		// Nothing to restore if the todo was purged or its category deleted
+		restored, err := repos.Todos.RestoreTodo(ctx, token.TodoID)
+		if err != nil {
+			return fmt.Errorf("failed to restore todo: %w", err)
+		}
+		if !restored {
+			return ErrTodoNotFound
+		}
+		return recordEvent(ctx, repos.TodoEvents, token.TodoID, req.UserID, models.TodoEventRestore, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := s.repo.GetTodoByID(ctx, token.TodoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch restored todo: %w", err)
+	}
+	return todo, nil
 }
 
-// GetTodosGroupedByCategory retrieves all accessible todos grouped by category
-func (s *TodoServiceImpl) GetTodosGroupedByCategory(ctx context.Context, userID uint) (*dto.TodosGroupedByCategoryResponse, error) {
+// RestoreAllTodos restores trashed todos in one transaction and returns how many were restored
+// A categoryID of 0 restores the user's own todos in every category; otherwise every trashed todo in that
+// category is restored, which requires write permission on it. Todos whose category was deleted stay trashed.
+// Every category must have room for its restored todos, otherwise nothing is restored.
+func (s *TodoServiceImpl) RestoreAllTodos(ctx context.Context, userID, categoryID uint) (int64, error) {
+	if categoryID != 0 {
+		if err := s.checkCategoryPermission(ctx, userID, categoryID, true); err != nil {
+			return 0, err
+		}
+	}
+
+	var restored int64
+	err := s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		todos, err := repos.Todos.GetDeletedTodos(ctx, userID, categoryID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch trashed todos: %w", err)
+		}
+		if len(todos) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(todos))
+		incoming := make(map[uint]int64)
+		for i, todo := range todos {
+			ids[i] = todo.ID
+			incoming[todo.CategoryID]++
+		}
+		// Categories are locked in ID order, so two restores sharing categories cannot deadlock
+		categoryIDs := make([]uint, 0, len(incoming))
+		for id := range incoming {
+			categoryIDs = append(categoryIDs, id)
+		}
+		slices.Sort(categoryIDs)
+		for _, id := range categoryIDs {
+			if err := checkCategoryCapacity(ctx, repos, s.limits.MaxTodosPerCategory, id, incoming[id]); err != nil {
+				return err
+			}
+		}
+
+		if restored, err = repos.Todos.RestoreTodos(ctx, ids); err != nil {
+			return fmt.Errorf("failed to restore todos: %w", err)
+		}
+
+		// The IDs are locked, so every one of them was restored and gets a history entry
+		return recordEvents(ctx, repos.TodoEvents, ids, userID, models.TodoEventRestore, nil)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return restored, nil
+}
+
+// BulkMoveTodos moves the todos in one category that match req.Completed into another in one transaction and
+// returns how many moved. Moved todos are reassigned to the target owner, as a single-todo move does, and each
+// gets a history entry. The target category's limit must fit every matching todo, otherwise nothing moves.
+func (s *TodoServiceImpl) BulkMoveTodos(ctx context.Context, req dto.BulkMoveTodosRequest) (int64, error) {
+	if req.FromCategoryID == req.ToCategoryID {
+		return 0, ErrSameCategory
+	}
+
+	if err := s.checkCategoryPermission(ctx, req.UserID, req.FromCategoryID, true); err != nil {
+		return 0, err
+	}
+	if err := s.checkCategoryPermission(ctx, req.UserID, req.ToCategoryID, true); err != nil {
+		return 0, err
+	}
+
+	target, err := s.categoryRepo.GetCategoryByID(ctx, req.ToCategoryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrCategoryNotFound
+		}
+		return 0, fmt.Errorf("failed to fetch category: %w", err)
+	}
+
+	var moved int64
+	err = s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		ids, err := repos.Todos.GetTodoIDsInCategory(ctx, req.FromCategoryID, req.Completed)
+		if err != nil {
+			return fmt.Errorf("failed to fetch todos to move: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := checkCategoryCapacity(ctx, repos, s.limits.MaxTodosPerCategory, req.ToCategoryID, int64(len(ids))); err != nil {
+			return err
+		}
+
+		if moved, err = repos.Todos.MoveTodos(ctx, ids, req.ToCategoryID, target.OwnerID); err != nil {
+			return fmt.Errorf("failed to move todos: %w", err)
+		}
+
+		// The IDs are locked, so every one of them moved and gets a history entry
+		return recordEvents(ctx, repos.TodoEvents, ids, req.UserID, models.TodoEventUpdate, []string{"category_id"})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+// GetTodosGroupedByCategory retrieves accessible todos grouped by category
+// scope limits the categories to those the user owns, those shared with them, or both (empty means all)
+// perCategory > 0 (capped at the maximum page size) keeps only each category's newest todos; a category that had
+// more gets a NextCursor for GetCategoryTodosAfter
+func (s *TodoServiceImpl) GetTodosGroupedByCategory(ctx context.Context, userID uint, scope string, perCategory int) (*dto.TodosGroupedByCategoryResponse, error) {
+	switch scope {
+	case "":
+		scope = ScopeAll
+	case ScopeAll, ScopeOwned, ScopeShared:
+	default:
+		return nil, ErrInvalidScope
+	}
+
 	// Get flat rows from repository
-	rows, err := s.categoryShareRepo.GetTodosGroupedByCategory(ctx, userID)
+	rows, err := s.categoryShareRepo.GetTodosGroupedByCategory(ctx, userID, scope)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch todos grouped by category: %w", err)
 	}
@@ -326,24 +1058,299 @@ func (s *TodoServiceImpl) GetTodosGroupedByCategory(ctx context.Context, userID
 				Completed:   row.TodoCompleted,
 				CreatedBy:   row.TodoCreatedBy,
 				CreatorName: row.TodoCreatorName,
-			}
-			if row.TodoCreatedAt != nil {
-				todoItem.CreatedAt = *row.TodoCreatedAt
-			}
-			if row.TodoUpdatedAt != nil {
-				todoItem.UpdatedAt = *row.TodoUpdatedAt
+				CreatedAt:   row.TodoCreatedAt,
+				UpdatedAt:   row.TodoUpdatedAt,
 			}
 			cat.Todos = append(cat.Todos, todoItem)
 		}
 	}
 
-	// Build response maintaining category order
+	// Build response in a deterministic order rather than the order rows arrived in
 	categories := make([]dto.CategoryWithTodos, 0, len(categoryOrder))
 	for _, catID := range categoryOrder {
 		categories = append(categories, *categoryMap[catID])
 	}
+	sortGroupedCategories(categories)
+
+	if perCategory > 0 {
+		perCategory = min(perCategory, s.pagination.MaxPageSize)
+		for i := range categories {
+			todos := categories[i].Todos
+			if len(todos) <= perCategory {
+				continue
+			}
+			categories[i].Todos = todos[:perCategory]
+			last := todos[perCategory-1]
+			categories[i].NextCursor = utils.TodoCursor{CreatedAt: *last.CreatedAt, ID: last.ID}.Encode()
+		}
+	}
 
 	return &dto.TodosGroupedByCategoryResponse{
 		Categories: categories,
 	}, nil
 }
+
+// sortGroupedCategories orders the categories the user owns first, then by name and ID, and each
+// category's todos newest first (ties broken by the higher ID)
+func sortGroupedCategories(categories []dto.CategoryWithTodos) {
+	slices.SortStableFunc(categories, func(a, b dto.CategoryWithTodos) int {
+		if aOwned, bOwned := a.UserPermission == "owner", b.UserPermission == "owner"; aOwned != bOwned {
+			if aOwned {
+				return -1
+			}
+			return 1
+		}
+		if c := cmp.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	// A todo without a creation time sorts as the zero time, after every dated todo
+	createdAt := func(todo dto.TodoInCategory) time.Time {
+		if todo.CreatedAt == nil {
+			return time.Time{}
+		}
+		return *todo.CreatedAt
+	}
+	for i := range categories {
+		slices.SortStableFunc(categories[i].Todos, func(a, b dto.TodoInCategory) int {
+			if c := createdAt(b).Compare(createdAt(a)); c != 0 {
+				return c
+			}
+			return cmp.Compare(b.ID, a.ID)
+		})
+	}
+}
+
+// GetRecentTodos lists the todos the user can access, owned or shared, most recently updated first.
+// A limit below 1 means DefaultRecentTodos; one above the configured maximum is lowered to it.
+func (s *TodoServiceImpl) GetRecentTodos(ctx context.Context, userID uint, limit int) ([]models.Todo, error) {
+	if limit < 1 {
+		limit = DefaultRecentTodos
+	}
+	if s.limits.MaxRecentTodos > 0 && limit > s.limits.MaxRecentTodos {
+		limit = s.limits.MaxRecentTodos
+	}
+
+	todos, err := s.repo.GetRecentTodos(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recent todos: %w", err)
+	}
+	return todos, nil
+}
+
+// GetTodoChanges lists the IDs of the user's accessible todos changed at or after since, oldest change first
+// Deleted todos are included as tombstones until they are purged; a zero since lists every todo
+func (s *TodoServiceImpl) GetTodoChanges(ctx context.Context, userID uint, since time.Time) ([]models.TodoChange, error) {
+	changes, err := s.repo.GetTodoChangesSince(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch todo changes: %w", err)
+	}
+	return changes, nil
+}
+
+// SyncTodos returns the user's accessible todos changed since the cursor, grouped into created, updated and
+// deleted, and the cursor to pass next time: the latest change returned. An empty cursor starts a full sync, which
+// lists every live todo as created and leaves out tombstones a new client has no copy of.
+func (s *TodoServiceImpl) SyncTodos(ctx context.Context, userID uint, cursor string) (*dto.TodoSyncResponse, error) {
+	var since time.Time
+	if cursor != "" {
+		parsed, err := utils.ParseSyncCursor(cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		since = parsed.ChangedAt
+	}
+
+	todos, err := s.repo.GetTodosChangedSince(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch changed todos: %w", err)
+	}
+
+	response := &dto.TodoSyncResponse{
+		Created:    []models.Todo{},
+		Updated:    []models.Todo{},
+		Deleted:    []uint{},
+		NextCursor: cursor,
+	}
+	for _, todo := range todos {
+		switch {
+		case todo.DeletedAt != nil:
+			if cursor != "" {
+				response.Deleted = append(response.Deleted, todo.ID)
+			}
+		case todo.CreatedAt.After(since):
+			response.Created = append(response.Created, todo)
+		default:
+			response.Updated = append(response.Updated, todo)
+		}
+	}
+
+	// Todos are ordered by change time, so the last one is the latest change seen
+	if len(todos) > 0 {
+		response.NextCursor = utils.SyncCursor{ChangedAt: todos[len(todos)-1].UpdatedAt}.Encode()
+	}
+	return response, nil
+}
+
+// userLocation returns the timezone the user's day boundaries are computed in, UTC when none is set
+func (s *TodoServiceImpl) userLocation(ctx context.Context, userID uint) (*time.Location, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+	if user == nil || user.Timezone == "" {
+		return time.UTC, nil
+	}
+	// A zone that was valid when saved can only fail to load if the server's tzdata changed; fall back to UTC
+	loc, err := loadTimezone(user.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// CleanupCompletedTodos soft deletes the user's own todos that were completed longer ago than req.OlderThan,
+// recording a history entry for each in the same transaction
+// With DryRun set, nothing is modified and the number of todos that would be deleted is returned
+func (s *TodoServiceImpl) CleanupCompletedTodos(ctx context.Context, req dto.CleanupTodosRequest) (int64, error) {
+	cutoff := time.Now().Add(-req.OlderThan)
+
+	if req.DryRun {
+		count, err := s.repo.CountCompletedTodosBefore(ctx, req.UserID, cutoff)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count completed todos: %w", err)
+		}
+		return count, nil
+	}
+
+	var count int64
+	err := s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		ids, err := repos.Todos.GetCompletedTodoIDsBefore(ctx, req.UserID, cutoff)
+		if err != nil {
+			return fmt.Errorf("failed to fetch completed todos: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if count, err = repos.Todos.DeleteTodos(ctx, ids); err != nil {
+			return fmt.Errorf("failed to clean up completed todos: %w", err)
+		}
+
+		// The IDs are locked, so every one of them was deleted and gets a history entry
+		return recordEvents(ctx, repos.TodoEvents, ids, req.UserID, models.TodoEventDelete, nil)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetCompletionReport counts the user's todos completed on each day from req.From to req.To inclusive
+// Every day in the window appears in the result, with a zero count when nothing was completed
+// Days run midnight to midnight in the user's timezone
+func (s *TodoServiceImpl) GetCompletionReport(ctx context.Context, req dto.CompletionReportRequest) (*dto.CompletionReport, error) {
+	// Count calendar days on the UTC dates, where every day is 24 hours long
+	fromDate := req.From.UTC().Truncate(24 * time.Hour)
+	toDate := req.To.UTC().Truncate(24 * time.Hour)
+	if toDate.Before(fromDate) {
+		return nil, ErrInvalidDateRange
+	}
+	days := int(toDate.Sub(fromDate)/(24*time.Hour)) + 1
+	if days > MaxReportDays {
+		return nil, ErrDateRangeTooLarge
+	}
+
+	loc, err := s.userLocation(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	from := time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 0, 0, 0, 0, loc)
+
+	counts, err := s.repo.CountCompletedTodosByDay(ctx, req.UserID, from, to.AddDate(0, 0, 1), loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count completed todos: %w", err)
+	}
+
+	byDay := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		byDay[c.Day.Format(time.DateOnly)] = c.Count
+	}
+
+	report := &dto.CompletionReport{
+		From: from,
+		To:   to,
+		Days: make([]dto.DayCompletionCount, 0, days),
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		report.Days = append(report.Days, dto.DayCompletionCount{Date: date, Count: byDay[date]})
+		report.Total += byDay[date]
+	}
+	return report, nil
+}
+
+// GetTodoHistory retrieves a todo's change history, newest first, for a user who can read the todo
+func (s *TodoServiceImpl) GetTodoHistory(ctx context.Context, req dto.GetTodoRequest, page, pageSize int) (*dto.TodoHistoryResponse, error) {
+	if _, err := s.GetTodoByID(ctx, req); err != nil {
+		return nil, err
+	}
+
+	// Normalize pagination parameters using config values
+	page = max(page, 1)
+	if pageSize < 1 {
+		pageSize = s.pagination.DefaultPageSize
+	}
+	pageSize = min(pageSize, s.pagination.MaxPageSize)
+
+	events, total, err := s.eventRepo.GetTodoEvents(ctx, req.ID, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch todo history: %w", err)
+	}
+
+	totalPages := (total + int64(pageSize) - 1) / int64(pageSize)
+
+	return &dto.TodoHistoryResponse{
+		Events:     events,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// CompleteAllInCategory marks every todo in a category as completed (or not completed) for a user with
+// write access in one transaction and returns the number of todos whose state changed. Each of them gets
+// a history entry.
+func (s *TodoServiceImpl) CompleteAllInCategory(ctx context.Context, userID, categoryID uint, completed bool) (int64, error) {
+	if err := s.checkCategoryPermission(ctx, userID, categoryID, true); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err := s.txManager.WithinTx(ctx, func(repos repository.RepoSet) error {
+		// Only the todos whose state changes are locked, updated and recorded
+		current := !completed
+		ids, err := repos.Todos.GetTodoIDsInCategory(ctx, categoryID, &current)
+		if err != nil {
+			return fmt.Errorf("failed to fetch todos to update: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if count, err = repos.Todos.SetCompleted(ctx, ids, completed); err != nil {
+			return fmt.Errorf("failed to update todos: %w", err)
+		}
+
+		// The IDs are locked, so every one of them changed and gets a history entry
+		return recordEvents(ctx, repos.TodoEvents, ids, userID, models.TodoEventUpdate, []string{"completed"})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -1,17 +1,26 @@
 package services
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"todo-app/internal/dto"
 	"todo-app/internal/models"
+	"todo-app/internal/repository"
 	"todo-app/internal/repository/mocks"
+	"todo-app/pkg/utils"
 )
 
+// Signs the undo tokens of every test TodoService
+var testUndoTokens, _ = utils.NewUndoTokenManager("test-secret")
+
 // Helper to create a TodoService with all required mocks
 func createTestTodoService(
 	todoRepo *mocks.MockTodoRepository,
@@ -24,7 +33,19 @@ func createTestTodoService(
 	if categoryShareRepo == nil {
 		categoryShareRepo = &mocks.MockCategoryShareRepository{}
 	}
-	return NewTodoService(todoRepo, categoryRepo, categoryShareRepo, PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100})
+	eventRepo := &mocks.MockTodoEventRepository{}
+	txManager := mockTodoTx(todoRepo, eventRepo)
+	txManager.Repos.Categories = categoryRepo
+	txManager.Repos.CategoryShares = categoryShareRepo
+	return NewTodoService(todoRepo, categoryRepo, categoryShareRepo, eventRepo, &mocks.MockUserRepository{}, txManager, PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+}
+
+// Helper to run a TodoService's transactions against the same mocks the service was given
+func mockTodoTx(todoRepo *mocks.MockTodoRepository, eventRepo *mocks.MockTodoEventRepository) *mocks.MockTxManager {
+	if eventRepo == nil {
+		eventRepo = &mocks.MockTodoEventRepository{}
+	}
+	return &mocks.MockTxManager{Repos: repository.RepoSet{Todos: todoRepo, Categories: &mocks.MockCategoryRepository{}, TodoEvents: eventRepo}}
 }
 
 // Default category mock that returns owner permission
@@ -155,13 +176,282 @@ func TestTodoService_CreateTodo(t *testing.T) {
 	}
 }
 
+func TestTodoService_CreateTodo_AutoCreateCategories(t *testing.T) {
+	tests := []struct {
+		name        string
+		autoCreate  bool
+		wantErr     error
+		wantCreated bool
+	}{
+		{name: "enabled creates the missing category", autoCreate: true, wantCreated: true},
+		{name: "disabled rejects the missing category", autoCreate: false, wantErr: ErrCategoryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			categoryRepo := &mocks.MockCategoryRepository{
+				GetCategoryByNameAndOwnerFunc: func(ctx context.Context, ownerID uint, name string) (*models.Category, error) {
+					return nil, sql.ErrNoRows
+				},
+				CreateCategoryFunc: func(ctx context.Context, category *models.Category) error {
+					created = true
+					category.ID = 7
+					return nil
+				},
+			}
+			todoRepo := &mocks.MockTodoRepository{
+				CreateTodoFunc: func(ctx context.Context, todo *models.Todo) error {
+					todo.ID = 1
+					return nil
+				},
+			}
+			service := NewTodoService(todoRepo, categoryRepo, &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, &mocks.MockUserRepository{}, mockTodoTx(todoRepo, nil),
+				PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, tt.autoCreate, testUndoTokens)
+
+			todo, err := service.CreateTodo(context.Background(), dto.CreateTodoRequest{Title: "Test Todo", Category: "NewCategory", UserID: 1})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateTodo() error = %v, want %v", err, tt.wantErr)
+			}
+			if created != tt.wantCreated {
+				t.Errorf("CreateCategory() called = %v, want %v", created, tt.wantCreated)
+			}
+			if tt.wantErr == nil && todo.CategoryID != 7 {
+				t.Errorf("CreateTodo() todo.CategoryID = %v, want 7", todo.CategoryID)
+			}
+		})
+	}
+}
+
+func TestTodoService_CreateTodo_CategoryID(t *testing.T) {
+	// Category 1 is owned by user 1, category 2 by user 2 and not shared; category 99 does not exist
+	categoryRepo := &mocks.MockCategoryRepository{
+		GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+			switch id {
+			case 1:
+				return &models.Category{ID: 1, Name: "Mine", OwnerID: 1, AllowDuplicateTitles: true}, nil
+			case 2:
+				return &models.Category{ID: 2, Name: "Theirs", OwnerID: 2, AllowDuplicateTitles: true}, nil
+			}
+			return nil, sql.ErrNoRows
+		},
+	}
+
+	tests := []struct {
+		name        string
+		categoryID  uint
+		expectedErr error
+	}{
+		{name: "own category", categoryID: 1},
+		{name: "existing category without access", categoryID: 2, expectedErr: ErrForbidden},
+		{name: "nonexistent category", categoryID: 99, expectedErr: ErrCategoryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			categoryShareRepo := &mocks.MockCategoryShareRepository{
+				GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+					if categoryID == 99 {
+						t.Error("permission should not be checked for a category that does not exist")
+					}
+					return "none", nil
+				},
+			}
+			service := createTestTodoService(&mocks.MockTodoRepository{}, categoryRepo, categoryShareRepo)
+
+			categoryID := tt.categoryID
+			_, err := service.CreateTodo(context.Background(), dto.CreateTodoRequest{
+				Title:      "Task",
+				CategoryID: &categoryID,
+				UserID:     1,
+			})
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("CreateTodo() error = %v, want %v", err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestTodoService_CreateTodo_DuplicateTitles(t *testing.T) {
+	tests := []struct {
+		name                 string
+		allowDuplicateTitles bool
+		titleExists          bool
+		expectedErr          error
+		expectLookup         bool
+	}{
+		{
+			name:                 "duplicates allowed - lookup skipped",
+			allowDuplicateTitles: true,
+			titleExists:          true,
+			expectedErr:          nil,
+			expectLookup:         false,
+		},
+		{
+			name:                 "duplicates disallowed - unique title",
+			allowDuplicateTitles: false,
+			titleExists:          false,
+			expectedErr:          nil,
+			expectLookup:         true,
+		},
+		{
+			name:                 "duplicates disallowed - existing title",
+			allowDuplicateTitles: false,
+			titleExists:          true,
+			expectedErr:          ErrDuplicateTodo,
+			expectLookup:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookedUp := false
+			created := false
+			todoRepo := &mocks.MockTodoRepository{
+				HasTodoWithTitleFunc: func(ctx context.Context, categoryID uint, title string) (bool, error) {
+					lookedUp = true
+					if title != "Buy milk" {
+						t.Errorf("HasTodoWithTitle() title = %q, want trimmed %q", title, "Buy milk")
+					}
+					return tt.titleExists, nil
+				},
+				CreateTodoFunc: func(ctx context.Context, todo *models.Todo) error {
+					created = true
+					return nil
+				},
+			}
+			categoryRepo := &mocks.MockCategoryRepository{
+				GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+					return &models.Category{ID: id, Name: "Groceries", OwnerID: 1, AllowDuplicateTitles: tt.allowDuplicateTitles}, nil
+				},
+			}
+
+			service := createTestTodoService(todoRepo, categoryRepo, nil)
+			categoryID := uint(1)
+			_, err := service.CreateTodo(context.Background(), dto.CreateTodoRequest{
+				Title:      " Buy milk ",
+				CategoryID: &categoryID,
+				UserID:     1,
+			})
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("CreateTodo() error = %v, want %v", err, tt.expectedErr)
+			}
+			if lookedUp != tt.expectLookup {
+				t.Errorf("CreateTodo() duplicate lookup = %v, want %v", lookedUp, tt.expectLookup)
+			}
+			if created != (tt.expectedErr == nil) {
+				t.Errorf("CreateTodo() created = %v, want %v", created, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+func TestTodoService_CreateTodo_TodoLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxTodos    int
+		activeTodos int64
+		expectedErr error
+	}{
+		{name: "below limit", maxTodos: 3, activeTodos: 2},
+		{name: "limit reached", maxTodos: 3, activeTodos: 3, expectedErr: ErrTodoLimitReached},
+		{name: "limit disabled", maxTodos: 0, activeTodos: 5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, locked := false, false
+			todoRepo := &mocks.MockTodoRepository{
+				// The repository count excludes soft-deleted todos
+				CountTodosInCategoryFunc: func(ctx context.Context, categoryID uint) (int64, error) {
+					if !locked {
+						t.Error("CreateTodo() counted todos without locking the category")
+					}
+					return tt.activeTodos, nil
+				},
+				CreateTodoFunc: func(ctx context.Context, todo *models.Todo) error {
+					created = true
+					return nil
+				},
+			}
+			txManager := mockTodoTx(todoRepo, nil)
+			txManager.Repos.Categories = &mocks.MockCategoryRepository{
+				LockCategoryFunc: func(ctx context.Context, id uint) error {
+					locked = id == 1
+					return nil
+				},
+			}
+			service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, &mocks.MockUserRepository{}, txManager,
+				PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{MaxTodosPerCategory: tt.maxTodos}, true, testUndoTokens)
+
+			categoryID := uint(1)
+			_, err := service.CreateTodo(context.Background(), dto.CreateTodoRequest{
+				Title:      "Buy milk",
+				CategoryID: &categoryID,
+				UserID:     1,
+			})
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("CreateTodo() error = %v, want %v", err, tt.expectedErr)
+			}
+			if created != (tt.expectedErr == nil) {
+				t.Errorf("CreateTodo() created = %v, want %v", created, tt.expectedErr == nil)
+			}
+		})
+	}
+}
+
+func TestTodoService_CreateTodo_HistoryInTransaction(t *testing.T) {
+	historyErr := errors.New("history insert failed")
+	inTx := false
+	todoRepo := &mocks.MockTodoRepository{
+		CreateTodoFunc: func(ctx context.Context, todo *models.Todo) error {
+			if !inTx {
+				t.Error("CreateTodo() wrote the todo outside the transaction")
+			}
+			todo.ID = 1
+			return nil
+		},
+	}
+	eventRepo := &mocks.MockTodoEventRepository{
+		CreateTodoEventFunc: func(ctx context.Context, event *models.TodoEvent) error {
+			if !inTx {
+				t.Error("CreateTodo() recorded history outside the transaction")
+			}
+			return historyErr
+		},
+	}
+	txManager := &mocks.MockTxManager{
+		WithinTxFunc: func(ctx context.Context, fn func(repos repository.RepoSet) error) error {
+			inTx = true
+			defer func() { inTx = false }()
+			return fn(repository.RepoSet{Todos: todoRepo, TodoEvents: eventRepo})
+		},
+	}
+	service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, eventRepo, &mocks.MockUserRepository{}, txManager,
+		PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+
+	categoryID := uint(1)
+	_, err := service.CreateTodo(context.Background(), dto.CreateTodoRequest{
+		Title:      "Buy milk",
+		CategoryID: &categoryID,
+		UserID:     1,
+	})
+
+	// The failed history write rolls the transaction back, so the create must fail too
+	if !errors.Is(err, historyErr) {
+		t.Errorf("CreateTodo() error = %v, want %v", err, historyErr)
+	}
+}
+
 func TestTodoService_GetTodos(t *testing.T) {
 	tests := []struct {
 		name      string
 		userID    uint
 		page      int
 		pageSize  int
-		mockFunc  func(ctx context.Context, userID uint, page, pageSize int) ([]models.Todo, int64, error)
+		mockFunc  func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error)
 		wantCount int
 		wantErr   bool
 	}{
@@ -170,7 +460,7 @@ func TestTodoService_GetTodos(t *testing.T) {
 			userID:   1,
 			page:     1,
 			pageSize: 10,
-			mockFunc: func(ctx context.Context, userID uint, page, pageSize int) ([]models.Todo, int64, error) {
+			mockFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error) {
 				return []models.Todo{
 					{ID: 1, Title: "Todo 1", UserID: userID, CategoryID: 1},
 					{ID: 2, Title: "Todo 2", UserID: userID, CategoryID: 1},
@@ -184,7 +474,7 @@ func TestTodoService_GetTodos(t *testing.T) {
 			userID:   1,
 			page:     1,
 			pageSize: 10,
-			mockFunc: func(ctx context.Context, userID uint, page, pageSize int) ([]models.Todo, int64, error) {
+			mockFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error) {
 				return []models.Todo{}, 0, nil
 			},
 			wantCount: 0,
@@ -195,7 +485,7 @@ func TestTodoService_GetTodos(t *testing.T) {
 			userID:   1,
 			page:     1,
 			pageSize: 10,
-			mockFunc: func(ctx context.Context, userID uint, page, pageSize int) ([]models.Todo, int64, error) {
+			mockFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error) {
 				return nil, 0, errors.New("database error")
 			},
 			wantErr: true,
@@ -205,7 +495,7 @@ func TestTodoService_GetTodos(t *testing.T) {
 			userID:   1,
 			page:     -1,
 			pageSize: 10,
-			mockFunc: func(ctx context.Context, userID uint, page, pageSize int) ([]models.Todo, int64, error) {
+			mockFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error) {
 				if page != 1 {
 					t.Errorf("Expected page to be normalized to 1, got %d", page)
 				}
@@ -223,7 +513,7 @@ func TestTodoService_GetTodos(t *testing.T) {
 			}
 			service := createTestTodoService(repo, nil, nil)
 
-			result, err := service.GetTodos(context.Background(), tt.userID, tt.page, tt.pageSize)
+			result, err := service.GetTodos(context.Background(), tt.userID, "", tt.page, tt.pageSize)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetTodos() error = %v, wantErr %v", err, tt.wantErr)
@@ -237,6 +527,291 @@ func TestTodoService_GetTodos(t *testing.T) {
 	}
 }
 
+func TestTodoService_GetTodos_Sort(t *testing.T) {
+	tests := []struct {
+		name    string
+		sortBy  models.TodoSort
+		want    models.TodoSort
+		wantErr error
+	}{
+		{name: "configured default applies", sortBy: "", want: models.TodoSortTitleAsc},
+		{name: "requested sort overrides the default", sortBy: models.TodoSortUpdatedAtDesc, want: models.TodoSortUpdatedAtDesc},
+		{name: "invalid sort", sortBy: "priority:asc", wantErr: ErrInvalidSort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got models.TodoSort
+			repo := &mocks.MockTodoRepository{
+				GetTodosFunc: func(ctx context.Context, userID uint, sortBy models.TodoSort, page, pageSize int) ([]models.Todo, int64, error) {
+					got = sortBy
+					return []models.Todo{}, 0, nil
+				},
+			}
+			service := NewTodoService(repo, &mocks.MockCategoryRepository{}, &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, &mocks.MockUserRepository{}, mockTodoTx(repo, nil),
+				PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortTitleAsc}, LimitsConfig{}, true, testUndoTokens)
+
+			_, err := service.GetTodos(context.Background(), 1, tt.sortBy, 1, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetTodos() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetTodos() repository sort = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetTodosGroupedByCategory_Scope(t *testing.T) {
+	tests := []struct {
+		name      string
+		scope     string
+		wantScope string
+		wantErr   error
+	}{
+		{name: "default is all", scope: "", wantScope: ScopeAll},
+		{name: "owned", scope: ScopeOwned, wantScope: ScopeOwned},
+		{name: "shared", scope: ScopeShared, wantScope: ScopeShared},
+		{name: "invalid", scope: "mine", wantErr: ErrInvalidScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotScope string
+			shareRepo := &mocks.MockCategoryShareRepository{
+				GetTodosGroupedByCategoryFunc: func(ctx context.Context, userID uint, scope string) ([]models.CategoryWithTodosRow, error) {
+					gotScope = scope
+					return nil, nil
+				},
+			}
+			service := createTestTodoService(&mocks.MockTodoRepository{}, nil, shareRepo)
+
+			_, err := service.GetTodosGroupedByCategory(context.Background(), 1, tt.scope, 0)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetTodosGroupedByCategory() error = %v, want %v", err, tt.wantErr)
+			}
+			if gotScope != tt.wantScope {
+				t.Errorf("repository scope = %q, want %q", gotScope, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetTodosGroupedByCategory_Ordering(t *testing.T) {
+	at := func(s string) *time.Time {
+		ts, _ := time.Parse(time.RFC3339, s)
+		return &ts
+	}
+	// Rows arrive shuffled: shared categories before owned ones and todos out of date order
+	rows := []models.CategoryWithTodosRow{
+		{CategoryID: 4, CategoryName: "Alpha", UserPermission: "read", TodoID: 40, TodoCreatedAt: at("2024-01-01T00:00:00Z")},
+		{CategoryID: 2, CategoryName: "Work", UserPermission: "owner", TodoID: 20, TodoCreatedAt: at("2024-01-01T00:00:00Z")},
+		{CategoryID: 3, CategoryName: "Home", UserPermission: "owner"},
+		{CategoryID: 2, CategoryName: "Work", UserPermission: "owner", TodoID: 21, TodoCreatedAt: at("2024-03-01T00:00:00Z")},
+		{CategoryID: 5, CategoryName: "Alpha", UserPermission: "write", TodoID: 50, TodoCreatedAt: at("2024-01-01T00:00:00Z")},
+		{CategoryID: 2, CategoryName: "Work", UserPermission: "owner", TodoID: 22, TodoCreatedAt: at("2024-02-01T00:00:00Z")},
+		{CategoryID: 2, CategoryName: "Work", UserPermission: "owner", TodoID: 23, TodoCreatedAt: at("2024-02-01T00:00:00Z")},
+	}
+	shareRepo := &mocks.MockCategoryShareRepository{
+		GetTodosGroupedByCategoryFunc: func(ctx context.Context, userID uint, scope string) ([]models.CategoryWithTodosRow, error) {
+			return rows, nil
+		},
+	}
+	service := createTestTodoService(&mocks.MockTodoRepository{}, nil, shareRepo)
+
+	response, err := service.GetTodosGroupedByCategory(context.Background(), 1, ScopeAll, 0)
+	if err != nil {
+		t.Fatalf("GetTodosGroupedByCategory() error = %v", err)
+	}
+
+	var gotCategories []uint
+	for _, category := range response.Categories {
+		gotCategories = append(gotCategories, category.ID)
+	}
+	// Owned first (Home, Work), then shared by name with the ID breaking the tie between the two Alphas
+	if want := []uint{3, 2, 4, 5}; !reflect.DeepEqual(gotCategories, want) {
+		t.Errorf("category order = %v, want %v", gotCategories, want)
+	}
+
+	var gotTodos []uint
+	for _, todo := range response.Categories[1].Todos {
+		gotTodos = append(gotTodos, todo.ID)
+	}
+	if want := []uint{21, 23, 22, 20}; !reflect.DeepEqual(gotTodos, want) {
+		t.Errorf("todo order in Work = %v, want %v", gotTodos, want)
+	}
+}
+
+func TestTodoService_GetTodosGroupedByCategory_NextCursor(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Category 1 has seven todos; 4 and 5 share a creation time so the ID has to break the tie
+	var todos []models.Todo
+	var rows []models.CategoryWithTodosRow
+	for id := uint(1); id <= 7; id++ {
+		createdAt := base.Add(time.Duration(id) * time.Hour)
+		if id == 5 {
+			createdAt = base.Add(4 * time.Hour)
+		}
+		todos = append(todos, models.Todo{ID: id, CategoryID: 1, CreatedAt: createdAt})
+		rows = append(rows, models.CategoryWithTodosRow{CategoryID: 1, CategoryName: "Work", UserPermission: "owner", TodoID: id, TodoCreatedAt: &createdAt})
+	}
+
+	shareRepo := &mocks.MockCategoryShareRepository{
+		GetTodosGroupedByCategoryFunc: func(ctx context.Context, userID uint, scope string) ([]models.CategoryWithTodosRow, error) {
+			return rows, nil
+		},
+	}
+	// Keyset over created_at DESC, id DESC, like the query
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodosByCategoryIDAfterFunc: func(ctx context.Context, categoryID uint, creator repository.TodoCreatorFilter, after *repository.TodoKeyset, limit int) ([]models.Todo, error) {
+			sorted := slices.Clone(todos)
+			slices.SortFunc(sorted, func(a, b models.Todo) int {
+				if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+					return c
+				}
+				return cmp.Compare(b.ID, a.ID)
+			})
+			var page []models.Todo
+			for _, todo := range sorted {
+				if after != nil && !todo.CreatedAt.Before(after.CreatedAt) && !(todo.CreatedAt.Equal(after.CreatedAt) && todo.ID < after.ID) {
+					continue
+				}
+				if len(page) == limit {
+					break
+				}
+				page = append(page, todo)
+			}
+			return page, nil
+		},
+	}
+	service := createTestTodoService(todoRepo, defaultCategoryMock(1), shareRepo)
+
+	grouped, err := service.GetTodosGroupedByCategory(context.Background(), 1, ScopeAll, 3)
+	if err != nil {
+		t.Fatalf("GetTodosGroupedByCategory() error = %v", err)
+	}
+	category := grouped.Categories[0]
+	seen := []uint{}
+	for _, todo := range category.Todos {
+		seen = append(seen, todo.ID)
+	}
+	if category.NextCursor == "" {
+		t.Fatal("GetTodosGroupedByCategory() NextCursor is empty, want a cursor for the remaining todos")
+	}
+
+	req := dto.ListCategoryTodosRequest{CategoryID: 1, UserID: 1}
+	cursor := category.NextCursor
+	for cursor != "" {
+		page, err := service.GetCategoryTodosAfter(context.Background(), req, cursor, 3)
+		if err != nil {
+			t.Fatalf("GetCategoryTodosAfter() error = %v", err)
+		}
+		for _, todo := range page.Todos {
+			seen = append(seen, todo.ID)
+		}
+		cursor = page.NextCursor
+	}
+
+	if want := []uint{7, 6, 5, 4, 3, 2, 1}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("todos across pages = %v, want %v without gaps or overlap", seen, want)
+	}
+}
+
+func TestTodoService_GetCategoryTodosAfter_Errors(t *testing.T) {
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+			return "none", nil
+		},
+	}
+	service := createTestTodoService(&mocks.MockTodoRepository{}, defaultCategoryMock(1), categoryShareRepo)
+
+	if _, err := service.GetCategoryTodosAfter(context.Background(), dto.ListCategoryTodosRequest{CategoryID: 1, UserID: 1}, "bogus", 10); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("GetCategoryTodosAfter() with a bad cursor error = %v, want ErrInvalidCursor", err)
+	}
+	if _, err := service.GetCategoryTodosAfter(context.Background(), dto.ListCategoryTodosRequest{CategoryID: 1, UserID: 2}, "", 10); !errors.Is(err, ErrForbidden) {
+		t.Errorf("GetCategoryTodosAfter() without access error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestTodoService_GetTodosByCategoryID_CreatorFilter(t *testing.T) {
+	// Category 1 is owned by user 1 and shared with user 2, user 3 has no access
+	todos := []models.Todo{
+		{ID: 1, Title: "Owner task", CategoryID: 1, UserID: 1, CreatedBy: 1},
+		{ID: 2, Title: "Shared task", CategoryID: 1, UserID: 1, CreatedBy: 2},
+	}
+
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodosByCategoryIDFunc: func(ctx context.Context, categoryID uint, creator repository.TodoCreatorFilter, page, pageSize int) ([]models.Todo, int64, error) {
+			var matched []models.Todo
+			for _, todo := range todos {
+				if creator.CreatedBy != 0 && todo.CreatedBy != creator.CreatedBy {
+					continue
+				}
+				if creator.NotCreatedBy != 0 && todo.CreatedBy == creator.NotCreatedBy {
+					continue
+				}
+				matched = append(matched, todo)
+			}
+			return matched, int64(len(matched)), nil
+		},
+	}
+
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+			if userID == 2 {
+				return "read", nil
+			}
+			return "", sql.ErrNoRows
+		},
+	}
+
+	service := createTestTodoService(todoRepo, defaultCategoryMock(1), categoryShareRepo)
+
+	tests := []struct {
+		name        string
+		userID      uint
+		createdBy   string
+		wantTitles  []string
+		expectedErr error
+	}{
+		{name: "no filter", userID: 2, wantTitles: []string{"Owner task", "Shared task"}},
+		{name: "created by me", userID: 2, createdBy: "me", wantTitles: []string{"Shared task"}},
+		{name: "created by others", userID: 2, createdBy: "others", wantTitles: []string{"Owner task"}},
+		{name: "created by user id", userID: 1, createdBy: "2", wantTitles: []string{"Shared task"}},
+		{name: "user id filter without access", userID: 3, createdBy: "1", expectedErr: ErrForbidden},
+		{name: "invalid filter", userID: 2, createdBy: "someone", expectedErr: ErrInvalidCreator},
+		{name: "zero user id", userID: 2, createdBy: "0", expectedErr: ErrInvalidCreator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.GetTodosByCategoryID(context.Background(), dto.ListCategoryTodosRequest{
+				CategoryID: 1,
+				UserID:     tt.userID,
+				CreatedBy:  tt.createdBy,
+			}, 1, 10)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("GetTodosByCategoryID() error = %v, expected %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTodosByCategoryID() unexpected error: %v", err)
+			}
+
+			titles := make([]string, 0, len(resp.Todos))
+			for _, todo := range resp.Todos {
+				titles = append(titles, todo.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("GetTodosByCategoryID() titles = %v, want %v", titles, tt.wantTitles)
+			}
+		})
+	}
+}
+
 func TestTodoService_GetTodoByID(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -348,29 +923,285 @@ func TestTodoService_GetTodoByID(t *testing.T) {
 	}
 }
 
-func TestTodoService_UpdateTodo(t *testing.T) {
-	title := "Updated Title"
-	completed := true
+func TestTodoService_GetTodoByID_IncludeDeleted(t *testing.T) {
+	deletedAt := time.Now().Add(-time.Hour)
+
+	// User 1 owns category 1; user 2 has a write share and user 3 a read share
+	permissions := map[uint]string{1: "owner", 2: "write", 3: "read"}
 
 	tests := []struct {
-		name             string
-		req              dto.UpdateTodoRequest
-		existingTodo     *models.Todo
-		categoryOwnerID  uint
-		sharedPermission string
-		updateErr        error
-		wantErr          bool
-		expectedErr      error
+		name          string
+		req           dto.GetTodoRequest
+		deleted       bool
+		wantErr       error
+		wantDeletedAt bool
 	}{
 		{
-			name: "successful update - owner",
-			req: dto.UpdateTodoRequest{
-				ID:        1,
-				UserID:    1,
-				Title:     &title,
-				Completed: &completed,
-			},
-			existingTodo: &models.Todo{
+			name:          "owner sees deleted todo",
+			req:           dto.GetTodoRequest{ID: 1, UserID: 1, IncludeDeleted: true},
+			deleted:       true,
+			wantDeletedAt: true,
+		},
+		{
+			name:          "writer sees deleted todo",
+			req:           dto.GetTodoRequest{ID: 1, UserID: 2, IncludeDeleted: true},
+			deleted:       true,
+			wantDeletedAt: true,
+		},
+		{
+			name:    "reader gets not found for deleted todo",
+			req:     dto.GetTodoRequest{ID: 1, UserID: 3, IncludeDeleted: true},
+			deleted: true,
+			wantErr: ErrTodoNotFound,
+		},
+		{
+			name:    "user without access gets not found for deleted todo",
+			req:     dto.GetTodoRequest{ID: 1, UserID: 4, IncludeDeleted: true},
+			deleted: true,
+			wantErr: ErrTodoNotFound,
+		},
+		{
+			name: "reader sees live todo",
+			req:  dto.GetTodoRequest{ID: 1, UserID: 3, IncludeDeleted: true},
+		},
+		{
+			name:    "deleted todo is not found without the flag",
+			req:     dto.GetTodoRequest{ID: 1, UserID: 1},
+			deleted: true,
+			wantErr: ErrTodoNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &models.Todo{ID: 1, Title: "Test Todo", UserID: 1, CategoryID: 1}
+			if tt.deleted {
+				todo.DeletedAt = &deletedAt
+			}
+
+			todoRepo := &mocks.MockTodoRepository{
+				GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+					if todo.DeletedAt != nil {
+						return nil, sql.ErrNoRows
+					}
+					return todo, nil
+				},
+				GetTodoByIDIncludingDeletedFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+					return todo, nil
+				},
+			}
+			categoryShareRepo := &mocks.MockCategoryShareRepository{
+				GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+					if perm, ok := permissions[userID]; ok {
+						return perm, nil
+					}
+					return "none", nil
+				},
+			}
+
+			service := createTestTodoService(todoRepo, defaultCategoryMock(1), categoryShareRepo)
+
+			got, err := service.GetTodoByID(context.Background(), tt.req)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetTodoByID() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetTodoByID() unexpected error = %v", err)
+			}
+			if (got.DeletedAt != nil) != tt.wantDeletedAt {
+				t.Errorf("GetTodoByID() DeletedAt = %v, want set %v", got.DeletedAt, tt.wantDeletedAt)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetTodosByIDs(t *testing.T) {
+	// User 1 owns category 10, has a read share on 20 and no access to 30
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodosByIDsFunc: func(ctx context.Context, ids []uint) ([]models.Todo, error) {
+			return []models.Todo{
+				{ID: 1, CategoryID: 10},
+				{ID: 2, CategoryID: 30},
+				{ID: 3, CategoryID: 20},
+				{ID: 5, CategoryID: 10},
+			}, nil
+		},
+	}
+	categoryRepo := &mocks.MockCategoryRepository{
+		GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+			ownerID := uint(2)
+			if id == 10 {
+				ownerID = 1
+			}
+			return &models.Category{ID: id, OwnerID: ownerID}, nil
+		},
+	}
+	permissionChecks := 0
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+			permissionChecks++
+			if categoryID == 20 {
+				return "read", nil
+			}
+			return "none", nil
+		},
+	}
+	service := createTestTodoService(todoRepo, categoryRepo, categoryShareRepo)
+
+	response, err := service.GetTodosByIDs(context.Background(), 1, []uint{5, 2, 4, 3, 1, 5})
+	if err != nil {
+		t.Fatalf("GetTodosByIDs() error = %v", err)
+	}
+
+	var gotIDs []uint
+	for _, todo := range response.Todos {
+		gotIDs = append(gotIDs, todo.ID)
+	}
+	if !reflect.DeepEqual(gotIDs, []uint{5, 3, 1}) {
+		t.Errorf("GetTodosByIDs() todos = %v, want [5 3 1]", gotIDs)
+	}
+	if !reflect.DeepEqual(response.Forbidden, []uint{2}) {
+		t.Errorf("GetTodosByIDs() forbidden = %v, want [2]", response.Forbidden)
+	}
+	if !reflect.DeepEqual(response.NotFound, []uint{4}) {
+		t.Errorf("GetTodosByIDs() not found = %v, want [4]", response.NotFound)
+	}
+	if permissionChecks != 2 {
+		t.Errorf("share permission checked %d times, want once per shared category", permissionChecks)
+	}
+}
+
+func TestTodoService_ExpandTodos(t *testing.T) {
+	todos := []models.Todo{
+		{ID: 1, Title: "A", CategoryID: 10},
+		{ID: 2, Title: "B", CategoryID: 20},
+		{ID: 3, Title: "C", CategoryID: 10},
+	}
+
+	t.Run("categories batch-loaded in one query", func(t *testing.T) {
+		calls := 0
+		categoryRepo := &mocks.MockCategoryRepository{
+			GetCategoriesByIDsFunc: func(ctx context.Context, ids []uint) ([]models.Category, error) {
+				calls++
+				if len(ids) != 2 {
+					t.Errorf("GetCategoriesByIDs() ids = %v, want the 2 distinct category IDs", ids)
+				}
+				return []models.Category{{ID: 10, Name: "Work"}, {ID: 20, Name: "Home"}}, nil
+			},
+			GetCategoryByIDFunc: func(ctx context.Context, id uint) (*models.Category, error) {
+				t.Error("categories should not be loaded one at a time")
+				return nil, sql.ErrNoRows
+			},
+		}
+		service := createTestTodoService(&mocks.MockTodoRepository{}, categoryRepo, nil)
+
+		expanded, err := service.ExpandTodos(context.Background(), todos, dto.TodoExpand{Category: true})
+		if err != nil {
+			t.Fatalf("ExpandTodos() error = %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("GetCategoriesByIDs() called %d times, want 1", calls)
+		}
+
+		want := []string{"Work", "Home", "Work"}
+		for i, todo := range expanded {
+			if todo.ID != todos[i].ID || todo.Category == nil || todo.Category.Name != want[i] {
+				t.Errorf("expanded[%d] = %+v, want todo %d in category %q", i, todo, todos[i].ID, want[i])
+			}
+		}
+	})
+
+	t.Run("creators batch-loaded for todos in a shared category", func(t *testing.T) {
+		// User 1 owns the category; user 2 has write access and created the second todo
+		shared := []models.Todo{
+			{ID: 1, Title: "Owner task", CategoryID: 10, UserID: 1, CreatedBy: 1},
+			{ID: 2, Title: "Shared task", CategoryID: 10, UserID: 1, CreatedBy: 2},
+		}
+		calls := 0
+		userRepo := &mocks.MockUserRepository{
+			GetUsersByIDsFunc: func(ctx context.Context, ids []uint) ([]models.User, error) {
+				calls++
+				return []models.User{
+					{ID: 1, Name: "Owner", Email: "owner@test.com"},
+					{ID: 2, Name: "Collaborator", Email: "collab@test.com"},
+				}, nil
+			},
+			GetUserByIDFunc: func(ctx context.Context, id uint) (*models.User, error) {
+				t.Error("creators should not be loaded one at a time")
+				return nil, sql.ErrNoRows
+			},
+		}
+		service := NewTodoService(&mocks.MockTodoRepository{}, &mocks.MockCategoryRepository{}, &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, userRepo, &mocks.MockTxManager{},
+			PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+
+		expanded, err := service.ExpandTodos(context.Background(), shared, dto.TodoExpand{Creator: true})
+		if err != nil {
+			t.Fatalf("ExpandTodos() error = %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("GetUsersByIDs() called %d times, want 1", calls)
+		}
+
+		creator := expanded[1].Creator
+		if creator == nil || creator.ID != 2 || creator.Name != "Collaborator" || creator.Email != "collab@test.com" {
+			t.Errorf("expanded[1].Creator = %+v, want the collaborator", creator)
+		}
+		if expanded[0].Creator == nil || expanded[0].Creator.Name != "Owner" {
+			t.Errorf("expanded[0].Creator = %+v, want the owner", expanded[0].Creator)
+		}
+		if expanded[1].Category != nil {
+			t.Errorf("expanded[1].Category = %+v, want none without expand=category", expanded[1].Category)
+		}
+	})
+
+	t.Run("nothing loaded when not requested", func(t *testing.T) {
+		categoryRepo := &mocks.MockCategoryRepository{
+			GetCategoriesByIDsFunc: func(ctx context.Context, ids []uint) ([]models.Category, error) {
+				t.Error("GetCategoriesByIDs() should not be called without expand=category")
+				return nil, nil
+			},
+		}
+		service := createTestTodoService(&mocks.MockTodoRepository{}, categoryRepo, nil)
+
+		expanded, err := service.ExpandTodos(context.Background(), todos, dto.TodoExpand{})
+		if err != nil {
+			t.Fatalf("ExpandTodos() error = %v", err)
+		}
+		for _, todo := range expanded {
+			if todo.Category != nil {
+				t.Errorf("todo %d has category %+v, want none", todo.ID, todo.Category)
+			}
+		}
+	})
+}
+
+func TestTodoService_UpdateTodo(t *testing.T) {
+	title := "Updated Title"
+	completed := true
+
+	tests := []struct {
+		name             string
+		req              dto.UpdateTodoRequest
+		existingTodo     *models.Todo
+		categoryOwnerID  uint
+		sharedPermission string
+		updateErr        error
+		wantErr          bool
+		expectedErr      error
+	}{
+		{
+			name: "successful update - owner",
+			req: dto.UpdateTodoRequest{
+				ID:        1,
+				UserID:    1,
+				Title:     &title,
+				Completed: &completed,
+			},
+			existingTodo: &models.Todo{
 				ID:         1,
 				Title:      "Original",
 				UserID:     1,
@@ -460,7 +1291,7 @@ func TestTodoService_UpdateTodo(t *testing.T) {
 
 			service := createTestTodoService(todoRepo, categoryRepo, categoryShareRepo)
 
-			todo, err := service.UpdateTodo(context.Background(), tt.req)
+			todo, _, err := service.UpdateTodo(context.Background(), tt.req)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UpdateTodo() error = %v, wantErr %v", err, tt.wantErr)
@@ -483,6 +1314,191 @@ func TestTodoService_UpdateTodo(t *testing.T) {
 	}
 }
 
+func TestTodoService_UpdateTodo_TodoLimit(t *testing.T) {
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+			return &models.Todo{ID: id, Title: "Task", CategoryID: 1, UserID: 1}, nil
+		},
+		CountTodosInCategoryFunc: func(ctx context.Context, categoryID uint) (int64, error) {
+			if categoryID != 2 {
+				t.Errorf("CountTodosInCategory() categoryID = %d, want target 2", categoryID)
+			}
+			return 2, nil
+		},
+		UpdateTodoFunc: func(ctx context.Context, todo *models.Todo) error {
+			t.Error("UpdateTodo should not save a move into a full category")
+			return nil
+		},
+	}
+	service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, &mocks.MockUserRepository{}, mockTodoTx(todoRepo, nil),
+		PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{MaxTodosPerCategory: 2}, true, testUndoTokens)
+
+	targetID := uint(2)
+	_, _, err := service.UpdateTodo(context.Background(), dto.UpdateTodoRequest{ID: 1, UserID: 1, CategoryID: &targetID})
+	if !errors.Is(err, ErrTodoLimitReached) {
+		t.Errorf("UpdateTodo() error = %v, want %v", err, ErrTodoLimitReached)
+	}
+}
+
+func TestTodoService_UpdateTodo_Replace(t *testing.T) {
+	title := "Replaced"
+	categoryID := uint(1)
+
+	tests := []struct {
+		name            string
+		replace         bool
+		wantDescription string
+		wantCompleted   bool
+	}{
+		{name: "partial update keeps omitted fields", replace: false, wantDescription: "Keep me", wantCompleted: true},
+		{name: "replace resets omitted fields", replace: true, wantDescription: "", wantCompleted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := &mocks.MockTodoRepository{
+				GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+					return &models.Todo{ID: id, Title: "Original", Description: "Keep me", Completed: true, UserID: 1, CategoryID: 1}, nil
+				},
+			}
+			service := createTestTodoService(todoRepo, defaultCategoryMock(1), nil)
+
+			todo, _, err := service.UpdateTodo(context.Background(), dto.UpdateTodoRequest{
+				ID:         1,
+				UserID:     1,
+				Title:      &title,
+				CategoryID: &categoryID,
+				Replace:    tt.replace,
+			})
+			if err != nil {
+				t.Fatalf("UpdateTodo() unexpected error: %v", err)
+			}
+			if todo.Title != title || todo.Description != tt.wantDescription || todo.Completed != tt.wantCompleted {
+				t.Errorf("UpdateTodo() = (%q, %q, %v), want (%q, %q, %v)",
+					todo.Title, todo.Description, todo.Completed, title, tt.wantDescription, tt.wantCompleted)
+			}
+		})
+	}
+}
+
+func TestTodoService_UpdateTodo_RecordsHistory(t *testing.T) {
+	title := "Updated Title"
+	completed := true
+
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+			return &models.Todo{ID: id, Title: "Original", UserID: 1, CategoryID: 1}, nil
+		},
+	}
+
+	var events []models.TodoEvent
+	eventRepo := &mocks.MockTodoEventRepository{
+		CreateTodoEventFunc: func(ctx context.Context, event *models.TodoEvent) error {
+			events = append(events, *event)
+			return nil
+		},
+	}
+
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+			return "write", nil
+		},
+	}
+
+	service := NewTodoService(todoRepo, defaultCategoryMock(1), categoryShareRepo, eventRepo, &mocks.MockUserRepository{}, mockTodoTx(todoRepo, eventRepo), PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+
+	_, _, err := service.UpdateTodo(context.Background(), dto.UpdateTodoRequest{
+		ID:        1,
+		UserID:    2,
+		Title:     &title,
+		Completed: &completed,
+	})
+	if err != nil {
+		t.Fatalf("UpdateTodo() unexpected error: %v", err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("UpdateTodo() recorded %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.TodoID != 1 || event.ActorID != 2 || event.Action != models.TodoEventUpdate {
+		t.Errorf("UpdateTodo() event = %+v, want todo 1 updated by actor 2", event)
+	}
+	if !reflect.DeepEqual(event.ChangedFields, []string{"title", "completed"}) {
+		t.Errorf("UpdateTodo() changed fields = %v, want [title completed]", event.ChangedFields)
+	}
+}
+
+func TestTodoService_UpdateTodo_NoChanges(t *testing.T) {
+	remindAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	existing := models.Todo{ID: 1, Title: "Same", Description: "Same description", Completed: true, RemindAt: &remindAt, UserID: 1, CategoryID: 1}
+
+	title, description, completed, categoryID := "Same", "Same description", true, uint(1)
+	sameRemindAt := remindAt.In(time.FixedZone("CEST", 2*60*60))
+
+	tests := []struct {
+		name        string
+		req         dto.UpdateTodoRequest
+		wantChanged bool
+	}{
+		{
+			name: "identical patch",
+			req:  dto.UpdateTodoRequest{ID: 1, UserID: 1, Title: &title, Description: &description, Completed: &completed, CategoryID: &categoryID, RemindAt: &sameRemindAt},
+		},
+		{
+			name: "identical replace",
+			req:  dto.UpdateTodoRequest{ID: 1, UserID: 1, Title: &title, Description: &description, Completed: &completed, CategoryID: &categoryID, RemindAt: &remindAt, Replace: true},
+		},
+		{
+			name:        "replace that clears the reminder",
+			req:         dto.UpdateTodoRequest{ID: 1, UserID: 1, Title: &title, Description: &description, Completed: &completed, CategoryID: &categoryID, Replace: true},
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writes, events := 0, 0
+			todoRepo := &mocks.MockTodoRepository{
+				GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+					todo := existing
+					return &todo, nil
+				},
+				UpdateTodoFunc: func(ctx context.Context, todo *models.Todo) error {
+					writes++
+					return nil
+				},
+			}
+			eventRepo := &mocks.MockTodoEventRepository{
+				CreateTodoEventFunc: func(ctx context.Context, event *models.TodoEvent) error {
+					events++
+					return nil
+				},
+			}
+			service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, eventRepo, &mocks.MockUserRepository{}, mockTodoTx(todoRepo, eventRepo), PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+
+			todo, changed, err := service.UpdateTodo(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("UpdateTodo() unexpected error: %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("UpdateTodo() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if todo == nil || todo.ID != 1 {
+				t.Fatalf("UpdateTodo() todo = %+v, want todo 1", todo)
+			}
+
+			wantWrites := 0
+			if tt.wantChanged {
+				wantWrites = 1
+			}
+			if writes != wantWrites || events != wantWrites {
+				t.Errorf("UpdateTodo() wrote %d times and recorded %d events, want %d of each", writes, events, wantWrites)
+			}
+		})
+	}
+}
+
 func TestTodoService_DeleteTodo(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -554,6 +1570,10 @@ func TestTodoService_DeleteTodo(t *testing.T) {
 				DeleteTodoFunc: func(ctx context.Context, id uint) error {
 					return tt.deleteErr
 				},
+				GetTodoByIDIncludingDeletedFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+					deletedAt := time.Now()
+					return &models.Todo{ID: id, DeletedAt: &deletedAt}, nil
+				},
 			}
 
 			categoryRepo := &mocks.MockCategoryRepository{
@@ -574,7 +1594,7 @@ func TestTodoService_DeleteTodo(t *testing.T) {
 
 			service := createTestTodoService(todoRepo, categoryRepo, categoryShareRepo)
 
-			err := service.DeleteTodo(context.Background(), tt.req)
+			_, err := service.DeleteTodo(context.Background(), tt.req)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeleteTodo() error = %v, wantErr %v", err, tt.wantErr)
@@ -588,6 +1608,163 @@ func TestTodoService_DeleteTodo(t *testing.T) {
 	}
 }
 
+// undoTodoRepo mocks todo 1 in category 1; deleting it stamps a fresh deletion time and restoring clears it
+func undoTodoRepo(deletedAt **time.Time) *mocks.MockTodoRepository {
+	return &mocks.MockTodoRepository{
+		GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+			if *deletedAt != nil {
+				return nil, sql.ErrNoRows
+			}
+			return &models.Todo{ID: id, Title: "Buy milk", CategoryID: 1, UserID: 1}, nil
+		},
+		GetTodoByIDIncludingDeletedFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+			return &models.Todo{ID: id, Title: "Buy milk", CategoryID: 1, UserID: 1, DeletedAt: *deletedAt}, nil
+		},
+		DeleteTodoFunc: func(ctx context.Context, id uint) error {
+			now := time.Now()
+			*deletedAt = &now
+			return nil
+		},
+		RestoreTodoFunc: func(ctx context.Context, id uint) (bool, error) {
+			if *deletedAt == nil {
+				return false, nil
+			}
+			*deletedAt = nil
+			return true, nil
+		},
+	}
+}
+
+func TestTodoService_UndoDelete(t *testing.T) {
+	var deletedAt *time.Time
+	todoRepo := undoTodoRepo(&deletedAt)
+	service := createTestTodoService(todoRepo, defaultCategoryMock(1), nil)
+
+	resp, err := service.DeleteTodo(context.Background(), dto.DeleteTodoRequest{ID: 1, UserID: 1})
+	if err != nil {
+		t.Fatalf("DeleteTodo() error = %v", err)
+	}
+	if resp.UndoToken == "" || time.Until(resp.UndoExpiresAt) > UndoDeleteWindow {
+		t.Fatalf("DeleteTodo() = %+v, want a token expiring within %v", resp, UndoDeleteWindow)
+	}
+
+	// Another user cannot spend the token
+	if _, err := service.UndoDelete(context.Background(), dto.UndoDeleteRequest{UserID: 2, UndoToken: resp.UndoToken}); !errors.Is(err, ErrInvalidUndoToken) {
+		t.Errorf("UndoDelete() by another user error = %v, want ErrInvalidUndoToken", err)
+	}
+
+	todo, err := service.UndoDelete(context.Background(), dto.UndoDeleteRequest{UserID: 1, UndoToken: resp.UndoToken})
+	if err != nil {
+		t.Fatalf("UndoDelete() error = %v", err)
+	}
+	if todo.ID != 1 || deletedAt != nil {
+		t.Errorf("UndoDelete() restored todo %d, deleted at %v", todo.ID, deletedAt)
+	}
+
+	// The todo is live again, so the same token has nothing left to restore
+	if _, err := service.UndoDelete(context.Background(), dto.UndoDeleteRequest{UserID: 1, UndoToken: resp.UndoToken}); !errors.Is(err, ErrTodoNotFound) {
+		t.Errorf("UndoDelete() reused token error = %v, want ErrTodoNotFound", err)
+	}
+
+	now := time.Now()
+	deletedAt = &now
+	expired, _ := testUndoTokens.GenerateUndoToken(1, 1, now, now.Add(-time.Second))
+	if _, err := service.UndoDelete(context.Background(), dto.UndoDeleteRequest{UserID: 1, UndoToken: expired}); !errors.Is(err, ErrInvalidUndoToken) {
+		t.Errorf("UndoDelete() with expired token error = %v, want ErrInvalidUndoToken", err)
+	}
+	if deletedAt == nil {
+		t.Error("expired token restored the todo")
+	}
+}
+
+func TestTodoService_UndoDelete_LaterDelete(t *testing.T) {
+	// User 2 deletes the owner's todo, the owner restores it and deletes it again
+	var deletedAt *time.Time
+	todoRepo := undoTodoRepo(&deletedAt)
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+			return "write", nil
+		},
+	}
+	service := createTestTodoService(todoRepo, defaultCategoryMock(1), categoryShareRepo)
+
+	resp, err := service.DeleteTodo(context.Background(), dto.DeleteTodoRequest{ID: 1, UserID: 2})
+	if err != nil {
+		t.Fatalf("DeleteTodo() error = %v", err)
+	}
+	if _, err := todoRepo.RestoreTodo(context.Background(), 1); err != nil {
+		t.Fatalf("RestoreTodo() error = %v", err)
+	}
+	if _, err := service.DeleteTodo(context.Background(), dto.DeleteTodoRequest{ID: 1, UserID: 1}); err != nil {
+		t.Fatalf("DeleteTodo() error = %v", err)
+	}
+
+	if _, err := service.UndoDelete(context.Background(), dto.UndoDeleteRequest{UserID: 2, UndoToken: resp.UndoToken}); !errors.Is(err, ErrTodoNotFound) {
+		t.Errorf("UndoDelete() of a later delete error = %v, want ErrTodoNotFound", err)
+	}
+	if deletedAt == nil {
+		t.Error("UndoDelete() undid the owner's later delete")
+	}
+}
+
+func TestTodoService_UndoDelete_ShareRevoked(t *testing.T) {
+	var deletedAt *time.Time
+	todoRepo := undoTodoRepo(&deletedAt)
+	permission := "write"
+	categoryShareRepo := &mocks.MockCategoryShareRepository{
+		GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+			return permission, nil
+		},
+	}
+	service := createTestTodoService(todoRepo, defaultCategoryMock(1), categoryShareRepo)
+
+	resp, err := service.DeleteTodo(context.Background(), dto.DeleteTodoRequest{ID: 1, UserID: 2})
+	if err != nil {
+		t.Fatalf("DeleteTodo() error = %v", err)
+	}
+
+	// The owner revokes the share before the token expires
+	permission = ""
+	if _, err := service.UndoDelete(context.Background(), dto.UndoDeleteRequest{UserID: 2, UndoToken: resp.UndoToken}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("UndoDelete() after the share was revoked error = %v, want ErrForbidden", err)
+	}
+	if deletedAt == nil {
+		t.Error("UndoDelete() restored a todo the user can no longer write to")
+	}
+}
+
+func TestTodoService_UndoDelete_TodoLimit(t *testing.T) {
+	deletedAt := time.Now()
+	restored := false
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodoByIDIncludingDeletedFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+			return &models.Todo{ID: id, CategoryID: 1, UserID: 1, DeletedAt: &deletedAt}, nil
+		},
+		// The category filled up while the todo was in the trash
+		CountTodosInCategoryFunc: func(ctx context.Context, categoryID uint) (int64, error) {
+			return 2, nil
+		},
+		RestoreTodoFunc: func(ctx context.Context, id uint) (bool, error) {
+			restored = true
+			return true, nil
+		},
+	}
+	categoryRepo := defaultCategoryMock(1)
+	txManager := mockTodoTx(todoRepo, nil)
+	txManager.Repos.Categories = categoryRepo
+	txManager.Repos.CategoryShares = &mocks.MockCategoryShareRepository{}
+	service := NewTodoService(todoRepo, categoryRepo, txManager.Repos.CategoryShares, &mocks.MockTodoEventRepository{}, &mocks.MockUserRepository{}, txManager,
+		PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{MaxTodosPerCategory: 2}, true, testUndoTokens)
+
+	token, _ := testUndoTokens.GenerateUndoToken(1, 1, deletedAt, time.Now().Add(UndoDeleteWindow))
+	if _, err := service.UndoDelete(context.Background(), dto.UndoDeleteRequest{UserID: 1, UndoToken: token}); !errors.Is(err, ErrTodoLimitReached) {
+		t.Errorf("UndoDelete() error = %v, want ErrTodoLimitReached", err)
+	}
+	if restored {
+		t.Error("UndoDelete() restored a todo into a full category")
+	}
+}
+
 func TestTodoService_GetOrCreateCategory(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -670,3 +1847,789 @@ func TestTodoService_GetOrCreateCategory(t *testing.T) {
 		})
 	}
 }
+
+func TestTodoService_CleanupCompletedTodos(t *testing.T) {
+	tests := []struct {
+		name        string
+		dryRun      bool
+		wantCount   int64
+		wantCounted bool
+		wantDeleted bool
+		wantEvents  int
+	}{
+		{
+			name:        "dry run only counts",
+			dryRun:      true,
+			wantCount:   4,
+			wantCounted: true,
+			wantDeleted: false,
+		},
+		{
+			name:        "actual deletion",
+			dryRun:      false,
+			wantCount:   3,
+			wantCounted: false,
+			wantDeleted: true,
+			wantEvents:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counted, deleted := false, false
+			checkCutoff := func(before time.Time) {
+				want := time.Now().Add(-30 * 24 * time.Hour)
+				if diff := want.Sub(before); diff < 0 || diff > time.Minute {
+					t.Errorf("cutoff = %v, want about %v", before, want)
+				}
+			}
+			todoRepo := &mocks.MockTodoRepository{
+				CountCompletedTodosBeforeFunc: func(ctx context.Context, userID uint, before time.Time) (int64, error) {
+					counted = true
+					checkCutoff(before)
+					return 4, nil
+				},
+				GetCompletedTodoIDsBeforeFunc: func(ctx context.Context, userID uint, before time.Time) ([]uint, error) {
+					checkCutoff(before)
+					return []uint{5, 6, 7}, nil
+				},
+				DeleteTodosFunc: func(ctx context.Context, ids []uint) (int64, error) {
+					deleted = true
+					return int64(len(ids)), nil
+				},
+			}
+			var events []models.TodoEvent
+			eventRepo := &mocks.MockTodoEventRepository{
+				CreateTodoEventFunc: func(ctx context.Context, event *models.TodoEvent) error {
+					events = append(events, *event)
+					return nil
+				},
+			}
+
+			service := NewTodoService(todoRepo, &mocks.MockCategoryRepository{}, &mocks.MockCategoryShareRepository{}, eventRepo, &mocks.MockUserRepository{}, mockTodoTx(todoRepo, eventRepo),
+				PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+			count, err := service.CleanupCompletedTodos(context.Background(), dto.CleanupTodosRequest{
+				UserID:    1,
+				OlderThan: 30 * 24 * time.Hour,
+				DryRun:    tt.dryRun,
+			})
+
+			if err != nil {
+				t.Fatalf("CleanupCompletedTodos() error = %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("CleanupCompletedTodos() count = %d, want %d", count, tt.wantCount)
+			}
+			if counted != tt.wantCounted {
+				t.Errorf("CleanupCompletedTodos() counted = %v, want %v", counted, tt.wantCounted)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("CleanupCompletedTodos() deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			// Each deleted todo gets a delete entry in its history
+			if len(events) != tt.wantEvents {
+				t.Errorf("CleanupCompletedTodos() recorded %d history events, want %d", len(events), tt.wantEvents)
+			}
+			for _, event := range events {
+				if event.ActorID != 1 || event.Action != models.TodoEventDelete {
+					t.Errorf("CleanupCompletedTodos() recorded %+v, want a delete by user 1", event)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoService_CompleteAllInCategory(t *testing.T) {
+	tests := []struct {
+		name             string
+		userID           uint
+		sharedPermission string
+		wantCount        int64
+		expectedErr      error
+	}{
+		{name: "owner", userID: 1, wantCount: 3},
+		{name: "write share", userID: 2, sharedPermission: "write", wantCount: 3},
+		{name: "read-only share", userID: 3, sharedPermission: "read", expectedErr: ErrNoWritePermission},
+		{name: "no access", userID: 4, expectedErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := false
+			todoRepo := &mocks.MockTodoRepository{
+				// Only the open todos change state, so only they are selected
+				GetTodoIDsInCategoryFunc: func(ctx context.Context, categoryID uint, completed *bool) ([]uint, error) {
+					if categoryID != 1 || completed == nil || *completed {
+						t.Errorf("GetTodoIDsInCategory(%d, %v), want (1, false)", categoryID, completed)
+					}
+					return []uint{10, 11, 12}, nil
+				},
+				SetCompletedFunc: func(ctx context.Context, ids []uint, completed bool) (int64, error) {
+					updated = true
+					if !slices.Equal(ids, []uint{10, 11, 12}) || !completed {
+						t.Errorf("SetCompleted(%v, %v), want ([10 11 12], true)", ids, completed)
+					}
+					return int64(len(ids)), nil
+				},
+			}
+			categoryShareRepo := &mocks.MockCategoryShareRepository{
+				GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+					return tt.sharedPermission, nil
+				},
+			}
+			var events []models.TodoEvent
+			eventRepo := &mocks.MockTodoEventRepository{
+				CreateTodoEventFunc: func(ctx context.Context, event *models.TodoEvent) error {
+					events = append(events, *event)
+					return nil
+				},
+			}
+			service := NewTodoService(todoRepo, defaultCategoryMock(1), categoryShareRepo, eventRepo, &mocks.MockUserRepository{}, mockTodoTx(todoRepo, eventRepo),
+				PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+
+			count, err := service.CompleteAllInCategory(context.Background(), tt.userID, 1, true)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("CompleteAllInCategory() error = %v, expected %v", err, tt.expectedErr)
+				}
+				if updated {
+					t.Error("CompleteAllInCategory() updated todos without write permission")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompleteAllInCategory() unexpected error: %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("CompleteAllInCategory() count = %d, want %d", count, tt.wantCount)
+			}
+			if int64(len(events)) != tt.wantCount {
+				t.Fatalf("CompleteAllInCategory() recorded %d history events, want %d", len(events), tt.wantCount)
+			}
+			for _, event := range events {
+				if event.ActorID != tt.userID || event.Action != models.TodoEventUpdate || !slices.Equal(event.ChangedFields, []string{"completed"}) {
+					t.Errorf("CompleteAllInCategory() recorded %+v, want a completed update by user %d", event, tt.userID)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoService_CountCategoryTodos(t *testing.T) {
+	tests := []struct {
+		name             string
+		userID           uint
+		sharedPermission string
+		expectedErr      error
+	}{
+		{name: "owner", userID: 1},
+		{name: "read share", userID: 2, sharedPermission: "read"},
+		{name: "no access", userID: 3, expectedErr: ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := &mocks.MockTodoRepository{
+				CountTodosByStatusInCategoryFunc: func(ctx context.Context, categoryID uint) (models.TodoStatusCounts, error) {
+					return models.TodoStatusCounts{Total: 5, Completed: 2, Open: 3}, nil
+				},
+			}
+			categoryShareRepo := &mocks.MockCategoryShareRepository{
+				GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+					return tt.sharedPermission, nil
+				},
+			}
+			service := createTestTodoService(todoRepo, defaultCategoryMock(1), categoryShareRepo)
+
+			counts, err := service.CountCategoryTodos(context.Background(), tt.userID, 1)
+
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("CountCategoryTodos() error = %v, expected %v", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CountCategoryTodos() unexpected error: %v", err)
+			}
+			if *counts != (models.TodoStatusCounts{Total: 5, Completed: 2, Open: 3}) {
+				t.Errorf("CountCategoryTodos() = %+v, want total 5, completed 2, open 3", *counts)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetCompletionReport(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	todoRepo := &mocks.MockTodoRepository{
+		CountCompletedTodosByDayFunc: func(ctx context.Context, userID uint, gotFrom, gotTo time.Time, loc *time.Location) ([]models.CompletionCount, error) {
+			if !gotFrom.Equal(from) || !gotTo.Equal(to.AddDate(0, 0, 1)) {
+				t.Errorf("window = [%v, %v), want [%v, %v)", gotFrom, gotTo, from, to.AddDate(0, 0, 1))
+			}
+			// Nothing was completed on the middle day, so the query returns no row for it
+			return []models.CompletionCount{
+				{Day: from, Count: 2},
+				{Day: to, Count: 5},
+			}, nil
+		},
+	}
+	service := createTestTodoService(todoRepo, &mocks.MockCategoryRepository{}, nil)
+
+	report, err := service.GetCompletionReport(context.Background(), dto.CompletionReportRequest{UserID: 1, From: from, To: to})
+	if err != nil {
+		t.Fatalf("GetCompletionReport() unexpected error = %v", err)
+	}
+
+	want := []dto.DayCompletionCount{
+		{Date: "2024-03-01", Count: 2},
+		{Date: "2024-03-02", Count: 0},
+		{Date: "2024-03-03", Count: 5},
+	}
+	if !reflect.DeepEqual(report.Days, want) {
+		t.Errorf("Days = %+v, want %+v", report.Days, want)
+	}
+	if report.Total != 7 {
+		t.Errorf("Total = %d, want 7", report.Total)
+	}
+}
+
+func TestTodoService_GetCompletionReport_InvalidRange(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		to      time.Time
+		wantErr error
+	}{
+		{name: "to before from", to: from.AddDate(0, 0, -1), wantErr: ErrInvalidDateRange},
+		{name: "longer than the cap", to: from.AddDate(0, 0, MaxReportDays), wantErr: ErrDateRangeTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := &mocks.MockTodoRepository{
+				CountCompletedTodosByDayFunc: func(ctx context.Context, userID uint, from, to time.Time, loc *time.Location) ([]models.CompletionCount, error) {
+					t.Error("CountCompletedTodosByDay() should not be called for an invalid range")
+					return nil, nil
+				},
+			}
+			service := createTestTodoService(todoRepo, &mocks.MockCategoryRepository{}, nil)
+
+			_, err := service.GetCompletionReport(context.Background(), dto.CompletionReportRequest{UserID: 1, From: from, To: tt.to})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetCompletionReport() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetTodoPermissions(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     uint
+		permission string
+		want       dto.TodoPermissions
+	}{
+		{name: "owner", userID: 1, want: dto.TodoPermissions{CanRead: true, CanWrite: true, CanDelete: true}},
+		{name: "write sharer", userID: 2, permission: "write", want: dto.TodoPermissions{CanRead: true, CanWrite: true, CanDelete: true}},
+		{name: "read sharer", userID: 2, permission: "read", want: dto.TodoPermissions{CanRead: true}},
+		{name: "no access", userID: 3, want: dto.TodoPermissions{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := &mocks.MockTodoRepository{
+				GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+					return &models.Todo{ID: id, CategoryID: 1, UserID: 1}, nil
+				},
+			}
+			shareRepo := &mocks.MockCategoryShareRepository{
+				GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+					if tt.permission == "" {
+						return "", sql.ErrNoRows
+					}
+					return tt.permission, nil
+				},
+			}
+			service := createTestTodoService(todoRepo, defaultCategoryMock(1), shareRepo)
+
+			got, err := service.GetTodoPermissions(context.Background(), dto.GetTodoRequest{ID: 1, UserID: tt.userID})
+			if err != nil {
+				t.Fatalf("GetTodoPermissions() unexpected error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("GetTodoPermissions() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetTodoPermissions_NotFound(t *testing.T) {
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+	service := createTestTodoService(todoRepo, nil, nil)
+
+	if _, err := service.GetTodoPermissions(context.Background(), dto.GetTodoRequest{ID: 9, UserID: 1}); !errors.Is(err, ErrTodoNotFound) {
+		t.Errorf("GetTodoPermissions() error = %v, want %v", err, ErrTodoNotFound)
+	}
+}
+
+func TestTodoService_TouchTodo(t *testing.T) {
+	touchedAt := time.Date(2024, 3, 18, 9, 0, 0, 0, time.UTC)
+	existing := models.Todo{ID: 1, Title: "Test", Description: "Keep", CategoryID: 1, UserID: 1, Completed: true,
+		UpdatedAt: touchedAt.Add(-time.Hour)}
+
+	tests := []struct {
+		name        string
+		userID      uint
+		permission  string
+		findErr     error
+		touchErr    error
+		expectedErr error
+	}{
+		{name: "owner", userID: 1},
+		{name: "write sharer", userID: 2, permission: "write"},
+		{name: "read sharer", userID: 2, permission: "read", expectedErr: ErrNoWritePermission},
+		{name: "no access", userID: 3, expectedErr: ErrForbidden},
+		{name: "not found", userID: 1, findErr: sql.ErrNoRows, expectedErr: ErrTodoNotFound},
+		{name: "deleted before the update", userID: 1, touchErr: sql.ErrNoRows, expectedErr: ErrTodoNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			touched := false
+			todoRepo := &mocks.MockTodoRepository{
+				GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+					if tt.findErr != nil {
+						return nil, tt.findErr
+					}
+					todo := existing
+					return &todo, nil
+				},
+				TouchTodoFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+					touched = true
+					if tt.touchErr != nil {
+						return nil, tt.touchErr
+					}
+					todo := existing
+					todo.UpdatedAt = touchedAt
+					return &todo, nil
+				},
+			}
+			shareRepo := &mocks.MockCategoryShareRepository{
+				GetUserPermissionForCategoryFunc: func(ctx context.Context, userID, categoryID uint) (string, error) {
+					if tt.permission == "" {
+						return "", sql.ErrNoRows
+					}
+					return tt.permission, nil
+				},
+			}
+			service := createTestTodoService(todoRepo, defaultCategoryMock(1), shareRepo)
+
+			got, err := service.TouchTodo(context.Background(), dto.GetTodoRequest{ID: 1, UserID: tt.userID})
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("TouchTodo() error = %v, want %v", err, tt.expectedErr)
+				}
+				if touched && tt.touchErr == nil {
+					t.Error("TouchTodo() touched a todo the user may not write")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TouchTodo() unexpected error = %v", err)
+			}
+
+			want := existing
+			want.UpdatedAt = touchedAt
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("TouchTodo() = %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
+
+func TestTodoService_GetRecentTodos(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     int
+		wantLimit int
+	}{
+		{name: "default", limit: 0, wantLimit: DefaultRecentTodos},
+		{name: "within max", limit: 5, wantLimit: 5},
+		{name: "above max", limit: 500, wantLimit: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLimit int
+			todoRepo := &mocks.MockTodoRepository{
+				GetRecentTodosFunc: func(ctx context.Context, userID uint, limit int) ([]models.Todo, error) {
+					gotLimit = limit
+					return []models.Todo{{ID: 2, UserID: userID}, {ID: 1, UserID: userID}}, nil
+				},
+			}
+			service := NewTodoService(todoRepo, &mocks.MockCategoryRepository{}, &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, &mocks.MockUserRepository{}, mockTodoTx(todoRepo, nil),
+				PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{MaxRecentTodos: 50}, true, testUndoTokens)
+
+			todos, err := service.GetRecentTodos(context.Background(), 1, tt.limit)
+			if err != nil {
+				t.Fatalf("GetRecentTodos() error = %v", err)
+			}
+			if gotLimit != tt.wantLimit {
+				t.Errorf("repository limit = %d, want %d", gotLimit, tt.wantLimit)
+			}
+			if len(todos) != 2 || todos[0].ID != 2 {
+				t.Errorf("GetRecentTodos() = %+v, want the repository order", todos)
+			}
+		})
+	}
+}
+
+func TestTodoService_SyncTodos(t *testing.T) {
+	cursorAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return cursorAt.Add(time.Duration(minutes) * time.Minute) }
+	deletedAt := at(3)
+	changed := []models.Todo{
+		{ID: 4, CreatedAt: at(-60), UpdatedAt: at(1)},
+		{ID: 9, CreatedAt: at(2), UpdatedAt: at(2)},
+		{ID: 5, CreatedAt: at(-60), UpdatedAt: at(3), DeletedAt: &deletedAt},
+	}
+
+	tests := []struct {
+		name        string
+		cursor      string
+		wantSince   time.Time
+		wantCreated []uint
+		wantUpdated []uint
+		wantDeleted []uint
+	}{
+		{name: "full sync", cursor: "", wantCreated: []uint{4, 9}, wantUpdated: []uint{}, wantDeleted: []uint{}},
+		{name: "incremental", cursor: utils.SyncCursor{ChangedAt: cursorAt}.Encode(), wantSince: cursorAt,
+			wantCreated: []uint{9}, wantUpdated: []uint{4}, wantDeleted: []uint{5}},
+	}
+
+	ids := func(todos []models.Todo) []uint {
+		out := []uint{}
+		for _, todo := range todos {
+			out = append(out, todo.ID)
+		}
+		return out
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSince time.Time
+			todoRepo := &mocks.MockTodoRepository{
+				GetTodosChangedSinceFunc: func(ctx context.Context, userID uint, since time.Time) ([]models.Todo, error) {
+					gotSince = since
+					return changed, nil
+				},
+			}
+			service := createTestTodoService(todoRepo, nil, nil)
+
+			response, err := service.SyncTodos(context.Background(), 1, tt.cursor)
+			if err != nil {
+				t.Fatalf("SyncTodos() error = %v", err)
+			}
+			if !gotSince.Equal(tt.wantSince) {
+				t.Errorf("repository since = %v, want %v", gotSince, tt.wantSince)
+			}
+			if got := ids(response.Created); !reflect.DeepEqual(got, tt.wantCreated) {
+				t.Errorf("created = %v, want %v", got, tt.wantCreated)
+			}
+			if got := ids(response.Updated); !reflect.DeepEqual(got, tt.wantUpdated) {
+				t.Errorf("updated = %v, want %v", got, tt.wantUpdated)
+			}
+			if !reflect.DeepEqual(response.Deleted, tt.wantDeleted) {
+				t.Errorf("deleted = %v, want %v", response.Deleted, tt.wantDeleted)
+			}
+
+			// The next cursor is the latest change seen, the deletion included
+			next, err := utils.ParseSyncCursor(response.NextCursor)
+			if err != nil || !next.ChangedAt.Equal(deletedAt) {
+				t.Errorf("next cursor = %v (%v), want %v", next.ChangedAt, err, deletedAt)
+			}
+		})
+	}
+}
+
+func TestTodoService_SyncTodos_NoChangesKeepsCursor(t *testing.T) {
+	service := createTestTodoService(&mocks.MockTodoRepository{}, nil, nil)
+	cursor := utils.SyncCursor{ChangedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)}.Encode()
+
+	response, err := service.SyncTodos(context.Background(), 1, cursor)
+	if err != nil {
+		t.Fatalf("SyncTodos() error = %v", err)
+	}
+	if response.NextCursor != cursor {
+		t.Errorf("NextCursor = %q, want the cursor passed in %q", response.NextCursor, cursor)
+	}
+
+	if _, err := service.SyncTodos(context.Background(), 1, "not a cursor!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("SyncTodos() with a bad cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestTodoService_GetCompletionReport_UserTimezone(t *testing.T) {
+	userRepo := &mocks.MockUserRepository{
+		GetUserByIDFunc: func(ctx context.Context, id uint) (*models.User, error) {
+			return &models.User{ID: id, Timezone: "Asia/Tokyo"}, nil
+		},
+	}
+	todoRepo := &mocks.MockTodoRepository{
+		CountCompletedTodosByDayFunc: func(ctx context.Context, userID uint, from, to time.Time, loc *time.Location) ([]models.CompletionCount, error) {
+			// March 1st in Tokyo (UTC+9) begins at 15:00 UTC on February 29th
+			if want := time.Date(2024, 2, 29, 15, 0, 0, 0, time.UTC); !from.Equal(want) {
+				t.Errorf("from = %v, want %v", from.UTC(), want)
+			}
+			return []models.CompletionCount{{Day: time.Date(2024, 3, 1, 0, 0, 0, 0, loc), Count: 3}}, nil
+		},
+	}
+	service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, userRepo, mockTodoTx(todoRepo, nil),
+		PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	report, err := service.GetCompletionReport(context.Background(), dto.CompletionReportRequest{UserID: 1, From: day, To: day})
+	if err != nil {
+		t.Fatalf("GetCompletionReport() error = %v", err)
+	}
+	if report.Total != 3 || report.Days[0].Date != "2024-03-01" {
+		t.Errorf("GetCompletionReport() = %+v, want 3 completions on 2024-03-01", report)
+	}
+}
+
+func TestTodoService_RestoreAllTodos(t *testing.T) {
+	var restoredIDs []uint
+	var events []models.TodoEvent
+	todoRepo := &mocks.MockTodoRepository{
+		GetDeletedTodosFunc: func(ctx context.Context, userID, categoryID uint) ([]models.Todo, error) {
+			return []models.Todo{{ID: 4, CategoryID: 1}, {ID: 7, CategoryID: 1}, {ID: 9, CategoryID: 2}}, nil
+		},
+		RestoreTodosFunc: func(ctx context.Context, ids []uint) (int64, error) {
+			restoredIDs = ids
+			return int64(len(ids)), nil
+		},
+	}
+	eventRepo := &mocks.MockTodoEventRepository{
+		CreateTodoEventFunc: func(ctx context.Context, event *models.TodoEvent) error {
+			events = append(events, *event)
+			return nil
+		},
+	}
+	txManager := &mocks.MockTxManager{Repos: repository.RepoSet{Todos: todoRepo, TodoEvents: eventRepo}}
+	service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, eventRepo, &mocks.MockUserRepository{}, txManager,
+		PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+
+	restored, err := service.RestoreAllTodos(context.Background(), 1, 0)
+	if err != nil {
+		t.Fatalf("RestoreAllTodos() error = %v", err)
+	}
+	if restored != 3 || !reflect.DeepEqual(restoredIDs, []uint{4, 7, 9}) {
+		t.Errorf("RestoreAllTodos() = %d restoring %v, want 3 restoring [4 7 9]", restored, restoredIDs)
+	}
+	if len(events) != 3 || events[0].Action != models.TodoEventRestore || events[0].ActorID != 1 {
+		t.Errorf("recorded events = %+v, want one restore event per todo", events)
+	}
+
+	// A user without write access on the category cannot empty its trash
+	restoredIDs = nil
+	if _, err := service.RestoreAllTodos(context.Background(), 2, 5); !errors.Is(err, ErrForbidden) {
+		t.Errorf("RestoreAllTodos() without access error = %v, want ErrForbidden", err)
+	}
+	if restoredIDs != nil {
+		t.Error("RestoreAllTodos() restored todos without write access")
+	}
+}
+
+func TestTodoService_RestoreAllTodos_TodoLimit(t *testing.T) {
+	restored := false
+	todoRepo := &mocks.MockTodoRepository{
+		// Category 1 gets two todos back and has room for them; category 2 gets one but is already full
+		GetDeletedTodosFunc: func(ctx context.Context, userID, categoryID uint) ([]models.Todo, error) {
+			return []models.Todo{{ID: 4, CategoryID: 1}, {ID: 7, CategoryID: 1}, {ID: 9, CategoryID: 2}}, nil
+		},
+		CountTodosInCategoryFunc: func(ctx context.Context, categoryID uint) (int64, error) {
+			if categoryID == 2 {
+				return 3, nil
+			}
+			return 1, nil
+		},
+		RestoreTodosFunc: func(ctx context.Context, ids []uint) (int64, error) {
+			restored = true
+			return int64(len(ids)), nil
+		},
+	}
+	service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, &mocks.MockUserRepository{}, mockTodoTx(todoRepo, nil),
+		PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{MaxTodosPerCategory: 3}, true, testUndoTokens)
+
+	if _, err := service.RestoreAllTodos(context.Background(), 1, 0); !errors.Is(err, ErrTodoLimitReached) {
+		t.Errorf("RestoreAllTodos() error = %v, want ErrTodoLimitReached", err)
+	}
+	if restored {
+		t.Error("RestoreAllTodos() restored todos past a category's limit")
+	}
+}
+
+func TestTodoService_BulkMoveTodos(t *testing.T) {
+	// Todos 1 and 3 in category 10 are completed, todo 2 is still open
+	completedByID := map[uint]bool{1: true, 2: false, 3: true}
+	var movedIDs []uint
+	var movedTo, movedOwner uint
+	var events []models.TodoEvent
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodoIDsInCategoryFunc: func(ctx context.Context, categoryID uint, completed *bool) ([]uint, error) {
+			ids := []uint{}
+			for _, id := range []uint{1, 2, 3} {
+				if completed == nil || completedByID[id] == *completed {
+					ids = append(ids, id)
+				}
+			}
+			return ids, nil
+		},
+		MoveTodosFunc: func(ctx context.Context, ids []uint, toCategoryID, toOwnerID uint) (int64, error) {
+			movedIDs, movedTo, movedOwner = ids, toCategoryID, toOwnerID
+			return int64(len(ids)), nil
+		},
+	}
+	eventRepo := &mocks.MockTodoEventRepository{
+		CreateTodoEventFunc: func(ctx context.Context, event *models.TodoEvent) error {
+			events = append(events, *event)
+			return nil
+		},
+	}
+	txManager := &mocks.MockTxManager{Repos: repository.RepoSet{Todos: todoRepo, TodoEvents: eventRepo}}
+	service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, eventRepo, &mocks.MockUserRepository{}, txManager,
+		PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{}, true, testUndoTokens)
+
+	completed := true
+	moved, err := service.BulkMoveTodos(context.Background(), dto.BulkMoveTodosRequest{
+		UserID: 1, FromCategoryID: 10, ToCategoryID: 20, Completed: &completed,
+	})
+	if err != nil {
+		t.Fatalf("BulkMoveTodos() error = %v", err)
+	}
+	if moved != 2 || !reflect.DeepEqual(movedIDs, []uint{1, 3}) {
+		t.Errorf("BulkMoveTodos() = %d moving %v, want 2 moving [1 3]", moved, movedIDs)
+	}
+	if movedTo != 20 || movedOwner != 1 {
+		t.Errorf("moved to category %d owned by %d, want category 20 owned by 1", movedTo, movedOwner)
+	}
+	if len(events) != 2 || events[0].Action != models.TodoEventUpdate || !reflect.DeepEqual(events[0].ChangedFields, []string{"category_id"}) {
+		t.Errorf("recorded events = %+v, want one category_id update per moved todo", events)
+	}
+
+	// Moving a category into itself is rejected before anything is read
+	movedIDs = nil
+	if _, err := service.BulkMoveTodos(context.Background(), dto.BulkMoveTodosRequest{UserID: 1, FromCategoryID: 10, ToCategoryID: 10}); !errors.Is(err, ErrSameCategory) {
+		t.Errorf("BulkMoveTodos() into the same category error = %v, want ErrSameCategory", err)
+	}
+
+	// A user without write access on both categories cannot move anything
+	if _, err := service.BulkMoveTodos(context.Background(), dto.BulkMoveTodosRequest{UserID: 2, FromCategoryID: 10, ToCategoryID: 20}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("BulkMoveTodos() without access error = %v, want ErrForbidden", err)
+	}
+	if movedIDs != nil {
+		t.Error("BulkMoveTodos() moved todos it was not allowed to")
+	}
+}
+
+func TestTodoService_BulkMoveTodos_TargetFull(t *testing.T) {
+	moveCalled, locked := false, false
+	todoRepo := &mocks.MockTodoRepository{
+		GetTodoIDsInCategoryFunc: func(ctx context.Context, categoryID uint, completed *bool) ([]uint, error) {
+			return []uint{1, 2}, nil
+		},
+		CountTodosInCategoryFunc: func(ctx context.Context, categoryID uint) (int64, error) {
+			if !locked {
+				t.Error("BulkMoveTodos() counted the target without locking it")
+			}
+			return 4, nil
+		},
+		MoveTodosFunc: func(ctx context.Context, ids []uint, toCategoryID, toOwnerID uint) (int64, error) {
+			moveCalled = true
+			return int64(len(ids)), nil
+		},
+	}
+	categoryRepo := &mocks.MockCategoryRepository{
+		LockCategoryFunc: func(ctx context.Context, id uint) error {
+			locked = id == 20
+			return nil
+		},
+	}
+	txManager := &mocks.MockTxManager{Repos: repository.RepoSet{Todos: todoRepo, Categories: categoryRepo, TodoEvents: &mocks.MockTodoEventRepository{}}}
+	service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, &mocks.MockUserRepository{}, txManager,
+		PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, LimitsConfig{MaxTodosPerCategory: 5}, true, testUndoTokens)
+
+	_, err := service.BulkMoveTodos(context.Background(), dto.BulkMoveTodosRequest{UserID: 1, FromCategoryID: 10, ToCategoryID: 20})
+	if !errors.Is(err, ErrTodoLimitReached) {
+		t.Errorf("BulkMoveTodos() error = %v, want ErrTodoLimitReached", err)
+	}
+	if moveCalled {
+		t.Error("BulkMoveTodos() moved some todos although the target could not fit them all")
+	}
+}
+
+func TestTodoService_ConfiguredLengthLimits(t *testing.T) {
+	limits := LimitsConfig{MaxTitleLen: 10, MaxDescriptionLen: 20}
+	// The short title is 15 bytes but 5 characters, so only Unicode characters are counted
+	short, longTitle, longDescription := "牛乳を買う", "Buy oat milk today", strings.Repeat("d", 21)
+
+	tests := []struct {
+		name        string
+		title       *string
+		description *string
+		wantField   string
+		wantMessage string
+	}{
+		{name: "within limits", title: &short, description: &short},
+		{name: "title too long", title: &longTitle, wantField: "title", wantMessage: "title must be at most 10 characters"},
+		{name: "description too long", title: &short, description: &longDescription, wantField: "description", wantMessage: "description must be at most 20 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := &mocks.MockTodoRepository{
+				GetTodoByIDFunc: func(ctx context.Context, id uint) (*models.Todo, error) {
+					return &models.Todo{ID: id, Title: "Old", CategoryID: 1, UserID: 1}, nil
+				},
+			}
+			service := NewTodoService(todoRepo, defaultCategoryMock(1), &mocks.MockCategoryShareRepository{}, &mocks.MockTodoEventRepository{}, &mocks.MockUserRepository{}, mockTodoTx(todoRepo, nil),
+				PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100, DefaultTodoSort: models.TodoSortCreatedAtDesc}, limits, true, testUndoTokens)
+
+			description := ""
+			if tt.description != nil {
+				description = *tt.description
+			}
+			categoryID := uint(1)
+			_, createErr := service.CreateTodo(context.Background(), dto.CreateTodoRequest{
+				Title: *tt.title, Description: description, CategoryID: &categoryID, UserID: 1,
+			})
+			_, _, updateErr := service.UpdateTodo(context.Background(), dto.UpdateTodoRequest{
+				ID: 1, UserID: 1, Title: tt.title, Description: tt.description,
+			})
+
+			for op, err := range map[string]error{"CreateTodo": createErr, "UpdateTodo": updateErr} {
+				var tooLong *FieldTooLongError
+				if tt.wantField == "" {
+					if errors.As(err, &tooLong) {
+						t.Errorf("%s() error = %v, want no length error", op, err)
+					}
+					continue
+				}
+				if !errors.As(err, &tooLong) || tooLong.Field != tt.wantField {
+					t.Fatalf("%s() error = %v, want a %s length error", op, err, tt.wantField)
+				}
+				if err.Error() != tt.wantMessage {
+					t.Errorf("%s() message = %q, want %q", op, err.Error(), tt.wantMessage)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"todo-app/internal/dto"
+	"todo-app/internal/repository"
+)
+
+// Ensure UserServiceImpl implements UserService
+var _ UserService = (*UserServiceImpl)(nil)
+
+// UserServiceImpl handles the business logic of administering users
+type UserServiceImpl struct {
+	repo       repository.UserRepository
+	pagination PaginationConfig
+}
+
+// NewUserService creates a new UserService with the provided repository
+func NewUserService(repo repository.UserRepository, pagination PaginationConfig) UserService {
+	return &UserServiceImpl{
+		repo:       repo,
+		pagination: pagination,
+	}
+}
+
+// ListUsers retrieves a page of all users, oldest account first
+func (s *UserServiceImpl) ListUsers(ctx context.Context, page, pageSize int) (*dto.UserListResponse, error) {
+	// Normalize pagination parameters using config values
+	page = max(page, 1)
+	if pageSize < 1 {
+		pageSize = s.pagination.DefaultPageSize
+	}
+	pageSize = min(pageSize, s.pagination.MaxPageSize)
+
+	users, total, err := s.repo.GetUsers(ctx, page, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch users: %w", err)
+	}
+
+	totalPages := (total + int64(pageSize) - 1) / int64(pageSize)
+
+	return &dto.UserListResponse{
+		Users:      users,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// SeedAdmin makes the user with the given email an admin, reporting whether anyone was promoted
+func (s *UserServiceImpl) SeedAdmin(ctx context.Context, email string) (bool, error) {
+	promoted, err := s.repo.SetAdminByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return false, fmt.Errorf("failed to seed admin: %w", err)
+	}
+	return promoted, nil
+}
+
+// NormalizeEmails lowercases and trims emails stored before registration normalized them, returning how many changed
+func (s *UserServiceImpl) NormalizeEmails(ctx context.Context) (int64, error) {
+	changed, err := s.repo.NormalizeEmails(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to normalize emails: %w", err)
+	}
+	return changed, nil
+}
@@ -0,0 +1,11 @@
+// Package version holds build metadata injected at link time, e.g.
+//
+//	go build -ldflags "-X todo-app/internal/version.Version=v1.2.0 -X todo-app/internal/version.Commit=$(git rev-parse --short HEAD) -X todo-app/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+package version
+
+// Build metadata, left at these defaults when not set via -ldflags
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestGenerateAPIToken(t *testing.T) {
+	first, err := GenerateAPIToken()
+	if err != nil {
+		t.Fatalf("GenerateAPIToken() error = %v", err)
+	}
+	second, err := GenerateAPIToken()
+	if err != nil {
+		t.Fatalf("GenerateAPIToken() error = %v", err)
+	}
+
+	if !IsAPIToken(first) {
+		t.Errorf("GenerateAPIToken() = %q, want %q prefix", first, APITokenPrefix)
+	}
+	if first == second {
+		t.Error("GenerateAPIToken() returned the same token twice")
+	}
+}
+
+func TestHashAPIToken(t *testing.T) {
+	token := APITokenPrefix + "abc"
+
+	if HashAPIToken(token) != HashAPIToken(token) {
+		t.Error("HashAPIToken() is not deterministic")
+	}
+	if HashAPIToken(token) == HashAPIToken(token+"d") {
+		t.Error("HashAPIToken() returned the same hash for different tokens")
+	}
+	if got := len(HashAPIToken(token)); got != 64 {
+		t.Errorf("HashAPIToken() length = %d, want 64", got)
+	}
+}
+
+func TestScopesAllow(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		resource string
+		write    bool
+		want     bool
+	}{
+		{name: "no scopes is unrestricted", scopes: nil, resource: "todos", write: true, want: true},
+		{name: "read scope allows read", scopes: []string{"todos:read"}, resource: "todos", want: true},
+		{name: "read scope denies write", scopes: []string{"todos:read"}, resource: "todos", write: true, want: false},
+		{name: "write scope allows read", scopes: []string{"todos:write"}, resource: "todos", want: true},
+		{name: "other resource denied", scopes: []string{"todos:write"}, resource: "categories", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScopesAllow(tt.scopes, tt.resource, tt.write); got != tt.want {
+				t.Errorf("ScopesAllow(%v, %q, %v) = %v, want %v", tt.scopes, tt.resource, tt.write, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name:  "days",
+			input: "30d",
+			want:  30 * 24 * time.Hour,
+		},
+		{
+			name:  "hours",
+			input: "24h",
+			want:  24 * time.Hour,
+		},
+		{
+			name:  "minutes",
+			input: "90m",
+			want:  90 * time.Minute,
+		},
+		{
+			name:    "empty",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "invalid days",
+			input:   "xd",
+			wantErr: true,
+		},
+		{
+			name:    "zero",
+			input:   "0d",
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			input:   "-1h",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
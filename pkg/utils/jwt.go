@@ -2,11 +2,30 @@ package utils
 
 import (
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// Errors returned by ValidateToken when a token was minted for a different issuer or audience,
+// or without the jti claim every token from GenerateToken carries
+var (
+	ErrInvalidIssuer   = errors.New("token issuer does not match")
+	ErrInvalidAudience = errors.New("token audience does not match")
+	ErrMissingTokenID  = errors.New("token has no jti claim")
+	ErrUnknownKeyID    = errors.New("token kid does not name a known key")
+	ErrMissingKeyID    = errors.New("token has no kid")
+)
+
+// TokenTTL is how long a token from GenerateToken stays valid
+const TokenTTL = 24 * time.Hour
+
+// AuthCookieName is the cookie that carries the JWT when the server runs in cookie mode
+const AuthCookieName = "auth_token"
+
 // Claims represents the JWT claims
 type Claims struct {
 	UserID uint `json:"user_id"`
@@ -14,33 +33,143 @@ type Claims struct {
 }
 
 // JWTManager handles JWT token operations with a configured secret
+// With a key ring (WithKeys) tokens are signed with the current key and carry its id in the kid header,
+// so older keys can stay in the ring and keep validating the sessions they signed until those expire.
 type JWTManager struct {
-	secret []byte
+	secret       []byte            // signs tokens without a key ring and validates tokens that carry no kid
+	keys         map[string][]byte // key ring, by kid
+	signingKeyID string
+	requireKID   bool // reject tokens without a kid once a key ring is configured
+	algorithm    string
+	method       jwt.SigningMethod
+	issuer       string
+	audience     string
+}
+
+// JWTOption configures optional JWTManager settings
+type JWTOption func(*JWTManager)
+
+// WithIssuer sets the iss claim on generated tokens and requires it on validated ones.
+// An empty issuer disables both.
+func WithIssuer(issuer string) JWTOption {
+	return func(j *JWTManager) {
+		j.issuer = issuer
+	}
+}
+
+// WithAudience sets the aud claim on generated tokens and requires it on validated ones.
+// An empty audience disables both.
+func WithAudience(audience string) JWTOption {
+	return func(j *JWTManager) {
+		j.audience = audience
+	}
+}
+
+// WithSigningMethod sets the HMAC algorithm generated tokens are signed with: HS256 (the default), HS384 or HS512.
+// Validation accepts any of them, so changing it does not invalidate existing sessions. An empty value keeps HS256.
+func WithSigningMethod(algorithm string) JWTOption {
+	return func(j *JWTManager) {
+		if algorithm != "" {
+			j.algorithm = algorithm
+		}
+	}
+}
+
+// WithKeys signs tokens with keys[signingKeyID] and names it in the kid header. A token is validated
+// against the key its kid names, and rejected with ErrUnknownKeyID when the ring has no such key;
+// tokens without a kid, such as those issued before the ring was configured, still use the base secret
+// unless WithRequireKID is set. An empty ring keeps signing with the base secret.
+func WithKeys(signingKeyID string, keys map[string]string) JWTOption {
+	return func(j *JWTManager) {
+		j.signingKeyID = signingKeyID
+		j.keys = make(map[string][]byte, len(keys))
+		for kid, secret := range keys {
+			j.keys[kid] = []byte(secret)
+		}
+	}
+}
+
+// WithRequireKID rejects tokens without a kid with ErrMissingKeyID once a key ring is configured, which
+// retires the base secret for validation. Without a ring it has no effect, since no token carries a kid.
+func WithRequireKID(require bool) JWTOption {
+	return func(j *JWTManager) {
+		j.requireKID = require
+	}
 }
 
 // NewJWTManager creates a new JWT manager with the given secret
-func NewJWTManager(secret string) (*JWTManager, error) {
+func NewJWTManager(secret string, opts ...JWTOption) (*JWTManager, error) {
 	if secret == "" {
 		return nil, errors.New("JWT secret cannot be empty")
 	}
-	return &JWTManager{
-		secret: []byte(secret),
-	}, nil
+	j := &JWTManager{
+		secret:    []byte(secret),
+		algorithm: jwt.SigningMethodHS256.Alg(),
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	method, ok := jwt.GetSigningMethod(j.algorithm).(*jwt.SigningMethodHMAC)
+	if !ok {
+		return nil, fmt.Errorf("JWT signing algorithm %q must be HS256, HS384 or HS512", j.algorithm)
+	}
+	j.method = method
+
+	for kid, key := range j.keys {
+		if kid == "" || len(key) == 0 {
+			return nil, errors.New("JWT key ids and secrets cannot be empty")
+		}
+	}
+	if len(j.keys) > 0 {
+		if _, ok := j.keys[j.signingKeyID]; !ok {
+			return nil, fmt.Errorf("JWT signing key %q is not in the key ring", j.signingKeyID)
+		}
+	}
+	return j, nil
 }
 
 // GenerateToken creates a new JWT token for a user
+// Each token gets a random jti, so tokens minted within the same second still differ
 func (j *JWTManager) GenerateToken(userID uint) (string, error) {
 	claims := &Claims{
 		UserID: userID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token expires in 24 hours
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    j.issuer,
+			ID:        uuid.NewString(),
 		},
 	}
+	if j.audience != "" {
+		claims.Audience = jwt.ClaimStrings{j.audience}
+	}
+
+	token := jwt.NewWithClaims(j.method, claims)
+	if len(j.keys) == 0 {
+		return token.SignedString(j.secret)
+	}
+	token.Header["kid"] = j.signingKeyID
+	return token.SignedString(j.keys[j.signingKeyID])
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secret)
+// verificationKey returns the secret a token was signed with: the ring key its kid names, or the base
+// secret when it has no kid and kid-less tokens are still accepted
+func (j *JWTManager) verificationKey(token *jwt.Token) ([]byte, error) {
+	raw, hasKID := token.Header["kid"]
+	if !hasKID {
+		if j.requireKID && len(j.keys) > 0 {
+			return nil, ErrMissingKeyID
+		}
+		return j.secret, nil
+	}
+	kid, _ := raw.(string)
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	return key, nil
 }
 
 // ValidateToken parses and validates a JWT token
@@ -50,7 +179,7 @@ func (j *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
-		return j.secret, nil
+		return j.verificationKey(token)
 	})
 
 	if err != nil {
@@ -62,6 +191,15 @@ func (j *JWTManager) ValidateToken(tokenString string) (*Claims, error) {
 		return nil, errors.New("invalid token")
 	}
 
+	if claims.ID == "" {
+		return nil, ErrMissingTokenID
+	}
+	if j.issuer != "" && claims.Issuer != j.issuer {
+		return nil, ErrInvalidIssuer
+	}
+	if j.audience != "" && !slices.Contains(claims.Audience, j.audience) {
+		return nil, ErrInvalidAudience
+	}
+
 	return claims, nil
 }
-
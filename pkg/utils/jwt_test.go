@@ -4,6 +4,8 @@ import (
 	"errors"
 	"testing"
 	"time"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func TestGenerateToken(t *testing.T) {
@@ -151,20 +153,279 @@ func TestValidateToken_WrongSecret(t *testing.T) {
 	}
 }
 
+func TestValidateToken_IssuerAndAudience(t *testing.T) {
+	validator, err := NewJWTManager("test-secret-key", WithIssuer("todo-app-prod"), WithAudience("todo-api"))
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []JWTOption
+		wantErr error
+	}{
+		{
+			name: "matching issuer and audience",
+			opts: []JWTOption{WithIssuer("todo-app-prod"), WithAudience("todo-api")},
+		},
+		{
+			name:    "different issuer",
+			opts:    []JWTOption{WithIssuer("todo-app-staging"), WithAudience("todo-api")},
+			wantErr: ErrInvalidIssuer,
+		},
+		{
+			name:    "different audience",
+			opts:    []JWTOption{WithIssuer("todo-app-prod"), WithAudience("admin-api")},
+			wantErr: ErrInvalidAudience,
+		},
+		{
+			name:    "no claims",
+			wantErr: ErrInvalidIssuer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minter, err := NewJWTManager("test-secret-key", tt.opts...)
+			if err != nil {
+				t.Fatalf("Failed to create JWT manager: %v", err)
+			}
+			token, _ := minter.GenerateToken(7)
+
+			claims, err := validator.ValidateToken(token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ValidateToken() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateToken() unexpected error = %v", err)
+			}
+			if claims.UserID != 7 {
+				t.Errorf("ValidateToken() userID = %d, want 7", claims.UserID)
+			}
+		})
+	}
+}
+
+func TestValidateToken_EmptyIssuerSkipsCheck(t *testing.T) {
+	minter, err := NewJWTManager("test-secret-key", WithIssuer("todo-app-prod"), WithAudience("todo-api"))
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	token, _ := minter.GenerateToken(1)
+
+	validator, err := NewJWTManager("test-secret-key")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	if _, err := validator.ValidateToken(token); err != nil {
+		t.Errorf("ValidateToken() without issuer/audience configured should accept the token, got %v", err)
+	}
+}
+
 func TestGenerateToken_DifferentTokensForSameUser(t *testing.T) {
 	jwtManager, err := NewJWTManager("test-secret-key")
 	if err != nil {
 		t.Fatalf("Failed to create JWT manager: %v", err)
 	}
 
+	// Both tokens share the same second-precision IssuedAt, only the jti tells them apart
 	token1, _ := jwtManager.GenerateToken(1)
-	time.Sleep(time.Second) // ensure different IssuedAt (JWT uses second precision)
 	token2, _ := jwtManager.GenerateToken(1)
 
 	if token1 == "" || token2 == "" {
 		t.Error("Tokens should not be empty")
 	}
 	if token1 == token2 {
-		t.Error("Tokens should be different for separate calls (different IssuedAt or jti)")
+		t.Error("Tokens should be different for separate calls (different jti)")
+	}
+
+	claims1, err := jwtManager.ValidateToken(token1)
+	if err != nil {
+		t.Fatalf("ValidateToken() unexpected error = %v", err)
+	}
+	claims2, err := jwtManager.ValidateToken(token2)
+	if err != nil {
+		t.Fatalf("ValidateToken() unexpected error = %v", err)
+	}
+	if claims1.ID == "" || claims1.ID == claims2.ID {
+		t.Errorf("jti claims should be distinct and non-empty, got %q and %q", claims1.ID, claims2.ID)
+	}
+}
+
+func TestValidateToken_MissingTokenID(t *testing.T) {
+	jwtManager, err := NewJWTManager("test-secret-key")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+
+	// A correctly signed token without a jti, as minted before the claim was added
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}).SignedString([]byte("test-secret-key"))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	if _, err := jwtManager.ValidateToken(token); !errors.Is(err, ErrMissingTokenID) {
+		t.Errorf("ValidateToken() error = %v, want %v", err, ErrMissingTokenID)
+	}
+}
+
+func TestValidateToken_KeyRotation(t *testing.T) {
+	before, err := NewJWTManager("base-secret", WithKeys("2026-04", map[string]string{"2026-04": "april-secret"}))
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	oldToken, _ := before.GenerateToken(7)
+
+	// Rotate: sign with the new key and keep the previous one for validation only
+	after, err := NewJWTManager("base-secret", WithKeys("2026-10", map[string]string{
+		"2026-10": "october-secret",
+		"2026-04": "april-secret",
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+
+	claims, err := after.ValidateToken(oldToken)
+	if err != nil {
+		t.Fatalf("ValidateToken() with the previous key error = %v", err)
+	}
+	if claims.UserID != 7 {
+		t.Errorf("ValidateToken() UserID = %d, want 7", claims.UserID)
+	}
+
+	newToken, _ := after.GenerateToken(7)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified() error = %v", err)
+	}
+	if kid := parsed.Header["kid"]; kid != "2026-10" {
+		t.Errorf("new token kid = %v, want 2026-10", kid)
+	}
+
+	// Once the previous key is dropped from the ring, its tokens are rejected
+	retired, err := NewJWTManager("base-secret", WithKeys("2026-10", map[string]string{"2026-10": "october-secret"}))
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	if _, err := retired.ValidateToken(oldToken); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("ValidateToken() with a retired key error = %v, want %v", err, ErrUnknownKeyID)
+	}
+	if _, err := retired.ValidateToken(newToken); err != nil {
+		t.Errorf("ValidateToken() with the current key error = %v", err)
+	}
+}
+
+func TestValidateToken_UnknownKeyID(t *testing.T) {
+	jwtManager, err := NewJWTManager("base-secret", WithKeys("current", map[string]string{"current": "current-secret"}))
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+
+	// Signed with a known secret, but naming a kid the ring does not have
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			ID:        "jti",
+		},
+	})
+	token.Header["kid"] = "someone-else"
+	signed, err := token.SignedString([]byte("current-secret"))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	if _, err := jwtManager.ValidateToken(signed); !errors.Is(err, ErrUnknownKeyID) {
+		t.Errorf("ValidateToken() error = %v, want %v", err, ErrUnknownKeyID)
+	}
+}
+
+func TestValidateToken_KeyRingAcceptsTokensWithoutKID(t *testing.T) {
+	// Sessions issued from JWT_SECRET before the key ring was configured stay valid
+	legacy, err := NewJWTManager("base-secret")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	token, _ := legacy.GenerateToken(3)
+
+	jwtManager, err := NewJWTManager("base-secret", WithKeys("current", map[string]string{"current": "current-secret"}))
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	if _, err := jwtManager.ValidateToken(token); err != nil {
+		t.Errorf("ValidateToken() error = %v", err)
+	}
+}
+
+func TestValidateToken_RequireKIDRetiresBaseSecret(t *testing.T) {
+	// A token signed with the old JWT_SECRET, which has since leaked and been replaced by the ring
+	legacy, err := NewJWTManager("leaked-secret")
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	token, _ := legacy.GenerateToken(3)
+
+	jwtManager, err := NewJWTManager("leaked-secret",
+		WithKeys("current", map[string]string{"current": "current-secret"}),
+		WithRequireKID(true),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create JWT manager: %v", err)
+	}
+	if _, err := jwtManager.ValidateToken(token); !errors.Is(err, ErrMissingKeyID) {
+		t.Errorf("ValidateToken() with the retired secret error = %v, want %v", err, ErrMissingKeyID)
+	}
+
+	current, _ := jwtManager.GenerateToken(3)
+	if _, err := jwtManager.ValidateToken(current); err != nil {
+		t.Errorf("ValidateToken() with the current key error = %v", err)
+	}
+}
+
+func TestNewJWTManager_SigningOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []JWTOption
+		wantAlg string
+		wantErr bool
+	}{
+		{name: "default algorithm", wantAlg: "HS256"},
+		{name: "HS512", opts: []JWTOption{WithSigningMethod("HS512")}, wantAlg: "HS512"},
+		{name: "non-HMAC algorithm", opts: []JWTOption{WithSigningMethod("RS256")}, wantErr: true},
+		{name: "unknown algorithm", opts: []JWTOption{WithSigningMethod("HS999")}, wantErr: true},
+		{name: "signing key missing from ring", opts: []JWTOption{WithKeys("b", map[string]string{"a": "secret"})}, wantErr: true},
+		{name: "empty key secret", opts: []JWTOption{WithKeys("a", map[string]string{"a": ""})}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwtManager, err := NewJWTManager("base-secret", tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewJWTManager() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			token, _ := jwtManager.GenerateToken(1)
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+			if err != nil {
+				t.Fatalf("ParseUnverified() error = %v", err)
+			}
+			if parsed.Method.Alg() != tt.wantAlg {
+				t.Errorf("token alg = %s, want %s", parsed.Method.Alg(), tt.wantAlg)
+			}
+			if _, err := jwtManager.ValidateToken(token); err != nil {
+				t.Errorf("ValidateToken() error = %v", err)
+			}
+		})
 	}
 }
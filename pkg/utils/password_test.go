@@ -2,6 +2,8 @@ package utils
 
 import (
 	"testing"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestHashPassword(t *testing.T) {
@@ -34,7 +36,7 @@ func TestHashPassword(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			hash, err := HashPassword(tt.password)
+			hash, err := HashPassword(tt.password, bcrypt.MinCost)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("HashPassword() error = %v, wantErr %v", err, tt.wantErr)
@@ -58,8 +60,8 @@ func TestHashPassword(t *testing.T) {
 
 func TestCheckPassword(t *testing.T) {
 	// Create some hashed passwords for testing
-	hash1, _ := HashPassword("password123")
-	hash2, _ := HashPassword("different-password")
+	hash1, _ := HashPassword("password123", bcrypt.MinCost)
+	hash2, _ := HashPassword("different-password", bcrypt.MinCost)
 
 	tests := []struct {
 		name     string
@@ -113,8 +115,8 @@ func TestCheckPassword(t *testing.T) {
 func TestHashPassword_UniqueHashes(t *testing.T) {
 	password := "same-password"
 
-	hash1, _ := HashPassword(password)
-	hash2, _ := HashPassword(password)
+	hash1, _ := HashPassword(password, bcrypt.MinCost)
+	hash2, _ := HashPassword(password, bcrypt.MinCost)
 
 	// bcrypt should generate different hashes for the same password (due to salt)
 	if hash1 == hash2 {
@@ -129,3 +131,24 @@ func TestHashPassword_UniqueHashes(t *testing.T) {
 		t.Error("CheckPassword() should validate hash2")
 	}
 }
+
+func TestHashPassword_CustomCost(t *testing.T) {
+	cost := bcrypt.MinCost + 1
+
+	hash, err := HashPassword("password123", cost)
+	if err != nil {
+		t.Fatalf("HashPassword() unexpected error = %v", err)
+	}
+
+	gotCost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		t.Fatalf("bcrypt.Cost() unexpected error = %v", err)
+	}
+	if gotCost != cost {
+		t.Errorf("HashPassword() cost = %v, want %v", gotCost, cost)
+	}
+
+	if !CheckPassword("password123", hash) {
+		t.Error("CheckPassword() should validate a hash generated with a custom cost")
+	}
+}
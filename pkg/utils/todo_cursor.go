@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned by ParseTodoCursor for a cursor it did not produce
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TodoCursor points at the last todo of a page listed newest first; the next page starts after it
+// Cursors are opaque to clients but not signed: they only choose where a listing resumes, never what may be read
+type TodoCursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
+// Encode returns the cursor as unpadded base64url of "<unix seconds>:<id>"
+func (c TodoCursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.Unix(), 10) + ":" + strconv.FormatUint(uint64(c.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseTodoCursor decodes a cursor produced by TodoCursor.Encode
+func ParseTodoCursor(cursor string) (TodoCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return TodoCursor{}, ErrInvalidCursor
+	}
+	secs, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return TodoCursor{}, ErrInvalidCursor
+	}
+	unix, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return TodoCursor{}, ErrInvalidCursor
+	}
+	parsedID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil || parsedID == 0 {
+		return TodoCursor{}, ErrInvalidCursor
+	}
+	return TodoCursor{CreatedAt: time.Unix(unix, 0).UTC(), ID: uint(parsedID)}, nil
+}
+
+// SyncCursor marks how far a client has synced: every change made up to and including ChangedAt has been sent
+type SyncCursor struct {
+	ChangedAt time.Time
+}
+
+// Encode returns the cursor as unpadded base64url of "<unix seconds>"
+func (c SyncCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.ChangedAt.Unix(), 10)))
+}
+
+// ParseSyncCursor decodes a cursor produced by SyncCursor.Encode
+func ParseSyncCursor(cursor string) (SyncCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return SyncCursor{}, ErrInvalidCursor
+	}
+	unix, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || unix <= 0 {
+		return SyncCursor{}, ErrInvalidCursor
+	}
+	return SyncCursor{ChangedAt: time.Unix(unix, 0).UTC()}, nil
+}
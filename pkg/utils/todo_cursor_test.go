@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTodoCursor_RoundTrip(t *testing.T) {
+	cursor := TodoCursor{CreatedAt: time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC), ID: 42}
+
+	parsed, err := ParseTodoCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("ParseTodoCursor() error = %v", err)
+	}
+	if !parsed.CreatedAt.Equal(cursor.CreatedAt) || parsed.ID != cursor.ID {
+		t.Errorf("ParseTodoCursor() = %+v, want %+v", parsed, cursor)
+	}
+}
+
+func TestParseTodoCursor_Invalid(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "empty", cursor: ""},
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "missing separator", cursor: encode("1709285400")},
+		{name: "bad time", cursor: encode("yesterday:42")},
+		{name: "bad id", cursor: encode("1709285400:abc")},
+		{name: "zero id", cursor: encode("1709285400:0")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseTodoCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("ParseTodoCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
+
+func TestSyncCursor_RoundTrip(t *testing.T) {
+	cursor := SyncCursor{ChangedAt: time.Date(2024, 3, 1, 9, 30, 15, 0, time.UTC)}
+
+	parsed, err := ParseSyncCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("ParseSyncCursor() error = %v", err)
+	}
+	if !parsed.ChangedAt.Equal(cursor.ChangedAt) {
+		t.Errorf("ParseSyncCursor() = %+v, want %+v", parsed, cursor)
+	}
+}
+
+func TestParseSyncCursor_Invalid(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "empty", cursor: ""},
+		{name: "not base64", cursor: "not a cursor!"},
+		{name: "bad time", cursor: encode("yesterday")},
+		{name: "todo cursor", cursor: encode("1709285400:42")},
+		{name: "zero time", cursor: encode("0")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSyncCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("ParseSyncCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
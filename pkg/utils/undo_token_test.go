@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUndoTokenManager_RoundTrip(t *testing.T) {
+	m, err := NewUndoTokenManager("test-secret")
+	if err != nil {
+		t.Fatalf("NewUndoTokenManager() error = %v", err)
+	}
+	deletedAt := time.Now().Truncate(time.Second)
+	expiresAt := deletedAt.Add(30 * time.Second)
+
+	token, err := m.GenerateUndoToken(3, 42, deletedAt, expiresAt)
+	if err != nil {
+		t.Fatalf("GenerateUndoToken() error = %v", err)
+	}
+	parsed, err := m.ParseUndoToken(token)
+	if err != nil {
+		t.Fatalf("ParseUndoToken() error = %v", err)
+	}
+	if parsed.UserID != 3 || parsed.TodoID != 42 || !parsed.DeletedAt.Equal(deletedAt) || !parsed.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("ParseUndoToken() = %+v, want user 3, todo 42, deleted %v, expiry %v", parsed, deletedAt, expiresAt)
+	}
+
+	again, _ := m.GenerateUndoToken(3, 42, deletedAt, expiresAt)
+	if again == token {
+		t.Error("GenerateUndoToken() returned the same token twice for the same deletion")
+	}
+}
+
+func TestUndoTokenManager_ParseRejectsTampering(t *testing.T) {
+	m, _ := NewUndoTokenManager("test-secret")
+	other, _ := NewUndoTokenManager("other-secret")
+	token, _ := m.GenerateUndoToken(3, 42, time.Now(), time.Now().Add(time.Minute))
+	forged, _ := other.GenerateUndoToken(3, 42, time.Now(), time.Now().Add(time.Minute))
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "no signature", token: "abc"},
+		{name: "signed with another secret", token: forged},
+		{name: "payload altered", token: "x" + token},
+		{name: "signature truncated", token: token[:len(token)-2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.ParseUndoToken(tt.token); !errors.Is(err, ErrInvalidUndoToken) {
+				t.Errorf("ParseUndoToken() error = %v, want ErrInvalidUndoToken", err)
+			}
+		})
+	}
+}
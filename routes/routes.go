@@ -1,6 +1,9 @@
 package routes
 
 import (
+	"slices"
+	"time"
+
 	"todo-app/internal/handlers"
 	"todo-app/internal/middleware"
 	"todo-app/pkg/utils"
@@ -14,10 +17,32 @@ func SetupRoutes(
 	authHandler *handlers.AuthHandler,
 	todoHandler *handlers.TodoHandler,
 	categoryHandler *handlers.CategoryHandler,
+	apiTokenHandler *handlers.APITokenHandler,
+	commentHandler *handlers.CommentHandler,
+	adminHandler *handlers.AdminHandler,
 	jwtManager *utils.JWTManager,
+	apiTokens middleware.APITokenAuthenticator,
+	users middleware.UserLoader,
+	readiness handlers.ReadinessChecker,
+	purger handlers.TodoPurger,
+	adminToken string,
+	corsMaxAge time.Duration,
+	maxConcurrentPerUser int,
 ) {
-	// API group
+	// Unmatched paths and methods get the same JSON error envelope as the handlers
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(handlers.RouteNotFound)
+	router.NoMethod(handlers.MethodNotAllowed)
+
+	// Protected routes accept a login JWT or a personal access token
+	authMiddleware := middleware.AuthMiddleware(jwtManager, apiTokens)
+
+	// One limiter shared by every protected group, so the limit covers all of a user's requests
+	perUserLimit := middleware.LimitConcurrentPerUser(maxConcurrentPerUser)
+
+	// API group (request bodies must be JSON)
 	api := router.Group("/api")
+	api.Use(middleware.RequireJSON())
 
 	// Health check endpoint
 	api.GET("/health", func(c *gin.Context) {
@@ -27,6 +52,12 @@ func SetupRoutes(
 		})
 	})
 
+	// Readiness endpoint (503 until the database is reachable and migrated)
+	api.GET("/ready", handlers.Ready(readiness))
+
+	// Build metadata (set via -ldflags at build time)
+	api.GET("/version", handlers.Version)
+
 	// Headers demo (shows reading a custom request header and returning a custom response header)
 	api.GET("/headers", handlers.Headers)
 
@@ -37,33 +68,121 @@ func SetupRoutes(
 		auth.POST("/login", authHandler.Login)
 	}
 
+	// Profile routes (protected; scoped tokens cannot change the profile)
+	profile := auth.Group("/profile")
+	profile.Use(authMiddleware, middleware.RequireScope("profile"), perUserLimit)
+	{
+		profile.GET("", middleware.LoadUser(users), authHandler.GetProfile)
+		profile.PATCH("", authHandler.UpdateProfile)
+	}
+
+	// Personal access token routes (protected; scoped tokens cannot manage tokens)
+	tokens := auth.Group("/tokens")
+	tokens.Use(authMiddleware, middleware.RequireScope("tokens"), perUserLimit)
+	{
+		tokens.POST("", apiTokenHandler.CreateToken)
+		tokens.GET("", apiTokenHandler.ListTokens)
+		tokens.DELETE("/:id", apiTokenHandler.RevokeToken)
+	}
+
 	// Todo routes (protected)
 	todos := api.Group("/todos")
-	todos.Use(middleware.AuthMiddleware(jwtManager))
+	todos.Use(authMiddleware, middleware.RequireScope("todos"), perUserLimit)
 	{
 		todos.POST("", todoHandler.CreateTodo)
 		todos.GET("", todoHandler.GetTodos)
+		todos.GET("/count", todoHandler.CountTodos)
 		todos.GET("/grouped", todoHandler.GetTodosGroupedByCategory)
+		todos.POST("/cleanup", todoHandler.CleanupTodos)
+		todos.POST("/batch-get", todoHandler.BatchGetTodos)
+		todos.POST("/undo", todoHandler.UndoDelete)
+		todos.POST("/trash/restore-all", todoHandler.RestoreAllTodos)
+		todos.POST("/bulk-move", todoHandler.BulkMoveTodos)
+		todos.GET("/report", todoHandler.GetCompletionReport)
+		todos.GET("/recent", todoHandler.GetRecentTodos)
+		todos.GET("/ids", todoHandler.GetTodoIDs)
+		todos.GET("/sync", todoHandler.SyncTodos)
 		todos.GET("/:id", todoHandler.GetTodo)
-		todos.PUT("/:id", todoHandler.UpdateTodo)
+		todos.GET("/:id/history", todoHandler.GetTodoHistory)
+		todos.GET("/:id/permissions", todoHandler.GetTodoPermissions)
+		todos.POST("/:id/touch", todoHandler.TouchTodo)
+		todos.PUT("/:id", todoHandler.ReplaceTodo)
+		todos.PATCH("/:id", todoHandler.UpdateTodo)
 		todos.DELETE("/:id", todoHandler.DeleteTodo)
+		todos.POST("/:id/comments", commentHandler.CreateComment)
+		todos.GET("/:id/comments", commentHandler.GetComments)
+		todos.DELETE("/:id/comments/:commentID", commentHandler.DeleteComment)
 	}
 
 	// Category routes (protected)
 	// Note: Categories are auto-created when creating todos
 	// These endpoints are for managing existing categories and sharing
 	categories := api.Group("/categories")
-	categories.Use(middleware.AuthMiddleware(jwtManager))
+	categories.Use(authMiddleware, middleware.RequireScope("categories"), perUserLimit)
 	{
 		categories.GET("", categoryHandler.GetCategories)
+		categories.GET("/writable", categoryHandler.GetWritableCategories)
+		categories.GET("/shared-by-me", categoryHandler.GetCategoriesSharedByMe)
+		categories.POST("/cleanup-empty", categoryHandler.CleanupEmptyCategories)
 		categories.GET("/:id", categoryHandler.GetCategory)
+		categories.GET("/:id/permission", categoryHandler.GetPermission)
+		categories.GET("/:id/todos", todoHandler.GetCategoryTodos)
+		categories.GET("/:id/count", todoHandler.CountCategoryTodos)
 		categories.PUT("/:id", categoryHandler.UpdateCategory)
 		categories.DELETE("/:id", categoryHandler.DeleteCategory)
+		categories.POST("/:id/move-todos", categoryHandler.MoveTodos)
+		categories.POST("/:id/complete-all", todoHandler.CompleteAllInCategory)
 
 		// Category sharing
 		categories.POST("/:id/share", categoryHandler.ShareCategory)
+		categories.PUT("/:id/share", categoryHandler.UpsertShare)
+		categories.POST("/:id/share/preview", categoryHandler.PreviewShare)
 		categories.GET("/:id/shares", categoryHandler.GetShares)
+		categories.PUT("/:id/shares", categoryHandler.BulkUpdateSharePermissions)
 		categories.PUT("/:id/shares/:user_id", categoryHandler.UpdateSharePermission)
+		categories.DELETE("/:id/shares", categoryHandler.UnshareAll)
 		categories.DELETE("/:id/shares/:user_id", categoryHandler.UnshareCategory)
 	}
+
+	// Share overview across all of the caller's categories (protected)
+	shares := api.Group("/shares")
+	shares.Use(authMiddleware, middleware.RequireScope("categories"), perUserLimit)
+	{
+		shares.GET("/granted", categoryHandler.GetGrantedMembers)
+	}
+
+	// Admin routes
+	admin := api.Group("/admin")
+	// Browsers may only GET and POST here, and must be allowed to send the admin token header
+	middleware.GroupCORS(admin, middleware.CORSConfig{
+		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowedHeaders: append(slices.Clone(middleware.DefaultCORSConfig(corsMaxAge).AllowedHeaders), middleware.AdminTokenHeader),
+		MaxAge:         corsMaxAge,
+	})
+
+	// Operations (guarded by the X-Admin-Token shared secret, disabled when no token is configured).
+	// These are called by schedulers and deploy scripts that have no user account, so they cannot use
+	// the admin flag the user tools below rely on.
+	ops := admin.Group("")
+	ops.Use(middleware.RequireAdminToken(adminToken))
+	{
+		ops.POST("/purge", handlers.Purge(purger))
+	}
+
+	// User tools (protected; only for users flagged as admins, scoped tokens cannot use them)
+	adminUsers := admin.Group("")
+	adminUsers.Use(authMiddleware, middleware.RequireScope("admin"), middleware.LoadUser(users), middleware.AdminMiddleware(), perUserLimit)
+	{
+		adminUsers.GET("/users", adminHandler.ListUsers)
+	}
+}
+
+// SetupDebugRoutes registers the /debug endpoints when enabled; otherwise they do not exist and return 404
+func SetupDebugRoutes(router *gin.Engine, enabled bool, startedAt time.Time) {
+	if !enabled {
+		return
+	}
+
+	// Runtime stats (goroutines, heap, uptime) for quick ops checks without a metrics stack
+	router.GET("/debug/stats", handlers.DebugStats(startedAt))
 }
@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestSetupDebugRoutes(t *testing.T) {
+	t.Run("disabled returns 404", func(t *testing.T) {
+		router := gin.New()
+		SetupDebugRoutes(router, false, time.Now())
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/stats", nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("GET /debug/stats: expected 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("enabled reports stats", func(t *testing.T) {
+		router := gin.New()
+		SetupDebugRoutes(router, true, time.Now().Add(-time.Minute))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/stats", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET /debug/stats: expected 200, got %d", w.Code)
+		}
+
+		var response map[string]float64
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		for _, field := range []string{"goroutines", "heap_alloc_bytes", "uptime_seconds"} {
+			if response[field] <= 0 {
+				t.Errorf("%s = %v, want a positive value", field, response[field])
+			}
+		}
+	})
+}
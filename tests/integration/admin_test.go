@@ -0,0 +1,67 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"todo-app/internal/repository"
+	"todo-app/internal/services"
+	"todo-app/tests/testutil"
+)
+
+func TestAdmin_ListUsers(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	// Registering never grants admin; the configured admin email is promoted the way startup does it
+	userToken := testutil.MustRegister(t, app.Router, "Regular User", "user@example.com", "password123")
+	adminToken := testutil.MustRegister(t, app.Router, "Admin User", "admin@example.com", "password123")
+	userSvc := services.NewUserService(repository.NewSQLUserRepository(app.DB.Queries), services.PaginationConfig{DefaultPageSize: 10, MaxPageSize: 100})
+	if promoted, err := userSvc.SeedAdmin(ctx, "admin@example.com"); err != nil || !promoted {
+		t.Fatalf("seed admin: promoted=%v err=%v", promoted, err)
+	}
+
+	w := testutil.Request(app.Router, http.MethodGet, "/api/admin/users", nil, userToken)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("non-admin list users: expected 403, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	w = testutil.Request(app.Router, http.MethodGet, "/api/admin/users", nil, adminToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("admin list users: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data  []map[string]any `json:"data"`
+		Total int64            `json:"total"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode list users: %v", err)
+	}
+	if resp.Total != 2 || len(resp.Data) != 2 {
+		t.Fatalf("list users: expected 2 users, got total=%d len=%d", resp.Total, len(resp.Data))
+	}
+	// Oldest account first
+	if resp.Data[0]["email"] != "user@example.com" || resp.Data[1]["email"] != "admin@example.com" {
+		t.Errorf("list users: expected user then admin, got %v then %v", resp.Data[0]["email"], resp.Data[1]["email"])
+	}
+	if resp.Data[0]["is_admin"] != false || resp.Data[1]["is_admin"] != true {
+		t.Errorf("list users: expected only the admin flagged, got %v and %v", resp.Data[0]["is_admin"], resp.Data[1]["is_admin"])
+	}
+	for _, user := range resp.Data {
+		if _, ok := user["password"]; ok {
+			t.Errorf("list users: user %v exposes a password", user["email"])
+		}
+	}
+}
@@ -4,6 +4,8 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"testing"
 	"time"
@@ -79,3 +81,80 @@ func TestAuth_ProtectedRouteWithoutToken(t *testing.T) {
 		t.Errorf("GET /api/todos without token: expected 401, got %d", w.Code)
 	}
 }
+
+func TestAuth_PersonalAccessToken(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	session := testutil.MustRegister(t, app.Router, "Token User", "token@example.com", "password123")
+
+	body, _ := json.Marshal(map[string]any{"name": "ci", "scopes": []string{"todos:read"}})
+	w := testutil.Request(app.Router, http.MethodPost, "/api/auth/tokens", body, session)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST /api/auth/tokens: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data struct {
+			Token    string `json:"token"`
+			APIToken struct {
+				ID uint `json:"id"`
+			} `json:"api_token"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode token response: %v", err)
+	}
+	pat := created.Data.Token
+
+	if w := testutil.Request(app.Router, http.MethodGet, "/api/todos", nil, pat); w.Code != http.StatusOK {
+		t.Errorf("GET /api/todos with PAT: expected 200, got %d", w.Code)
+	}
+	todoBody, _ := json.Marshal(map[string]string{"title": "From script", "category": "Work"})
+	if w := testutil.Request(app.Router, http.MethodPost, "/api/todos", todoBody, pat); w.Code != http.StatusForbidden {
+		t.Errorf("POST /api/todos with read-only PAT: expected 403, got %d", w.Code)
+	}
+
+	revokePath := fmt.Sprintf("/api/auth/tokens/%d", created.Data.APIToken.ID)
+	if w := testutil.Request(app.Router, http.MethodDelete, revokePath, nil, session); w.Code != http.StatusOK {
+		t.Fatalf("DELETE %s: expected 200, got %d", revokePath, w.Code)
+	}
+	if w := testutil.Request(app.Router, http.MethodGet, "/api/todos", nil, pat); w.Code != http.StatusUnauthorized {
+		t.Errorf("GET /api/todos with revoked PAT: expected 401, got %d", w.Code)
+	}
+}
+
+func TestAuth_UpdateProfileTimezone(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Zoned", "zoned@example.com", "password123")
+
+	w := testutil.Request(app.Router, http.MethodPatch, "/api/auth/profile", []byte(`{"timezone":"Not/A_Zone"}`), token)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid timezone: expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = testutil.Request(app.Router, http.MethodPatch, "/api/auth/profile", []byte(`{"timezone":"Europe/Berlin"}`), token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update timezone: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			Timezone string `json:"timezone"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Data.Timezone != "Europe/Berlin" {
+		t.Errorf("timezone = %q, want Europe/Berlin", resp.Data.Timezone)
+	}
+}
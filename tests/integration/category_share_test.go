@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
@@ -210,3 +211,648 @@ func TestCategoryShare_ShareAlreadyExists(t *testing.T) {
 		t.Errorf("duplicate share: expected 409, got %d body=%s", w.Code, w.Body.String())
 	}
 }
+
+func TestCategoryShare_ListTodosByCreator(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@creator.com", "password123")
+	sharedToken := testutil.MustRegister(t, app.Router, "Shared User", "shared@creator.com", "password123")
+
+	// Owner creates a todo (auto-creates category "Team")
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Owner task","description":"","category":"Team"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var todoResp struct {
+		Data struct {
+			CategoryID uint `json:"category_id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&todoResp); err != nil {
+		t.Fatalf("decode todo response: %v", err)
+	}
+	categoryIDStr := strconv.FormatUint(uint64(todoResp.Data.CategoryID), 10)
+
+	// Share with write access so the second user can add a todo of their own
+	shareBody := []byte(`{"email":"shared@creator.com","permission":"write"}`)
+	w = testutil.Request(app.Router, http.MethodPost, "/api/categories/"+categoryIDStr+"/share", shareBody, ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("share category: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	w = testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Shared task","description":"","category_id":`+categoryIDStr+`}`), sharedToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create shared todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	listTitles := func(token, createdBy string) []string {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodGet, "/api/categories/"+categoryIDStr+"/todos?created_by="+createdBy, nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("list todos created_by=%s: expected 200, got %d body=%s", createdBy, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []struct {
+				Title string `json:"title"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode todos: %v", err)
+		}
+		titles := make([]string, 0, len(resp.Data))
+		for _, todo := range resp.Data {
+			titles = append(titles, todo.Title)
+		}
+		return titles
+	}
+
+	if got := listTitles(sharedToken, "me"); len(got) != 1 || got[0] != "Shared task" {
+		t.Errorf("created_by=me: expected [Shared task], got %v", got)
+	}
+	if got := listTitles(sharedToken, "others"); len(got) != 1 || got[0] != "Owner task" {
+		t.Errorf("created_by=others: expected [Owner task], got %v", got)
+	}
+	if got := listTitles(ownerToken, ""); len(got) != 2 {
+		t.Errorf("no filter: expected 2 todos, got %v", got)
+	}
+
+	// Unknown filter values are rejected
+	w = testutil.Request(app.Router, http.MethodGet, "/api/categories/"+categoryIDStr+"/todos?created_by=someone", nil, ownerToken)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid created_by: expected 400, got %d", w.Code)
+	}
+}
+
+func TestCategoryShare_ExpandCreator(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@expand.com", "password123")
+	sharedToken := testutil.MustRegister(t, app.Router, "Shared User", "shared@expand.com", "password123")
+
+	// Owner creates a todo (auto-creates category "Team")
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Owner task","category":"Team"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var ownerTodo struct {
+		Data struct {
+			CategoryID uint `json:"category_id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&ownerTodo); err != nil {
+		t.Fatalf("decode todo response: %v", err)
+	}
+	categoryIDStr := strconv.FormatUint(uint64(ownerTodo.Data.CategoryID), 10)
+
+	w = testutil.Request(app.Router, http.MethodPost, "/api/categories/"+categoryIDStr+"/share", []byte(`{"email":"shared@expand.com","permission":"write"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("share category: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	// The shared user adds a todo; it belongs to the owner but records who created it
+	w = testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Shared task","category_id":`+categoryIDStr+`}`), sharedToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create shared todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var todoResp struct {
+		Data struct {
+			ID uint `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&todoResp); err != nil {
+		t.Fatalf("decode todo response: %v", err)
+	}
+
+	var resp struct {
+		Data struct {
+			CreatedBy uint `json:"created_by"`
+			Creator   *struct {
+				ID    uint   `json:"id"`
+				Name  string `json:"name"`
+				Email string `json:"email"`
+			} `json:"creator"`
+		} `json:"data"`
+	}
+	w = testutil.Request(app.Router, http.MethodGet, "/api/todos/"+strconv.FormatUint(uint64(todoResp.Data.ID), 10)+"?expand=creator", nil, ownerToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode todo: %v", err)
+	}
+	if resp.Data.Creator == nil || resp.Data.Creator.ID != resp.Data.CreatedBy || resp.Data.Creator.Name != "Shared User" || resp.Data.Creator.Email != "shared@expand.com" {
+		t.Errorf("creator = %+v, want the shared user", resp.Data.Creator)
+	}
+}
+
+func TestCategoryShare_GroupedScope(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@scope.com", "password123")
+	userToken := testutil.MustRegister(t, app.Router, "User", "user@scope.com", "password123")
+
+	// The user owns "Home"; "Team" is owned by someone else and shared with the user
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Own task","description":"","category":"Home"}`), userToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create own todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	w = testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Team task","description":"","category":"Team"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create team todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var todoResp struct {
+		Data struct {
+			CategoryID uint `json:"category_id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&todoResp); err != nil {
+		t.Fatalf("decode todo response: %v", err)
+	}
+	categoryIDStr := strconv.FormatUint(uint64(todoResp.Data.CategoryID), 10)
+	w = testutil.Request(app.Router, http.MethodPost, "/api/categories/"+categoryIDStr+"/share", []byte(`{"email":"user@scope.com","permission":"read"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("share category: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	groupedNames := func(scope string) []string {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodGet, "/api/todos/grouped?scope="+scope, nil, userToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("grouped scope=%s: expected 200, got %d body=%s", scope, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []struct {
+				Name string `json:"name"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode grouped: %v", err)
+		}
+		names := make([]string, 0, len(resp.Data))
+		for _, category := range resp.Data {
+			names = append(names, category.Name)
+		}
+		return names
+	}
+
+	if got := groupedNames("owned"); len(got) != 1 || got[0] != "Home" {
+		t.Errorf("scope=owned: expected [Home], got %v", got)
+	}
+	if got := groupedNames("shared"); len(got) != 1 || got[0] != "Team" {
+		t.Errorf("scope=shared: expected [Team], got %v", got)
+	}
+	if got := groupedNames("all"); len(got) != 2 {
+		t.Errorf("scope=all: expected 2 categories, got %v", got)
+	}
+	if got := groupedNames(""); len(got) != 2 {
+		t.Errorf("default scope: expected 2 categories, got %v", got)
+	}
+
+	w = testutil.Request(app.Router, http.MethodGet, "/api/todos/grouped?scope=mine", nil, userToken)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid scope: expected 400, got %d", w.Code)
+	}
+}
+
+func TestCategoryShare_WritableCategories(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@writable.com", "password123")
+	userToken := testutil.MustRegister(t, app.Router, "User", "user@writable.com", "password123")
+
+	// The user owns "Home"; "Editable" is shared with write and "ReadOnly" with read
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Own task","description":"","category":"Home"}`), userToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create own todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	share := func(category, permission string) {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Task","description":"","category":"`+category+`"}`), ownerToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo in %s: expected 201, got %d body=%s", category, w.Code, w.Body.String())
+		}
+		var todoResp struct {
+			Data struct {
+				CategoryID uint `json:"category_id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&todoResp); err != nil {
+			t.Fatalf("decode todo response: %v", err)
+		}
+		categoryIDStr := strconv.FormatUint(uint64(todoResp.Data.CategoryID), 10)
+		w = testutil.Request(app.Router, http.MethodPost, "/api/categories/"+categoryIDStr+"/share", []byte(`{"email":"user@writable.com","permission":"`+permission+`"}`), ownerToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("share %s: expected 201, got %d body=%s", category, w.Code, w.Body.String())
+		}
+	}
+	share("Editable", "write")
+	share("ReadOnly", "read")
+
+	w = testutil.Request(app.Router, http.MethodGet, "/api/categories/writable", nil, userToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("writable: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []struct {
+			ID         uint   `json:"id"`
+			Name       string `json:"name"`
+			Permission string `json:"permission"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode writable: %v", err)
+	}
+
+	got := make(map[string]string, len(resp.Data))
+	for _, category := range resp.Data {
+		got[category.Name] = category.Permission
+	}
+	if len(got) != 2 || got["Home"] != "owner" || got["Editable"] != "write" {
+		t.Errorf("expected {Home: owner, Editable: write}, got %v", got)
+	}
+	if _, ok := got["ReadOnly"]; ok {
+		t.Error("read-only shared category should not be writable")
+	}
+}
+
+func TestCategoryShare_SharedByMe(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@sharedbyme.com", "password123")
+	testutil.MustRegister(t, app.Router, "Reader", "reader@sharedbyme.com", "password123")
+
+	// The owner has two categories, "Shared" and "Private"; only "Shared" is shared
+	createCategory := func(category string) string {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Task","category":"`+category+`"}`), ownerToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo in %s: expected 201, got %d body=%s", category, w.Code, w.Body.String())
+		}
+		var todoResp struct {
+			Data struct {
+				CategoryID uint `json:"category_id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&todoResp); err != nil {
+			t.Fatalf("decode todo response: %v", err)
+		}
+		return strconv.FormatUint(uint64(todoResp.Data.CategoryID), 10)
+	}
+	sharedID := createCategory("Shared")
+	createCategory("Private")
+
+	w := testutil.Request(app.Router, http.MethodPost, "/api/categories/"+sharedID+"/share", []byte(`{"email":"reader@sharedbyme.com","permission":"read"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("share: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	w = testutil.Request(app.Router, http.MethodGet, "/api/categories/shared-by-me", nil, ownerToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("shared-by-me: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []struct {
+			Name   string `json:"name"`
+			Shares []struct {
+				SharedWithUserEmail string `json:"shared_with_user_email"`
+				Permission          string `json:"permission"`
+			} `json:"shares"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode shared-by-me: %v", err)
+	}
+
+	if len(resp.Data) != 1 || resp.Data[0].Name != "Shared" {
+		t.Fatalf("expected only the Shared category, got %+v", resp.Data)
+	}
+	shares := resp.Data[0].Shares
+	if len(shares) != 1 || shares[0].SharedWithUserEmail != "reader@sharedbyme.com" || shares[0].Permission != "read" {
+		t.Errorf("expected one read share for reader@sharedbyme.com, got %+v", shares)
+	}
+}
+
+func TestCategoryShare_ListCategoriesOnlyShowsAccessibleTodos(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@visible.com", "password123")
+	userToken := testutil.MustRegister(t, app.Router, "User", "user@visible.com", "password123")
+
+	create := func(token, title, category string) uint {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"`+title+`","category":"`+category+`"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create %q: expected 201, got %d body=%s", title, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				CategoryID uint `json:"category_id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode todo response: %v", err)
+		}
+		return resp.Data.CategoryID
+	}
+
+	// The owner keeps "Private" to themselves and shares "Team" with the user
+	create(userToken, "Own task", "Home")
+	create(ownerToken, "Secret task", "Private")
+	teamID := strconv.FormatUint(uint64(create(ownerToken, "Team task", "Team")), 10)
+	w := testutil.Request(app.Router, http.MethodPost, "/api/categories/"+teamID+"/share", []byte(`{"email":"user@visible.com","permission":"read"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("share category: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	visibleTitles := func() map[string]bool {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodGet, "/api/categories", nil, userToken)
+		if w.Code != http.StatusOK {
+			t.Fatalf("list categories: expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		type withTodos struct {
+			Todos []struct {
+				Title string `json:"title"`
+			} `json:"todos"`
+		}
+		var resp struct {
+			Data struct {
+				Owned  []withTodos `json:"owned_categories"`
+				Shared []withTodos `json:"shared_categories"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode categories: %v", err)
+		}
+		titles := map[string]bool{}
+		for _, category := range append(resp.Data.Owned, resp.Data.Shared...) {
+			for _, todo := range category.Todos {
+				titles[todo.Title] = true
+			}
+		}
+		return titles
+	}
+
+	if got := visibleTitles(); len(got) != 2 || !got["Own task"] || !got["Team task"] {
+		t.Errorf("visible todos = %v, want only Own task and Team task", got)
+	}
+
+	// Once the share is gone, so are its todos
+	w = testutil.Request(app.Router, http.MethodDelete, "/api/categories/"+teamID+"/shares", nil, ownerToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unshare all: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if got := visibleTitles(); len(got) != 1 || !got["Own task"] {
+		t.Errorf("visible todos after unsharing = %v, want only Own task", got)
+	}
+}
+
+func TestCategoryShare_ListTodosInSeveralCategories(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	userToken := testutil.MustRegister(t, app.Router, "User", "user@multi.com", "password123")
+	otherToken := testutil.MustRegister(t, app.Router, "Other", "other@multi.com", "password123")
+
+	createTodo := func(token, title, category string) string {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"`+title+`","category":"`+category+`"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo %s: expected 201, got %d body=%s", title, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				CategoryID uint `json:"category_id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode todo response: %v", err)
+		}
+		return strconv.FormatUint(uint64(resp.Data.CategoryID), 10)
+	}
+
+	// The user owns Home, reads Team through a share, and has no access to Private
+	homeID := createTodo(userToken, "Home task", "Home")
+	teamID := createTodo(otherToken, "Team task", "Team")
+	privateID := createTodo(otherToken, "Private task", "Private")
+
+	w := testutil.Request(app.Router, http.MethodPost, "/api/categories/"+teamID+"/share", []byte(`{"email":"user@multi.com","permission":"read"}`), otherToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("share category: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	w = testutil.Request(app.Router, http.MethodGet, "/api/todos?category_ids="+homeID+","+teamID+","+privateID, nil, userToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list todos: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data []struct {
+			Title string `json:"title"`
+		} `json:"data"`
+		Total int64 `json:"total"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode todos: %v", err)
+	}
+	titles := make(map[string]bool, len(resp.Data))
+	for _, todo := range resp.Data {
+		titles[todo.Title] = true
+	}
+	if resp.Total != 2 || len(titles) != 2 || !titles["Home task"] || !titles["Team task"] {
+		t.Errorf("expected Home task and Team task (total 2), got %v (total %d)", titles, resp.Total)
+	}
+
+	w = testutil.Request(app.Router, http.MethodGet, "/api/todos?category_ids="+homeID+",x", nil, userToken)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unparsable category_ids: expected 400, got %d", w.Code)
+	}
+}
+
+func TestCategoryShare_WriteSharerCannotDeleteOrRenameCategory(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@guard.com", "password123")
+	writerToken := testutil.MustRegister(t, app.Router, "Writer", "writer@guard.com", "password123")
+
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Task","description":"","category":"Team"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var todoResp struct {
+		Data struct {
+			CategoryID uint `json:"category_id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&todoResp); err != nil {
+		t.Fatalf("decode todo response: %v", err)
+	}
+	categoryPath := "/api/categories/" + strconv.FormatUint(uint64(todoResp.Data.CategoryID), 10)
+
+	w = testutil.Request(app.Router, http.MethodPost, categoryPath+"/share", []byte(`{"email":"writer@guard.com","permission":"write"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("share: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	w = testutil.Request(app.Router, http.MethodPut, categoryPath, []byte(`{"name":"Taken over"}`), writerToken)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("rename by write sharer: expected 403, got %d body=%s", w.Code, w.Body.String())
+	}
+	w = testutil.Request(app.Router, http.MethodDelete, categoryPath, nil, writerToken)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("delete by write sharer: expected 403, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	// The category is untouched and still shared
+	w = testutil.Request(app.Router, http.MethodGet, categoryPath, nil, writerToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get category: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode category: %v", err)
+	}
+	if resp.Data.Name != "Team" {
+		t.Errorf("category name = %q, want Team", resp.Data.Name)
+	}
+}
+
+func TestCategoryShare_GrantedMembersAreDeduplicated(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@granted.com", "password123")
+	testutil.MustRegister(t, app.Router, "Bob", "bob@granted.com", "password123")
+	testutil.MustRegister(t, app.Router, "Alice", "alice@granted.com", "password123")
+
+	createCategory := func(category string) string {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Task","category":"`+category+`"}`), ownerToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo in %s: expected 201, got %d body=%s", category, w.Code, w.Body.String())
+		}
+		var todoResp struct {
+			Data struct {
+				CategoryID uint `json:"category_id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&todoResp); err != nil {
+			t.Fatalf("decode todo response: %v", err)
+		}
+		return strconv.FormatUint(uint64(todoResp.Data.CategoryID), 10)
+	}
+	share := func(categoryID, email, permission string) {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/categories/"+categoryID+"/share", []byte(`{"email":"`+email+`","permission":"`+permission+`"}`), ownerToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("share %s with %s: expected 201, got %d body=%s", categoryID, email, w.Code, w.Body.String())
+		}
+	}
+
+	// Bob has access to both categories, Alice only to Work
+	homeID := createCategory("Home")
+	workID := createCategory("Work")
+	share(homeID, "bob@granted.com", "read")
+	share(workID, "bob@granted.com", "write")
+	share(workID, "alice@granted.com", "read")
+
+	w := testutil.Request(app.Router, http.MethodGet, "/api/shares/granted", nil, ownerToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("granted: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	type grant struct {
+		CategoryName string `json:"category_name"`
+		Permission   string `json:"permission"`
+	}
+	var resp struct {
+		Data []struct {
+			Email      string  `json:"email"`
+			Categories []grant `json:"categories"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode granted: %v", err)
+	}
+
+	if len(resp.Data) != 2 || resp.Data[0].Email != "alice@granted.com" || resp.Data[1].Email != "bob@granted.com" {
+		t.Fatalf("expected alice then bob once each, got %+v", resp.Data)
+	}
+	if got, want := resp.Data[0].Categories, []grant{{"Work", "read"}}; !slices.Equal(got, want) {
+		t.Errorf("alice grants = %+v, want %+v", got, want)
+	}
+	if got, want := resp.Data[1].Categories, []grant{{"Home", "read"}, {"Work", "write"}}; !slices.Equal(got, want) {
+		t.Errorf("bob grants = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,239 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"todo-app/tests/testutil"
+)
+
+func TestCategory_RecreateDeletedName(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Category User", "category@example.com", "password123")
+
+	createCategory := func(wantStatus int) string {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/categories", []byte(`{"name":"Errands"}`), token)
+		if w.Code != wantStatus {
+			t.Fatalf("create category: expected %d, got %d body=%s", wantStatus, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode category response: %v", err)
+		}
+		return strconv.FormatUint(uint64(resp.Data.ID), 10)
+	}
+
+	categoryID := createCategory(http.StatusCreated)
+
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Buy milk","category_id":`+categoryID+`}`), token)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var todoResp struct {
+		Data struct {
+			ID uint `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&todoResp); err != nil {
+		t.Fatalf("decode todo response: %v", err)
+	}
+	todoID := strconv.FormatUint(uint64(todoResp.Data.ID), 10)
+
+	w = testutil.Request(app.Router, http.MethodDelete, "/api/categories/"+categoryID, nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete category: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	// The deleted category and its todos are gone
+	if w = testutil.Request(app.Router, http.MethodGet, "/api/categories/"+categoryID, nil, token); w.Code != http.StatusNotFound {
+		t.Errorf("get deleted category: expected 404, got %d", w.Code)
+	}
+	if w = testutil.Request(app.Router, http.MethodGet, "/api/todos/"+todoID, nil, token); w.Code != http.StatusNotFound {
+		t.Errorf("get todo in deleted category: expected 404, got %d", w.Code)
+	}
+
+	// The name is free again, but only for one live category
+	if recreatedID := createCategory(http.StatusCreated); recreatedID == categoryID {
+		t.Errorf("recreated category reused deleted id %s", categoryID)
+	}
+	w = testutil.Request(app.Router, http.MethodPost, "/api/categories", []byte(`{"name":"Errands"}`), token)
+	if w.Code != http.StatusConflict {
+		t.Errorf("create duplicate live category: expected 409, got %d body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestCategory_UnshareAll(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@example.com", "password123")
+	recipients := []string{"one@example.com", "two@example.com", "three@example.com"}
+	recipientTokens := make([]string, len(recipients))
+	for i, email := range recipients {
+		recipientTokens[i] = testutil.MustRegister(t, app.Router, "Recipient", email, "password123")
+	}
+
+	w := testutil.Request(app.Router, http.MethodPost, "/api/categories", []byte(`{"name":"Team"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create category: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var catResp struct {
+		Data struct {
+			ID uint `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&catResp); err != nil {
+		t.Fatalf("decode category response: %v", err)
+	}
+	categoryID := strconv.FormatUint(uint64(catResp.Data.ID), 10)
+
+	for _, email := range recipients {
+		body := []byte(`{"email":"` + email + `","permission":"read"}`)
+		if w = testutil.Request(app.Router, http.MethodPost, "/api/categories/"+categoryID+"/share", body, ownerToken); w.Code != http.StatusCreated {
+			t.Fatalf("share with %s: expected 201, got %d body=%s", email, w.Code, w.Body.String())
+		}
+	}
+
+	// A recipient cannot revoke everyone else's access
+	if w = testutil.Request(app.Router, http.MethodDelete, "/api/categories/"+categoryID+"/shares", nil, recipientTokens[0]); w.Code != http.StatusForbidden {
+		t.Fatalf("unshare all as recipient: expected 403, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	w = testutil.Request(app.Router, http.MethodDelete, "/api/categories/"+categoryID+"/shares", nil, ownerToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unshare all: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			Removed int64 `json:"removed"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode unshare response: %v", err)
+	}
+	if resp.Data.Removed != 3 {
+		t.Errorf("expected 3 shares removed, got %d", resp.Data.Removed)
+	}
+
+	for i, token := range recipientTokens {
+		if w = testutil.Request(app.Router, http.MethodGet, "/api/categories/"+categoryID, nil, token); w.Code == http.StatusOK {
+			t.Errorf("recipient %s still has access after unshare all", recipients[i])
+		}
+	}
+}
+
+func TestCategory_CleanupEmpty(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Tidy User", "tidy@example.com", "password123")
+	testutil.MustRegister(t, app.Router, "Friend", "friend@example.com", "password123")
+
+	createTodo := func(category string) (todoID, categoryID string) {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Task","category":"`+category+`"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				ID         uint `json:"id"`
+				CategoryID uint `json:"category_id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode todo response: %v", err)
+		}
+		return strconv.FormatUint(uint64(resp.Data.ID), 10), strconv.FormatUint(uint64(resp.Data.CategoryID), 10)
+	}
+
+	_, busyID := createTodo("Busy")
+	// Auto-created for a todo that was later deleted
+	deletedTodoID, emptyID := createTodo("Empty")
+	if w := testutil.Request(app.Router, http.MethodDelete, "/api/todos/"+deletedTodoID, nil, token); w.Code != http.StatusOK {
+		t.Fatalf("delete todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	// Empty too, but shared, so it is kept
+	sharedTodoID, sharedID := createTodo("Shared")
+	if w := testutil.Request(app.Router, http.MethodPost, "/api/categories/"+sharedID+"/share", []byte(`{"email":"friend@example.com","permission":"read"}`), token); w.Code != http.StatusCreated {
+		t.Fatalf("share: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := testutil.Request(app.Router, http.MethodDelete, "/api/todos/"+sharedTodoID, nil, token); w.Code != http.StatusOK {
+		t.Fatalf("delete shared todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	cleanupEmpty := func(path string) []string {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, path, nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("cleanup: expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode cleanup response: %v", err)
+		}
+		var ids []string
+		for _, category := range resp.Data {
+			ids = append(ids, strconv.FormatUint(uint64(category.ID), 10))
+		}
+		return ids
+	}
+
+	// The default dry run only reports what would go
+	if ids := cleanupEmpty("/api/categories/cleanup-empty"); len(ids) != 1 || ids[0] != emptyID {
+		t.Fatalf("dry run: removed %v, want [%s]", ids, emptyID)
+	}
+	if w := testutil.Request(app.Router, http.MethodGet, "/api/categories/"+emptyID, nil, token); w.Code != http.StatusOK {
+		t.Errorf("empty category after dry run: expected 200, got %d", w.Code)
+	}
+
+	if ids := cleanupEmpty("/api/categories/cleanup-empty?dry_run=false"); len(ids) != 1 || ids[0] != emptyID {
+		t.Fatalf("cleanup: removed %v, want [%s]", ids, emptyID)
+	}
+	if w := testutil.Request(app.Router, http.MethodGet, "/api/categories/"+emptyID, nil, token); w.Code != http.StatusNotFound {
+		t.Errorf("empty category after cleanup: expected 404, got %d", w.Code)
+	}
+	for _, id := range []string{busyID, sharedID} {
+		if w := testutil.Request(app.Router, http.MethodGet, "/api/categories/"+id, nil, token); w.Code != http.StatusOK {
+			t.Errorf("category %s after cleanup: expected 200, got %d", id, w.Code)
+		}
+	}
+}
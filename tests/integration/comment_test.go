@@ -0,0 +1,117 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"todo-app/tests/testutil"
+)
+
+func TestComment_PermissionGates(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	ownerToken := testutil.MustRegister(t, app.Router, "Owner", "owner@comment.com", "password123")
+	writerToken := testutil.MustRegister(t, app.Router, "Writer", "writer@comment.com", "password123")
+	readerToken := testutil.MustRegister(t, app.Router, "Reader", "reader@comment.com", "password123")
+	outsiderToken := testutil.MustRegister(t, app.Router, "Outsider", "outsider@comment.com", "password123")
+
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Plan offsite","category":"Team"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	var todoResp struct {
+		Data struct {
+			ID         uint `json:"id"`
+			CategoryID uint `json:"category_id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&todoResp); err != nil {
+		t.Fatalf("decode todo response: %v", err)
+	}
+	commentsPath := "/api/todos/" + strconv.FormatUint(uint64(todoResp.Data.ID), 10) + "/comments"
+	sharePath := "/api/categories/" + strconv.FormatUint(uint64(todoResp.Data.CategoryID), 10) + "/share"
+
+	for email, permission := range map[string]string{"writer@comment.com": "write", "reader@comment.com": "read"} {
+		w = testutil.Request(app.Router, http.MethodPost, sharePath, []byte(`{"email":"`+email+`","permission":"`+permission+`"}`), ownerToken)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("share with %s: expected 201, got %d body=%s", email, w.Code, w.Body.String())
+		}
+	}
+
+	comment := func(token, body string) string {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, commentsPath, []byte(`{"body":"`+body+`"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("comment %q: expected 201, got %d body=%s", body, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode comment response: %v", err)
+		}
+		return strconv.FormatUint(uint64(resp.Data.ID), 10)
+	}
+	readerComment := comment(readerToken, "Can we do Thursday?")
+	writerComment := comment(writerToken, "Thursday works")
+
+	// Users without access can neither comment nor read the discussion
+	if w := testutil.Request(app.Router, http.MethodPost, commentsPath, []byte(`{"body":"Hi"}`), outsiderToken); w.Code != http.StatusForbidden {
+		t.Errorf("outsider comment: expected 403, got %d", w.Code)
+	}
+	if w := testutil.Request(app.Router, http.MethodGet, commentsPath, nil, outsiderToken); w.Code != http.StatusForbidden {
+		t.Errorf("outsider list: expected 403, got %d", w.Code)
+	}
+
+	// Only the author or the category owner may delete a comment, write access is not enough
+	if w := testutil.Request(app.Router, http.MethodDelete, commentsPath+"/"+readerComment, nil, writerToken); w.Code != http.StatusForbidden {
+		t.Errorf("writer deletes reader's comment: expected 403, got %d", w.Code)
+	}
+	if w := testutil.Request(app.Router, http.MethodDelete, commentsPath+"/"+writerComment, nil, readerToken); w.Code != http.StatusForbidden {
+		t.Errorf("reader deletes writer's comment: expected 403, got %d", w.Code)
+	}
+	if w := testutil.Request(app.Router, http.MethodDelete, commentsPath+"/"+writerComment, nil, ownerToken); w.Code != http.StatusOK {
+		t.Errorf("owner deletes writer's comment: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	w = testutil.Request(app.Router, http.MethodGet, commentsPath+"?page_size=1", nil, readerToken)
+	if w.Code != http.StatusOK {
+		t.Fatalf("reader list: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var listResp struct {
+		Data []struct {
+			ID   uint   `json:"id"`
+			Body string `json:"body"`
+		} `json:"data"`
+		Total int64 `json:"total"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&listResp); err != nil {
+		t.Fatalf("decode list response: %v", err)
+	}
+	if listResp.Total != 1 || len(listResp.Data) != 1 || strconv.FormatUint(uint64(listResp.Data[0].ID), 10) != readerComment {
+		t.Errorf("comments after owner delete = %+v (total %d), want only the reader's comment", listResp.Data, listResp.Total)
+	}
+
+	if w := testutil.Request(app.Router, http.MethodDelete, commentsPath+"/"+readerComment, nil, readerToken); w.Code != http.StatusOK {
+		t.Errorf("reader deletes own comment: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := testutil.Request(app.Router, http.MethodDelete, commentsPath+"/"+readerComment, nil, ownerToken); w.Code != http.StatusNotFound {
+		t.Errorf("delete already deleted comment: expected 404, got %d", w.Code)
+	}
+}
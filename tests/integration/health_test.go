@@ -3,8 +3,11 @@
 package integration
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"todo-app/tests/testutil"
@@ -23,3 +26,102 @@ func TestHealth(t *testing.T) {
 		t.Errorf("GET /api/health: expected 200, got %d", w.Code)
 	}
 }
+
+func TestReady_RequiresMigratedSchema(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx := context.Background()
+	ready := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/api/ready", nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Drop the schema in dependency order to simulate a database that was never migrated
+	for _, table := range []string{"todo_comments", "todo_events", "todos", "category_shares", "categories", "users"} {
+		if _, err := app.DB.SQL.ExecContext(ctx, "DROP TABLE IF EXISTS "+table); err != nil {
+			t.Fatalf("drop %s: %v", table, err)
+		}
+	}
+	if code := ready(); code != http.StatusServiceUnavailable {
+		t.Errorf("GET /api/ready before migration: expected 503, got %d", code)
+	}
+
+	if err := app.DB.Migrate(ctx, "../../db/schema.sql"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if code := ready(); code != http.StatusOK {
+		t.Errorf("GET /api/ready after migration: expected 200, got %d", code)
+	}
+}
+
+func TestVerifySchema_FailsOnEmptyDatabase(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := app.DB.VerifySchema(ctx, "../../db/schema.sql"); err != nil {
+		t.Fatalf("VerifySchema() on a migrated database: %v", err)
+	}
+
+	for _, table := range []string{"api_tokens", "todo_comments", "todo_events", "todos", "category_shares", "categories", "users"} {
+		if _, err := app.DB.SQL.ExecContext(ctx, "DROP TABLE IF EXISTS "+table); err != nil {
+			t.Fatalf("drop %s: %v", table, err)
+		}
+	}
+
+	err := app.DB.VerifySchema(ctx, "../../db/schema.sql")
+	if err == nil || !strings.Contains(err.Error(), "table users") {
+		t.Errorf("VerifySchema() on an empty database = %v, want drift listing table users", err)
+	}
+
+	// Verification must not have recreated anything
+	var count int
+	if err := app.DB.SQL.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = 'users'").Scan(&count); err != nil {
+		t.Fatalf("count tables: %v", err)
+	}
+	if count != 0 {
+		t.Error("VerifySchema() created the users table")
+	}
+
+	if err := app.DB.Migrate(ctx, "../../db/schema.sql"); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+}
+
+func TestUnknownRouteAndMethod_ReturnJSON(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	tests := []struct {
+		method   string
+		path     string
+		wantCode int
+	}{
+		{method: http.MethodGet, path: "/api/does-not-exist", wantCode: http.StatusNotFound},
+		{method: http.MethodPost, path: "/api/health", wantCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		app.Router.ServeHTTP(w, req)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.wantCode, w.Code)
+		}
+		var body struct {
+			Success bool   `json:"success"`
+			Code    string `json:"code"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body.Success || body.Code == "" {
+			t.Errorf("%s %s: expected a JSON error envelope, got %q", tt.method, tt.path, w.Body.String())
+		}
+	}
+}
@@ -5,11 +5,17 @@ package integration
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/http/httptest"
+	"slices"
 	"strconv"
 	"testing"
 	"time"
 
+	"todo-app/internal/models"
+	"todo-app/internal/repository"
+	"todo-app/internal/services"
 	"todo-app/tests/testutil"
 )
 
@@ -68,9 +74,9 @@ func TestTodo_CRUD(t *testing.T) {
 		t.Fatalf("get todo by id: expected 200, got %d", w.Code)
 	}
 
-	// Update
+	// Partial update
 	updateBody := []byte(`{"title":"Updated title","completed":true}`)
-	w = testutil.Request(app.Router, http.MethodPut, "/api/todos/"+idStr, updateBody, token)
+	w = testutil.Request(app.Router, http.MethodPatch, "/api/todos/"+idStr, updateBody, token)
 	if w.Code != http.StatusOK {
 		t.Fatalf("update todo: expected 200, got %d", w.Code)
 	}
@@ -87,3 +93,782 @@ func TestTodo_CRUD(t *testing.T) {
 		t.Errorf("get deleted todo: expected 404, got %d", w.Code)
 	}
 }
+
+func TestTodo_RetentionPurge(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Purge User", "purge@example.com", "password123")
+
+	createAndDelete := func(title string) string {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"`+title+`","category":"Purge"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode create response: %v", err)
+		}
+		id := strconv.FormatUint(uint64(resp.Data.ID), 10)
+		if w := testutil.Request(app.Router, http.MethodDelete, "/api/todos/"+id, nil, token); w.Code != http.StatusOK {
+			t.Fatalf("delete todo: expected 200, got %d", w.Code)
+		}
+		return id
+	}
+	oldID := createAndDelete("Deleted long ago")
+	recentID := createAndDelete("Deleted recently")
+
+	// Backdate one deletion past the 30 day test retention
+	if _, err := app.DB.SQL.ExecContext(ctx, "UPDATE todos SET deleted_at = ? WHERE id = ?", time.Now().Add(-40*24*time.Hour), oldID); err != nil {
+		t.Fatalf("backdate deleted_at: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/purge", nil)
+	req.Header.Set("X-Admin-Token", "test-admin-token")
+	w := httptest.NewRecorder()
+	app.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("purge: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	exists := func(id string) bool {
+		t.Helper()
+		var count int
+		if err := app.DB.SQL.QueryRowContext(ctx, "SELECT COUNT(*) FROM todos WHERE id = ?", id).Scan(&count); err != nil {
+			t.Fatalf("count todo %s: %v", id, err)
+		}
+		return count > 0
+	}
+	if exists(oldID) {
+		t.Error("todo deleted beyond retention was not purged")
+	}
+	if !exists(recentID) {
+		t.Error("recently deleted todo was purged")
+	}
+}
+
+func TestTodo_GroupedTimestampsMatchList(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Grouped User", "grouped@example.com", "password123")
+
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Water plants","category":"Home"}`), token)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	type timestamps struct {
+		ID        uint            `json:"id"`
+		CreatedAt json.RawMessage `json:"created_at"`
+		UpdatedAt json.RawMessage `json:"updated_at"`
+	}
+
+	w = testutil.Request(app.Router, http.MethodGet, "/api/todos", nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list todos: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var list struct {
+		Data []timestamps `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+
+	w = testutil.Request(app.Router, http.MethodGet, "/api/todos/grouped", nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("grouped todos: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var grouped struct {
+		Data []struct {
+			Todos []timestamps `json:"todos"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&grouped); err != nil {
+		t.Fatalf("decode grouped: %v", err)
+	}
+
+	if len(list.Data) != 1 || len(grouped.Data) != 1 || len(grouped.Data[0].Todos) != 1 {
+		t.Fatalf("expected one todo in both responses, got list=%+v grouped=%+v", list.Data, grouped.Data)
+	}
+	flat, nested := list.Data[0], grouped.Data[0].Todos[0]
+	if string(nested.CreatedAt) != string(flat.CreatedAt) {
+		t.Errorf("grouped created_at = %s, list created_at = %s", nested.CreatedAt, flat.CreatedAt)
+	}
+	if string(nested.UpdatedAt) != string(flat.UpdatedAt) {
+		t.Errorf("grouped updated_at = %s, list updated_at = %s", nested.UpdatedAt, flat.UpdatedAt)
+	}
+}
+
+func TestTodo_GroupedNextCursor(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Cursor User", "cursor@example.com", "password123")
+
+	// Created within the same second, so the keyset has to fall back to the ID to order them
+	for i := 1; i <= 5; i++ {
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Task `+strconv.Itoa(i)+`","category":"Busy"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo %d: expected 201, got %d body=%s", i, w.Code, w.Body.String())
+		}
+	}
+
+	w := testutil.Request(app.Router, http.MethodGet, "/api/todos/grouped?per_category=2", nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("grouped todos: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var grouped struct {
+		Data []struct {
+			ID    uint `json:"id"`
+			Todos []struct {
+				ID uint `json:"id"`
+			} `json:"todos"`
+			NextCursor string `json:"next_cursor"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&grouped); err != nil {
+		t.Fatalf("decode grouped: %v", err)
+	}
+	if len(grouped.Data) != 1 || len(grouped.Data[0].Todos) != 2 || grouped.Data[0].NextCursor == "" {
+		t.Fatalf("expected one category with 2 todos and a next_cursor, got %+v", grouped.Data)
+	}
+
+	var seen []uint
+	for _, todo := range grouped.Data[0].Todos {
+		seen = append(seen, todo.ID)
+	}
+	path := "/api/categories/" + strconv.FormatUint(uint64(grouped.Data[0].ID), 10) + "/todos?page_size=2&cursor="
+	cursor := grouped.Data[0].NextCursor
+	for cursor != "" {
+		w = testutil.Request(app.Router, http.MethodGet, path+cursor, nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("category todos: expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var page struct {
+			Data []struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+			NextCursor *string `json:"next_cursor"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+			t.Fatalf("decode page: %v", err)
+		}
+		for _, todo := range page.Data {
+			seen = append(seen, todo.ID)
+		}
+		cursor = ""
+		if page.NextCursor != nil {
+			cursor = *page.NextCursor
+		}
+	}
+
+	if len(seen) != 5 {
+		t.Fatalf("expected all 5 todos across pages, got %v", seen)
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i] >= seen[i-1] {
+			t.Fatalf("expected strictly descending IDs without overlap, got %v", seen)
+		}
+	}
+}
+
+func TestTodo_RestoreAllFromTrash(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Trash User", "trash@example.com", "password123")
+
+	for _, title := range []string{"Water plants", "Pay rent", "Call mom"} {
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"`+title+`","category":"Home"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+		}
+		var created struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+			t.Fatalf("decode create: %v", err)
+		}
+		w = testutil.Request(app.Router, http.MethodDelete, "/api/todos/"+strconv.FormatUint(uint64(created.Data.ID), 10), nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("delete todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+	}
+
+	restoreAll := func() int64 {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos/trash/restore-all", nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("restore all: expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Restored int64 `json:"restored"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode restore all: %v", err)
+		}
+		return resp.Data.Restored
+	}
+
+	if restored := restoreAll(); restored != 3 {
+		t.Errorf("restored = %d, want 3", restored)
+	}
+
+	w := testutil.Request(app.Router, http.MethodGet, "/api/todos/count", nil, token)
+	var count struct {
+		Data struct {
+			Count int64 `json:"count"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&count); err != nil {
+		t.Fatalf("decode count: %v", err)
+	}
+	if count.Data.Count != 3 {
+		t.Errorf("todo count after restore = %d, want 3", count.Data.Count)
+	}
+
+	// The trash is empty now
+	if restored := restoreAll(); restored != 0 {
+		t.Errorf("second restore = %d, want 0", restored)
+	}
+}
+
+func TestTodo_TouchOnlyUpdatesTimestamp(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Touch User", "touch@example.com", "password123")
+
+	type todoFields struct {
+		ID          uint      `json:"id"`
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		CategoryID  uint      `json:"category_id"`
+		Completed   bool      `json:"completed"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+	}
+	decode := func(w *httptest.ResponseRecorder) todoFields {
+		t.Helper()
+		var resp struct {
+			Data todoFields `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp.Data
+	}
+
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"Renew passport","description":"Before June","category":"Errands"}`), token)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+	id := strconv.FormatUint(uint64(decode(w).ID), 10)
+
+	// Backdate updated_at so the touch is visible at the column's one second precision
+	if _, err := app.DB.SQL.ExecContext(ctx, "UPDATE todos SET updated_at = ? WHERE id = ?", time.Now().Add(-time.Hour), id); err != nil {
+		t.Fatalf("backdate updated_at: %v", err)
+	}
+	w = testutil.Request(app.Router, http.MethodGet, "/api/todos/"+id, nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	before := decode(w)
+
+	w = testutil.Request(app.Router, http.MethodPost, "/api/todos/"+id+"/touch", nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("touch todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	after := decode(w)
+
+	if !after.UpdatedAt.After(before.UpdatedAt) {
+		t.Errorf("updated_at = %v, want later than %v", after.UpdatedAt, before.UpdatedAt)
+	}
+	after.UpdatedAt = before.UpdatedAt
+	if after != before {
+		t.Errorf("touch changed other fields: got %+v, want %+v", after, before)
+	}
+}
+
+func TestTodo_RecentFeedFollowsUpdates(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Recent User", "recent@example.com", "password123")
+	ownerToken := testutil.MustRegister(t, app.Router, "Recent Owner", "recent-owner@example.com", "password123")
+
+	create := func(token, title, category string) (todoID, categoryID uint) {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"`+title+`","category":"`+category+`"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				ID         uint `json:"id"`
+				CategoryID uint `json:"category_id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode create response: %v", err)
+		}
+		return resp.Data.ID, resp.Data.CategoryID
+	}
+	oldest, _ := create(token, "Oldest", "Home")
+	middle, _ := create(token, "Middle", "Work")
+	shared, sharedCategory := create(ownerToken, "Shared", "Team")
+	create(ownerToken, "Not shared", "Private")
+
+	w := testutil.Request(app.Router, http.MethodPost, "/api/categories/"+strconv.FormatUint(uint64(sharedCategory), 10)+"/share",
+		[]byte(`{"email":"recent@example.com","permission":"write"}`), ownerToken)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("share category: expected 201, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	// Spread updated_at out, since the column only has one second precision
+	for i, id := range []uint{oldest, middle, shared} {
+		updatedAt := time.Now().Add(time.Duration(i-3) * time.Hour)
+		if _, err := app.DB.SQL.ExecContext(ctx, "UPDATE todos SET updated_at = ? WHERE id = ?", updatedAt, id); err != nil {
+			t.Fatalf("backdate updated_at: %v", err)
+		}
+	}
+
+	recentIDs := func() []uint {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodGet, "/api/todos/recent", nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("recent todos: expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode recent todos: %v", err)
+		}
+		ids := make([]uint, 0, len(resp.Data))
+		for _, todo := range resp.Data {
+			ids = append(ids, todo.ID)
+		}
+		return ids
+	}
+
+	if got, want := recentIDs(), []uint{shared, middle, oldest}; !slices.Equal(got, want) {
+		t.Fatalf("recent todos = %v, want %v", got, want)
+	}
+
+	w = testutil.Request(app.Router, http.MethodPatch, "/api/todos/"+strconv.FormatUint(uint64(oldest), 10), []byte(`{"title":"Oldest, now newest"}`), token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	if got, want := recentIDs(), []uint{oldest, shared, middle}; !slices.Equal(got, want) {
+		t.Errorf("recent todos after update = %v, want %v", got, want)
+	}
+}
+
+func TestTodo_BulkMoveCompletedOnly(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Move User", "move@example.com", "password123")
+
+	type todoFields struct {
+		ID         uint `json:"id"`
+		CategoryID uint `json:"category_id"`
+	}
+	create := func(title, category string) todoFields {
+		t.Helper()
+		body := []byte(`{"title":"` + title + `","category":"` + category + `"}`)
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", body, token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo %q: expected 201, got %d body=%s", title, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data todoFields `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp.Data
+	}
+	categoryOf := func(id uint) uint {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodGet, "/api/todos/"+strconv.FormatUint(uint64(id), 10), nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("get todo %d: expected 200, got %d body=%s", id, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data todoFields `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp.Data.CategoryID
+	}
+
+	filed := create("File taxes", "Inbox")
+	paid := create("Pay rent", "Inbox")
+	open := create("Book dentist", "Inbox")
+	archive := create("Old notes", "Archive")
+	inboxID, archiveID := filed.CategoryID, archive.CategoryID
+
+	for _, todo := range []todoFields{filed, paid} {
+		w := testutil.Request(app.Router, http.MethodPatch, "/api/todos/"+strconv.FormatUint(uint64(todo.ID), 10), []byte(`{"completed":true}`), token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("complete todo %d: expected 200, got %d body=%s", todo.ID, w.Code, w.Body.String())
+		}
+	}
+
+	body := []byte(`{"from_category_id":` + strconv.FormatUint(uint64(inboxID), 10) +
+		`,"to_category_id":` + strconv.FormatUint(uint64(archiveID), 10) + `,"filter":{"completed":true}}`)
+	w := testutil.Request(app.Router, http.MethodPost, "/api/todos/bulk-move", body, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("bulk move: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			Moved int64 `json:"moved"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Data.Moved != 2 {
+		t.Errorf("moved = %d, want 2", resp.Data.Moved)
+	}
+
+	for _, todo := range []todoFields{filed, paid} {
+		if got := categoryOf(todo.ID); got != archiveID {
+			t.Errorf("completed todo %d is in category %d, want archive %d", todo.ID, got, archiveID)
+		}
+	}
+	if got := categoryOf(open.ID); got != inboxID {
+		t.Errorf("open todo %d is in category %d, want inbox %d", open.ID, got, inboxID)
+	}
+}
+
+func TestTodo_IDsSinceIncludeTombstones(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Sync User", "sync@example.com", "password123")
+
+	create := func(title string) uint {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"`+title+`","category":"Sync"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo %q: expected 201, got %d body=%s", title, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp.Data.ID
+	}
+	type change struct {
+		ID      uint `json:"id"`
+		Deleted bool `json:"deleted"`
+	}
+	changesSince := func(since string) []change {
+		t.Helper()
+		path := "/api/todos/ids"
+		if since != "" {
+			path += "?since=" + since
+		}
+		w := testutil.Request(app.Router, http.MethodGet, path, nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("get ids: expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data []change `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp.Data
+	}
+
+	unchanged := create("Water plants")
+	deleted := create("Cancel gym")
+
+	// Backdate both todos so only the delete below falls inside the since window
+	if _, err := app.DB.SQL.ExecContext(ctx, "UPDATE todos SET updated_at = ?", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("backdate updated_at: %v", err)
+	}
+	if got := changesSince(""); len(got) != 2 {
+		t.Fatalf("ids without since = %+v, want both todos", got)
+	}
+
+	since := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+	if got := changesSince(since); len(got) != 0 {
+		t.Fatalf("ids since %s before the delete = %+v, want none", since, got)
+	}
+
+	w := testutil.Request(app.Router, http.MethodDelete, "/api/todos/"+strconv.FormatUint(uint64(deleted), 10), nil, token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("delete todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+
+	got := changesSince(since)
+	if want := []change{{ID: deleted, Deleted: true}}; !slices.Equal(got, want) {
+		t.Errorf("ids since %s = %+v, want only the tombstone %+v (todo %d is unchanged)", since, got, want, unchanged)
+	}
+}
+
+func TestTodo_SyncFullThenIncremental(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Delta User", "delta@example.com", "password123")
+
+	create := func(title string) uint {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"`+title+`","category":"Delta"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo %q: expected 201, got %d body=%s", title, w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp.Data.ID
+	}
+	// Changes from the current second are held back, so the test moves its writes into the past
+	backdate := func(age time.Duration, ids ...uint) {
+		t.Helper()
+		for _, id := range ids {
+			if _, err := app.DB.SQL.ExecContext(ctx, "UPDATE todos SET updated_at = ?, created_at = LEAST(created_at, ?) WHERE id = ?",
+				time.Now().Add(-age), time.Now().Add(-age), id); err != nil {
+				t.Fatalf("backdate todo %d: %v", id, err)
+			}
+		}
+	}
+	type syncData struct {
+		Created []struct {
+			ID uint `json:"id"`
+		} `json:"created"`
+		Updated []struct {
+			ID uint `json:"id"`
+		} `json:"updated"`
+		Deleted    []uint `json:"deleted"`
+		NextCursor string `json:"next_cursor"`
+	}
+	sync := func(cursor string) (created, updated, deleted []uint, next string) {
+		t.Helper()
+		w := testutil.Request(app.Router, http.MethodGet, "/api/todos/sync?cursor="+cursor, nil, token)
+		if w.Code != http.StatusOK {
+			t.Fatalf("sync: expected 200, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data syncData `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		created, updated = []uint{}, []uint{}
+		for _, todo := range resp.Data.Created {
+			created = append(created, todo.ID)
+		}
+		for _, todo := range resp.Data.Updated {
+			updated = append(updated, todo.ID)
+		}
+		return created, updated, resp.Data.Deleted, resp.Data.NextCursor
+	}
+
+	edited := create("Draft report")
+	removed := create("Book venue")
+	untouched := create("Order cake")
+	backdate(2*time.Hour, edited, removed, untouched)
+
+	created, updated, deleted, cursor := sync("")
+	if !slices.Equal(created, []uint{edited, removed, untouched}) || len(updated) != 0 || len(deleted) != 0 {
+		t.Fatalf("full sync = created %v updated %v deleted %v, want all three created", created, updated, deleted)
+	}
+	if cursor == "" {
+		t.Fatal("full sync returned no cursor")
+	}
+
+	idPath := func(id uint) string { return "/api/todos/" + strconv.FormatUint(uint64(id), 10) }
+	if w := testutil.Request(app.Router, http.MethodPatch, idPath(edited), []byte(`{"title":"Final report"}`), token); w.Code != http.StatusOK {
+		t.Fatalf("update todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := testutil.Request(app.Router, http.MethodDelete, idPath(removed), nil, token); w.Code != http.StatusOK {
+		t.Fatalf("delete todo: expected 200, got %d body=%s", w.Code, w.Body.String())
+	}
+	added := create("Send invites")
+	backdate(time.Hour, edited, removed, added)
+
+	created, updated, deleted, next := sync(cursor)
+	if !slices.Equal(created, []uint{added}) || !slices.Equal(updated, []uint{edited}) || !slices.Equal(deleted, []uint{removed}) {
+		t.Errorf("incremental sync = created %v updated %v deleted %v, want created [%d] updated [%d] deleted [%d]",
+			created, updated, deleted, added, edited, removed)
+	}
+
+	// Nothing changed since, so the next sync is empty and keeps its cursor
+	created, updated, deleted, again := sync(next)
+	if len(created)+len(updated)+len(deleted) != 0 || again != next {
+		t.Errorf("sync without changes = created %v updated %v deleted %v cursor %q, want nothing and cursor %q",
+			created, updated, deleted, again, next)
+	}
+}
+
+// failingNotifier fails every delivery while fail is set and records the todos it delivered
+type failingNotifier struct {
+	fail      bool
+	delivered []uint
+}
+
+func (n *failingNotifier) NotifyReminder(ctx context.Context, todo models.Todo) error {
+	if n.fail {
+		return errors.New("notifier unavailable")
+	}
+	n.delivered = append(n.delivered, todo.ID)
+	return nil
+}
+
+func TestTodo_ReminderDispatchKeepsUpdatedAt(t *testing.T) {
+	testutil.SkipIfNoTestDB(t)
+	app, cleanup := testutil.NewTestApp(t, "../../db/schema.sql")
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := testutil.TruncateAll(ctx, app.DB); err != nil {
+		t.Fatalf("truncate: %v", err)
+	}
+
+	token := testutil.MustRegister(t, app.Router, "Reminder User", "reminder@example.com", "password123")
+
+	create := func(title string) uint {
+		t.Helper()
+		remindAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		w := testutil.Request(app.Router, http.MethodPost, "/api/todos", []byte(`{"title":"`+title+`","category":"Reminders","remind_at":"`+remindAt+`"}`), token)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create todo: expected 201, got %d body=%s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode create response: %v", err)
+		}
+		return resp.Data.ID
+	}
+	openID := create("Call the bank")
+	doneID := create("Already called")
+
+	// Make both reminders due and backdate updated_at so a bump is visible at one second precision
+	updatedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if _, err := app.DB.SQL.ExecContext(ctx, "UPDATE todos SET remind_at = ?, updated_at = ? WHERE id IN (?, ?)", time.Now().Add(-time.Minute), updatedAt, openID, doneID); err != nil {
+		t.Fatalf("backdate todos: %v", err)
+	}
+	if _, err := app.DB.SQL.ExecContext(ctx, "UPDATE todos SET completed = TRUE, completed_at = ?, updated_at = ? WHERE id = ?", updatedAt, updatedAt, doneID); err != nil {
+		t.Fatalf("complete todo: %v", err)
+	}
+
+	// A failed delivery is released, then the next dispatch delivers it; neither counts as an edit
+	notifier := &failingNotifier{fail: true}
+	dispatcher := services.NewReminderDispatcher(repository.NewSQLTodoRepository(app.DB.Queries), notifier, time.Minute)
+	for _, fail := range []bool{true, false} {
+		notifier.fail = fail
+		if _, err := dispatcher.DispatchDue(ctx, time.Now()); err != nil {
+			t.Fatalf("dispatch reminders: %v", err)
+		}
+	}
+
+	if !slices.Equal(notifier.delivered, []uint{openID}) {
+		t.Errorf("delivered reminders for %v, want only the open todo %d", notifier.delivered, openID)
+	}
+	var got time.Time
+	if err := app.DB.SQL.QueryRowContext(ctx, "SELECT updated_at FROM todos WHERE id = ?", openID).Scan(&got); err != nil {
+		t.Fatalf("read updated_at: %v", err)
+	}
+	if !got.Equal(updatedAt) {
+		t.Errorf("updated_at = %v after dispatch, want it left at %v", got, updatedAt)
+	}
+}
@@ -43,6 +43,15 @@ func NewTestApp(t *testing.T, schemaPath string) (*TestApp, func()) {
 		User:     cfg.DBUser,
 		Password: cfg.DBPassword,
 		DBName:   cfg.DBName,
+
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+
+		ConnectRetries: cfg.DBConnectRetries,
+		ConnectBackoff: cfg.DBConnectBackoff,
+
+		SlowQueryThreshold: cfg.SlowQueryThreshold,
 	}
 	database, err := db.ConnectDB(ctx, dbCfg)
 	if err != nil {
@@ -54,7 +63,13 @@ func NewTestApp(t *testing.T, schemaPath string) (*TestApp, func()) {
 		t.Fatalf("migrate test db: %v", err)
 	}
 
-	jwtManager, err := utils.NewJWTManager(cfg.JWTSecret)
+	jwtManager, err := utils.NewJWTManager(cfg.JWTSecret,
+		utils.WithIssuer(cfg.JWTIssuer),
+		utils.WithAudience(cfg.JWTAudience),
+		utils.WithSigningMethod(cfg.JWTAlgorithm),
+		utils.WithKeys(cfg.JWTSigningKeyID, cfg.JWTKeys),
+		utils.WithRequireKID(cfg.JWTRequireKID),
+	)
 	if err != nil {
 		database.Close()
 		t.Fatalf("jwt manager: %v", err)
@@ -64,33 +79,56 @@ func NewTestApp(t *testing.T, schemaPath string) (*TestApp, func()) {
 	todoRepo := repository.NewSQLTodoRepository(database.Queries)
 	categoryRepo := repository.NewSQLCategoryRepository(database.Queries)
 	categoryShareRepo := repository.NewSQLCategoryShareRepository(database.Queries)
+	todoEventRepo := repository.NewSQLTodoEventRepository(database.Queries)
+	apiTokenRepo := repository.NewSQLAPITokenRepository(database.Queries)
+	commentRepo := repository.NewSQLCommentRepository(database.Queries)
+	txManager := repository.NewSQLTxManager(database)
 
-	authSvc := services.NewAuthService(userRepo, jwtManager)
-	todoSvc := services.NewTodoService(todoRepo, categoryRepo, categoryShareRepo, services.PaginationConfig{
+	authSvc := services.NewAuthService(userRepo, jwtManager, services.AuthConfig{
+		BcryptCost:                     cfg.BcryptCost,
+		BlockedEmailDomains:            cfg.BlockedEmailDomains,
+		RegisterReturnsLoginOnExisting: cfg.RegisterReturnsLoginOnExisting,
+	})
+	pagination := services.PaginationConfig{
 		DefaultPageSize: cfg.DefaultPageSize,
 		MaxPageSize:     cfg.MaxPageSize,
-	})
-	categorySvc := services.NewCategoryService(categoryRepo, categoryShareRepo, userRepo, todoRepo)
+		DefaultTodoSort: cfg.DefaultTodoSort,
+	}
+	limits := services.LimitsConfig{
+		MaxTodosPerCategory: cfg.MaxTodosPerCategory,
+		MaxRecentTodos:      cfg.MaxRecentTodos,
+		MaxTitleLen:         cfg.MaxTitleLen,
+		MaxDescriptionLen:   cfg.MaxDescriptionLen,
+	}
+	undoTokens, err := utils.NewUndoTokenManager(cfg.JWTSecret)
+	if err != nil {
+		database.Close()
+		t.Fatalf("undo token manager: %v", err)
+	}
+	todoSvc := services.NewTodoService(todoRepo, categoryRepo, categoryShareRepo, todoEventRepo, userRepo, txManager, pagination, limits, cfg.AutoCreateCategories, undoTokens)
+	categorySvc := services.NewCategoryService(categoryRepo, categoryShareRepo, userRepo, todoRepo, txManager, pagination, limits)
+	apiTokenSvc := services.NewAPITokenService(apiTokenRepo)
+	commentSvc := services.NewCommentService(commentRepo, todoRepo, categoryRepo, categoryShareRepo, pagination)
+	userSvc := services.NewUserService(userRepo, pagination)
 
-	authHandler := handlers.NewAuthHandler(authSvc)
-	todoHandler := handlers.NewTodoHandler(todoSvc)
-	categoryHandler := handlers.NewCategoryHandler(categorySvc)
+	authHandler := handlers.NewAuthHandler(authSvc, cfg.AuthCookieMode)
+	todoHandler := handlers.NewTodoHandler(todoSvc, cfg.MinTodoTitleRunes, pagination)
+	categoryHandler := handlers.NewCategoryHandler(categorySvc, pagination)
+	apiTokenHandler := handlers.NewAPITokenHandler(apiTokenSvc)
+	commentHandler := handlers.NewCommentHandler(commentSvc, pagination)
+	adminHandler := handlers.NewAdminHandler(userSvc, pagination)
 
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-Id")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		database.Close()
+		t.Fatalf("set trusted proxies: %v", err)
+	}
+	router.Use(middleware.CORS(cfg.CORSMaxAge))
 	router.Use(middleware.RequestIDMiddleware())
-	routes.SetupRoutes(router, authHandler, todoHandler, categoryHandler, jwtManager)
+	router.Use(middleware.Gzip())
+	routes.SetupRoutes(router, authHandler, todoHandler, categoryHandler, apiTokenHandler, commentHandler, adminHandler, jwtManager, apiTokenSvc, authSvc, database,
+		services.NewRetentionPurger(todoRepo, cfg.SoftDeleteRetention, cfg.PurgeInterval), cfg.AdminToken, cfg.CORSMaxAge, cfg.MaxConcurrentPerUser)
 
 	app := &TestApp{Router: router, DB: database, cfg: cfg}
 	cleanup := func() {
@@ -3,8 +3,12 @@ package testutil
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"todo-app/config"
+	"todo-app/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 // LoadTestConfig loads config for integration tests.
@@ -12,16 +16,37 @@ import (
 // with e.g. DB_NAME=todo_test. JWT_SECRET is required (use TEST_JWT_SECRET or JWT_SECRET).
 func LoadTestConfig() (*config.Config, error) {
 	cfg := &config.Config{
-		ServerPort:      "0",
-		DBHost:          getTestEnv("TEST_DB_HOST", "DB_HOST"),
-		DBPort:          getTestEnvDefault("TEST_DB_PORT", "DB_PORT", "3306"),
-		DBUser:          getTestEnv("TEST_DB_USER", "DB_USER"),
-		DBPassword:      getTestEnv("TEST_DB_PASSWORD", "DB_PASSWORD"),
-		DBName:          getTestEnv("TEST_DB_NAME", "DB_NAME"),
-		RunMigrations:   true,
-		JWTSecret:       getTestEnv("TEST_JWT_SECRET", "JWT_SECRET"),
-		DefaultPageSize: 10,
-		MaxPageSize:     100,
+		ServerPort:           "0",
+		DBHost:               getTestEnv("TEST_DB_HOST", "DB_HOST"),
+		DBPort:               getTestEnvDefault("TEST_DB_PORT", "DB_PORT", "3306"),
+		DBUser:               getTestEnv("TEST_DB_USER", "DB_USER"),
+		DBPassword:           getTestEnv("TEST_DB_PASSWORD", "DB_PASSWORD"),
+		DBName:               getTestEnv("TEST_DB_NAME", "DB_NAME"),
+		DBMaxOpenConns:       10,
+		DBMaxIdleConns:       5,
+		DBConnMaxLifetime:    time.Hour,
+		DBConnectRetries:     0, // fail fast when the test database is unavailable
+		DBConnectBackoff:     time.Second,
+		SlowQueryThreshold:   500 * time.Millisecond,
+		MigrationMode:        config.MigrationModeApply,
+		JWTSecret:            getTestEnv("TEST_JWT_SECRET", "JWT_SECRET"),
+		BcryptCost:           bcrypt.MinCost, // keep password hashing fast in tests
+		DefaultPageSize:      10,
+		MaxPageSize:          100,
+		DefaultTodoSort:      models.TodoSortCreatedAtDesc,
+		ReminderInterval:     time.Minute,
+		SoftDeleteRetention:  30 * 24 * time.Hour,
+		PurgeInterval:        time.Hour,
+		AdminToken:           "test-admin-token",
+		AdminEmail:           "admin@example.com",
+		MaxTodosPerCategory:  1000,
+		MaxRecentTodos:       100,
+		AutoCreateCategories: true,
+		MinTodoTitleRunes:    1,
+		MaxTitleLen:          255,
+		MaxDescriptionLen:    1000,
+		MaxConcurrentPerUser: 20,
+		CORSMaxAge:           10 * time.Minute,
 	}
 	if err := validateTestConfig(cfg); err != nil {
 		return nil, fmt.Errorf("test config: %w", err)
@@ -23,7 +23,7 @@ func TruncateAll(ctx context.Context, database *db.DB) error {
 	timeout, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	tables := []string{"todos", "category_shares", "categories", "users"}
+	tables := []string{"api_tokens", "todo_comments", "todo_events", "todos", "category_shares", "categories", "users"}
 	for _, table := range tables {
 		if _, err := database.SQL.ExecContext(timeout, "DELETE FROM "+table); err != nil {
 			return err